@@ -0,0 +1,63 @@
+package tryl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// actionReplacer maps the separators of legacy action names to underscores.
+var actionReplacer = strings.NewReplacer(" ", "_", "-", "_")
+
+// MatchesAction reports whether action matches an action filter pattern,
+// using the same rules as EventFilter.Action.
+// A "*" matches any sequence of characters, including dots, so "user.*"
+// matches "user.created" and "user.profile.updated", and "*.created"
+// matches "org.created". A pattern without wildcards must match exactly.
+//
+// This is useful for routing events locally (e.g., in webhook handlers)
+// with the same semantics the API applies to queries.
+func MatchesAction(pattern, action string) bool {
+	return validation.MatchAction(pattern, action)
+}
+
+// ValidateActionPattern checks that an action filter pattern is well formed.
+// Patterns follow the action format, with "*" allowed in place of any
+// fragment (e.g., "user.*", "*.created", "org.*.added").
+// It returns a *ValidationError describing the problem, or nil.
+func ValidateActionPattern(pattern string) error {
+	if err := validation.ValidateActionPattern(pattern); err != nil {
+		return newValidationError(err)
+	}
+	return nil
+}
+
+// NormalizeAction converts a legacy action name to the action format by
+// trimming surrounding whitespace, lowercasing, and replacing spaces and
+// hyphens with underscores (e.g., " User-Profile Updated" becomes
+// "user_profile_updated"). The result is not validated.
+func NormalizeAction(action string) string {
+	return actionReplacer.Replace(strings.ToLower(strings.TrimSpace(action)))
+}
+
+// normalizeAction applies the client's action normalization mode to
+// event before validation.
+func (c *Client) normalizeAction(event *Event) error {
+	if c.config.actionNormalization == actionNormalizationOff {
+		return nil
+	}
+	normalized := NormalizeAction(event.Action)
+	if normalized == event.Action {
+		return nil
+	}
+	if c.config.actionNormalization == actionNormalizationStrict {
+		return &ValidationError{
+			Field:   "action",
+			Message: fmt.Sprintf("is not normalized (normalized form: %q)", normalized),
+			Value:   event.Action,
+		}
+	}
+	event.Action = normalized
+	return nil
+}
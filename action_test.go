@@ -0,0 +1,92 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatchesAction(t *testing.T) {
+	t.Parallel()
+
+	if !MatchesAction("user.*", "user.created") {
+		t.Error(`MatchesAction("user.*", "user.created") = false, want true`)
+	}
+	if MatchesAction("*.created", "user.deleted") {
+		t.Error(`MatchesAction("*.created", "user.deleted") = true, want false`)
+	}
+}
+
+func TestClient_List_InvalidActionPattern(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for invalid action pattern")
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.List(context.Background(), EventFilter{Action: "User.**"})
+	if !IsClientValidationError(err) {
+		t.Errorf("List() error = %v, want ValidationError", err)
+	}
+}
+
+func TestNormalizeAction(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"user.created":         "user.created",
+		" User Created ":       "user_created",
+		"Billing.Invoice-Paid": "billing.invoice_paid",
+		"ORG.member added":     "org.member_added",
+	}
+	for in, want := range tests {
+		if got := NormalizeAction(in); got != want {
+			t.Errorf("NormalizeAction(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestClient_ActionNormalization(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		json.NewDecoder(r.Body).Decode(&e)
+		got = append(got, e.Action)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	legacy := Event{UserID: "user_1", Action: "User-Profile Updated"}
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if _, err := client.Log(ctx, legacy); !IsClientValidationError(err) {
+		t.Errorf("Log() without normalization error = %v, want validation error", err)
+	}
+
+	client, _ = NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithActionNormalization())
+	if _, err := client.Log(ctx, legacy); err != nil {
+		t.Fatalf("Log() with normalization error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "user_profile_updated" {
+		t.Errorf("sent actions = %v, want [user_profile_updated]", got)
+	}
+
+	client, _ = NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithStrictActionNormalization())
+	_, err := client.Log(ctx, legacy)
+	if verr, ok := AsValidationError(err); !ok || !strings.Contains(verr.Message, "user_profile_updated") {
+		t.Errorf("Log() with strict normalization error = %v, want validation error naming the normalized form", err)
+	}
+}
@@ -0,0 +1,194 @@
+package tryl
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// TimeBucket is the number of events in one interval of a TimeSeries.
+type TimeBucket struct {
+	// Start is the start of the interval.
+	Start time.Time
+	// Count is the number of events in the interval.
+	Count int
+}
+
+// TimeSeries is a sequence of equal, contiguous intervals in time order.
+type TimeSeries []TimeBucket
+
+// BucketEvents counts events per interval, from the interval containing
+// the earliest event to the one containing the latest. Intervals with no
+// events are included with a zero count. Interval starts are aligned with
+// time.Truncate.
+func BucketEvents(events []StoredEvent, interval time.Duration) TimeSeries {
+	if len(events) == 0 || interval <= 0 {
+		return nil
+	}
+	first, last := events[0].Timestamp, events[0].Timestamp
+	for _, e := range events[1:] {
+		if e.Timestamp.Before(first) {
+			first = e.Timestamp
+		}
+		if e.Timestamp.After(last) {
+			last = e.Timestamp
+		}
+	}
+	start := first.UTC().Truncate(interval)
+
+	series := make(TimeSeries, int(last.Sub(start)/interval)+1)
+	for i := range series {
+		series[i].Start = start.Add(time.Duration(i) * interval)
+	}
+	for _, e := range events {
+		series[int(e.Timestamp.Sub(start)/interval)].Count++
+	}
+	return series
+}
+
+// SpikeOptions configures DetectSpikes.
+type SpikeOptions struct {
+	// Window is the number of preceding buckets used as the baseline.
+	// Default: 24
+	Window int
+	// Threshold is how many standard deviations above the baseline mean a
+	// bucket must be to count as a spike.
+	// Default: 3
+	Threshold float64
+	// MinCount ignores buckets with fewer events, so that small absolute
+	// changes in quiet periods are not reported.
+	// Default: 1
+	MinCount int
+}
+
+// Spike is a bucket with abnormally high volume.
+type Spike struct {
+	TimeBucket
+	// Baseline is the mean count of the preceding window.
+	Baseline float64
+	// Score is the number of standard deviations above Baseline.
+	Score float64
+}
+
+// DetectSpikes returns the buckets of series whose count is unusually
+// high compared with the buckets before them. At least two preceding
+// buckets are needed before a bucket can be flagged. A flat baseline is
+// treated as having a standard deviation of 1.
+func DetectSpikes(series TimeSeries, opts SpikeOptions) []Spike {
+	if opts.Window <= 0 {
+		opts.Window = 24
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = 3
+	}
+	if opts.MinCount <= 0 {
+		opts.MinCount = 1
+	}
+
+	var spikes []Spike
+	for i := 2; i < len(series); i++ {
+		if series[i].Count < opts.MinCount {
+			continue
+		}
+		window := series[max(0, i-opts.Window):i]
+		mean, stddev := meanStddev(window)
+		if stddev == 0 {
+			stddev = 1
+		}
+		score := (float64(series[i].Count) - mean) / stddev
+		if score >= opts.Threshold {
+			spikes = append(spikes, Spike{TimeBucket: series[i], Baseline: mean, Score: score})
+		}
+	}
+	return spikes
+}
+
+// meanStddev returns the mean and population standard deviation of the
+// bucket counts.
+func meanStddev(buckets []TimeBucket) (mean, stddev float64) {
+	for _, b := range buckets {
+		mean += float64(b.Count)
+	}
+	mean /= float64(len(buckets))
+	for _, b := range buckets {
+		d := float64(b.Count) - mean
+		stddev += d * d
+	}
+	return mean, math.Sqrt(stddev / float64(len(buckets)))
+}
+
+// ActorActivity is the event volume of one actor.
+type ActorActivity struct {
+	// ActorID is the event's ActorID, or its UserID if no actor was set.
+	ActorID string
+	// Count is the number of events by the actor.
+	Count int
+	// Score is the actor's modified z-score; values above 3.5 are
+	// considered unusual.
+	Score float64
+}
+
+// UnusualActors returns the actors whose event count is an outlier among
+// all actors in events, highest score first. It uses the median absolute
+// deviation, so a few very active actors do not hide each other. At least
+// three actors are needed to find outliers.
+func UnusualActors(events []StoredEvent) []ActorActivity {
+	counts := map[string]int{}
+	for _, e := range events {
+		actor := e.ActorID
+		if actor == "" {
+			actor = e.UserID
+		}
+		counts[actor]++
+	}
+	if len(counts) < 3 {
+		return nil
+	}
+
+	values := make([]float64, 0, len(counts))
+	for _, n := range counts {
+		values = append(values, float64(n))
+	}
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	// Modified z-score (Iglewicz and Hoaglin). When more than half the
+	// actors have the same count the MAD is zero, so the mean absolute
+	// deviation is used instead.
+	scale := 0.6745 / medianOf(deviations)
+	if math.IsInf(scale, 1) {
+		var sum float64
+		for _, d := range deviations {
+			sum += d
+		}
+		scale = 1 / (1.253314 * sum / float64(len(deviations)))
+	}
+
+	var unusual []ActorActivity
+	for actor, n := range counts {
+		score := (float64(n) - median) * scale
+		if score > 3.5 {
+			unusual = append(unusual, ActorActivity{ActorID: actor, Count: n, Score: score})
+		}
+	}
+	sort.Slice(unusual, func(i, j int) bool {
+		if unusual[i].Score != unusual[j].Score {
+			return unusual[i].Score > unusual[j].Score
+		}
+		return unusual[i].ActorID < unusual[j].ActorID
+	})
+	return unusual
+}
+
+// medianOf returns the median of values, reordering them.
+func medianOf(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
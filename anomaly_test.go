@@ -0,0 +1,76 @@
+package tryl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketEvents(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	series := BucketEvents([]StoredEvent{
+		{Timestamp: base.Add(65 * time.Minute)},
+		{Timestamp: base.Add(5 * time.Minute)},
+		{Timestamp: base.Add(10 * time.Minute)},
+	}, time.Hour)
+
+	if len(series) != 2 {
+		t.Fatalf("BucketEvents() = %v, want 2 buckets", series)
+	}
+	if !series[0].Start.Equal(base) || series[0].Count != 2 || series[1].Count != 1 {
+		t.Errorf("BucketEvents() = %v", series)
+	}
+	if BucketEvents(nil, time.Hour) != nil {
+		t.Error("BucketEvents(nil) should be nil")
+	}
+}
+
+func TestDetectSpikes(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	counts := []int{10, 12, 9, 11, 10, 60, 11, 10, 4}
+	series := make(TimeSeries, len(counts))
+	for i, n := range counts {
+		series[i] = TimeBucket{Start: base.Add(time.Duration(i) * time.Hour), Count: n}
+	}
+
+	spikes := DetectSpikes(series, SpikeOptions{})
+	if len(spikes) != 1 {
+		t.Fatalf("DetectSpikes() = %v, want one spike", spikes)
+	}
+	if spikes[0].Count != 60 || spikes[0].Baseline != 10.4 || spikes[0].Score < 3 {
+		t.Errorf("spike = %+v", spikes[0])
+	}
+
+	if got := DetectSpikes(series, SpikeOptions{MinCount: 100}); len(got) != 0 {
+		t.Errorf("DetectSpikes() with MinCount = %v, want none", got)
+	}
+}
+
+func TestUnusualActors(t *testing.T) {
+	t.Parallel()
+
+	var events []StoredEvent
+	add := func(actor string, n int) {
+		for i := 0; i < n; i++ {
+			events = append(events, StoredEvent{UserID: "user_1", ActorID: actor})
+		}
+	}
+	add("alice", 5)
+	add("bob", 6)
+	add("carol", 4)
+	add("dave", 5)
+	add("mallory", 80)
+	events = append(events, StoredEvent{UserID: "svc_sync"})
+
+	got := UnusualActors(events)
+	if len(got) != 1 || got[0].ActorID != "mallory" || got[0].Count != 80 {
+		t.Errorf("UnusualActors() = %+v, want mallory", got)
+	}
+
+	if got := UnusualActors(events[:11]); got != nil {
+		t.Errorf("UnusualActors() with two actors = %+v, want nil", got)
+	}
+}
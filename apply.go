@@ -0,0 +1,273 @@
+package tryl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ApplyOp is the kind of change Apply makes.
+type ApplyOp string
+
+const (
+	// ApplyCreate creates a resource.
+	ApplyCreate ApplyOp = "create"
+	// ApplyUpdate changes an existing resource.
+	ApplyUpdate ApplyOp = "update"
+	// ApplyDelete deletes a resource.
+	ApplyDelete ApplyOp = "delete"
+)
+
+// ApplyOptions configures Apply.
+type ApplyOptions struct {
+	// DryRun computes the changes without making them.
+	DryRun bool
+	// Prune deletes projects that are not in the desired snapshot, and
+	// with them their events. Without it, Apply never deletes a project,
+	// so a snapshot of some projects leaves the others alone.
+	Prune bool
+}
+
+// ApplyChange is a change Apply made, or would make in a dry run.
+type ApplyChange struct {
+	// Op is the kind of change.
+	Op ApplyOp
+	// Resource is "project", "retention", or "webhook".
+	Resource string
+	// ProjectID is the project changed. It is empty for a project that a
+	// dry run would create.
+	ProjectID string
+	// Name identifies the resource: the project name, or the webhook URL.
+	// It is empty for retention changes.
+	Name string
+	// Secret is the initial API key of a created project or the signing
+	// secret of a created webhook.
+	// IMPORTANT: This is only returned once at creation time. Store it securely.
+	Secret string
+}
+
+// ApplyResult lists the changes made by Apply.
+type ApplyResult struct {
+	// DryRun reports whether the changes were only planned.
+	DryRun bool
+	// Changes are the changes in the order they were made.
+	Changes []ApplyChange
+}
+
+// Apply makes the account's configuration match desired, typically a
+// snapshot taken with Snapshot and kept under version control.
+//
+// Projects are matched by name and environment and created if missing.
+// A project's retention policy is set if desired has one, and its
+// webhooks, matched by URL, are created, updated, or deleted to match
+// desired if its Webhooks are not nil. API keys and saved queries are not
+// changed, and projects missing from desired are deleted only with
+// ApplyOptions.Prune.
+//
+// Apply stops at the first error and returns the changes made so far
+// with it.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) Apply(ctx context.Context, desired AccountSnapshot, opts ApplyOptions) (*ApplyResult, error) {
+	if err := validateApply(desired); err != nil {
+		return nil, err
+	}
+	result := &ApplyResult{DryRun: opts.DryRun}
+
+	list, err := c.ListProjects(ctx)
+	if err != nil {
+		return result, err
+	}
+	existing := make(map[projectKey]Project, len(list.Projects))
+	for _, p := range list.Projects {
+		if _, ok := existing[keyOf(p)]; !ok {
+			existing[keyOf(p)] = p
+		}
+	}
+
+	kept := make(map[string]bool, len(desired.Projects))
+	for _, want := range desired.Projects {
+		actual, ok := existing[keyOf(want.Project)]
+		if ok {
+			kept[actual.ID] = true
+		}
+		if err := c.applyProject(ctx, result, want, actual, ok, opts); err != nil {
+			return result, fmt.Errorf("apply of project %s: %w", want.Name, err)
+		}
+	}
+
+	if opts.Prune {
+		for _, p := range list.Projects {
+			if kept[p.ID] {
+				continue
+			}
+			if !opts.DryRun {
+				if err := c.DeleteProject(ctx, p.ID); err != nil {
+					return result, fmt.Errorf("apply of project %s: %w", p.Name, err)
+				}
+			}
+			result.Changes = append(result.Changes, ApplyChange{Op: ApplyDelete, Resource: "project", ProjectID: p.ID, Name: p.Name})
+		}
+	}
+	return result, nil
+}
+
+// projectKey identifies a project across accounts.
+type projectKey struct {
+	name        string
+	environment Environment
+}
+
+func keyOf(p Project) projectKey {
+	return projectKey{name: p.Name, environment: p.Environment}
+}
+
+// validateApply checks desired before Apply changes anything.
+func validateApply(desired AccountSnapshot) error {
+	projects := make(map[projectKey]bool, len(desired.Projects))
+	for _, p := range desired.Projects {
+		if p.Name == "" {
+			return &ValidationError{Field: "projects.name", Message: "is required"}
+		}
+		if err := p.Environment.validate(true); err != nil {
+			return err
+		}
+		if projects[keyOf(p.Project)] {
+			return &ValidationError{Field: "projects", Message: "duplicate project", Value: p.Name}
+		}
+		projects[keyOf(p.Project)] = true
+
+		if p.Retention != nil && p.Retention.Days < 0 {
+			return &ValidationError{Field: "retention_days", Message: "cannot be negative", Value: p.Name}
+		}
+		urls := make(map[string]bool, len(p.Webhooks))
+		for _, w := range p.Webhooks {
+			if w.URL == "" {
+				return &ValidationError{Field: "webhooks.url", Message: "is required", Value: p.Name}
+			}
+			if urls[w.URL] {
+				return &ValidationError{Field: "webhooks", Message: "duplicate webhook", Value: w.URL}
+			}
+			urls[w.URL] = true
+		}
+	}
+	return nil
+}
+
+// applyProject applies the configuration of one project, creating it
+// unless exists is set.
+func (c *Client) applyProject(ctx context.Context, result *ApplyResult, want ProjectSnapshot, actual Project, exists bool, opts ApplyOptions) error {
+	projectID := actual.ID
+	if !exists {
+		change := ApplyChange{Op: ApplyCreate, Resource: "project", Name: want.Name}
+		if !opts.DryRun {
+			resp, err := c.CreateProject(ctx, CreateProjectRequest{Name: want.Name, Environment: want.Environment})
+			if err != nil {
+				return err
+			}
+			projectID = resp.Project.ID
+			change.ProjectID, change.Secret = projectID, resp.APIKey
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	if want.Retention != nil {
+		var current *RetentionPolicy
+		if projectID != "" {
+			var err error
+			if current, err = c.GetProjectRetentionPolicy(ctx, projectID); err != nil {
+				return err
+			}
+		}
+		if current == nil || current.Days != want.Retention.Days {
+			if !opts.DryRun {
+				if _, err := c.SetProjectRetentionPolicy(ctx, projectID, *want.Retention); err != nil {
+					return err
+				}
+			}
+			result.Changes = append(result.Changes, ApplyChange{Op: ApplyUpdate, Resource: "retention", ProjectID: projectID})
+		}
+	}
+
+	if want.Webhooks != nil {
+		return c.applyWebhooks(ctx, result, projectID, want.Webhooks, opts)
+	}
+	return nil
+}
+
+// applyWebhooks makes the webhooks of a project match desired. projectID
+// is empty for a project that a dry run would create.
+func (c *Client) applyWebhooks(ctx context.Context, result *ApplyResult, projectID string, desired []Webhook, opts ApplyOptions) error {
+	var actual []Webhook
+	if projectID != "" {
+		list, err := c.ListWebhooks(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		actual = list.Webhooks
+	}
+	byURL := make(map[string]Webhook, len(actual))
+	for _, w := range actual {
+		if _, ok := byURL[w.URL]; !ok {
+			byURL[w.URL] = w
+		}
+	}
+
+	kept := make(map[string]bool, len(desired))
+	for _, want := range desired {
+		have, ok := byURL[want.URL]
+		if !ok {
+			change := ApplyChange{Op: ApplyCreate, Resource: "webhook", ProjectID: projectID, Name: want.URL}
+			if !opts.DryRun {
+				resp, err := c.CreateWebhook(ctx, projectID, CreateWebhookRequest{URL: want.URL, Actions: want.Actions, Disabled: !want.Enabled})
+				if err != nil {
+					return err
+				}
+				change.Secret = resp.Secret
+			}
+			result.Changes = append(result.Changes, change)
+			continue
+		}
+
+		kept[have.ID] = true
+		req, changed := webhookUpdate(have, want)
+		if !changed {
+			continue
+		}
+		if !opts.DryRun {
+			if _, err := c.UpdateWebhook(ctx, have.ID, req); err != nil {
+				return err
+			}
+		}
+		result.Changes = append(result.Changes, ApplyChange{Op: ApplyUpdate, Resource: "webhook", ProjectID: projectID, Name: want.URL})
+	}
+
+	for _, w := range actual {
+		if kept[w.ID] {
+			continue
+		}
+		if !opts.DryRun {
+			if err := c.DeleteWebhook(ctx, w.ID); err != nil {
+				return err
+			}
+		}
+		result.Changes = append(result.Changes, ApplyChange{Op: ApplyDelete, Resource: "webhook", ProjectID: projectID, Name: w.URL})
+	}
+	return nil
+}
+
+// webhookUpdate returns the update that turns have into want, and whether
+// one is needed. Action patterns are compared regardless of order.
+func webhookUpdate(have, want Webhook) (UpdateWebhookRequest, bool) {
+	var req UpdateWebhookRequest
+	haveActions, wantActions := slices.Clone(have.Actions), slices.Clone(want.Actions)
+	slices.Sort(haveActions)
+	slices.Sort(wantActions)
+	if !slices.Equal(haveActions, wantActions) {
+		req.Actions = nonNil(want.Actions)
+	}
+	if have.Enabled != want.Enabled {
+		enabled := want.Enabled
+		req.Enabled = &enabled
+	}
+	return req, req.Actions != nil || req.Enabled != nil
+}
@@ -0,0 +1,220 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newApplyServer serves an account with projects Billing (live) and Old
+// (test), recording the requests that change it.
+func newApplyServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			mu.Lock()
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/projects":
+			w.Write([]byte(`{"projects":[{"id":"proj_1","name":"Billing","environment":"live"},{"id":"proj_2","name":"Old","environment":"test"}]}`))
+		case "POST /v1/projects":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"project":{"id":"proj_3","name":"Search","environment":"live"},"api_key":"actlog_live_new"}`))
+		case "GET /v1/projects/proj_1/retention", "GET /v1/projects/proj_3/retention":
+			w.Write([]byte(`{"retention_days":30}`))
+		case "GET /v1/projects/proj_1/webhooks":
+			w.Write([]byte(`{"webhooks":[{"id":"wh_1","url":"https://a.example","actions":["b.*","a.*"],"enabled":true},{"id":"wh_2","url":"https://b.example","enabled":true}]}`))
+		case "GET /v1/projects/proj_3/webhooks":
+			w.Write([]byte(`{"webhooks":[]}`))
+		case "POST /v1/projects/proj_1/webhooks":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"webhook":{"id":"wh_3"},"secret":"whsec_1"}`))
+		case "PUT /v1/projects/proj_1/retention", "PUT /v1/projects/proj_3/retention":
+			w.Write([]byte(`{"retention_days":90}`))
+		case "PATCH /v1/webhooks/wh_1":
+			w.Write([]byte(`{"id":"wh_1"}`))
+		case "DELETE /v1/webhooks/wh_2", "DELETE /v1/projects/proj_2":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), writes...)
+	}
+}
+
+func desiredAccount() AccountSnapshot {
+	return AccountSnapshot{Projects: []ProjectSnapshot{
+		{
+			Project:   Project{Name: "Billing", Environment: EnvLive},
+			Retention: &RetentionPolicy{Days: 90},
+			Webhooks: []Webhook{
+				{URL: "https://a.example", Actions: []string{"a.*", "b.*"}, Enabled: false},
+				{URL: "https://c.example", Enabled: true},
+			},
+		},
+		{
+			Project:   Project{Name: "Search", Environment: EnvLive},
+			Retention: &RetentionPolicy{Days: 90},
+			Webhooks:  []Webhook{},
+		},
+	}}
+}
+
+func formatChanges(changes []ApplyChange) string {
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, fmt.Sprintf("%s %s %s %s", c.Op, c.Resource, c.ProjectID, c.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestClient_Apply(t *testing.T) {
+	t.Parallel()
+
+	server, writes := newApplyServer(t)
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	plan, err := client.Apply(ctx, desiredAccount(), ApplyOptions{DryRun: true, Prune: true})
+	if err != nil {
+		t.Fatalf("Apply(DryRun) error = %v", err)
+	}
+	wantPlan := strings.Join([]string{
+		"update retention proj_1 ",
+		"update webhook proj_1 https://a.example",
+		"create webhook proj_1 https://c.example",
+		"delete webhook proj_1 https://b.example",
+		"create project  Search",
+		"update retention  ",
+		"delete project proj_2 Old",
+	}, "\n")
+	if got := formatChanges(plan.Changes); got != wantPlan {
+		t.Errorf("Apply(DryRun) changes =\n%s\nwant\n%s", got, wantPlan)
+	}
+	if got := writes(); len(got) != 0 {
+		t.Fatalf("Apply(DryRun) made changes: %v", got)
+	}
+
+	result, err := client.Apply(ctx, desiredAccount(), ApplyOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	wantWrites := strings.Join([]string{
+		"PUT /v1/projects/proj_1/retention",
+		"PATCH /v1/webhooks/wh_1",
+		"POST /v1/projects/proj_1/webhooks",
+		"DELETE /v1/webhooks/wh_2",
+		"POST /v1/projects",
+		"PUT /v1/projects/proj_3/retention",
+		"DELETE /v1/projects/proj_2",
+	}, "\n")
+	if got := strings.Join(writes(), "\n"); got != wantWrites {
+		t.Errorf("Apply() requests =\n%s\nwant\n%s", got, wantWrites)
+	}
+	if result.DryRun || len(result.Changes) != 7 {
+		t.Fatalf("Apply() result = %+v", result)
+	}
+	if c := result.Changes[2]; c.Secret != "whsec_1" {
+		t.Errorf("created webhook Secret = %q", c.Secret)
+	}
+	if c := result.Changes[4]; c.ProjectID != "proj_3" || c.Secret != "actlog_live_new" {
+		t.Errorf("created project change = %+v", c)
+	}
+}
+
+func TestClient_Apply_NoPrune(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newApplyServer(t)
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	desired := desiredAccount()
+	desired.Projects = desired.Projects[:1]
+	desired.Projects[0].Webhooks = nil
+	result, err := client.Apply(context.Background(), desired, ApplyOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := formatChanges(result.Changes); got != "update retention proj_1 " {
+		t.Errorf("Apply() changes =\n%s", got)
+	}
+}
+
+func TestClient_Apply_Validation(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewManagementClient("session_token", WithBaseURL("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	tests := []struct {
+		name    string
+		desired AccountSnapshot
+		field   string
+	}{
+		{"missing name", AccountSnapshot{Projects: []ProjectSnapshot{{Project: Project{Environment: EnvLive}}}}, "projects.name"},
+		{"bad environment", AccountSnapshot{Projects: []ProjectSnapshot{{Project: Project{Name: "a", Environment: "prod"}}}}, "environment"},
+		{"duplicate project", AccountSnapshot{Projects: []ProjectSnapshot{
+			{Project: Project{Name: "a", Environment: EnvLive}},
+			{Project: Project{Name: "a", Environment: EnvLive}},
+		}}, "projects"},
+		{"duplicate webhook", AccountSnapshot{Projects: []ProjectSnapshot{{
+			Project:  Project{Name: "a", Environment: EnvLive},
+			Webhooks: []Webhook{{URL: "https://a.example"}, {URL: "https://a.example"}},
+		}}}, "webhooks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Apply(context.Background(), tt.desired, ApplyOptions{})
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Apply() error = %v, want a %s *ValidationError", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestUpdateWebhookRequest_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	enabled := false
+	tests := []struct {
+		req  UpdateWebhookRequest
+		want string
+	}{
+		{UpdateWebhookRequest{Enabled: &enabled}, `{"enabled":false}`},
+		{UpdateWebhookRequest{Actions: []string{}}, `{"actions":[]}`},
+		{UpdateWebhookRequest{Actions: []string{"user.*"}}, `{"actions":["user.*"]}`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.req)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal() = %s, want %s", got, tt.want)
+		}
+	}
+}
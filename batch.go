@@ -3,16 +3,66 @@ package tryl
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// pendingEvent tracks an event and its result channel.
+// ErrEventDropped is the result of an asynchronously logged event that
+// BatchConfig.BeforeSend removed from its batch.
+var ErrEventDropped = errors.New("tryl: event dropped by BeforeSend")
+
+// ErrPartialFlush is returned by Flush when some events were sent and
+// others failed. The FlushReport has the counts.
+var ErrPartialFlush = errors.New("tryl: some events failed to flush")
+
+// FlushReport describes the events sent by a Flush.
+type FlushReport struct {
+	// Sent is the number of events the API committed.
+	Sent int
+	// Failed is the number of events rejected by the API or not
+	// delivered because their batch request failed.
+	Failed int
+	// Dropped is the number of events removed by BatchConfig.BeforeSend.
+	Dropped int
+	// Batches is the number of batch requests made.
+	Batches int
+	// Duration is how long the flush took.
+	Duration time.Duration
+}
+
+// add adds the counts of r2 to r.
+func (r *FlushReport) add(r2 FlushReport) {
+	r.Sent += r2.Sent
+	r.Failed += r2.Failed
+	r.Dropped += r2.Dropped
+	r.Batches += r2.Batches
+}
+
+// err returns the error for a flush that made r, given the first batch
+// request error and the first rejected event's error.
+func (r *FlushReport) err(requestErr, itemErr error) error {
+	switch {
+	case r.Failed == 0:
+		return nil
+	case r.Sent > 0 && requestErr != nil:
+		return fmt.Errorf("%w (%d of %d events): %w", ErrPartialFlush, r.Failed, r.Sent+r.Failed, requestErr)
+	case r.Sent > 0:
+		return fmt.Errorf("%w (%d of %d events): %w", ErrPartialFlush, r.Failed, r.Sent+r.Failed, itemErr)
+	case requestErr != nil:
+		return requestErr
+	default:
+		return fmt.Errorf("all %d events were rejected: %w", r.Failed, itemErr)
+	}
+}
+
+// pendingEvent tracks an event and its result handle.
 type pendingEvent struct {
-	ctx      context.Context
-	event    Event
-	resultCh chan<- AsyncResult
-	index    int
+	ctx     context.Context
+	event   Event
+	pending *PendingEvent
+	index   int
 }
 
 // Batcher accumulates events and sends them in batches.
@@ -26,6 +76,10 @@ type Batcher struct {
 
 	mu      sync.Mutex
 	stopped bool
+
+	// pressureStep is the tenth of queue capacity last reported to
+	// OnPressure.
+	pressureStep atomic.Int32
 }
 
 // newBatcher creates a new Batcher.
@@ -51,49 +105,103 @@ func newBatcher(client *Client, config *BatchConfig) *Batcher {
 		doneCh:  make(chan struct{}),
 	}
 
-	go b.run()
+	// The ticker is created before run starts, so time advanced by a
+	// Clock after newBatcher returns counts towards the first flush.
+	ticker := client.config.clock.NewTicker(config.FlushInterval)
+	client.goLabeled(context.Background(), "batcher", func(context.Context) { b.run(ticker) })
 
 	return b
 }
 
 // Add queues an event for batching.
-func (b *Batcher) Add(ctx context.Context, event Event, resultCh chan<- AsyncResult) {
+func (b *Batcher) Add(ctx context.Context, event Event, pending *PendingEvent) {
 	b.mu.Lock()
 	if b.stopped {
 		b.mu.Unlock()
-		resultCh <- AsyncResult{Error: errors.New("batcher is stopped")}
-		close(resultCh)
+		pending.complete(AsyncResult{Error: ErrClientClosed})
 		return
 	}
 	b.mu.Unlock()
 
 	select {
-	case b.pending <- pendingEvent{ctx: ctx, event: event, resultCh: resultCh}:
+	case b.pending <- pendingEvent{ctx: ctx, event: event, pending: pending}:
+		b.reportPressure()
 	case <-ctx.Done():
-		resultCh <- AsyncResult{Error: ctx.Err()}
-		close(resultCh)
+		pending.complete(AsyncResult{Error: ctx.Err()})
+	}
+}
+
+// pressure returns the fraction of the queue that is full.
+func (b *Batcher) pressure() float64 {
+	return float64(len(b.pending)) / float64(cap(b.pending))
+}
+
+// reportPressure calls OnPressure when the queue level has moved to
+// another tenth of its capacity since the last call.
+func (b *Batcher) reportPressure() {
+	if b.config.OnPressure == nil {
+		return
+	}
+	level := b.pressure()
+	step := int32(level * 10)
+	if b.pressureStep.Swap(step) != step {
+		b.config.OnPressure(level)
 	}
 }
 
-// Flush sends all pending events immediately.
-func (b *Batcher) Flush(ctx context.Context) error {
+// Flush sends all pending events immediately and reports what was sent.
+// It stops at the first failed batch request, leaving later events
+// queued. If some events were sent and others failed, the error wraps
+// ErrPartialFlush. The report is never nil.
+func (b *Batcher) Flush(ctx context.Context) (*FlushReport, error) {
+	start := b.client.config.clock.Now()
+	report := &FlushReport{}
 	var batch []pendingEvent
+	var requestErr, itemErr error
 
+	// send sends batch and reports whether its request succeeded.
+	send := func() bool {
+		r, firstItemErr, err := b.sendBatch(ctx, batch)
+		report.add(r)
+		if itemErr == nil {
+			itemErr = firstItemErr
+		}
+		requestErr, batch = err, nil
+		return err == nil
+	}
+
+loop:
 	for {
 		select {
 		case pe := <-b.pending:
 			batch = append(batch, pe)
-			if len(batch) >= b.config.MaxBatchSize {
-				if err := b.sendBatch(ctx, batch); err != nil {
-					return err
-				}
-				batch = nil
+			if len(batch) >= b.config.MaxBatchSize && !send() {
+				break loop
 			}
 		default:
 			if len(batch) > 0 {
-				return b.sendBatch(ctx, batch)
+				send()
+			}
+			break loop
+		}
+	}
+	report.Duration = b.client.config.clock.Now().Sub(start)
+	return report, report.err(requestErr, itemErr)
+}
+
+// drain moves queued events into batch, sending it whenever it is full,
+// and returns the unsent remainder.
+func (b *Batcher) drain(batch []pendingEvent) []pendingEvent {
+	for {
+		select {
+		case pe := <-b.pending:
+			batch = append(batch, pe)
+			if len(batch) >= b.config.MaxBatchSize {
+				b.sendBatch(context.Background(), batch)
+				batch = nil
 			}
-			return nil
+		default:
+			return batch
 		}
 	}
 }
@@ -119,10 +227,8 @@ func (b *Batcher) Stop(ctx context.Context) error {
 }
 
 // run is the background loop that processes batches.
-func (b *Batcher) run() {
+func (b *Batcher) run(ticker Ticker) {
 	defer close(b.doneCh)
-
-	ticker := time.NewTicker(b.config.FlushInterval)
 	defer ticker.Stop()
 
 	var batch []pendingEvent
@@ -137,7 +243,10 @@ func (b *Batcher) run() {
 				batch = nil
 			}
 
-		case <-ticker.C:
+		case <-ticker.C():
+			// Include events queued before the tick, which select may
+			// not have received yet.
+			batch = b.drain(batch)
 			if len(batch) > 0 {
 				b.sendBatch(context.Background(), batch)
 				batch = nil
@@ -150,7 +259,7 @@ func (b *Batcher) run() {
 					batch = append(batch, pe)
 				default:
 					if len(batch) > 0 {
-						b.sendBatch(context.Background(), batch)
+						b.sendBatch(finalFlushContext(), batch)
 					}
 					return
 				}
@@ -159,59 +268,100 @@ func (b *Batcher) run() {
 	}
 }
 
-// sendBatch sends a batch of events to the API.
-func (b *Batcher) sendBatch(ctx context.Context, batch []pendingEvent) error {
+// sendBatch sends a batch of events to the API. The report counts the
+// batch's events, itemErr is the error of the first event the API
+// rejected, and err is the batch request's error.
+func (b *Batcher) sendBatch(ctx context.Context, batch []pendingEvent) (report FlushReport, itemErr, err error) {
 	if len(batch) == 0 {
-		return nil
+		return report, nil, nil
 	}
 
 	events := make([]Event, len(batch))
+	targets := make([]*PendingEvent, len(batch))
 	for i, pe := range batch {
 		events[i] = pe.event
+		targets[i] = pe.pending
 		batch[i].index = i
 	}
+	if b.config.BeforeSend != nil {
+		queued := len(events)
+		events, targets = b.beforeSend(events, targets)
+		report.Dropped = max(queued-len(events), 0)
+		if len(events) == 0 {
+			return report, nil, nil
+		}
+	}
 
+	report.Batches = 1
 	resp, err := b.client.LogBatch(ctx, events)
 
-	if err != nil {
-		for _, pe := range batch {
-			pe.resultCh <- AsyncResult{Error: err}
-			close(pe.resultCh)
+	// A result returned with an error still reports the committed items.
+	if err != nil && resp == nil {
+		for _, pending := range targets {
+			pending.complete(AsyncResult{Error: err})
 		}
 		if b.config.OnError != nil {
 			b.config.OnError(events, err)
 		}
-		return err
+		report.Failed = len(events)
+		b.client.stats.dropped.Add(uint64(report.Dropped + report.Failed))
+		return report, nil, err
 	}
 
-	// Map results by index since API returns results in order
-	resultMap := make(map[int]*EventResponse)
-	for i, r := range resp.Results {
-		// Use the batch item's original index
-		if i < len(batch) {
-			resultMap[batch[i].index] = &EventResponse{ID: r.ID, Timestamp: r.Timestamp}
+	// Results are in request order, so items map to events by index.
+	for i, pending := range targets {
+		var result AsyncResult
+		switch {
+		case i >= len(resp.Items):
+			result.Error = errors.New("missing response for event")
+		case resp.Items[i].Error != nil:
+			result.Error = resp.Items[i].Error
+		default:
+			result.Response = resp.Items[i].Response
+		}
+		pending.complete(result)
+		if result.Error == nil {
+			report.Sent++
+			continue
+		}
+		report.Failed++
+		if itemErr == nil {
+			itemErr = result.Error
 		}
 	}
 
-	errorMap := make(map[int]error)
-	for _, e := range resp.Errors {
-		errorMap[e.Index] = &APIError{
-			HTTPStatus: 400,
-			Code:       e.Code,
-			Message:    e.Message,
-		}
+	b.client.stats.dropped.Add(uint64(report.Dropped + report.Failed))
+	return report, itemErr, nil
+}
+
+// beforeSend passes events to BatchConfig.BeforeSend and returns the
+// events to send with the handle to complete for each. Returned events
+// are matched to queued ones by IdempotencyKey, which every event is
+// assigned first; queued events not returned complete with
+// ErrEventDropped, and events added by BeforeSend complete nothing.
+func (b *Batcher) beforeSend(events []Event, pending []*PendingEvent) ([]Event, []*PendingEvent) {
+	events = withIdempotencyKeys(events)
+	byKey := make(map[string]int, len(events))
+	for i := range events {
+		byKey[events[i].IdempotencyKey] = i
 	}
 
-	for i, pe := range batch {
-		if err, ok := errorMap[i]; ok {
-			pe.resultCh <- AsyncResult{Error: err}
-		} else if i < len(resp.Results) {
-			pe.resultCh <- AsyncResult{Response: &resp.Results[i]}
+	out := b.config.BeforeSend(append([]Event(nil), events...))
+
+	targets := make([]*PendingEvent, len(out))
+	sent := make([]bool, len(events))
+	for i := range out {
+		if j, ok := byKey[out[i].IdempotencyKey]; ok && !sent[j] {
+			targets[i] = pending[j]
+			sent[j] = true
 		} else {
-			pe.resultCh <- AsyncResult{Error: errors.New("missing response for event")}
+			targets[i] = newPendingEvent()
 		}
-		close(pe.resultCh)
 	}
-
-	return nil
+	for j := range events {
+		if !sent[j] {
+			pending[j].complete(AsyncResult{Error: ErrEventDropped})
+		}
+	}
+	return out, targets
 }
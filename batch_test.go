@@ -3,8 +3,11 @@ package tryl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -48,27 +51,25 @@ func TestBatcher_ResultMapping(t *testing.T) {
 	}
 
 	// Verify each event gets the correct result by INDEX, not by matching fields
-	if len(resp.Results) < 3 {
-		t.Fatalf("expected 3 results, got %d", len(resp.Results))
+	if len(resp.Items) < 3 {
+		t.Fatalf("expected 3 items, got %d", len(resp.Items))
 	}
-	if resp.Results[0].ID != "evt_result_0" {
-		t.Errorf("index 0: got %v, want evt_result_0", resp.Results[0].ID)
+	if resp.Items[0].Response.ID != "evt_result_0" {
+		t.Errorf("index 0: got %v, want evt_result_0", resp.Items[0].Response.ID)
 	}
-	if resp.Results[1].ID != "evt_result_1" {
-		t.Errorf("index 1: got %v, want evt_result_1", resp.Results[1].ID)
+	if resp.Items[1].Response.ID != "evt_result_1" {
+		t.Errorf("index 1: got %v, want evt_result_1", resp.Items[1].Response.ID)
 	}
 
 	// THIS IS THE CRITICAL TEST: Index 2 should get evt_result_2
 	// With the bug, it gets evt_result_0 because it matches user_1+user.created
-	if resp.Results[2].ID != "evt_result_2" {
-		t.Errorf("index 2: got %v, want evt_result_2 (BUG: result mapping by UserID+Action instead of index)", resp.Results[2].ID)
+	if resp.Items[2].Response.ID != "evt_result_2" {
+		t.Errorf("index 2: got %v, want evt_result_2 (BUG: result mapping by UserID+Action instead of index)", resp.Items[2].Response.ID)
 	}
 
 	// Verify no errors in batch response
-	if len(resp.Errors) > 0 {
-		for _, e := range resp.Errors {
-			t.Errorf("unexpected error at index %d: %s", e.Index, e.Message)
-		}
+	for _, item := range resp.Failed() {
+		t.Errorf("unexpected error at index %d: %v", item.Index, item.Error)
 	}
 }
 
@@ -114,20 +115,18 @@ func TestBatcher_Add(t *testing.T) {
 			defer client.Close()
 
 			// Log async (uses batcher)
-			resultCh := client.LogAsync(context.Background(), tt.event)
+			pending := client.LogAsync(context.Background(), tt.event)
 
 			if !tt.wantErr {
 				// Wait for result
-				select {
-				case result := <-resultCh:
-					if result.Error != nil {
-						t.Errorf("unexpected error in result: %v", result.Error)
-					}
-					if result.Response == nil {
-						t.Error("expected response, got nil")
-					}
-				case <-time.After(500 * time.Millisecond):
-					t.Error("timeout waiting for batch result")
+				ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+				defer cancel()
+				resp, err := pending.Wait(ctx)
+				if err != nil {
+					t.Errorf("unexpected error in result: %v", err)
+				}
+				if err == nil && resp == nil {
+					t.Error("expected response, got nil")
 				}
 			}
 		})
@@ -164,9 +163,13 @@ func TestBatcher_Flush(t *testing.T) {
 	})
 
 	// Manual flush
-	if err := client.Flush(context.Background()); err != nil {
+	report, err := client.Flush(context.Background())
+	if err != nil {
 		t.Errorf("Flush() error = %v", err)
 	}
+	if report.Sent != 1 || report.Batches != 1 || report.Failed != 0 {
+		t.Errorf("Flush() report = %+v, want 1 event sent in 1 batch", report)
+	}
 
 	// Verify server was called
 	if callCount != 1 {
@@ -174,6 +177,39 @@ func TestBatcher_Flush(t *testing.T) {
 	}
 }
 
+func TestBatcher_FlushReport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(batchResponse{
+			Results: []EventResponse{{ID: "evt_0", Timestamp: time.Now()}, {}, {ID: "evt_2", Timestamp: time.Now()}},
+			Errors:  []batchResultError{{Index: 1, Code: ErrCodeValidationError, Message: "metadata too large"}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithBatching(BatchConfig{MaxBatchSize: 10, FlushInterval: time.Hour}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		client.LogAsync(context.Background(), Event{UserID: "user_123", Action: "user.created"})
+	}
+
+	report, err := client.Flush(context.Background())
+	if !errors.Is(err, ErrPartialFlush) {
+		t.Errorf("Flush() error = %v, want ErrPartialFlush", err)
+	}
+	if report.Sent != 2 || report.Failed != 1 || report.Batches != 1 {
+		t.Errorf("Flush() report = %+v, want 2 sent and 1 failed in 1 batch", report)
+	}
+}
+
 func TestBatcher_Stop(t *testing.T) {
 	t.Parallel()
 
@@ -195,7 +231,7 @@ func TestBatcher_Stop(t *testing.T) {
 	}
 
 	// Add event
-	resultCh := client.LogAsync(context.Background(), Event{
+	pending := client.LogAsync(context.Background(), Event{
 		UserID: "user_123",
 		Action: "user.created",
 	})
@@ -207,11 +243,311 @@ func TestBatcher_Stop(t *testing.T) {
 
 	// Pending event should still get result
 	select {
-	case result := <-resultCh:
-		if result.Error != nil {
+	case <-pending.Done():
+		if result, _ := pending.Result(); result.Error != nil {
 			t.Errorf("unexpected error after close: %v", result.Error)
 		}
 	case <-time.After(1 * time.Second):
 		t.Error("timeout waiting for pending event result after close")
 	}
 }
+
+func TestClient_LogBatch_RetriesOnlyFailedItems(t *testing.T) {
+	t.Parallel()
+
+	var requests [][]Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req.Events)
+
+		w.WriteHeader(http.StatusMultiStatus)
+		if len(requests) == 1 {
+			// Commit items 0 and 2; item 1 hits a transient failure.
+			w.Write([]byte(`{"results":[{"id":"evt_0"},{},{"id":"evt_2"}],"errors":[{"index":1,"code":"internal_error","message":"try again"}]}`))
+			return
+		}
+		w.Write([]byte(`{"results":[{"id":"evt_1"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.LogBatch(context.Background(), []Event{
+		{UserID: "user_0", Action: "user.created"},
+		{UserID: "user_1", Action: "user.created"},
+		{UserID: "user_2", Action: "user.created", IdempotencyKey: "caller_key"},
+	})
+	if err != nil {
+		t.Fatalf("LogBatch() error = %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if len(requests[1]) != 1 || requests[1][0].UserID != "user_1" {
+		t.Errorf("retry resubmitted %+v, want only user_1", requests[1])
+	}
+	if requests[0][1].IdempotencyKey == "" || requests[1][0].IdempotencyKey != requests[0][1].IdempotencyKey {
+		t.Error("retried item did not keep its idempotency key")
+	}
+	if requests[0][2].IdempotencyKey != "caller_key" {
+		t.Errorf("caller idempotency key overwritten: %q", requests[0][2].IdempotencyKey)
+	}
+
+	for i, want := range []string{"evt_0", "evt_1", "evt_2"} {
+		if resp.Items[i].Response == nil || resp.Items[i].Response.ID != want {
+			t.Errorf("Items[%d].Response = %+v, want ID %q", i, resp.Items[i].Response, want)
+		}
+	}
+	if failed := resp.Failed(); len(failed) != 0 {
+		t.Errorf("unexpected failures after successful retry: %+v", failed)
+	}
+}
+
+func TestClient_LogBatch_RetryFailureKeepsCommittedItems(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`{"results":[{"id":"evt_0"},{},{"id":"evt_2"}],"errors":[{"index":1,"code":"internal_error","message":"try again"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"invalid_request","message":"bad retry"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.LogBatch(context.Background(), []Event{
+		{UserID: "user_0", Action: "user.created"},
+		{UserID: "user_1", Action: "user.created"},
+		{UserID: "user_2", Action: "user.created"},
+	})
+	if err == nil {
+		t.Fatal("LogBatch() error = nil, want the failed retry's error")
+	}
+	if resp == nil {
+		t.Fatal("LogBatch() result = nil, want the committed items")
+	}
+	if got := len(resp.Succeeded()); got != 2 {
+		t.Errorf("got %d succeeded items, want 2", got)
+	}
+	if resp.Items[1].Status != BatchItemFailed {
+		t.Errorf("Items[1] = %+v, want failed", resp.Items[1])
+	}
+}
+
+func TestClient_LogBatch_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(batchResponse{
+			Results: []EventResponse{
+				{ID: "evt_0", Timestamp: time.Now()},
+				{},
+				{ID: "evt_2", Timestamp: time.Now()},
+			},
+			Errors: []batchResultError{
+				{Index: 1, Code: ErrCodeValidationError, Message: "metadata too large"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.LogBatch(context.Background(), []Event{
+		{UserID: "user_0", Action: "user.created"},
+		{UserID: "user_1", Action: "user.created"},
+		{UserID: "user_2", Action: "user.created"},
+	})
+	if err != nil {
+		t.Fatalf("LogBatch() error = %v", err)
+	}
+
+	if got := len(resp.Succeeded()); got != 2 {
+		t.Errorf("got %d succeeded items, want 2", got)
+	}
+	failed := resp.Failed()
+	if len(failed) != 1 {
+		t.Fatalf("got %d failed items, want 1", len(failed))
+	}
+	item := failed[0]
+	if item.Index != 1 || item.Status != BatchItemFailed || item.Response != nil {
+		t.Errorf("failed item = %+v, want index 1 with no response", item)
+	}
+	var apiErr *APIError
+	if !errors.As(item.Error, &apiErr) || apiErr.Code != ErrCodeValidationError {
+		t.Errorf("failed item error = %v, want %s", item.Error, ErrCodeValidationError)
+	}
+	if resp.Items[2].Status != BatchItemCommitted || resp.Items[2].Response.ID != "evt_2" {
+		t.Errorf("Items[2] = %+v, want committed evt_2", resp.Items[2])
+	}
+	if got := client.Stats().EventsSent; got != 2 {
+		t.Errorf("Stats().EventsSent = %d, want 2 (rejected events are not sent)", got)
+	}
+}
+
+func TestPendingEvent(t *testing.T) {
+	t.Parallel()
+
+	pending := newPendingEvent()
+	if _, ok := pending.Result(); ok {
+		t.Error("Result() ok = true before completion")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pending.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait() with cancelled ctx error = %v, want context.Canceled", err)
+	}
+
+	pending.complete(AsyncResult{Response: &EventResponse{ID: "evt_1"}})
+	pending.complete(AsyncResult{Error: errors.New("ignored")})
+
+	select {
+	case <-pending.Done():
+	default:
+		t.Fatal("Done() not closed after completion")
+	}
+	resp, err := pending.Wait(context.Background())
+	if err != nil || resp.ID != "evt_1" {
+		t.Errorf("Wait() = %v, %v; want evt_1, nil", resp, err)
+	}
+	if result, ok := pending.Result(); !ok || result.Response.ID != "evt_1" {
+		t.Errorf("Result() = %+v, %v; want evt_1, true", result, ok)
+	}
+}
+
+func TestBatcher_BeforeSend(t *testing.T) {
+	t.Parallel()
+
+	var sent []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		sent = append(sent, req.Events...)
+		resp := batchResponse{}
+		for _, e := range req.Events {
+			resp.Results = append(resp.Results, EventResponse{ID: "evt_" + e.UserID})
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithBatching(BatchConfig{
+			MaxBatchSize:  10,
+			FlushInterval: time.Hour,
+			BeforeSend: func(events []Event) []Event {
+				var out []Event
+				for _, e := range events {
+					if e.UserID == "internal" {
+						continue
+					}
+					e.ActorID = "batcher"
+					out = append(out, e)
+				}
+				return out
+			},
+		}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	kept := client.LogAsync(ctx, Event{UserID: "user_1", Action: "user.created"})
+	dropped := client.LogAsync(ctx, Event{UserID: "internal", Action: "user.created"})
+	last := client.LogAsync(ctx, Event{UserID: "user_2", Action: "user.created"})
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(sent) != 2 || sent[0].ActorID != "batcher" || sent[1].ActorID != "batcher" {
+		t.Fatalf("sent events = %+v, want the two non-internal events with ActorID set", sent)
+	}
+	if resp, err := kept.Wait(ctx); err != nil || resp.ID != "evt_user_1" {
+		t.Errorf("kept event result = %v, %v, want evt_user_1", resp, err)
+	}
+	if resp, err := last.Wait(ctx); err != nil || resp.ID != "evt_user_2" {
+		t.Errorf("last event result = %v, %v, want evt_user_2", resp, err)
+	}
+	if _, err := dropped.Wait(ctx); !errors.Is(err, ErrEventDropped) {
+		t.Errorf("dropped event error = %v, want ErrEventDropped", err)
+	}
+}
+
+func TestClient_QueuePressure(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`{"results":[{"id":"evt_1"}]}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var levels []float64
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithBatching(BatchConfig{
+			MaxBatchSize:     1,
+			MaxPendingEvents: 10,
+			OnPressure: func(level float64) {
+				mu.Lock()
+				levels = append(levels, level)
+				mu.Unlock()
+			},
+		}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	event := Event{UserID: "user_1", Action: "user.created"}
+
+	// The first event occupies the sender; the rest queue up behind it.
+	client.LogAsync(ctx, event)
+	<-received
+	for i := 0; i < 5; i++ {
+		client.LogAsync(ctx, event)
+	}
+
+	if got := client.QueuePressure(); got != 0.5 {
+		t.Errorf("QueuePressure() = %v, want 0.5", got)
+	}
+	mu.Lock()
+	if len(levels) == 0 || levels[len(levels)-1] != 0.5 {
+		t.Errorf("OnPressure levels = %v, want the last to be 0.5", levels)
+	}
+	mu.Unlock()
+
+	close(release)
+	client.Close()
+}
@@ -0,0 +1,120 @@
+package tryl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrActionBudgetExceeded is returned for events suppressed because their
+// action exceeded its budget; see WithActionBudget.
+var ErrActionBudgetExceeded = errors.New("tryl: action budget exceeded")
+
+// Rate is an event rate of Events per Per. Bursts of up to Events are
+// allowed.
+type Rate struct {
+	Events int
+	Per    time.Duration
+}
+
+// actionBudget is a token bucket for the actions matching a pattern.
+type actionBudget struct {
+	pattern    string
+	rate       Rate
+	suppressed atomic.Uint64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token if one is available at now.
+func (b *actionBudget) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	burst := float64(b.rate.Events)
+	if !b.last.IsZero() {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.rate.Per) * burst
+	}
+	b.tokens = min(b.tokens, burst)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// actionBudgets holds the budgets set with WithActionBudget, most specific
+// pattern first.
+type actionBudgets struct {
+	budgets []*actionBudget
+	now     func() time.Time
+}
+
+// newActionBudgets creates full budgets for the given patterns. More
+// specific patterns, with fewer wildcards and then more characters, come
+// first, so an event is charged to the most specific matching budget.
+func newActionBudgets(rates map[string]Rate) *actionBudgets {
+	ab := &actionBudgets{now: time.Now}
+	for pattern, rate := range rates {
+		ab.budgets = append(ab.budgets, &actionBudget{pattern: pattern, rate: rate, tokens: float64(rate.Events)})
+	}
+	sort.Slice(ab.budgets, func(i, j int) bool {
+		pi, pj := ab.budgets[i].pattern, ab.budgets[j].pattern
+		if wi, wj := wildcards(pi), wildcards(pj); wi != wj {
+			return wi < wj
+		}
+		if len(pi) != len(pj) {
+			return len(pi) > len(pj)
+		}
+		return pi < pj
+	})
+	return ab
+}
+
+// wildcards counts the "*" wildcards in pattern.
+func wildcards(pattern string) int {
+	n := 0
+	for _, r := range pattern {
+		if r == '*' {
+			n++
+		}
+	}
+	return n
+}
+
+// allow charges action to its budget, returning ErrActionBudgetExceeded if
+// the budget is spent. Actions without a budget are always allowed, as is
+// everything when no budgets are set.
+func (ab *actionBudgets) allow(action string) error {
+	if ab == nil {
+		return nil
+	}
+	for _, b := range ab.budgets {
+		if !MatchesAction(b.pattern, action) {
+			continue
+		}
+		if b.allow(ab.now()) {
+			return nil
+		}
+		b.suppressed.Add(1)
+		return fmt.Errorf("%w: %s (budget %s)", ErrActionBudgetExceeded, action, b.pattern)
+	}
+	return nil
+}
+
+// suppressed returns the number of suppressed events per budget pattern.
+func (ab *actionBudgets) suppressed() map[string]uint64 {
+	out := make(map[string]uint64)
+	if ab == nil {
+		return out
+	}
+	for _, b := range ab.budgets {
+		out[b.pattern] = b.suppressed.Load()
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WithActionBudget(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"id":"evt_1"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithActionBudget(map[string]Rate{
+			"heartbeat.*":      {Events: 2, Per: time.Minute},
+			"heartbeat.worker": {Events: 1, Per: time.Minute},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	now := time.Now()
+	client.budgets.now = func() time.Time { return now }
+	ctx := context.Background()
+	log := func(action string) error {
+		_, err := client.Log(ctx, Event{UserID: "user_1", Action: action})
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := log("heartbeat.api"); err != nil {
+			t.Fatalf("Log() within budget error = %v", err)
+		}
+	}
+	if err := log("heartbeat.api"); !errors.Is(err, ErrActionBudgetExceeded) {
+		t.Errorf("Log() over budget error = %v, want ErrActionBudgetExceeded", err)
+	}
+	// The more specific budget applies to heartbeat.worker.
+	if err := log("heartbeat.worker"); err != nil {
+		t.Errorf("Log() heartbeat.worker error = %v", err)
+	}
+	if err := log("heartbeat.worker"); !errors.Is(err, ErrActionBudgetExceeded) {
+		t.Errorf("Log() heartbeat.worker over budget error = %v, want ErrActionBudgetExceeded", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := log("user.created"); err != nil {
+			t.Errorf("Log() unbudgeted action error = %v", err)
+		}
+	}
+
+	now = now.Add(30 * time.Second)
+	if err := log("heartbeat.api"); err != nil {
+		t.Errorf("Log() after refill error = %v", err)
+	}
+
+	if got := requests.Load(); got != 9 {
+		t.Errorf("requests = %d, want 9", got)
+	}
+	stats := client.Stats()
+	if stats.SuppressedEvents["heartbeat.*"] != 1 || stats.SuppressedEvents["heartbeat.worker"] != 1 {
+		t.Errorf("SuppressedEvents = %v, want 1 for each budget", stats.SuppressedEvents)
+	}
+}
+
+func TestWithActionBudget_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, budgets := range []map[string]Rate{
+		nil,
+		{"Heartbeat": {Events: 1, Per: time.Second}},
+		{"heartbeat.*": {Events: 0, Per: time.Second}},
+	} {
+		if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithActionBudget(budgets)); err == nil {
+			t.Errorf("WithActionBudget(%v) expected error", budgets)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package tryl
+
+import (
+	"bytes"
+	"sync"
+)
+
+// listBuffers pools the response bodies and event slices of List calls
+// for WithReusableBuffers.
+type listBuffers struct {
+	bodies sync.Pool
+	events sync.Pool
+}
+
+func newListBuffers() *listBuffers {
+	b := &listBuffers{}
+	b.bodies.New = func() any { return new(bytes.Buffer) }
+	return b
+}
+
+// prepare gives l a cleared event slice from the pool, for json.Unmarshal
+// to decode into, and makes Release return it.
+func (b *listBuffers) prepare(l *EventList) {
+	l.events = &b.events
+	s, ok := b.events.Get().(*[]StoredEvent)
+	if !ok {
+		return
+	}
+	// Unmarshal decodes into existing elements without zeroing them, so
+	// fields omitted from the response would keep stale values.
+	events := (*s)[:cap(*s)]
+	clear(events)
+	l.Events = events[:0]
+}
+
+// Release returns the list's events to the pool of a client created with
+// WithReusableBuffers, for reuse by later List calls. Events must not be
+// used afterwards; copy any events to keep first. Release is a no-op for
+// other lists.
+func (l *EventList) Release() {
+	if l.events == nil || l.Events == nil {
+		return
+	}
+	events := l.Events[:0]
+	l.events.Put(&events)
+	l.Events, l.events = nil, nil
+}
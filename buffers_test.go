@@ -0,0 +1,85 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_WithReusableBuffers(t *testing.T) {
+	t.Parallel()
+
+	pages := []string{
+		`{"events":[{"id":"evt_1","user_id":"user_1","action":"doc.viewed","tags":["beta"],"metadata":{"page":1}},{"id":"evt_2","user_id":"user_1","action":"doc.viewed"}],"has_more":false}`,
+		`{"events":[{"id":"evt_3","user_id":"user_2","action":"doc.edited"}],"has_more":false}`,
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(pages[int(requests.Add(1)-1)%len(pages)]))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithReusableBuffers())
+	ctx := context.Background()
+
+	first, err := client.List(ctx, EventFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(first.Events) != 2 || string(first.Events[0].Metadata) != `{"page":1}` {
+		t.Fatalf("first page = %+v", first.Events)
+	}
+	kept := first.Events[0]
+	first.Release()
+	if first.Events != nil {
+		t.Error("Events not cleared by Release")
+	}
+
+	second, err := client.List(ctx, EventFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(second.Events) != 1 {
+		t.Fatalf("second page = %+v", second.Events)
+	}
+	if e := second.Events[0]; e.ID != "evt_3" || e.Tags != nil || e.Metadata != nil {
+		t.Errorf("reused event = %+v, want no fields from the first page", e)
+	}
+	if kept.ID != "evt_1" || kept.Tags[0] != "beta" || string(kept.Metadata) != `{"page":1}` {
+		t.Errorf("event copied before Release = %+v", kept)
+	}
+	second.Release()
+	second.Release()
+}
+
+func TestClient_WithMaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"events":[{"id":"evt_1","user_id":"user_1","action":"doc.viewed","metadata":{"blob":"` + strings.Repeat("x", 4096) + `"}}],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithMaxResponseBytes(1024))
+	_, err := client.List(context.Background(), EventFilter{})
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("List() error = %v, want ErrResponseTooLarge", err)
+	}
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		t.Errorf("List() error = %T, want a NetworkError", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1 (not retried)", got)
+	}
+
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithMaxResponseBytes(0)); err == nil {
+		t.Error("expected error for zero limit")
+	}
+}
@@ -0,0 +1,203 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxBatchSize is the largest batch the API accepts.
+const maxBatchSize = 100
+
+// BulkOption configures LogMany.
+type BulkOption func(*bulkConfig) error
+
+// bulkConfig holds LogMany settings.
+type bulkConfig struct {
+	chunkSize   int
+	concurrency int
+	onProgress  func(BulkProgress)
+}
+
+// WithChunkSize sets the number of events sent per batch request.
+// Default: 100 (the API maximum)
+func WithChunkSize(n int) BulkOption {
+	return func(c *bulkConfig) error {
+		if n <= 0 || n > maxBatchSize {
+			return errors.New("chunk size must be between 1 and 100")
+		}
+		c.chunkSize = n
+		return nil
+	}
+}
+
+// WithConcurrency sets the number of batch requests in flight at once.
+// Default: 4
+func WithConcurrency(n int) BulkOption {
+	return func(c *bulkConfig) error {
+		if n <= 0 {
+			return errors.New("concurrency must be positive")
+		}
+		c.concurrency = n
+		return nil
+	}
+}
+
+// WithProgress sets a function called after each chunk completes.
+// Calls are serialized.
+func WithProgress(fn func(BulkProgress)) BulkOption {
+	return func(c *bulkConfig) error {
+		if fn == nil {
+			return errors.New("progress function cannot be nil")
+		}
+		c.onProgress = fn
+		return nil
+	}
+}
+
+// BulkProgress reports how far a LogMany call has progressed.
+type BulkProgress struct {
+	// Total is the number of events passed to LogMany.
+	Total int
+	// Processed is the number of events with a final outcome so far.
+	Processed int
+	// Succeeded is the number of events committed so far.
+	Succeeded int
+	// Failed is the number of events rejected or not sent so far.
+	Failed int
+}
+
+// BulkReport is the result of a LogMany call, with one item per event in
+// the original order.
+type BulkReport struct {
+	BatchResult
+	// Chunks is the number of batch requests made.
+	Chunks int
+	// Duration is the wall-clock time LogMany took.
+	Duration time.Duration
+}
+
+// LogMany sends any number of events by splitting them into batches and
+// sending several batches concurrently. Each batch is retried like
+// LogBatch, so only failed items are resubmitted.
+//
+// Events that fail client-side validation are reported as failed items
+// with a *ValidationError rather than aborting the run. If a whole batch
+// request fails, each of its events is reported with that error.
+// The returned error is non-nil only if ctx is done before all events are
+// processed; the report is returned either way.
+func (c *Client) LogMany(ctx context.Context, events []Event, opts ...BulkOption) (*BulkReport, error) {
+	config := bulkConfig{chunkSize: maxBatchSize, concurrency: 4}
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return nil, fmt.Errorf("invalid option: %w", err)
+		}
+	}
+
+	start := time.Now()
+	report := &BulkReport{BatchResult: BatchResult{Items: make([]BatchItem, len(events))}}
+	progress := BulkProgress{Total: len(events)}
+	var mu sync.Mutex
+
+	// record stores outcomes and reports progress.
+	record := func(items []BatchItem) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, item := range items {
+			report.Items[item.Index] = item
+			progress.Processed++
+			if item.Status == BatchItemCommitted {
+				progress.Succeeded++
+			} else {
+				progress.Failed++
+			}
+		}
+		if config.onProgress != nil {
+			config.onProgress(progress)
+		}
+	}
+
+	// Validate up front so one bad event cannot fail a whole batch.
+	var valid []int
+	var invalid []BatchItem
+	for i := range events {
+		event := events[i]
+		if err := c.validateEvent(&event); err != nil {
+			invalid = append(invalid, BatchItem{Index: i, Status: BatchItemFailed, Error: err})
+			continue
+		}
+		valid = append(valid, i)
+	}
+	if len(invalid) > 0 {
+		record(invalid)
+	}
+
+	var chunks [][]int
+	for len(valid) > 0 {
+		n := min(config.chunkSize, len(valid))
+		chunks = append(chunks, valid[:n])
+		valid = valid[n:]
+	}
+	report.Chunks = len(chunks)
+
+	sem := make(chan struct{}, config.concurrency)
+	var wg sync.WaitGroup
+	var ctxErr error
+	for _, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+		}
+		if ctxErr != nil {
+			record(failedItems(chunk, ctxErr))
+			continue
+		}
+
+		wg.Add(1)
+		go func(chunk []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			record(c.sendChunk(ctx, events, chunk))
+		}(chunk)
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	if ctxErr != nil {
+		return report, fmt.Errorf("context cancelled: %w", ctxErr)
+	}
+	return report, nil
+}
+
+// sendChunk sends the events at indices as one batch and returns their
+// outcomes indexed into events.
+func (c *Client) sendChunk(ctx context.Context, events []Event, indices []int) []BatchItem {
+	batch := make([]Event, len(indices))
+	for i, idx := range indices {
+		batch[i] = events[idx]
+	}
+
+	result, err := c.LogBatch(ctx, batch)
+	if err != nil && result == nil {
+		return failedItems(indices, err)
+	}
+
+	items := make([]BatchItem, len(indices))
+	for i, idx := range indices {
+		items[i] = result.Items[i]
+		items[i].Index = idx
+	}
+	return items
+}
+
+// failedItems reports every event at indices as failed with err.
+func failedItems(indices []int, err error) []BatchItem {
+	items := make([]BatchItem, len(indices))
+	for i, idx := range indices {
+		items[i] = BatchItem{Index: idx, Status: BatchItemFailed, Error: err}
+	}
+	return items
+}
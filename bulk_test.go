@@ -0,0 +1,122 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_LogMany(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req batchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		resp := batchResponse{Results: make([]EventResponse, len(req.Events))}
+		for i, e := range req.Events {
+			resp.Results[i] = EventResponse{ID: "evt_" + e.UserID, Timestamp: time.Now()}
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	events := make([]Event, 250)
+	for i := range events {
+		events[i] = Event{UserID: fmt.Sprintf("u%d", i), Action: "user.imported"}
+	}
+	events[42].Action = "Not Valid"
+
+	var mu sync.Mutex
+	var updates []BulkProgress
+	report, err := client.LogMany(context.Background(), events,
+		WithChunkSize(50),
+		WithConcurrency(3),
+		WithProgress(func(p BulkProgress) {
+			mu.Lock()
+			updates = append(updates, p)
+			mu.Unlock()
+		}))
+	if err != nil {
+		t.Fatalf("LogMany() error = %v", err)
+	}
+
+	if got := requests.Load(); got != 5 {
+		t.Errorf("got %d batch requests, want 5", got)
+	}
+	if report.Chunks != 5 {
+		t.Errorf("Chunks = %d, want 5", report.Chunks)
+	}
+	if got := len(report.Succeeded()); got != 249 {
+		t.Errorf("got %d succeeded, want 249", got)
+	}
+
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Index != 42 || !IsClientValidationError(failed[0].Error) {
+		t.Errorf("Failed() = %+v, want index 42 with a validation error", failed)
+	}
+	for i, item := range report.Items {
+		if i != 42 && (item.Response == nil || item.Response.ID != fmt.Sprintf("evt_u%d", i)) {
+			t.Fatalf("Items[%d] = %+v, want evt_u%d", i, item, i)
+		}
+	}
+
+	last := updates[len(updates)-1]
+	if last.Total != 250 || last.Processed != 250 || last.Succeeded != 249 || last.Failed != 1 {
+		t.Errorf("final progress = %+v", last)
+	}
+}
+
+func TestClient_LogMany_ChunkFailure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":"unauthorized","message":"bad key"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	report, err := client.LogMany(context.Background(), []Event{
+		{UserID: "u1", Action: "user.imported"},
+		{UserID: "u2", Action: "user.imported"},
+	})
+	if err != nil {
+		t.Fatalf("LogMany() error = %v", err)
+	}
+	for _, item := range report.Items {
+		if item.Status != BatchItemFailed || !IsUnauthorized(item.Error) {
+			t.Errorf("item %d = %+v, want failed with unauthorized", item.Index, item)
+		}
+	}
+}
+
+func TestClient_LogMany_InvalidOption(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithDryRun())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.LogMany(context.Background(), nil, WithChunkSize(101)); err == nil {
+		t.Error("LogMany() with chunk size 101 succeeded, want error")
+	}
+}
@@ -0,0 +1,46 @@
+package tryl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in, want string
+	}{
+		{`{ "b": 1, "a": [true, null, "x"] }`, `{"a":[true,null,"x"],"b":1}`},
+		{`{"n": [1.0, 1e2, -0.0, 0.000001, 1.5e-7, 1e21, 123456789012345680000]}`, `{"n":[1,100,0,0.000001,1.5e-7,1e+21,123456789012345680000]}`},
+		{`{"s": "<a href=\"x\">é\u0001\n</a>"}`, `{"s":"<a href=\"x\">é\u0001\n</a>"}`},
+		{`{"\ufb01": 1, "😀": 2, "a": 3}`, `{"a":3,"😀":2,"ﬁ":1}`},
+	}
+	for _, tt := range tests {
+		got, err := CanonicalizeJSON([]byte(tt.in))
+		if err != nil {
+			t.Errorf("CanonicalizeJSON(%s) error = %v", tt.in, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("CanonicalizeJSON(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := CanonicalizeJSON([]byte(`{"a":1} {}`)); err == nil {
+		t.Error("CanonicalizeJSON() with trailing data succeeded")
+	}
+}
+
+func TestClient_WithCanonicalMetadata(t *testing.T) {
+	t.Parallel()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithDryRun(), WithCanonicalMetadata())
+	event := Event{UserID: "user_1", Action: "doc.updated", Metadata: json.RawMessage(`{"z": 1.50, "a": "b"}`)}
+	if err := client.validateEvent(&event); err != nil {
+		t.Fatalf("validateEvent() error = %v", err)
+	}
+	if string(event.Metadata) != `{"a":"b","z":1.5}` {
+		t.Errorf("metadata = %s, want canonical form", event.Metadata)
+	}
+}
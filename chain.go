@@ -0,0 +1,257 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrChainBroken is returned when a hash chain fails verification: an
+// event was altered, removed, or inserted.
+var ErrChainBroken = errors.New("tryl: hash chain is broken")
+
+// Chain logs events as a hash chain: each event carries the content hash
+// of the event logged before it, so removing, altering, or reordering
+// events after the fact is detectable with Client.VerifyChain. Use one
+// Chain per stream of events that must be tamper-evident, such as an
+// admin audit trail.
+//
+// A chain must have a single writer. Chain.Log calls are serialized, and
+// each waits for the previous event to be stored, so chained logging is
+// slower than Log; use it for the events that need the guarantee.
+//
+//	chain, err := client.Chain(ctx, "admin-actions")
+//	...
+//	_, err = chain.Log(ctx, tryl.Event{UserID: adminID, Action: "user.role_changed", TargetID: userID})
+type Chain struct {
+	client *Client
+	id     string
+
+	mu   sync.Mutex
+	last string
+	// pending is an event whose Log failed without a definite outcome; it
+	// may have been stored as the next link.
+	pending *Event
+}
+
+// Chain returns the hash chain with the given ID, continuing after its
+// latest stored event, or starting it if it has no events yet.
+func (c *Client) Chain(ctx context.Context, chainID string) (*Chain, error) {
+	if chainID == "" {
+		return nil, &ValidationError{Field: "chain_id", Message: "is required"}
+	}
+	// A cached page could miss the latest events, and events logged in
+	// the same instant share a timestamp, so fetch a fresh page and find
+	// the head by its links rather than by order.
+	list, err := c.listFresh(ctx, EventFilter{ChainID: chainID, Order: "desc", Limit: maxListLimit})
+	if err != nil {
+		return nil, err
+	}
+	ch := &Chain{client: c, id: chainID}
+	if len(list.Events) > 0 {
+		latest, err := chainHead(list.Events)
+		if err != nil {
+			return nil, err
+		}
+		if latest.ContentHash == "" {
+			return nil, fmt.Errorf("%w: latest event %s has no content hash", ErrChainBroken, latest.ID)
+		}
+		ch.last = latest.ContentHash
+	}
+	return ch, nil
+}
+
+// ID returns the chain ID.
+func (ch *Chain) ID() string {
+	return ch.id
+}
+
+// LastHash returns the content hash of the latest event in the chain, or
+// an empty string for a new chain. Storing it outside the API (e.g., in a
+// daily signed report) also makes removing the latest events detectable.
+func (ch *Chain) LastHash() string {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.last
+}
+
+// chainHead returns the latest event among the newest events of a chain:
+// the one with the latest timestamp that no other event links to.
+func chainHead(events []StoredEvent) (StoredEvent, error) {
+	newest := events[0].Timestamp
+	followed := make(map[string]bool, len(events))
+	for _, e := range events {
+		if e.Timestamp.After(newest) {
+			newest = e.Timestamp
+		}
+		if e.PrevHash != "" {
+			followed[e.PrevHash] = true
+		}
+	}
+	var heads []StoredEvent
+	for _, e := range events {
+		if e.Timestamp.Equal(newest) && (e.ContentHash == "" || !followed[e.ContentHash]) {
+			heads = append(heads, e)
+		}
+	}
+	if len(heads) != 1 {
+		return StoredEvent{}, fmt.Errorf("%w: %d events are the latest of the chain", ErrChainBroken, len(heads))
+	}
+	return heads[0], nil
+}
+
+// Log sends event as the next link of the chain. The chain advances only
+// if the event is stored; after it is rejected, the next Log links to the
+// same predecessor. Chain events are never counted by WithCompaction, as
+// every link must be stored.
+//
+// If Log fails without a definite outcome, such as after a network error,
+// timeout, or 5xx response, the event may have been stored. Each later Log
+// first resends it with the same idempotency key, so the chain does not
+// fork, and fails without sending its own event until the outcome is
+// known.
+func (ch *Chain) Log(ctx context.Context, event Event) (*EventResponse, error) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if ch.pending != nil {
+		if err := ch.resolve(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	c := ch.client
+	event.ChainID = ch.id
+	event.PrevHash = ch.last
+	if event.IdempotencyKey == "" {
+		event.IdempotencyKey = newIdempotencyKey()
+	}
+	c.correlate(ctx, &event)
+	if err := c.validateEvent(&event); err != nil {
+		return nil, err
+	}
+	hash, err := EventHash(event)
+	if err != nil {
+		return nil, err
+	}
+	event.ContentHash = hash
+
+	// A compacted event would not be stored, leaving the next event linked
+	// to a missing hash.
+	resp, err := c.logEvent(ctx, event)
+	if err != nil {
+		if ambiguous(err) {
+			ch.pending = &event
+		}
+		return nil, err
+	}
+	ch.last = hash
+	return resp, nil
+}
+
+// resolve resends the pending event with its idempotency key, advancing
+// the chain if it is stored and dropping it if it is rejected.
+func (ch *Chain) resolve(ctx context.Context) error {
+	_, err := ch.client.logEvent(ctx, *ch.pending)
+	if err != nil && ambiguous(err) {
+		return fmt.Errorf("previous chain event %s has an unknown outcome: %w", ch.pending.IdempotencyKey, err)
+	}
+	if err == nil {
+		ch.last = ch.pending.ContentHash
+	}
+	ch.pending = nil
+	return nil
+}
+
+// ambiguous reports whether err leaves it unknown if an event was stored,
+// because the request may have reached the API before failing.
+func ambiguous(err error) bool {
+	var netErr *NetworkError
+	if errors.As(err, &netErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.HTTPStatus >= 500 || apiErr.HTTPStatus == http.StatusRequestTimeout)
+}
+
+// VerifyChain fetches every event of a hash chain and checks it with
+// VerifyChainEvents.
+func (c *Client) VerifyChain(ctx context.Context, chainID string) error {
+	filter := EventFilter{ChainID: chainID, Order: "asc", Limit: 100}
+	var events []StoredEvent
+	for {
+		page, err := c.listFresh(ctx, filter)
+		if err != nil {
+			return err
+		}
+		events = append(events, page.Events...)
+		if !page.HasMore || page.NextCursor == "" {
+			return VerifyChainEvents(events)
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// VerifyChainEvents checks that events form one complete hash chain: every
+// event's content hash is valid, exactly one event starts the chain, and
+// each other event links to an event in the set, with no forks. Events may
+// be in any order. The error wraps ErrChainBroken.
+//
+// Removing events from the end of a chain cannot be detected from the
+// events alone; compare the last hash with Chain.LastHash or a copy kept
+// elsewhere.
+func VerifyChainEvents(events []StoredEvent) error {
+	next := make(map[string]StoredEvent, len(events))
+	var first *StoredEvent
+	for i, e := range events {
+		if err := VerifyEventHash(e); err != nil {
+			return fmt.Errorf("%w: event %s: %v", ErrChainBroken, e.ID, err)
+		}
+		if e.PrevHash == "" {
+			if first != nil {
+				return fmt.Errorf("%w: events %s and %s both start the chain", ErrChainBroken, first.ID, e.ID)
+			}
+			first = &events[i]
+			continue
+		}
+		if other, ok := next[e.PrevHash]; ok {
+			return fmt.Errorf("%w: events %s and %s follow the same event", ErrChainBroken, other.ID, e.ID)
+		}
+		next[e.PrevHash] = e
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	if first == nil {
+		return fmt.Errorf("%w: no event starts the chain", ErrChainBroken)
+	}
+
+	seen := 1
+	for e, ok := next[first.ContentHash]; ok; e, ok = next[e.ContentHash] {
+		seen++
+		if seen > len(events) {
+			break
+		}
+	}
+	if seen != len(events) {
+		for _, e := range events {
+			if e.PrevHash != "" && !linked(events, e.PrevHash) {
+				return fmt.Errorf("%w: event %s follows a missing event", ErrChainBroken, e.ID)
+			}
+		}
+		return fmt.Errorf("%w: %d of %d events are not linked to the start", ErrChainBroken, len(events)-seen, len(events))
+	}
+	return nil
+}
+
+// linked reports whether an event in events has the given content hash.
+func linked(events []StoredEvent, hash string) bool {
+	for _, e := range events {
+		if e.ContentHash == hash {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testChain returns n linked stored events.
+func testChain(t *testing.T, n int) []StoredEvent {
+	t.Helper()
+	var events []StoredEvent
+	prev := ""
+	for i := 0; i < n; i++ {
+		e := Event{UserID: "admin_1", Action: "user.role_changed", TargetID: string(rune('a' + i)), ChainID: "admin", PrevHash: prev}
+		hash, err := EventHash(e)
+		if err != nil {
+			t.Fatalf("EventHash() error = %v", err)
+		}
+		events = append(events, StoredEvent{
+			ID: "evt_" + e.TargetID, UserID: e.UserID, Action: e.Action, TargetID: e.TargetID,
+			ChainID: e.ChainID, PrevHash: prev, ContentHash: hash,
+		})
+		prev = hash
+	}
+	return events
+}
+
+func TestVerifyChainEvents(t *testing.T) {
+	t.Parallel()
+
+	events := testChain(t, 4)
+	if err := VerifyChainEvents(events); err != nil {
+		t.Errorf("VerifyChainEvents() error = %v", err)
+	}
+	shuffled := []StoredEvent{events[2], events[0], events[3], events[1]}
+	if err := VerifyChainEvents(shuffled); err != nil {
+		t.Errorf("VerifyChainEvents(shuffled) error = %v", err)
+	}
+
+	tests := map[string][]StoredEvent{
+		"removed":  {events[0], events[1], events[3]},
+		"first":    events[1:],
+		"altered":  append([]StoredEvent{events[0], events[1]}, StoredEvent{ID: "evt_c", UserID: "admin_2", Action: events[2].Action, ChainID: "admin", PrevHash: events[2].PrevHash, ContentHash: events[2].ContentHash}),
+		"forked":   append(testChain(t, 2), StoredEvent{ID: "evt_x", ChainID: "admin", PrevHash: events[0].ContentHash, ContentHash: events[1].ContentHash, UserID: events[1].UserID, Action: events[1].Action, TargetID: events[1].TargetID}),
+		"restarts": append(testChain(t, 2), testChain(t, 1)...),
+	}
+	for name, chain := range tests {
+		if err := VerifyChainEvents(chain); !errors.Is(err, ErrChainBroken) {
+			t.Errorf("%s: VerifyChainEvents() error = %v, want ErrChainBroken", name, err)
+		}
+	}
+}
+
+func TestChainHead(t *testing.T) {
+	t.Parallel()
+
+	events := testChain(t, 3)
+	now := time.Now()
+	for i := range events {
+		events[i].Timestamp = now
+	}
+	// Newest first as listed, but the links decide among equal timestamps.
+	head, err := chainHead([]StoredEvent{events[0], events[2], events[1]})
+	if err != nil || head.ID != events[2].ID {
+		t.Errorf("chainHead() = %s, %v, want %s", head.ID, err, events[2].ID)
+	}
+
+	forked := append(testChain(t, 2), StoredEvent{ID: "evt_x", PrevHash: events[0].ContentHash, ContentHash: "other"})
+	for i := range forked {
+		forked[i].Timestamp = now
+	}
+	if _, err := chainHead(forked); !errors.Is(err, ErrChainBroken) {
+		t.Errorf("chainHead(forked) error = %v, want ErrChainBroken", err)
+	}
+}
+
+func TestChain_ResendsAmbiguousEvent(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var posted []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"events":[]}`))
+			return
+		}
+		var event Event
+		json.NewDecoder(r.Body).Decode(&event)
+		mu.Lock()
+		posted = append(posted, event)
+		n := len(posted)
+		mu.Unlock()
+		if n == 1 {
+			// Stored, but the response is lost.
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithRetry(RetryConfig{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	chain, err := client.Chain(ctx, "admin")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+
+	if _, err := chain.Log(ctx, Event{UserID: "admin_1", Action: "user.invited"}); err == nil {
+		t.Fatal("first Log() error = nil, want the 502")
+	}
+	if _, err := chain.Log(ctx, Event{UserID: "admin_1", Action: "user.removed"}); err != nil {
+		t.Fatalf("second Log() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posted) != 3 {
+		t.Fatalf("server received %d events, want 3", len(posted))
+	}
+	first, resent, second := posted[0], posted[1], posted[2]
+	if resent.IdempotencyKey != first.IdempotencyKey || resent.ContentHash != first.ContentHash {
+		t.Errorf("resent event = %+v, want the first event again", resent)
+	}
+	if second.Action != "user.removed" || second.PrevHash != first.ContentHash {
+		t.Errorf("second event links to %q, want %q", second.PrevHash, first.ContentHash)
+	}
+	if chain.LastHash() != second.ContentHash {
+		t.Errorf("LastHash() = %q, want %q", chain.LastHash(), second.ContentHash)
+	}
+}
+
+func TestChain_BypassesCompaction(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var posted []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"events":[]}`))
+			return
+		}
+		var event Event
+		json.NewDecoder(r.Body).Decode(&event)
+		mu.Lock()
+		posted = append(posted, event)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithCompaction(CompactionConfig{Actions: []string{"admin.*"}, Interval: time.Hour}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	chain, err := client.Chain(ctx, "admin")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := chain.Log(ctx, Event{UserID: "admin_1", Action: "admin.login"})
+		if err != nil || resp.ID == "" {
+			t.Fatalf("Log() = %+v, %v, want a stored event", resp, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posted) != 2 {
+		t.Fatalf("server received %d chain events, want 2", len(posted))
+	}
+	if posted[1].PrevHash != posted[0].ContentHash {
+		t.Errorf("second event links to %q, want %q", posted[1].PrevHash, posted[0].ContentHash)
+	}
+}
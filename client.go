@@ -2,11 +2,17 @@ package tryl
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
@@ -16,9 +22,32 @@ import (
 // Client is the Activity Logger SDK client.
 type Client struct {
 	transport *transport.Transport
+	ws        *transport.WebSocket
+	agent     *transport.Transport
+	stats     stats
+	buffers   *listBuffers
 	retryer   *retryer
 	batcher   *Batcher
 	config    *clientConfig
+	cache     *queryCache
+	etags     *etagCache
+	budgets   *actionBudgets
+	compactor *compactor
+	presets   filterPresets
+	retention *retentionCache
+
+	// dryRunSeq numbers synthetic event IDs in dry-run mode.
+	dryRunSeq atomic.Uint64
+	// closed is set when Close starts, so only its final flush may send
+	// afterwards.
+	closed    atomic.Bool
+	closeOnce sync.Once
+	// skew tracks the server clock offset from response Date headers.
+	skew clockSkew
+	// deprecations reports deprecation headers of responses.
+	deprecations deprecations
+	// keyScopes caches the scopes of the client's API key for CanLog.
+	keyScopes scopeCache
 }
 
 // NewClient creates a new Activity Logger client with API key authentication.
@@ -40,8 +69,22 @@ func NewManagementClient(sessionToken string, opts ...Option) (*Client, error) {
 	return newClientWithToken(sessionToken, opts...)
 }
 
-// newClientWithToken is the internal constructor shared by NewClient and NewManagementClient.
-// It accepts any bearer token (API key or session token) and creates a configured client.
+// NewServiceAccountClient creates a new Activity Logger client with service
+// account token authentication. Service account tokens (actlog_sa_...) are
+// long-lived management credentials for automation such as CI pipelines,
+// which should not depend on a user's session. The client can perform the
+// same operations as one created with NewManagementClient.
+func NewServiceAccountClient(token string, opts ...Option) (*Client, error) {
+	if err := validation.ValidateServiceAccountToken(token); err != nil {
+		return nil, fmt.Errorf("invalid service account token: %w", err)
+	}
+	return newClientWithToken(token, opts...)
+}
+
+// newClientWithToken is the internal constructor shared by NewClient, NewManagementClient,
+// and NewServiceAccountClient.
+// It accepts any bearer token (API key, session token, or service account token) and
+// creates a configured client.
 func newClientWithToken(token string, opts ...Option) (*Client, error) {
 	config := newDefaultConfig()
 	for _, opt := range opts {
@@ -50,46 +93,161 @@ func newClientWithToken(token string, opts ...Option) (*Client, error) {
 		}
 	}
 
+	if err := config.checkEnvironment(keyEnvironment(token)); err != nil {
+		return nil, err
+	}
+	if config.clientID == "" {
+		config.clientID = newClientID()
+	}
+	if err := config.resolveSocket(); err != nil {
+		return nil, fmt.Errorf("invalid option: %w", err)
+	}
+	if config.transport != nil && config.webSocket {
+		return nil, fmt.Errorf("invalid option: WithWebSocket cannot be combined with WithTransport")
+	}
+	if config.agentURL != "" && config.webSocket {
+		return nil, fmt.Errorf("invalid option: WithWebSocket cannot be combined with WithAgent")
+	}
+
 	httpClient := config.httpClient
 	if httpClient == nil {
 		httpClient = &http.Client{
-			Timeout: config.timeout,
+			Timeout:   config.timeout,
+			Transport: config.httpTransport(),
 		}
 	}
+	if config.connMaxLifetime > 0 {
+		httpClient = newRecyclingDoer(httpClient, config.connMaxLifetime)
+	}
 
 	userAgent := fmt.Sprintf("activity-logger-go/%s", Version)
 	if config.userAgent != "" {
 		userAgent = userAgent + " " + config.userAgent
 	}
+	var sdkHeader string
+	if !config.noSDKHeader {
+		sdkHeader = config.sdkHeader()
+	}
 
 	client := &Client{
 		transport: &transport.Transport{
-			BaseURL:    config.baseURL,
-			HTTPClient: httpClient,
-			APIKey:     token, // Note: APIKey field holds any bearer token
-			UserAgent:  userAgent,
+			BaseURL:      config.baseURL,
+			HTTPClient:   httpClient,
+			APIKey:       token, // Note: APIKey field holds any bearer token
+			UserAgent:    userAgent,
+			SDKHeader:    sdkHeader,
+			Custom:       config.transport,
+			MaxBodyBytes: config.maxResponseBytes,
 		},
 		retryer: newRetryer(config.retryConfig),
 		config:  config,
 	}
+	client.retryer.perTryTimeout = config.perTryTimeout
+	client.retryer.overallTimeout = config.overallTimeout
+	client.retryer.clock, client.retryer.rand = config.clock, config.rand
+	client.retryer.closed = &client.closed
+	client.retryer.retries = &client.stats.retries
+	if config.reusableBuffers {
+		client.buffers = newListBuffers()
+		if config.etagEntries == 0 {
+			client.transport.Buffers = &client.buffers.bodies
+		}
+	}
+	client.skew.now = time.Now
+	client.skew.warnAt = config.skewWarnAt
+	client.skew.onWarn = config.onSkewWarn
+	client.deprecations.onWarn = config.onDeprecation
+	client.transport.OnResponse = client.observeResponse
+
+	if config.agentURL != "" {
+		agentURL, agentClient, err := config.agentClient()
+		if err != nil {
+			return nil, fmt.Errorf("invalid option: %w", err)
+		}
+		client.agent = &transport.Transport{
+			BaseURL:    agentURL,
+			HTTPClient: agentClient,
+			APIKey:     token,
+			UserAgent:  userAgent,
+			SDKHeader:  sdkHeader,
+		}
+	}
+
+	if config.webSocket {
+		client.ws = &transport.WebSocket{
+			BaseURL:   config.baseURL,
+			APIKey:    token,
+			UserAgent: userAgent,
+			SDKHeader: sdkHeader,
+			Dialer:    config.dialContext(),
+		}
+	}
 
 	if config.batchConfig != nil {
 		client.batcher = newBatcher(client, config.batchConfig)
 	}
 
+	if config.queryCacheTTL > 0 {
+		client.cache = newQueryCache(config.queryCacheTTL, config.queryCacheEntries, config.clock.Now)
+	}
+	if config.etagEntries > 0 {
+		client.etags = newETagCache(config.etagEntries)
+	}
+	if len(config.actionBudgets) > 0 {
+		client.budgets = newActionBudgets(config.actionBudgets)
+		client.budgets.now = config.clock.Now
+	}
+	if config.compaction != nil {
+		client.compactor = newCompactor(client, *config.compaction)
+	}
+	if config.retentionWarnings {
+		client.retention = newRetentionCache()
+		client.retention.now = config.clock.Now
+	}
+
 	return client, nil
 }
 
+// observeResponse inspects the headers of every API response.
+func (c *Client) observeResponse(resp *http.Response) {
+	c.skew.observe(resp.Header)
+	c.deprecations.observe(resp)
+}
+
 // Log sends a single event synchronously.
-// It returns the created event's ID and timestamp on success.
+// It returns the created event's ID and timestamp on success. Events
+// counted by WithCompaction are not sent individually; their response has
+// an empty ID.
 func (c *Client) Log(ctx context.Context, event Event) (*EventResponse, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	if compacted, err := c.compact(event); compacted {
+		if err != nil {
+			return nil, err
+		}
+		return &EventResponse{Timestamp: c.config.clock.Now().UTC()}, nil
+	}
+	return c.logEvent(ctx, event)
+}
+
+// logEvent sends a single event like Log, but is never compacted, for
+// callers such as Chain that need every event stored.
+func (c *Client) logEvent(ctx context.Context, event Event) (*EventResponse, error) {
+	if err := c.budgets.allow(event.Action); err != nil {
+		return nil, err
+	}
+	c.correlate(ctx, &event)
+	// Validate once, so warnings are not repeated for each retry.
+	if err := c.validateEvent(&event); err != nil {
+		return nil, err
+	}
+
 	var resp *EventResponse
-	var lastErr error
 
-	err := c.retryer.do(ctx, func() error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
 		r, err := c.doLog(ctx, event)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		resp = r
@@ -99,30 +257,77 @@ func (c *Client) Log(ctx context.Context, event Event) (*EventResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	return resp, lastErr
+	return resp, nil
 }
 
-// doLog performs a single log request without retries.
-func (c *Client) doLog(ctx context.Context, event Event) (*EventResponse, error) {
-	// Validate event before sending
-	if err := validation.ValidateEvent(&event); err != nil {
-		// Wrap internal validation error as public ValidationError
-		if fieldErr, ok := err.(*validation.FieldError); ok {
-			return nil, &ValidationError{
-				Field:   fieldErr.Field,
-				Message: fieldErr.Message,
-			}
+// validateEvent applies action normalization, the metadata size policy,
+// and metadata canonicalization, then runs client-side validation and, if
+// configured, the taxonomy check. Failures are returned as
+// *ValidationError. Valid events get their content hash last, so it
+// covers the final content.
+func (c *Client) validateEvent(event *Event) error {
+	if err := c.normalizeAction(event); err != nil {
+		return err
+	}
+	c.fitMetadata(event)
+	c.canonicalize(event)
+	if err := c.checkEvent(event); err != nil {
+		return err
+	}
+	if c.config.taxonomy != nil && !c.config.taxonomy.Has(event.Action) {
+		return &ValidationError{
+			Field:   "action",
+			Message: "is not registered in the action taxonomy",
+			Value:   event.Action,
 		}
-		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	c.hashContent(event)
+	return nil
+}
+
+// checkEvent applies the client's local event rules according to its
+// validation mode.
+func (c *Client) checkEvent(event *Event) error {
+	if c.config.validation == ValidationOff {
+		return nil
+	}
+	var verr error
+	if err := validation.ValidateEvent(event); err != nil {
+		// Wrap internal validation error as public ValidationError
+		verr = newValidationError(err)
+	} else if len(event.Metadata) > c.config.maxMetadataBytes {
+		verr = metadataSizeError(len(event.Metadata), c.config.maxMetadataBytes)
+	}
+	if verr == nil {
+		return nil
+	}
+	if c.config.validation == ValidationStrict {
+		return verr
+	}
+	if c.config.onValidationWarning != nil {
+		c.config.onValidationWarning(*event, verr)
+	}
+	return nil
+}
 
+// doLog performs a single log request for a validated event without
+// retries.
+func (c *Client) doLog(ctx context.Context, event Event) (*EventResponse, error) {
+	if c.config.dryRun {
+		return c.dryRunResponse(), nil
+	}
+
+	body, err := c.eventBody(event)
+	if err != nil {
+		return nil, err
+	}
 	req := transport.Request{
 		Method: "POST",
 		Path:   "/v1/events",
-		Body:   event,
+		Body:   body,
 	}
 
-	resp, err := c.transport.Do(ctx, req)
+	resp, err := c.ingest(ctx, req)
 	if err != nil {
 		return nil, &NetworkError{Op: "request", Err: err}
 	}
@@ -135,67 +340,244 @@ func (c *Client) doLog(ctx context.Context, event Event) (*EventResponse, error)
 	if err := json.Unmarshal(resp.Body, &eventResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	c.reportWarnings(req, eventResp.Warnings)
+	c.stats.eventsSent.Add(1)
 
 	return &eventResp, nil
 }
 
 // LogBatch sends multiple events in a single request.
-func (c *Client) LogBatch(ctx context.Context, events []Event) (*batchResponse, error) {
-	var resp *batchResponse
-	var lastErr error
+// The result has one item per event, in order; events the server rejected
+// are reported as failed items rather than as an error.
+//
+// Each event without an IdempotencyKey is assigned one, so a retried request
+// cannot create duplicates. When the server partially commits a batch (207)
+// and reports retryable per-item errors, only the failed items are
+// resubmitted; results are merged back in the original event order. If a
+// resubmission then fails with an error, LogBatch returns that error
+// together with the result, so the items that did commit are not lost.
+func (c *Client) LogBatch(ctx context.Context, events []Event) (*BatchResult, error) {
+	events = withIdempotencyKeys(events)
+	for i := range events {
+		c.correlate(ctx, &events[i])
+	}
+	if err := c.validateBatch(events); err != nil {
+		return nil, err
+	}
+
+	merged := &batchResponse{Results: make([]EventResponse, len(events))}
+	remaining := make([]int, len(events))
+	for i := range events {
+		remaining[i] = i
+	}
+	var itemErrs map[int]batchResultError
+	var itemFailure bool
 
-	err := c.retryer.do(ctx, func() error {
-		r, err := c.doLogBatch(ctx, events)
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		itemFailure = false
+		pending := make([]Event, len(remaining))
+		for j, idx := range remaining {
+			pending[j] = events[idx]
+		}
+
+		r, err := c.doLogBatch(ctx, pending)
 		if err != nil {
-			lastErr = err
 			return err
 		}
-		resp = r
+
+		failed := make(map[int]batchResultError, len(r.Errors))
+		for _, e := range r.Errors {
+			failed[e.Index] = e
+		}
+
+		var retry []int
+		var retryErr error
+		itemErrs = make(map[int]batchResultError)
+		for j, idx := range remaining {
+			if e, ok := failed[j]; ok {
+				e.Index = idx
+				itemErrs[idx] = e
+				if apiErr := batchItemError(e); apiErr.IsRetryable() {
+					retry = append(retry, idx)
+					retryErr = apiErr
+				}
+				continue
+			}
+			if j < len(r.Results) {
+				merged.Results[idx] = r.Results[j]
+			}
+		}
+
+		// Items that failed permanently are final; only retryable ones stay pending.
+		for idx, e := range itemErrs {
+			if !containsIndex(retry, idx) {
+				merged.Errors = append(merged.Errors, e)
+				delete(itemErrs, idx)
+			}
+		}
+		remaining = retry
+
+		if retryErr != nil {
+			itemFailure = true
+			return fmt.Errorf("%d batch items failed: %w", len(retry), retryErr)
+		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		if !itemFailure && len(remaining) == len(events) {
+			return nil, err
+		}
+		// Report the items still failing alongside the items that did
+		// commit. If retries ran out on per-item failures, those are the
+		// whole story; otherwise a resubmission failed outright and its
+		// error is returned too.
+		for _, idx := range remaining {
+			merged.Errors = append(merged.Errors, itemErrs[idx])
+		}
+		if !itemFailure {
+			return newBatchResult(merged), err
+		}
 	}
-	return resp, lastErr
+
+	return newBatchResult(merged), nil
 }
 
-// doLogBatch performs a batch log request without retries.
-func (c *Client) doLogBatch(ctx context.Context, events []Event) (*batchResponse, error) {
+// newBatchResult converts a merged batch response into a BatchResult.
+func newBatchResult(resp *batchResponse) *BatchResult {
+	failed := make(map[int]batchResultError, len(resp.Errors))
+	for _, e := range resp.Errors {
+		failed[e.Index] = e
+	}
+
+	result := &BatchResult{Items: make([]BatchItem, len(resp.Results))}
+	for i := range resp.Results {
+		item := BatchItem{Index: i, Status: BatchItemCommitted}
+		if e, ok := failed[i]; ok {
+			item.Status = BatchItemFailed
+			item.Error = batchItemError(e)
+		} else if resp.Results[i].ID == "" {
+			item.Status = BatchItemFailed
+			item.Error = &APIError{
+				HTTPStatus: http.StatusBadGateway,
+				Code:       "unknown_error",
+				Message:    "missing response for event",
+			}
+		} else {
+			r := resp.Results[i]
+			item.Response = &r
+		}
+		result.Items[i] = item
+	}
+	return result
+}
+
+// withIdempotencyKeys returns a copy of events where every event has an
+// idempotency key, generating random keys for events that lack one.
+func withIdempotencyKeys(events []Event) []Event {
+	out := make([]Event, len(events))
+	copy(out, events)
+	for i := range out {
+		if out[i].IdempotencyKey == "" {
+			out[i].IdempotencyKey = newIdempotencyKey()
+		}
+	}
+	return out
+}
+
+// newIdempotencyKey returns a random 128-bit hex key.
+func newIdempotencyKey() string {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		// crypto/rand failing is unrecoverable; fall back to a time-based key.
+		return fmt.Sprintf("idem_%d", time.Now().UnixNano())
+	}
+	return "idem_" + hex.EncodeToString(buf[:])
+}
+
+// batchItemError converts a per-item batch error into an APIError.
+// Item errors carry no HTTP status, so one is inferred from the error code.
+func batchItemError(e batchResultError) *APIError {
+	status := http.StatusBadRequest
+	switch e.Code {
+	case ErrCodeRateLimited:
+		status = http.StatusTooManyRequests
+	case ErrCodeInternalError:
+		status = http.StatusInternalServerError
+	}
+	return &APIError{
+		HTTPStatus: status,
+		Code:       e.Code,
+		Message:    e.Message,
+	}
+}
+
+// containsIndex reports whether idx is in indices.
+func containsIndex(indices []int, idx int) bool {
+	for _, i := range indices {
+		if i == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBatch checks the batch size and validates each event.
+func (c *Client) validateBatch(events []Event) error {
 	// Validate batch size
 	if len(events) == 0 {
-		return nil, &ValidationError{
+		return &ValidationError{
 			Field:   "events",
 			Message: "must contain at least one event",
 		}
 	}
 	if len(events) > 100 {
-		return nil, &ValidationError{
+		return &ValidationError{
 			Field:   "events",
 			Message: "must contain at most 100 events",
 		}
 	}
 
 	// Validate each event
-	for i, event := range events {
-		if err := validation.ValidateEvent(&event); err != nil {
-			if fieldErr, ok := err.(*validation.FieldError); ok {
-				return nil, &ValidationError{
-					Field:   fmt.Sprintf("events[%d].%s", i, fieldErr.Field),
-					Message: fieldErr.Message,
+	for i := range events {
+		if err := c.validateEvent(&events[i]); err != nil {
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				return &ValidationError{
+					Field:   fmt.Sprintf("events[%d].%s", i, validationErr.Field),
+					Message: validationErr.Message,
+					Value:   validationErr.Value,
+					err:     validationErr.err,
 				}
 			}
-			return nil, fmt.Errorf("event at index %d: %w", i, err)
+			return fmt.Errorf("event at index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// doLogBatch performs a request for a batch of validated events without
+// retries.
+func (c *Client) doLogBatch(ctx context.Context, events []Event) (*batchResponse, error) {
+	if c.config.dryRun {
+		results := make([]EventResponse, len(events))
+		for i := range events {
+			results[i] = *c.dryRunResponse()
 		}
+		return &batchResponse{Results: results}, nil
 	}
 
+	body, err := c.batchBody(batchRequest{Events: events})
+	if err != nil {
+		return nil, err
+	}
 	req := transport.Request{
 		Method: "POST",
 		Path:   "/v1/events/batch",
-		Body:   batchRequest{Events: events},
+		Body:   body,
 	}
 
-	resp, err := c.transport.Do(ctx, req)
+	resp, err := c.ingest(ctx, req)
 	if err != nil {
 		return nil, &NetworkError{Op: "request", Err: err}
 	}
@@ -208,38 +590,102 @@ func (c *Client) doLogBatch(ctx context.Context, events []Event) (*batchResponse
 	if err := json.Unmarshal(resp.Body, &batchResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	batchResp.reportWarnings(c, req)
+	c.stats.batches.Add(1)
+	c.stats.eventsSent.Add(uint64(batchResp.committed()))
 
 	return &batchResp, nil
 }
 
+// dryRunResponse returns a synthetic response for an event accepted in dry-run mode.
+func (c *Client) dryRunResponse() *EventResponse {
+	return &EventResponse{
+		ID:        fmt.Sprintf("evt_dryrun_%d", c.dryRunSeq.Add(1)),
+		Timestamp: c.config.clock.Now().UTC(),
+	}
+}
+
 // LogAsync queues an event for asynchronous delivery.
-// It returns immediately. Use the returned channel to receive the result.
+// It returns immediately. Use the returned PendingEvent to wait for or
+// poll the result; it is safe to ignore.
 // If batching is enabled, events are accumulated and sent in bulk.
-func (c *Client) LogAsync(ctx context.Context, event Event) <-chan AsyncResult {
-	resultCh := make(chan AsyncResult, 1)
+func (c *Client) LogAsync(ctx context.Context, event Event) *PendingEvent {
+	pending := newPendingEvent()
+	c.logAsync(ctx, event, pending)
+	return pending
+}
 
-	if c.batcher != nil {
-		c.batcher.Add(ctx, event, resultCh)
-	} else {
-		go func() {
-			resp, err := c.Log(ctx, event)
-			resultCh <- AsyncResult{Response: resp, Error: err}
-			close(resultCh)
-		}()
+// LogFireAndForget queues an event for asynchronous delivery without
+// returning a handle. Delivery failures are passed to the handler set with
+// WithAsyncErrorHandler, or dropped if none is set.
+//
+// ctx must outlive delivery; for request-scoped contexts, pass
+// context.WithoutCancel(ctx).
+func (c *Client) LogFireAndForget(ctx context.Context, event Event) {
+	pending := newPendingEvent()
+	if handler := c.config.asyncErrorHandler; handler != nil {
+		pending.onComplete = func(result AsyncResult) {
+			if result.Error != nil && !errors.Is(result.Error, ErrEventDropped) && !errors.Is(result.Error, ErrActionBudgetExceeded) {
+				handler(event, result.Error)
+			}
+		}
 	}
+	c.logAsync(ctx, event, pending)
+}
 
-	return resultCh
+// logAsync delivers event in the background and completes pending.
+func (c *Client) logAsync(ctx context.Context, event Event, pending *PendingEvent) {
+	if c.closed.Load() {
+		pending.complete(AsyncResult{Error: ErrClientClosed})
+		return
+	}
+	if compacted, err := c.compact(event); compacted {
+		if err != nil {
+			pending.complete(AsyncResult{Error: err})
+		} else {
+			pending.complete(AsyncResult{Response: &EventResponse{Timestamp: c.config.clock.Now().UTC()}})
+		}
+		return
+	}
+	c.correlate(ctx, &event)
+	if c.batcher != nil {
+		// Without batching, the budget is charged by Log.
+		if err := c.budgets.allow(event.Action); err != nil {
+			pending.complete(AsyncResult{Error: err})
+			return
+		}
+		c.batcher.Add(ctx, event, pending)
+		return
+	}
+	c.goLabeled(ctx, "log_async", func(ctx context.Context) {
+		resp, err := c.Log(ctx, event)
+		pending.complete(AsyncResult{Response: resp, Error: err})
+	})
 }
 
 // List retrieves events matching the given filter.
 func (c *Client) List(ctx context.Context, filter EventFilter) (*EventList, error) {
+	return c.list(ctx, filter, c.cache)
+}
+
+// listFresh is List without the query cache, for internal readers that
+// must see events logged after a cached page was fetched.
+func (c *Client) listFresh(ctx context.Context, filter EventFilter) (*EventList, error) {
+	return c.list(ctx, filter, nil)
+}
+
+// list lists events with retries, reading and filling cache unless it is
+// nil.
+func (c *Client) list(ctx context.Context, filter EventFilter, cache *queryCache) (*EventList, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
 	var resp *EventList
-	var lastErr error
 
-	err := c.retryer.do(ctx, func() error {
-		r, err := c.doList(ctx, filter)
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doList(ctx, filter, cache)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		resp = r
@@ -249,11 +695,93 @@ func (c *Client) List(ctx context.Context, filter EventFilter) (*EventList, erro
 	if err != nil {
 		return nil, err
 	}
-	return resp, lastErr
+	return c.warnRetention(ctx, filter, resp), nil
+}
+
+// maxListLimit is the largest EventFilter.Limit the API accepts.
+const maxListLimit = 100
+
+// validateFilter checks the fields of filter that can be validated
+// client-side, so mistakes fail with a ValidationError naming the field
+// instead of a server 400 or an empty result.
+func validateFilter(filter EventFilter) error {
+	if filter.Limit < 0 || filter.Limit > maxListLimit {
+		return &ValidationError{Field: "limit", Message: fmt.Sprintf("must be between 0 and %d", maxListLimit), Value: strconv.Itoa(filter.Limit)}
+	}
+	if filter.Offset < 0 {
+		return &ValidationError{Field: "offset", Message: "must not be negative", Value: strconv.Itoa(filter.Offset)}
+	}
+	if filter.Cursor != "" && filter.Offset > 0 {
+		return &ValidationError{Field: "offset", Message: "cannot be combined with cursor"}
+	}
+	if filter.StartTime != nil && filter.EndTime != nil && filter.EndTime.Before(*filter.StartTime) {
+		return &ValidationError{Field: "end_time", Message: "must not be before start_time", Value: formatTime(*filter.EndTime)}
+	}
+	if filter.Action != "" {
+		if err := ValidateActionPattern(filter.Action); err != nil {
+			return err
+		}
+	}
+	if err := validation.ValidateTags("tags", filter.Tags); err != nil {
+		return newValidationError(err)
+	}
+	if err := validation.ValidateTags("tags_any", filter.TagsAny); err != nil {
+		return newValidationError(err)
+	}
+	return validateOrder(filter)
 }
 
 // doList performs a list request without retries.
-func (c *Client) doList(ctx context.Context, filter EventFilter) (*EventList, error) {
+func (c *Client) doList(ctx context.Context, filter EventFilter, cache *queryCache) (*EventList, error) {
+	query, err := c.listQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	key := query.Encode()
+	if cached, ok := cache.get(ctx, key); ok {
+		return cached, nil
+	}
+
+	req := transport.Request{
+		Method: "GET",
+		Path:   "/v1/events",
+		Query:  query,
+	}
+
+	resp, err := c.conditionalGet(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var eventList EventList
+	if c.buffers != nil {
+		c.buffers.prepare(&eventList)
+	}
+	err = json.Unmarshal(resp.Body, &eventList)
+	// The decoded list does not reference the body.
+	resp.Release()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	c.reportWarnings(req, eventList.Warnings)
+
+	if c.config.migrations != nil {
+		if err := c.config.migrations.UpgradeList(&eventList); err != nil {
+			return nil, err
+		}
+	}
+
+	cache.put(key, &eventList)
+	return &eventList, nil
+}
+
+// listQuery returns the query parameters selecting filter's events.
+func (c *Client) listQuery(filter EventFilter) (url.Values, error) {
 	query := url.Values{}
 
 	// Basic filters
@@ -276,11 +804,11 @@ func (c *Client) doList(ctx context.Context, filter EventFilter) (*EventList, er
 	}
 
 	// Time range filters
-	if filter.StartTime != nil {
-		query.Set("start_time", filter.StartTime.Format(time.RFC3339))
+	if start := c.toServerTime(filter.StartTime); start != nil {
+		query.Set("start_time", formatTime(*start))
 	}
-	if filter.EndTime != nil {
-		query.Set("end_time", filter.EndTime.Format(time.RFC3339))
+	if end := c.toServerTime(filter.EndTime); end != nil {
+		query.Set("end_time", formatTime(*end))
 	}
 
 	// Metadata filters
@@ -298,7 +826,31 @@ func (c *Client) doList(ctx context.Context, filter EventFilter) (*EventList, er
 		query.Set("metadata_search", filter.MetadataSearch)
 	}
 
-	// Pagination: Cursor takes precedence over Offset
+	if filter.TenantID != "" {
+		query.Set("tenant_id", filter.TenantID)
+	}
+	if filter.CorrelationID != "" {
+		query.Set("correlation_id", filter.CorrelationID)
+	}
+	if filter.ParentEventID != "" {
+		query.Set("parent_event_id", filter.ParentEventID)
+	}
+	if filter.GroupID != "" {
+		query.Set("group_id", filter.GroupID)
+	}
+	if filter.ChainID != "" {
+		query.Set("chain_id", filter.ChainID)
+	}
+
+	// Tag filters
+	if len(filter.Tags) > 0 {
+		query.Set("tags", strings.Join(filter.Tags, ","))
+	}
+	if len(filter.TagsAny) > 0 {
+		query.Set("tags_any", strings.Join(filter.TagsAny, ","))
+	}
+
+	// Pagination: Cursor and Offset are exclusive (see validateFilter)
 	if filter.Cursor != "" {
 		query.Set("cursor", filter.Cursor)
 	} else if filter.Offset > 0 {
@@ -314,45 +866,122 @@ func (c *Client) doList(ctx context.Context, filter EventFilter) (*EventList, er
 	if filter.Order != "" {
 		query.Set("order", filter.Order)
 	}
-
-	req := transport.Request{
-		Method: "GET",
-		Path:   "/v1/events",
-		Query:  query,
+	if filter.OrderBy != "" {
+		query.Set("order_by", filter.OrderBy)
 	}
 
-	resp, err := c.transport.Do(ctx, req)
-	if err != nil {
-		return nil, &NetworkError{Op: "request", Err: err}
+	// Schema version
+	if filter.SchemaVersion > 0 {
+		query.Set("schema_version", strconv.Itoa(filter.SchemaVersion))
 	}
 
-	if resp.StatusCode >= 400 {
-		return nil, c.parseError(resp)
+	if filter.IncludeTotal {
+		query.Set("include_total", "true")
 	}
 
-	var eventList EventList
-	if err := json.Unmarshal(resp.Body, &eventList); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	return query, nil
+}
+
+// ListChildren retrieves the direct children of an event, the events whose
+// ParentEventID is eventID, oldest first. All pages are fetched; to walk a
+// tree, call ListChildren for each child.
+func (c *Client) ListChildren(ctx context.Context, eventID string) ([]StoredEvent, error) {
+	if eventID == "" {
+		return nil, &ValidationError{Field: "event_id", Message: "is required"}
 	}
 
-	return &eventList, nil
+	filter := EventFilter{ParentEventID: eventID, Order: "asc", Limit: 100}
+	children := []StoredEvent{}
+	for {
+		page, err := c.listFresh(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, page.Events...)
+		if !page.HasMore || page.NextCursor == "" {
+			return children, nil
+		}
+		filter.Cursor = page.NextCursor
+	}
 }
 
 // Flush sends any buffered events immediately.
 // Should be called before application shutdown.
-func (c *Client) Flush(ctx context.Context) error {
+// The report counts the batched events sent, so shutdown code can log the
+// outcome; events summarized by compaction are not counted. If some
+// events were sent and others failed, the error wraps ErrPartialFlush.
+// The report is never nil.
+func (c *Client) Flush(ctx context.Context) (*FlushReport, error) {
+	if c.closed.Load() {
+		return &FlushReport{}, ErrClientClosed
+	}
+	start := c.config.clock.Now()
+	err := c.compactor.flush(ctx)
+	report := &FlushReport{}
 	if c.batcher != nil {
-		return c.batcher.Flush(ctx)
+		var batchErr error
+		report, batchErr = c.batcher.Flush(ctx)
+		err = errors.Join(err, batchErr)
 	}
-	return nil
+	report.Duration = c.config.clock.Now().Sub(start)
+	return report, err
+}
+
+// QueuePressure returns how full the batching queue is, from 0 (empty) to
+// 1 (full, so LogAsync blocks). Producers can shed optional events, such
+// as analytics, as it rises. It is 0 without WithBatching.
+func (c *Client) QueuePressure() float64 {
+	if c.batcher == nil {
+		return 0
+	}
+	return c.batcher.pressure()
+}
+
+// ingest sends an event ingestion request to the agent when WithAgent is
+// set, and with ingestDirect otherwise.
+func (c *Client) ingest(ctx context.Context, req transport.Request) (*transport.Response, error) {
+	if c.agent != nil {
+		return c.agent.Do(ctx, req)
+	}
+	return c.ingestDirect(ctx, req)
+}
+
+// ingestDirect sends an event ingestion request to the API over the
+// WebSocket when WithWebSocket is enabled, and over HTTP otherwise.
+func (c *Client) ingestDirect(ctx context.Context, req transport.Request) (*transport.Response, error) {
+	if c.ws != nil {
+		return c.ws.Do(ctx, req)
+	}
+	return c.transport.Do(ctx, req)
+}
+
+// finalFlushKey marks the context of the flush run by Close, the only
+// requests allowed once the client is closing.
+type finalFlushKey struct{}
+
+// finalFlushContext returns the context for the flush run by Close.
+func finalFlushContext() context.Context {
+	return context.WithValue(context.Background(), finalFlushKey{}, true)
 }
 
 // Close gracefully shuts down the client, flushing any pending events.
+// Calls made once Close has started fail with ErrClientClosed, so no
+// event is accepted after the final flush. Close is safe to call more
+// than once and concurrently; later calls wait for the first to finish and
+// return nil.
 func (c *Client) Close() error {
-	if c.batcher != nil {
-		return c.batcher.Stop(context.Background())
-	}
-	return nil
+	var err error
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		c.compactor.stop()
+		if c.batcher != nil {
+			err = c.batcher.Stop(context.Background())
+		}
+		if c.ws != nil {
+			c.ws.Close()
+		}
+	})
+	return err
 }
 
 // ========== Project Management Methods ==========
@@ -361,12 +990,10 @@ func (c *Client) Close() error {
 // Requires session token authentication (use NewManagementClient).
 func (c *Client) ListProjects(ctx context.Context) (*ProjectList, error) {
 	var resp *ProjectList
-	var lastErr error
 
-	err := c.retryer.do(ctx, func() error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
 		r, err := c.doListProjects(ctx)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		resp = r
@@ -376,7 +1003,7 @@ func (c *Client) ListProjects(ctx context.Context) (*ProjectList, error) {
 	if err != nil {
 		return nil, err
 	}
-	return resp, lastErr
+	return resp, nil
 }
 
 // doListProjects performs the list projects request without retries.
@@ -407,13 +1034,18 @@ func (c *Client) doListProjects(ctx context.Context) (*ProjectList, error) {
 // Requires session token authentication (use NewManagementClient).
 // Returns the project details and an initial API key (shown only once).
 func (c *Client) CreateProject(ctx context.Context, req CreateProjectRequest) (*CreateProjectResponse, error) {
+	if err := req.Environment.validate(true); err != nil {
+		return nil, err
+	}
+	if err := c.config.checkEnvironment(req.Environment); err != nil {
+		return nil, err
+	}
+
 	var resp *CreateProjectResponse
-	var lastErr error
 
-	err := c.retryer.do(ctx, func() error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
 		r, err := c.doCreateProject(ctx, req)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		resp = r
@@ -423,7 +1055,7 @@ func (c *Client) CreateProject(ctx context.Context, req CreateProjectRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	return resp, lastErr
+	return resp, nil
 }
 
 // doCreateProject performs the create project request without retries.
@@ -454,12 +1086,9 @@ func (c *Client) doCreateProject(ctx context.Context, req CreateProjectRequest)
 // DeleteProject deletes a project by ID.
 // Requires session token authentication (use NewManagementClient).
 func (c *Client) DeleteProject(ctx context.Context, projectID string) error {
-	var lastErr error
-
-	err := c.retryer.do(ctx, func() error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
 		err := c.doDeleteProject(ctx, projectID)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		return nil
@@ -468,7 +1097,7 @@ func (c *Client) DeleteProject(ctx context.Context, projectID string) error {
 	if err != nil {
 		return err
 	}
-	return lastErr
+	return nil
 }
 
 // doDeleteProject performs the delete project request without retries.
@@ -490,18 +1119,114 @@ func (c *Client) doDeleteProject(ctx context.Context, projectID string) error {
 	return nil
 }
 
+// GetProject retrieves a project by ID.
+// Requires session token authentication (use NewManagementClient).
+// If the project does not exist, the error is a *NotFoundError.
+func (c *Client) GetProject(ctx context.Context, projectID string) (*Project, error) {
+	var resp *Project
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doGetProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, notFound(err, "project", projectID)
+	}
+	return resp, nil
+}
+
+// doGetProject performs the get project request without retries.
+func (c *Client) doGetProject(ctx context.Context, projectID string) (*Project, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/projects/%s", projectID),
+	}
+
+	resp, err := c.conditionalGet(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var project Project
+	if err := json.Unmarshal(resp.Body, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &project, nil
+}
+
+// FindProjectByName returns the project with the given name and
+// environment. If none exists, the error is a *NotFoundError.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) FindProjectByName(ctx context.Context, name string, environment Environment) (*Project, error) {
+	list, err := c.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Projects {
+		if p := &list.Projects[i]; p.Name == name && p.Environment == environment {
+			return p, nil
+		}
+	}
+	return nil, &NotFoundError{Resource: "project", ID: name}
+}
+
+// CreateProjectIfNotExists creates a project unless one with the same name
+// and environment already exists, making repeated applies idempotent. It
+// reports whether the project was created. For an existing project the
+// response's APIKey is empty, since key values are only shown at creation.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) CreateProjectIfNotExists(ctx context.Context, req CreateProjectRequest) (*CreateProjectResponse, bool, error) {
+	project, err := c.FindProjectByName(ctx, req.Name, req.Environment)
+	if err == nil {
+		return &CreateProjectResponse{Project: *project}, false, nil
+	}
+	if !IsNotFound(err) {
+		return nil, false, err
+	}
+
+	resp, err := c.CreateProject(ctx, req)
+	if err != nil {
+		return nil, false, err
+	}
+	return resp, true, nil
+}
+
 // ========== API Key Management Methods ==========
 
 // ListAPIKeys retrieves all API keys for a project.
+// An optional APIKeyFilter narrows the results server-side by environment,
+// status, or scope. At most one filter may be given.
 // Requires session token authentication (use NewManagementClient).
-func (c *Client) ListAPIKeys(ctx context.Context, projectID string) (*APIKeyList, error) {
+func (c *Client) ListAPIKeys(ctx context.Context, projectID string, filter ...APIKeyFilter) (*APIKeyList, error) {
+	if len(filter) > 1 {
+		return nil, &ValidationError{
+			Field:   "filter",
+			Message: "at most one filter may be provided",
+		}
+	}
+	var f APIKeyFilter
+	if len(filter) == 1 {
+		f = filter[0]
+	}
+	if err := validateAPIKeyFilter(f); err != nil {
+		return nil, err
+	}
+
 	var resp *APIKeyList
-	var lastErr error
 
-	err := c.retryer.do(ctx, func() error {
-		r, err := c.doListAPIKeys(ctx, projectID)
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doListAPIKeys(ctx, projectID, f)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		resp = r
@@ -511,14 +1236,26 @@ func (c *Client) ListAPIKeys(ctx context.Context, projectID string) (*APIKeyList
 	if err != nil {
 		return nil, err
 	}
-	return resp, lastErr
+	return resp, nil
 }
 
 // doListAPIKeys performs the list API keys request without retries.
-func (c *Client) doListAPIKeys(ctx context.Context, projectID string) (*APIKeyList, error) {
+func (c *Client) doListAPIKeys(ctx context.Context, projectID string, filter APIKeyFilter) (*APIKeyList, error) {
+	query := url.Values{}
+	if filter.Environment != "" {
+		query.Set("environment", string(filter.Environment))
+	}
+	if filter.Status != "" {
+		query.Set("status", string(filter.Status))
+	}
+	if filter.Scope != "" {
+		query.Set("scope", filter.Scope)
+	}
+
 	req := transport.Request{
 		Method: "GET",
 		Path:   fmt.Sprintf("/v1/projects/%s/keys", projectID),
+		Query:  query,
 	}
 
 	resp, err := c.transport.Do(ctx, req)
@@ -538,17 +1275,41 @@ func (c *Client) doListAPIKeys(ctx context.Context, projectID string) (*APIKeyLi
 	return &keyList, nil
 }
 
+// validateAPIKeyFilter checks that the filter's enumerated fields hold known values.
+func validateAPIKeyFilter(filter APIKeyFilter) error {
+	switch filter.Status {
+	case "", APIKeyStatusActive, APIKeyStatusRevoked, APIKeyStatusExpired:
+	default:
+		return &ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("must be one of active, revoked, expired (got: %s)", filter.Status),
+		}
+	}
+	return filter.Environment.validate(false)
+}
+
 // CreateAPIKey creates a new API key for a project.
 // Requires session token authentication (use NewManagementClient).
 // Returns the full API key value (shown only once).
 func (c *Client) CreateAPIKey(ctx context.Context, projectID string, req CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	if err := req.Environment.validate(true); err != nil {
+		return nil, err
+	}
+	if err := validateScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+	if err := validateKeyRestrictions(req.AllowedCIDRs, req.AllowedOrigins); err != nil {
+		return nil, err
+	}
+	if err := c.config.checkEnvironment(req.Environment); err != nil {
+		return nil, err
+	}
+
 	var resp *CreateAPIKeyResponse
-	var lastErr error
 
-	err := c.retryer.do(ctx, func() error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
 		r, err := c.doCreateAPIKey(ctx, projectID, req)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		resp = r
@@ -558,7 +1319,7 @@ func (c *Client) CreateAPIKey(ctx context.Context, projectID string, req CreateA
 	if err != nil {
 		return nil, err
 	}
-	return resp, lastErr
+	return resp, nil
 }
 
 // doCreateAPIKey performs the create API key request without retries.
@@ -589,12 +1350,9 @@ func (c *Client) doCreateAPIKey(ctx context.Context, projectID string, req Creat
 // RevokeAPIKey revokes an API key by ID.
 // Requires session token authentication (use NewManagementClient).
 func (c *Client) RevokeAPIKey(ctx context.Context, keyID string) error {
-	var lastErr error
-
-	err := c.retryer.do(ctx, func() error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
 		err := c.doRevokeAPIKey(ctx, keyID)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		return nil
@@ -603,7 +1361,7 @@ func (c *Client) RevokeAPIKey(ctx context.Context, keyID string) error {
 	if err != nil {
 		return err
 	}
-	return lastErr
+	return nil
 }
 
 // doRevokeAPIKey performs the revoke API key request without retries.
@@ -625,17 +1383,77 @@ func (c *Client) doRevokeAPIKey(ctx context.Context, keyID string) error {
 	return nil
 }
 
+// UpdateAPIKey changes the name, scopes, restrictions, or expiration of an API key.
+// Requires session token authentication (use NewManagementClient).
+// Returns the updated key metadata.
+func (c *Client) UpdateAPIKey(ctx context.Context, keyID string, req UpdateAPIKeyRequest) (*APIKey, error) {
+	if req.Name == nil && req.Scopes == nil && req.ExpiresAt == nil && req.AllowedCIDRs == nil && req.AllowedOrigins == nil {
+		return nil, &ValidationError{
+			Field:   "request",
+			Message: "at least one of name, scopes, allowed_cidrs, allowed_origins, or expires_at must be set",
+		}
+	}
+	if req.Name != nil && *req.Name == "" {
+		return nil, &ValidationError{Field: "name", Message: "cannot be empty"}
+	}
+	if err := validateScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+	if err := validateKeyRestrictions(req.AllowedCIDRs, req.AllowedOrigins); err != nil {
+		return nil, err
+	}
+
+	var resp *APIKey
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doUpdateAPIKey(ctx, keyID, req)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doUpdateAPIKey performs the update API key request without retries.
+func (c *Client) doUpdateAPIKey(ctx context.Context, keyID string, req UpdateAPIKeyRequest) (*APIKey, error) {
+	transportReq := transport.Request{
+		Method: "PATCH",
+		Path:   fmt.Sprintf("/v1/keys/%s", keyID),
+		Body:   req,
+	}
+
+	resp, err := c.transport.Do(ctx, transportReq)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var key APIKey
+	if err := json.Unmarshal(resp.Body, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &key, nil
+}
+
 // RotateAPIKey rotates an API key, creating a new key and revoking the old one.
 // Requires session token authentication (use NewManagementClient).
 // Returns the new API key value (shown only once) and the revocation timestamp.
 func (c *Client) RotateAPIKey(ctx context.Context, keyID string, req RotateAPIKeyRequest) (*RotateAPIKeyResponse, error) {
 	var resp *RotateAPIKeyResponse
-	var lastErr error
 
-	err := c.retryer.do(ctx, func() error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
 		r, err := c.doRotateAPIKey(ctx, keyID, req)
 		if err != nil {
-			lastErr = err
 			return err
 		}
 		resp = r
@@ -645,7 +1463,7 @@ func (c *Client) RotateAPIKey(ctx context.Context, keyID string, req RotateAPIKe
 	if err != nil {
 		return nil, err
 	}
-	return resp, lastErr
+	return resp, nil
 }
 
 // doRotateAPIKey performs the rotate API key request without retries.
@@ -673,16 +1491,184 @@ func (c *Client) doRotateAPIKey(ctx context.Context, keyID string, req RotateAPI
 	return &rotateResp, nil
 }
 
+// GetAPIKey retrieves an API key's metadata by ID.
+// Requires session token authentication (use NewManagementClient).
+// If the key does not exist, the error is a *NotFoundError.
+func (c *Client) GetAPIKey(ctx context.Context, keyID string) (*APIKey, error) {
+	var resp *APIKey
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doGetAPIKey(ctx, keyID)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, notFound(err, "api_key", keyID)
+	}
+	return resp, nil
+}
+
+// doGetAPIKey performs the get API key request without retries.
+func (c *Client) doGetAPIKey(ctx context.Context, keyID string) (*APIKey, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/keys/%s", keyID),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var key APIKey
+	if err := json.Unmarshal(resp.Body, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &key, nil
+}
+
+// FindAPIKeyByName returns the active API key with the given name in a
+// project. Revoked and expired keys are ignored. If none exists, the error
+// is a *NotFoundError.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) FindAPIKeyByName(ctx context.Context, projectID, name string) (*APIKey, error) {
+	list, err := c.ListAPIKeys(ctx, projectID, APIKeyFilter{Status: APIKeyStatusActive})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.APIKeys {
+		if k := &list.APIKeys[i]; k.Name == name && k.Status(c.config.clock.Now()) == APIKeyStatusActive {
+			return k, nil
+		}
+	}
+	return nil, &NotFoundError{Resource: "api_key", ID: name}
+}
+
+// CreateAPIKeyIfNotExists creates an API key unless an active key with the
+// same name already exists in the project. It reports whether the key was
+// created. For an existing key the response's APIKey value is empty.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) CreateAPIKeyIfNotExists(ctx context.Context, projectID string, req CreateAPIKeyRequest) (*CreateAPIKeyResponse, bool, error) {
+	key, err := c.FindAPIKeyByName(ctx, projectID, req.Name)
+	if err == nil {
+		return &CreateAPIKeyResponse{APIKeyMetadata: *key}, false, nil
+	}
+	if !IsNotFound(err) {
+		return nil, false, err
+	}
+
+	resp, err := c.CreateAPIKey(ctx, projectID, req)
+	if err != nil {
+		return nil, false, err
+	}
+	return resp, true, nil
+}
+
+// ========== Management Audit Methods ==========
+
+// ListManagementAudit retrieves the audit trail of management actions
+// (project and API key changes) matching the given filter.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) ListManagementAudit(ctx context.Context, filter AuditFilter) (*AuditRecordList, error) {
+	var resp *AuditRecordList
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doListManagementAudit(ctx, filter)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doListManagementAudit performs the list audit request without retries.
+func (c *Client) doListManagementAudit(ctx context.Context, filter AuditFilter) (*AuditRecordList, error) {
+	query := url.Values{}
+
+	if filter.ProjectID != "" {
+		query.Set("project_id", filter.ProjectID)
+	}
+	if filter.ActorID != "" {
+		query.Set("actor_id", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query.Set("action", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		query.Set("resource_type", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query.Set("resource_id", filter.ResourceID)
+	}
+	if start := c.toServerTime(filter.StartTime); start != nil {
+		query.Set("start_time", formatTime(*start))
+	}
+	if end := c.toServerTime(filter.EndTime); end != nil {
+		query.Set("end_time", formatTime(*end))
+	}
+	if filter.Cursor != "" {
+		query.Set("cursor", filter.Cursor)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	req := transport.Request{
+		Method: "GET",
+		Path:   "/v1/audit",
+		Query:  query,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var records AuditRecordList
+	if err := json.Unmarshal(resp.Body, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &records, nil
+}
+
 // parseError converts an HTTP error response to an APIError.
 func (c *Client) parseError(resp *transport.Response) error {
 	errResp := transport.ParseError(resp)
 	if errResp != nil {
-		return &APIError{
+		apiErr := &APIError{
 			HTTPStatus: resp.StatusCode,
 			Code:       errResp.Error.Code,
 			Message:    errResp.Error.Message,
 			RequestID:  resp.RequestID,
 		}
+		for _, d := range errResp.Error.Details {
+			apiErr.Details = append(apiErr.Details, FieldDetail{
+				Field:   d.Field,
+				Code:    d.Code,
+				Message: d.Message,
+			})
+		}
+		return apiErr
 	}
 
 	return &APIError{
@@ -698,3 +1684,59 @@ type AsyncResult struct {
 	Response *EventResponse
 	Error    error
 }
+
+// PendingEvent is a handle to an event queued by LogAsync.
+// The result is retained until read, so callers that never wait on it do
+// not leak goroutines.
+type PendingEvent struct {
+	once   sync.Once
+	done   chan struct{}
+	result AsyncResult
+
+	// onComplete, if set, is called with the result on completion.
+	onComplete func(AsyncResult)
+}
+
+// newPendingEvent returns an incomplete PendingEvent.
+func newPendingEvent() *PendingEvent {
+	return &PendingEvent{done: make(chan struct{})}
+}
+
+// complete records the result. Only the first call has an effect.
+func (p *PendingEvent) complete(result AsyncResult) {
+	p.once.Do(func() {
+		p.result = result
+		close(p.done)
+		if p.onComplete != nil {
+			p.onComplete(result)
+		}
+	})
+}
+
+// Done returns a channel that is closed when the result is available,
+// for use in select statements.
+func (p *PendingEvent) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait blocks until the event has been delivered or ctx is done.
+// It returns the created event, or the delivery error or ctx.Err().
+func (p *PendingEvent) Wait(ctx context.Context) (*EventResponse, error) {
+	select {
+	case <-p.done:
+		return p.result.Response, p.result.Error
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Result returns the outcome without blocking. ok is false while the event
+// is still pending.
+func (p *PendingEvent) Result() (result AsyncResult, ok bool) {
+	select {
+	case <-p.done:
+		return p.result, true
+	default:
+		return AsyncResult{}, false
+	}
+}
@@ -3,9 +3,13 @@ package tryl
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -127,6 +131,186 @@ func TestClient_Log_ValidationError(t *testing.T) {
 	}
 }
 
+func TestClient_WithValidation(t *testing.T) {
+	t.Parallel()
+
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	event := Event{UserID: "user_123", Action: "Legacy:Action"}
+
+	var warned []error
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithValidation(ValidationWarn),
+		WithValidationWarningHandler(func(e Event, err error) { warned = append(warned, err) }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.Log(ctx, event); err != nil {
+		t.Errorf("Log() with ValidationWarn error = %v", err)
+	}
+	if len(warned) != 1 || !IsClientValidationError(warned[0]) {
+		t.Errorf("warnings = %v, want one validation error", warned)
+	}
+
+	client, _ = NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithValidation(ValidationOff))
+	if _, err := client.Log(ctx, event); err != nil {
+		t.Errorf("Log() with ValidationOff error = %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("server received %d events, want 2", sent)
+	}
+
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithValidation(ValidationMode(7))); err == nil {
+		t.Error("NewClient() with an unknown validation mode succeeded")
+	}
+}
+
+func TestClient_ValidationWarnOnce(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	var warnings atomic.Int32
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+		WithValidation(ValidationWarn),
+		WithValidationWarningHandler(func(Event, error) { warnings.Add(1) }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.Log(context.Background(), Event{UserID: "user_123", Action: "Legacy:Action"}); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("server received %d requests, want 2", requests.Load())
+	}
+	if n := warnings.Load(); n != 1 {
+		t.Errorf("warning handler called %d times, want 1", n)
+	}
+}
+
+func TestAsValidationError(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithDryRun())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Log(context.Background(), Event{UserID: "user_123", Action: "User.Created"})
+	validationErr, ok := AsValidationError(err)
+	if !ok {
+		t.Fatalf("AsValidationError(%v) ok = false, want true", err)
+	}
+	if validationErr.Field != "action" || validationErr.Value != "User.Created" {
+		t.Errorf("got Field=%q Value=%q, want action/User.Created", validationErr.Field, validationErr.Value)
+	}
+	if validationErr.Unwrap() == nil {
+		t.Error("Unwrap() = nil, want underlying validation failure")
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("errors.Is(err, ErrValidation) = false, want true")
+	}
+
+	_, err = client.LogBatch(context.Background(), []Event{
+		{UserID: "user_1", Action: "user.created"},
+		{UserID: "", Action: "user.created"},
+	})
+	validationErr, ok = AsValidationError(err)
+	if !ok || validationErr.Field != "events[1].user_id" {
+		t.Errorf("LogBatch() validation error = %v, want field events[1].user_id", err)
+	}
+
+	if _, ok := AsValidationError(errors.New("other")); ok {
+		t.Error("AsValidationError(other) ok = true, want false")
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"validation", fmt.Errorf("wrapped: %w", &ValidationError{Field: "action"}), true},
+		{"bad request", &APIError{HTTPStatus: 400, Code: ErrCodeValidationError}, true},
+		{"server error", &APIError{HTTPStatus: 503, Code: ErrCodeInternalError}, false},
+		{"rate limited", &APIError{HTTPStatus: 429, Code: ErrCodeRateLimited}, false},
+		{"network", &NetworkError{Op: "request", Err: errors.New("reset")}, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsPermanent(tt.err); got != tt.want {
+				t.Errorf("IsPermanent(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Log_ErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"validation_error","message":"invalid event","details":[` +
+			`{"field":"metadata.amount","code":"type","message":"must be a number"},` +
+			`{"field":"target_id","code":"too_long","message":"must be at most 255 characters"}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Log(context.Background(), Event{UserID: "user_123", Action: "user.created"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Log() error = %v, want *APIError", err)
+	}
+	if len(apiErr.Details) != 2 {
+		t.Fatalf("got %d details, want 2", len(apiErr.Details))
+	}
+
+	if got := apiErr.DetailsFor("metadata"); len(got) != 1 || got[0].Code != "type" {
+		t.Errorf("DetailsFor(metadata) = %+v, want the metadata.amount detail", got)
+	}
+	if got := apiErr.DetailsFor("target_id"); len(got) != 1 || got[0].Message != "must be at most 255 characters" {
+		t.Errorf("DetailsFor(target_id) = %+v", got)
+	}
+	if got := apiErr.DetailsFor("user_id"); len(got) != 0 {
+		t.Errorf("DetailsFor(user_id) = %+v, want none", got)
+	}
+	if got := FieldDetails(err); len(got) != 2 {
+		t.Errorf("FieldDetails() returned %d details, want 2", len(got))
+	}
+}
+
 func TestClient_Log_ContextCancellation(t *testing.T) {
 	t.Parallel()
 
@@ -237,6 +421,14 @@ func TestClient_List_EnhancedQueryFeatures(t *testing.T) {
 			wantQueryParam: "order",
 			wantValue:      "desc",
 		},
+		{
+			name: "order by action",
+			filter: EventFilter{
+				OrderBy: OrderByAction,
+			},
+			wantQueryParam: "order_by",
+			wantValue:      "action",
+		},
 	}
 
 	for _, tt := range tests {
@@ -267,6 +459,35 @@ func TestClient_List_EnhancedQueryFeatures(t *testing.T) {
 	}
 }
 
+func TestClient_List_InvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL("http://127.0.0.1:0"))
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+
+	tests := []struct {
+		filter EventFilter
+		field  string
+	}{
+		{EventFilter{Order: "newest"}, "order"},
+		{EventFilter{OrderBy: "metadata"}, "order_by"},
+		{EventFilter{Limit: 101}, "limit"},
+		{EventFilter{Limit: -1}, "limit"},
+		{EventFilter{Offset: -5}, "offset"},
+		{EventFilter{Cursor: "c2", Offset: 100}, "offset"},
+		{EventFilter{StartTime: &start, EndTime: &end}, "end_time"},
+		{EventFilter{Action: "user.**"}, "action"},
+	}
+	for _, tt := range tests {
+		_, err := client.List(context.Background(), tt.filter)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || vErr.Field != tt.field {
+			t.Errorf("List(%+v) error = %v, want *ValidationError for %s", tt.filter, err, tt.field)
+		}
+	}
+}
+
 func TestClient_List_TimeRangeFilters(t *testing.T) {
 	t.Parallel()
 
@@ -345,11 +566,11 @@ func TestClient_List_MetadataContainsFilter(t *testing.T) {
 	}
 }
 
-func TestClient_List_CursorPaginationPrecedence(t *testing.T) {
+func TestClient_List_CursorPagination(t *testing.T) {
 	t.Parallel()
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Verify cursor is sent and offset is NOT sent when both are provided
+		// Verify cursor is sent and offset is NOT sent
 		if got := r.URL.Query().Get("cursor"); got != "test_cursor_123" {
 			t.Errorf("cursor = %q, want test_cursor_123", got)
 		}
@@ -369,7 +590,6 @@ func TestClient_List_CursorPaginationPrecedence(t *testing.T) {
 
 	filter := EventFilter{
 		Cursor: "test_cursor_123",
-		Offset: 100, // Should be ignored
 	}
 
 	resp, err := client.List(context.Background(), filter)
@@ -383,6 +603,40 @@ func TestClient_List_CursorPaginationPrecedence(t *testing.T) {
 	}
 }
 
+func TestClient_List_IncludeTotal(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include_total") != "true" {
+			w.Write([]byte(`{"events":[],"has_more":true,"next_cursor":"c2"}`))
+			return
+		}
+		w.Write([]byte(`{"events":[],"has_more":true,"next_cursor":"c2","total":120000,"total_accuracy":"estimated"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.List(context.Background(), EventFilter{IncludeTotal: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if resp.Total != 120000 || resp.TotalAccuracy != TotalEstimated {
+		t.Errorf("Total = %d (%q), want 120000 (estimated)", resp.Total, resp.TotalAccuracy)
+	}
+
+	resp, err = client.List(context.Background(), EventFilter{Cursor: "c2"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if resp.Total != 0 || resp.TotalAccuracy != "" {
+		t.Errorf("Total = %d (%q) without IncludeTotal, want none", resp.Total, resp.TotalAccuracy)
+	}
+}
+
 func TestClient_List_BackwardCompatibility(t *testing.T) {
 	t.Parallel()
 
@@ -417,3 +671,193 @@ func TestClient_List_BackwardCompatibility(t *testing.T) {
 		t.Errorf("Total = %d, want 10", resp.Total)
 	}
 }
+
+func TestClient_DryRun(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request in dry-run mode: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithDryRun())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Log(context.Background(), Event{UserID: "user_123", Action: "user.created"})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if !strings.HasPrefix(resp.ID, "evt_dryrun_") {
+		t.Errorf("got ID %q, want evt_dryrun_ prefix", resp.ID)
+	}
+
+	_, err = client.Log(context.Background(), Event{UserID: "user_123", Action: "Invalid Action"})
+	if !IsClientValidationError(err) {
+		t.Errorf("Log() with invalid action error = %v, want ValidationError", err)
+	}
+
+	batch, err := client.LogBatch(context.Background(), []Event{
+		{UserID: "user_1", Action: "user.created"},
+		{UserID: "user_2", Action: "user.created"},
+	})
+	if err != nil {
+		t.Fatalf("LogBatch() error = %v", err)
+	}
+	if len(batch.Succeeded()) != 2 {
+		t.Errorf("got %d succeeded items, want 2", len(batch.Succeeded()))
+	}
+}
+
+func TestClient_LogFireAndForget(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		json.NewDecoder(r.Body).Decode(&event)
+		if event.UserID == "user_bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"validation_error","message":"rejected"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	type failure struct {
+		event Event
+		err   error
+	}
+	failures := make(chan failure, 2)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithAsyncErrorHandler(func(event Event, err error) {
+			failures <- failure{event, err}
+		}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	client.LogFireAndForget(context.Background(), Event{UserID: "user_ok", Action: "user.created"})
+	client.LogFireAndForget(context.Background(), Event{UserID: "user_bad", Action: "user.created"})
+
+	select {
+	case f := <-failures:
+		if f.event.UserID != "user_bad" {
+			t.Errorf("handler got event for %q, want user_bad", f.event.UserID)
+		}
+		if !IsValidationError(f.err) {
+			t.Errorf("handler got error %v, want validation error", f.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for async error handler")
+	}
+
+	select {
+	case f := <-failures:
+		t.Errorf("unexpected second failure: %+v", f)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClient_CloseRejectsCallsDuringFinalFlush(t *testing.T) {
+	t.Parallel()
+
+	flushing := make(chan struct{})
+	release := make(chan struct{})
+	var batches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if batches.Add(1) == 1 {
+			close(flushing)
+			<-release
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`{"results":[{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithBatching(BatchConfig{MaxBatchSize: 10, FlushInterval: time.Hour}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	event := Event{UserID: "user_123", Action: "user.created"}
+	pending := client.LogAsync(ctx, event)
+
+	closed := make(chan error, 1)
+	go func() { closed <- client.Close() }()
+	<-flushing
+
+	// The final flush is in progress; new calls must not slip in behind it.
+	if _, err := client.Log(ctx, event); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Log() during Close error = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.LogAsync(ctx, event).Wait(ctx); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("LogAsync() during Close error = %v, want ErrClientClosed", err)
+	}
+	close(release)
+
+	if err := <-closed; err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := pending.Wait(ctx); err != nil {
+		t.Errorf("event queued before Close: error = %v, want delivered by the final flush", err)
+	}
+	if n := batches.Load(); n != 1 {
+		t.Errorf("batches sent = %d, want 1", n)
+	}
+}
+
+func TestClient_UseAfterClose(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`{"results":[{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	for _, batching := range []bool{false, true} {
+		opts := []Option{WithBaseURL(server.URL)}
+		if batching {
+			opts = append(opts, WithBatching(BatchConfig{MaxBatchSize: 10, FlushInterval: time.Hour}))
+		}
+		client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", opts...)
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := client.Close(); err != nil {
+					t.Errorf("Close() error = %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		ctx := context.Background()
+		event := Event{UserID: "user_123", Action: "user.created"}
+		if _, err := client.Log(ctx, event); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Log() after Close (batching %v) error = %v, want ErrClientClosed", batching, err)
+		}
+		if _, err := client.LogAsync(ctx, event).Wait(ctx); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("LogAsync() after Close (batching %v) error = %v, want ErrClientClosed", batching, err)
+		}
+		if _, err := client.List(ctx, EventFilter{}); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("List() after Close (batching %v) error = %v, want ErrClientClosed", batching, err)
+		}
+		if _, err := client.Flush(ctx); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Flush() after Close (batching %v) error = %v, want ErrClientClosed", batching, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package tryl
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// dateResolution is the precision of the HTTP Date header. Skew smaller
+// than this cannot be measured and is reported as zero.
+const dateResolution = time.Second
+
+// clockSkew estimates the offset between the server clock and the local
+// clock from the Date header of API responses.
+type clockSkew struct {
+	mu     sync.Mutex
+	skew   time.Duration
+	warned bool
+	now    func() time.Time
+	warnAt time.Duration
+	onWarn func(skew time.Duration)
+}
+
+// observe records a sample from a response's Date header.
+func (s *clockSkew) observe(header http.Header) {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	// Date is truncated to the second, so the server's clock lies somewhere
+	// in [date, date+1s); use the midpoint.
+	skew := date.Add(dateResolution / 2).Sub(s.now())
+	if skew > -dateResolution && skew < dateResolution {
+		skew = 0
+	}
+
+	s.mu.Lock()
+	s.skew = skew
+	exceeded := s.onWarn != nil && absDuration(skew) > s.warnAt
+	notify := exceeded && !s.warned
+	s.warned = exceeded
+	s.mu.Unlock()
+
+	if notify {
+		s.onWarn(skew)
+	}
+}
+
+// get returns the latest skew estimate.
+func (s *clockSkew) get() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skew
+}
+
+// ClockSkew returns the estimated offset of the server's clock from the
+// local clock, measured from the Date header of the latest API response.
+// A positive value means the server is ahead. It is zero until a response
+// has been received, and skew under one second is reported as zero.
+func (c *Client) ClockSkew() time.Duration {
+	return c.skew.get()
+}
+
+// toServerTime converts a locally computed time to the server's clock when
+// WithClockSkewCorrection is enabled.
+func (c *Client) toServerTime(t *time.Time) *time.Time {
+	if t == nil || !c.config.skewCorrection {
+		return t
+	}
+	adjusted := t.Add(c.skew.get())
+	return &adjusted
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
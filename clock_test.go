@@ -0,0 +1,111 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// skewedServer returns a server whose Date header is offset from real time.
+func skewedServer(t *testing.T, offset time.Duration, onQuery func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if onQuery != nil {
+			onQuery(r)
+		}
+		w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"events": []any{}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ClockSkew(t *testing.T) {
+	t.Parallel()
+
+	var warned []time.Duration
+	server := skewedServer(t, time.Hour, nil)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithClockSkewWarning(time.Minute, func(skew time.Duration) {
+			warned = append(warned, skew)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if got := client.ClockSkew(); got != 0 {
+		t.Errorf("ClockSkew() before any request = %v, want 0", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.List(context.Background(), EventFilter{}); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+	}
+
+	skew := client.ClockSkew()
+	if skew < time.Hour-2*time.Second || skew > time.Hour+2*time.Second {
+		t.Errorf("ClockSkew() = %v, want about 1h", skew)
+	}
+	if len(warned) != 1 {
+		t.Errorf("warning called %d times, want 1", len(warned))
+	}
+}
+
+func TestClient_ClockSkew_SubSecond(t *testing.T) {
+	t.Parallel()
+
+	server := skewedServer(t, 0, nil)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := client.List(context.Background(), EventFilter{}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := client.ClockSkew(); got != 0 {
+		t.Errorf("ClockSkew() = %v, want 0", got)
+	}
+}
+
+func TestClient_ClockSkewCorrection(t *testing.T) {
+	t.Parallel()
+
+	var startTimes []string
+	server := skewedServer(t, -time.Hour, func(r *http.Request) {
+		startTimes = append(startTimes, r.URL.Query().Get("start_time"))
+	})
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithClockSkewCorrection(),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if _, err := client.List(context.Background(), EventFilter{StartTime: &start}); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+	}
+
+	// The first request has no skew measurement yet.
+	if startTimes[0] != "2026-03-01T12:00:00Z" {
+		t.Errorf("first start_time = %q, want unadjusted", startTimes[0])
+	}
+	got, err := time.Parse(time.RFC3339, startTimes[1])
+	if err != nil {
+		t.Fatalf("parse start_time: %v", err)
+	}
+	if diff := start.Sub(got); diff < time.Hour-2*time.Second || diff > time.Hour+2*time.Second {
+		t.Errorf("second start_time = %v, want about 1h earlier than %v", got, start)
+	}
+}
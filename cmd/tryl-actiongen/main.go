@@ -0,0 +1,51 @@
+// Command tryl-actiongen generates typed action constants, metadata structs,
+// and a tryl.Taxonomy from an action registry file.
+//
+// Usage with go:generate:
+//
+//	//go:generate go run github.com/joshuawatkins04/tryl_sdk/cmd/tryl-actiongen -in actions.yaml -out actions_gen.go
+//
+// The registry is a YAML or JSON file; see internal/actiongen for the
+// format.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/actiongen"
+)
+
+func main() {
+	in := flag.String("in", "actions.json", "path to the action registry (YAML or JSON)")
+	out := flag.String("out", "actions_gen.go", "path of the generated Go file")
+	pkg := flag.String("package", "", "override the registry's package name")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "tryl-actiongen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg string) error {
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+
+	reg, err := actiongen.Parse(data)
+	if err != nil {
+		return err
+	}
+	if pkg != "" {
+		reg.Package = pkg
+	}
+
+	src, err := actiongen.Generate(reg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
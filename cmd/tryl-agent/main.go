@@ -0,0 +1,121 @@
+// Command tryl-agent is a local forwarding agent for the Activity Logger.
+// Applications configured with tryl.WithAgent send it their events, which
+// it acknowledges as soon as they are queued and forwards to the API in
+// batches, retrying failures and spilling to disk under backpressure.
+//
+// Usage:
+//
+//	TRYL_API_KEY=actlog_live_... tryl-agent -listen unix:///var/run/tryl-agent.sock -spool /var/lib/tryl-agent
+//
+// and in the application:
+//
+//	client, err := tryl.NewClient(apiKey, tryl.WithAgent("unix:///var/run/tryl-agent.sock"))
+//
+// Events are forwarded with the agent's TRYL_API_KEY, so it refuses
+// requests made with any other key; run one agent per project. The agent
+// serves Prometheus metrics at /metrics and its queue state at /healthz.
+// It listens on localhost:4327 by default, clear of the OpenTelemetry
+// collector's 4317 and 4318. See internal/agent for delivery guarantees.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+	"github.com/joshuawatkins04/tryl_sdk/internal/agent"
+)
+
+func main() {
+	listen := flag.String("listen", "localhost:4327", "address to listen on, host:port or unix:///path/to.sock")
+	baseURL := flag.String("base-url", "", "API base URL (default: the SDK's)")
+	spool := flag.String("spool", "", "directory to spill events to (default: no spilling)")
+	maxQueue := flag.Int("max-queue", 10000, "events held in memory")
+	batchSize := flag.Int("batch-size", 100, "events sent per batch")
+	interval := flag.Duration("flush-interval", time.Second, "how often queued events are sent")
+	flag.Parse()
+
+	if err := run(*listen, *baseURL, agent.Config{
+		SpoolDir:      *spool,
+		MaxQueue:      *maxQueue,
+		BatchSize:     *batchSize,
+		FlushInterval: *interval,
+		Logf:          log.Printf,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "tryl-agent: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(listen, baseURL string, config agent.Config) error {
+	apiKey := os.Getenv("TRYL_API_KEY")
+	if apiKey == "" {
+		return errors.New("TRYL_API_KEY is required")
+	}
+	opts := []tryl.Option{}
+	if baseURL != "" {
+		opts = append(opts, tryl.WithBaseURL(baseURL))
+	}
+	client, err := tryl.NewClient(apiKey, opts...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	config.Client = client
+	config.APIKey = apiKey
+	a, err := agent.New(config)
+	if err != nil {
+		return err
+	}
+
+	listener, err := listenOn(listen)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: a, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The agent stops after the server, so events accepted during
+	// shutdown are still sent or spooled.
+	agentCtx, stopAgent := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- a.Run(agentCtx) }()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+	log.Printf("tryl-agent: listening on %s", listen)
+
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = server.Shutdown(shutdownCtx)
+		cancel()
+	}
+	stopAgent()
+	return errors.Join(err, <-done)
+}
+
+// listenOn listens on a host:port address or a unix:// socket path,
+// replacing a stale socket file.
+func listenOn(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix://")
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	os.Remove(path)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,201 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CompactionConfig configures local aggregation of counter-like actions;
+// see WithCompaction.
+type CompactionConfig struct {
+	// Actions are the action patterns to compact (e.g., "api.request",
+	// "heartbeat.*").
+	Actions []string
+
+	// Interval is how often compacted counts are sent.
+	// Default: 1 minute
+	Interval time.Duration
+}
+
+// compactionKey identifies the events rolled up into one event: those of
+// the same action, user, tenant, actor, and target.
+type compactionKey struct {
+	userID, tenantID, action, actorID, targetType, targetID string
+}
+
+// compactedEvent is the rolled-up event of one key in the current window.
+type compactedEvent struct {
+	event Event
+	count int
+}
+
+// compactionMetadata is the metadata of a rolled-up event.
+type compactionMetadata struct {
+	Count       int       `json:"count"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+}
+
+// compactor counts events of compacted actions and periodically sends
+// one event per key with the count.
+type compactor struct {
+	client *Client
+	config CompactionConfig
+
+	mu     sync.Mutex
+	events map[compactionKey]*compactedEvent
+	start  time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func newCompactor(client *Client, config CompactionConfig) *compactor {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	c := &compactor{
+		client: client,
+		config: config,
+		events: make(map[compactionKey]*compactedEvent),
+		start:  client.config.clock.Now().UTC(),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	ticker := client.config.clock.NewTicker(config.Interval)
+	client.goLabeled(context.Background(), "compactor", func(context.Context) { c.run(ticker) })
+	return c
+}
+
+// rollup returns the event sent for the key of event, without its
+// metadata. Fields besides the key are dropped; the tags of the first
+// event in the window are kept.
+func rollup(event Event) Event {
+	return Event{
+		UserID:     event.UserID,
+		TenantID:   event.TenantID,
+		Action:     event.Action,
+		ActorID:    event.ActorID,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		Tags:       event.Tags,
+	}
+}
+
+// add counts event, which is a validated rollup.
+func (c *compactor) add(event Event) {
+	key := compactionKey{event.UserID, event.TenantID, event.Action, event.ActorID, event.TargetType, event.TargetID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ce, ok := c.events[key]
+	if !ok {
+		ce = &compactedEvent{event: rollup(event)}
+		c.events[key] = ce
+	}
+	ce.count++
+}
+
+func (c *compactor) matches(action string) bool {
+	for _, pattern := range c.config.Actions {
+		if MatchesAction(pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// compact counts event if its action is compacted, reporting whether it
+// was. The rolled-up event is validated first, as it will be sent, so an
+// event the flush would reject, such as one whose action is not in the
+// taxonomy, fails its own call rather than reaching only the async error
+// handler.
+func (c *Client) compact(event Event) (bool, error) {
+	if c.compactor == nil || !c.compactor.matches(event.Action) {
+		return false, nil
+	}
+	r := rollup(event)
+	r.Metadata, _ = json.Marshal(compactionMetadata{Count: 1})
+	if err := c.validateEvent(&r); err != nil {
+		return true, err
+	}
+	c.compactor.add(r)
+	return true, nil
+}
+
+// run sends the compacted events every interval until stopped.
+func (c *compactor) run(ticker Ticker) {
+	defer close(c.doneCh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			c.flush(context.Background())
+		case <-c.stopCh:
+			c.flush(finalFlushContext())
+			return
+		}
+	}
+}
+
+// flush sends one event per key counted since the last flush and starts
+// a new window. Delivery failures are passed to the async error handler.
+func (c *compactor) flush(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	counted := c.events
+	start, end := c.start, c.client.config.clock.Now().UTC()
+	c.events = make(map[compactionKey]*compactedEvent)
+	c.start = end
+	c.mu.Unlock()
+
+	events := make([]Event, 0, len(counted))
+	for _, ce := range counted {
+		event := ce.event
+		event.Metadata, _ = json.Marshal(compactionMetadata{Count: ce.count, WindowStart: start, WindowEnd: end})
+		events = append(events, event)
+	}
+
+	var errs []error
+	for len(events) > 0 {
+		n := min(len(events), 100)
+		batch := events[:n]
+		events = events[n:]
+
+		result, err := c.client.LogBatch(ctx, batch)
+		if err != nil && result == nil {
+			c.reportError(batch, err)
+			errs = append(errs, err)
+			continue
+		}
+		for _, item := range result.Failed() {
+			c.reportError(batch[item.Index:item.Index+1], item.Error)
+			errs = append(errs, item.Error)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *compactor) reportError(events []Event, err error) {
+	if handler := c.client.config.asyncErrorHandler; handler != nil {
+		for _, event := range events {
+			handler(event, err)
+		}
+	}
+}
+
+// stop sends the remaining counts and stops the compactor.
+func (c *compactor) stop() {
+	if c == nil {
+		return
+	}
+	c.stopOnce.Do(func() { close(c.stopCh) })
+	<-c.doneCh
+}
@@ -0,0 +1,139 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_WithCompaction(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var single int
+	var rolled []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.HasSuffix(r.URL.Path, "/batch") {
+			single++
+			w.Write([]byte(`{"id":"evt_1"}`))
+			return
+		}
+		var req batchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		rolled = append(rolled, req.Events...)
+		resp := batchResponse{Results: make([]EventResponse, len(req.Events))}
+		for i := range resp.Results {
+			resp.Results[i].ID = "evt_rollup"
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithCompaction(CompactionConfig{Actions: []string{"api.*"}, Interval: time.Hour}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	for _, user := range []string{"user_1", "user_1", "user_2", "user_1"} {
+		resp, err := client.Log(ctx, Event{UserID: user, Action: "api.request"})
+		if err != nil || resp.ID != "" {
+			t.Fatalf("Log() compacted = %+v, %v, want a response without ID", resp, err)
+		}
+	}
+	if _, err := client.Log(ctx, Event{Action: "api.request"}); err == nil {
+		t.Error("expected validation error for compacted event without user")
+	}
+	if _, err := client.Log(ctx, Event{UserID: "user_1", Action: "user.created"}); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if _, err := client.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if single != 1 {
+		t.Errorf("single event requests = %d, want 1", single)
+	}
+	counts := map[string]int{}
+	for _, e := range rolled {
+		var m compactionMetadata
+		if err := json.Unmarshal(e.Metadata, &m); err != nil {
+			t.Fatalf("rolled-up metadata = %s: %v", e.Metadata, err)
+		}
+		if e.Action != "api.request" || m.WindowEnd.Before(m.WindowStart) {
+			t.Errorf("rolled-up event = %+v, metadata %+v", e, m)
+		}
+		counts[e.UserID] = m.Count
+	}
+	if len(rolled) != 2 || counts["user_1"] != 3 || counts["user_2"] != 1 {
+		t.Errorf("rolled-up counts = %v from %d events, want user_1: 3, user_2: 1", counts, len(rolled))
+	}
+}
+
+func TestClient_WithCompaction_ValidatesRollup(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithCompaction(CompactionConfig{Actions: []string{"api.*"}, Interval: time.Hour}),
+		WithTaxonomy(MustTaxonomy(ActionDef{Name: "api.request"})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Log(context.Background(), Event{UserID: "user_1", Action: "api.unknown"}); !IsClientValidationError(err) {
+		t.Errorf("Log() error = %v, want a taxonomy ValidationError", err)
+	}
+	// The server fails the test if the rejected event is rolled up and sent.
+	if _, err := client.Flush(context.Background()); err != nil {
+		t.Errorf("Flush() error = %v", err)
+	}
+}
+
+func TestClient_WithCompaction_UsesClock(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"id":"evt_rollup"}]}`))
+	}))
+	defer server.Close()
+
+	clock := &stepClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithClock(clock),
+		WithCompaction(CompactionConfig{Actions: []string{"api.*"}, Interval: time.Hour}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	resp, err := client.Log(context.Background(), Event{UserID: "user_1", Action: "api.request"})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if !resp.Timestamp.Equal(clock.Now()) {
+		t.Errorf("Timestamp = %v, want the injected clock's %v", resp.Timestamp, clock.Now())
+	}
+}
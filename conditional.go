@@ -0,0 +1,134 @@
+package tryl
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ChangeStore records the last value LogChanged saw for each key. Set one
+// shared by several processes, such as a Redis-backed store, with
+// WithChangeStore. Implementations must be safe for concurrent use.
+type ChangeStore interface {
+	// Swap stores value under key and returns the value it replaced, with
+	// loaded reporting whether there was one.
+	Swap(ctx context.Context, key, value string) (previous string, loaded bool, err error)
+	// Delete removes key.
+	Delete(ctx context.Context, key string) error
+}
+
+// defaultChangeStoreEntries is the capacity of the default ChangeStore.
+const defaultChangeStoreEntries = 10000
+
+// MemoryChangeStore is an in-process ChangeStore that keeps the most
+// recently used keys. It is the default store of LogChanged.
+type MemoryChangeStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// changeEntry is a key and value stored in a MemoryChangeStore.
+type changeEntry struct {
+	key, value string
+}
+
+// NewMemoryChangeStore returns a MemoryChangeStore holding up to
+// maxEntries keys, evicting the least recently used. LogChanged logs the
+// event again for an evicted key. A non-positive maxEntries means 10000.
+func NewMemoryChangeStore(maxEntries int) *MemoryChangeStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultChangeStoreEntries
+	}
+	return &MemoryChangeStore{
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Swap implements ChangeStore.
+func (s *MemoryChangeStore) Swap(ctx context.Context, key, value string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		entry := el.Value.(*changeEntry)
+		previous := entry.value
+		entry.value = value
+		s.lru.MoveToFront(el)
+		return previous, true, nil
+	}
+	s.entries[key] = s.lru.PushFront(&changeEntry{key: key, value: value})
+	if s.lru.Len() > s.maxEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*changeEntry).key)
+	}
+	return "", false, nil
+}
+
+// Delete implements ChangeStore.
+func (s *MemoryChangeStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		s.lru.Remove(el)
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// LogIf logs event if cond is true. Otherwise it returns a nil response
+// and nil error without sending anything.
+func (c *Client) LogIf(ctx context.Context, cond bool, event Event) (*EventResponse, error) {
+	if !cond {
+		return nil, nil
+	}
+	return c.Log(ctx, event)
+}
+
+// LogChanged logs event only if value differs from the value last passed
+// with key, cutting duplicate events such as repeated "settings.viewed"
+// for unchanged settings. Values are compared by their JSON encoding. The
+// first call for a key always logs. If Log fails, the previous value is
+// restored so the next call logs again.
+//
+// When the value is unchanged, LogChanged returns a nil response and nil
+// error. Keys are remembered in the store set with WithChangeStore, by
+// default an in-process MemoryChangeStore.
+func (c *Client) LogChanged(ctx context.Context, key string, value any, event Event) (*EventResponse, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	store := c.config.changeStore
+	previous, loaded, err := store.Swap(ctx, key, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("change store: %w", err)
+	}
+	if loaded && previous == fingerprint {
+		return nil, nil
+	}
+
+	resp, err := c.Log(ctx, event)
+	if err != nil {
+		// Restore the previous value; the event's error matters more than
+		// a failure to restore it.
+		if loaded {
+			store.Swap(ctx, key, previous)
+		} else {
+			store.Delete(ctx, key)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,111 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_LogIf(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-03-01T12:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	ctx := context.Background()
+	event := Event{UserID: "user_1", Action: "settings.viewed"}
+
+	if resp, err := client.LogIf(ctx, false, event); resp != nil || err != nil {
+		t.Errorf("LogIf(false) = %v, %v, want nil, nil", resp, err)
+	}
+	if resp, err := client.LogIf(ctx, true, event); err != nil || resp.ID != "evt_1" {
+		t.Errorf("LogIf(true) = %v, %v", resp, err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestClient_LogChanged(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"invalid_request","message":"rejected"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-03-01T12:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL),
+		WithChangeStore(NewMemoryChangeStore(10)))
+	ctx := context.Background()
+	event := Event{UserID: "user_1", Action: "settings.viewed"}
+	type settings struct{ Theme string }
+
+	steps := []struct {
+		key    string
+		value  any
+		fail   bool
+		logged bool
+	}{
+		{"user_1", settings{"dark"}, false, true},
+		{"user_1", settings{"dark"}, false, false},
+		{"user_2", settings{"dark"}, false, true},
+		{"user_1", settings{"light"}, true, true},
+		// The failed Log restored "dark", so "light" is still a change.
+		{"user_1", settings{"light"}, false, true},
+		{"user_1", settings{"light"}, false, false},
+	}
+	for i, step := range steps {
+		fail.Store(step.fail)
+		before := requests.Load()
+		resp, err := client.LogChanged(ctx, step.key, step.value, event)
+		logged := requests.Load() > before
+		if logged != step.logged {
+			t.Errorf("step %d: logged = %v, want %v", i, logged, step.logged)
+		}
+		if (err != nil) != step.fail {
+			t.Errorf("step %d: error = %v", i, err)
+		}
+		if !step.logged && resp != nil {
+			t.Errorf("step %d: response = %v for a skipped event", i, resp)
+		}
+	}
+
+	if _, err := client.LogChanged(ctx, "user_1", func() {}, event); err == nil {
+		t.Error("expected error for a value that cannot be encoded")
+	}
+}
+
+func TestMemoryChangeStore_Evicts(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryChangeStore(2)
+	ctx := context.Background()
+	store.Swap(ctx, "a", "1")
+	store.Swap(ctx, "b", "1")
+	store.Swap(ctx, "a", "2")
+	store.Swap(ctx, "c", "1")
+
+	if _, loaded, _ := store.Swap(ctx, "b", "1"); loaded {
+		t.Error("least recently used key b was not evicted")
+	}
+	if previous, loaded, _ := store.Swap(ctx, "c", "2"); !loaded || previous != "1" {
+		t.Errorf("Swap(c) = %q, %v, want 1, true", previous, loaded)
+	}
+}
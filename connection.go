@@ -0,0 +1,142 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// idleCloser is implemented by *http.Client and *http.Transport.
+type idleCloser interface {
+	CloseIdleConnections()
+}
+
+// recyclingDoer closes the idle connections of an HTTP client once per
+// lifetime, so new requests dial afresh and re-resolve DNS instead of
+// reusing connections to backends that may have been replaced.
+type recyclingDoer struct {
+	next     HTTPDoer
+	closer   idleCloser
+	lifetime time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	renewed time.Time
+}
+
+func newRecyclingDoer(next HTTPDoer, lifetime time.Duration) HTTPDoer {
+	closer, ok := next.(idleCloser)
+	if !ok {
+		// Connections of a custom HTTPDoer cannot be recycled.
+		return next
+	}
+	return &recyclingDoer{next: next, closer: closer, lifetime: lifetime, now: time.Now, renewed: time.Now()}
+}
+
+func (d *recyclingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.mu.Lock()
+	if now := d.now(); now.Sub(d.renewed) >= d.lifetime {
+		d.renewed = now
+		d.closer.CloseIdleConnections()
+	}
+	d.mu.Unlock()
+	return d.next.Do(req)
+}
+
+// unixSocketHost is the host of requests sent over a Unix domain socket.
+const unixSocketHost = "localhost"
+
+// socketPath returns the socket path of a unix:// URL, and false for
+// other URLs.
+func socketPath(url string) (string, bool, error) {
+	path, ok := strings.CutPrefix(url, "unix:")
+	if !ok {
+		return "", false, nil
+	}
+	// Options trim the trailing slash, so "unix://" arrives as "unix:/".
+	path = strings.TrimPrefix(path, "//")
+	if path == "" || path == "/" {
+		return "", true, errors.New("unix URL must include a socket path")
+	}
+	return path, true, nil
+}
+
+// resolveSocket replaces a unix:// base URL with an HTTP one and records
+// the socket path for dialContext. Only the default HTTP client dials the
+// socket, so a client set with WithHTTPClient would send the requests to
+// localhost over TCP instead.
+func (c *clientConfig) resolveSocket() error {
+	path, ok, err := socketPath(c.baseURL)
+	if !ok || err != nil {
+		return err
+	}
+	if c.httpClient != nil {
+		return errors.New("a unix:// base URL cannot be combined with WithHTTPClient; dial the socket in the custom client's transport and use an http:// base URL")
+	}
+	c.socketPath = path
+	c.baseURL = "http://" + unixSocketHost
+	return nil
+}
+
+// agentClient returns the base URL and HTTP client for requests to the
+// agent set with WithAgent.
+func (c *clientConfig) agentClient() (string, HTTPDoer, error) {
+	path, ok, err := socketPath(c.agentURL)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		return c.agentURL, &http.Client{Timeout: c.timeout}, nil
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "unix", path)
+	}
+	return "http://" + unixSocketHost, &http.Client{Timeout: c.timeout, Transport: t}, nil
+}
+
+// dialContext returns the dial function for the client's connections,
+// or nil to dial as usual.
+func (c *clientConfig) dialContext() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dial := c.dialer
+	switch {
+	case dial == nil && c.dialTimeout > 0:
+		dial = (&net.Dialer{Timeout: c.dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	case dial == nil && c.socketPath != "":
+		dial = (&net.Dialer{}).DialContext
+	case dial != nil && c.dialTimeout > 0:
+		custom, timeout := dial, c.dialTimeout
+		dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return custom(ctx, network, addr)
+		}
+	}
+	if c.socketPath == "" {
+		return dial
+	}
+	path, next := c.socketPath, dial
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return next(ctx, "unix", path)
+	}
+}
+
+// httpTransport returns the round tripper for the client's default HTTP
+// client, or nil to use http.DefaultTransport. Connection settings need a
+// transport of the client's own, so recycling connections does not affect
+// other users of the shared default.
+func (c *clientConfig) httpTransport() http.RoundTripper {
+	dial := c.dialContext()
+	if c.connMaxLifetime <= 0 && dial == nil {
+		return nil
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if dial != nil {
+		t.DialContext = dial
+	}
+	return t
+}
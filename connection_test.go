@@ -0,0 +1,134 @@
+package tryl
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WithConnectionMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithConnectionMaxLifetime(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	doer := client.transport.HTTPClient.(*recyclingDoer)
+	now := time.Now()
+	doer.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	list := func() {
+		if _, err := client.List(ctx, EventFilter{}); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+	}
+
+	list()
+	list()
+	if got := conns.Load(); got != 1 {
+		t.Errorf("connections within lifetime = %d, want 1", got)
+	}
+
+	now = now.Add(time.Hour)
+	list()
+	if got := conns.Load(); got != 2 {
+		t.Errorf("connections after lifetime = %d, want 2", got)
+	}
+}
+
+func TestWithConnectionMaxLifetime_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithConnectionMaxLifetime(0)); err == nil {
+		t.Error("expected error for zero lifetime")
+	}
+}
+
+func TestClient_UnixSocketBaseURL(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "tryl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "tryl.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL("unix://"+socket))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := client.List(context.Background(), EventFilter{}); err != nil {
+		t.Fatalf("List() over unix socket error = %v", err)
+	}
+
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL("unix://")); err == nil {
+		t.Error("expected error for unix base URL without a socket path")
+	}
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL("unix://"+socket), WithHTTPClient(http.DefaultClient)); err == nil {
+		t.Error("expected error for unix base URL with a custom HTTP client")
+	}
+}
+
+func TestClient_WithDialer(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	var dialed atomic.Value
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL("http://collector.internal"),
+		WithDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed.Store(addr)
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := client.List(context.Background(), EventFilter{}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got, _ := dialed.Load().(string); got != "collector.internal:80" {
+		t.Errorf("dialed %q, want collector.internal:80", got)
+	}
+
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithDialer(nil)); err == nil {
+		t.Error("expected error for nil dialer")
+	}
+}
@@ -0,0 +1,35 @@
+package tryl
+
+import "context"
+
+// correlationKey is the context key for ContextWithCorrelationID.
+type correlationKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id. Events logged
+// with the returned context get id as their CorrelationID unless they set
+// one themselves.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFromContext returns the ID set by ContextWithCorrelationID,
+// or "" if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// correlate sets event.CorrelationID from ctx if it is empty: first from
+// ContextWithCorrelationID, then from the WithCorrelationIDFunc function.
+func (c *Client) correlate(ctx context.Context, event *Event) {
+	if event.CorrelationID != "" {
+		return
+	}
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		event.CorrelationID = id
+		return
+	}
+	if fn := c.config.correlationFunc; fn != nil {
+		event.CorrelationID = fn(ctx)
+	}
+}
@@ -0,0 +1,97 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type traceKey struct{}
+
+func TestClient_CorrelationID(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/v1/events/batch" {
+			var req struct{ Events []Event }
+			json.NewDecoder(r.Body).Decode(&req)
+			for _, e := range req.Events {
+				got = append(got, e.CorrelationID)
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]any{"results": make([]EventResponse, len(req.Events))})
+			return
+		}
+		var e Event
+		json.NewDecoder(r.Body).Decode(&e)
+		got = append(got, e.CorrelationID)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithCorrelationIDFunc(func(ctx context.Context) string {
+			id, _ := ctx.Value(traceKey{}).(string)
+			return id
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	traced := context.WithValue(context.Background(), traceKey{}, "4bf92f3577b34da6a3ce929d0e0e4736")
+	explicit := ContextWithCorrelationID(traced, "req_123")
+	event := Event{UserID: "user_1", Action: "doc.viewed"}
+
+	client.Log(traced, event)
+	client.Log(explicit, event)
+	client.Log(explicit, Event{UserID: "user_1", Action: "doc.viewed", CorrelationID: "own"})
+	client.Log(context.Background(), event)
+	client.LogBatch(explicit, []Event{event})
+	if _, err := client.LogAsync(explicit, event).Wait(context.Background()); err != nil {
+		t.Fatalf("LogAsync() error = %v", err)
+	}
+
+	want := []string{"4bf92f3577b34da6a3ce929d0e0e4736", "req_123", "own", "", "req_123", "req_123"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != len(want) {
+		t.Fatalf("correlation IDs = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d correlation ID = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestClient_List_CorrelationID(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("correlation_id"); got != "req_123" {
+			t.Errorf("correlation_id = %q, want req_123", got)
+		}
+		json.NewEncoder(w).Encode(EventList{Events: []StoredEvent{{ID: "evt_1", CorrelationID: "req_123", Timestamp: time.Now()}}})
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	list, err := client.List(context.Background(), EventFilter{CorrelationID: "req_123"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if list.Events[0].CorrelationID != "req_123" {
+		t.Errorf("List() = %+v", list.Events)
+	}
+}
@@ -0,0 +1,154 @@
+package tryl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrOpaqueCursor is returned by DecodeCursor when a cursor's format does
+// not expose a position.
+var ErrOpaqueCursor = errors.New("tryl: cursor format is opaque")
+
+// CursorInfo is the position encoded in a pagination cursor.
+type CursorInfo struct {
+	// Timestamp is the timestamp of the boundary event. It is zero if the
+	// cursor does not encode one.
+	Timestamp time.Time
+	// ID is the ID of the boundary event, if the cursor encodes one.
+	ID string
+	// Offset is the number of events before the cursor, for offset-based
+	// cursors.
+	Offset int
+	// Resume reports whether the cursor is a ResumeCursor, as returned by
+	// EventList.ResumeCursor, rather than a server cursor.
+	Resume bool
+	// Fields holds every field decoded from a structured cursor, for
+	// formats with more than a timestamp and ID.
+	Fields map[string]any
+}
+
+// cursorTimeKeys and cursorIDKeys are the field names recognized as the
+// boundary timestamp and ID in structured cursors.
+var (
+	cursorTimeKeys = []string{"timestamp", "ts", "t", "after"}
+	cursorIDKeys   = []string{"id", "event_id", "last_id"}
+)
+
+// DecodeCursor returns the position encoded in a pagination cursor, for
+// debugging pagination and showing the progress of stored checkpoints.
+// Server cursors are opaque by contract and their format may change, so
+// never build cursors from a CursorInfo or rely on it for correctness.
+//
+// It understands base64-encoded JSON cursors, cursors of the form
+// "<timestamp>_<id>", offset cursors of the form "cur_<n>" as issued by
+// tryltest, and ResumeCursor strings. Other formats return
+// ErrOpaqueCursor.
+func DecodeCursor(cursor string) (CursorInfo, error) {
+	if cursor == "" {
+		return CursorInfo{}, &ValidationError{Field: "cursor", Message: "is required"}
+	}
+	if fields, ok := decodeCursorJSON(cursor); ok {
+		return cursorFromFields(fields), nil
+	}
+	if rest, ok := strings.CutPrefix(cursor, "cur_"); ok {
+		if n, err := strconv.Atoi(rest); err == nil && n >= 0 {
+			return CursorInfo{Offset: n}, nil
+		}
+	}
+	if ts, id, ok := strings.Cut(cursor, "_"); ok {
+		if t, ok := parseCursorTime(ts); ok {
+			return CursorInfo{Timestamp: t, ID: id}, nil
+		}
+	}
+	return CursorInfo{}, ErrOpaqueCursor
+}
+
+// decodeCursorJSON decodes a base64-encoded JSON object in any of the
+// common base64 alphabets.
+func decodeCursorJSON(cursor string) (map[string]any, bool) {
+	encodings := []*base64.Encoding{
+		base64.RawURLEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.StdEncoding,
+	}
+	for _, enc := range encodings {
+		data, err := enc.DecodeString(cursor)
+		if err != nil {
+			continue
+		}
+		var fields map[string]any
+		if json.Unmarshal(data, &fields) == nil && fields != nil {
+			return fields, true
+		}
+	}
+	return nil, false
+}
+
+// cursorFromFields extracts the boundary from decoded cursor fields.
+func cursorFromFields(fields map[string]any) CursorInfo {
+	info := CursorInfo{Fields: fields}
+	_, hasFilter := fields["filter"]
+	_, hasAfter := fields["after"]
+	info.Resume = hasFilter && hasAfter
+	for _, key := range cursorTimeKeys {
+		if t, ok := cursorFieldTime(fields[key]); ok {
+			info.Timestamp = t
+			break
+		}
+	}
+	for _, key := range cursorIDKeys {
+		if id, ok := fields[key].(string); ok && id != "" {
+			info.ID = id
+			break
+		}
+	}
+	if info.ID == "" && info.Resume {
+		// A ResumeCursor records the IDs seen at After; the last is the
+		// boundary.
+		if seen, ok := fields["seen_ids"].([]any); ok && len(seen) > 0 {
+			info.ID, _ = seen[len(seen)-1].(string)
+		}
+	}
+	if offset, ok := fields["offset"].(float64); ok {
+		info.Offset = int(offset)
+	}
+	return info
+}
+
+// cursorFieldTime converts a decoded JSON timestamp: an RFC 3339 string
+// or a Unix time in seconds, milliseconds, or nanoseconds.
+func cursorFieldTime(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case string:
+		return parseCursorTime(v)
+	case float64:
+		return unixCursorTime(int64(v)), v > 0
+	}
+	return time.Time{}, false
+}
+
+// parseCursorTime parses an RFC 3339 or Unix timestamp.
+func parseCursorTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, true
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+		return unixCursorTime(n), true
+	}
+	return time.Time{}, false
+}
+
+// unixCursorTime interprets n as Unix seconds, milliseconds, or
+// nanoseconds, by magnitude.
+func unixCursorTime(n int64) time.Time {
+	switch {
+	case n >= 1e17:
+		return time.Unix(0, n).UTC()
+	case n >= 1e11:
+		return time.UnixMilli(n).UTC()
+	default:
+		return time.Unix(n, 0).UTC()
+	}
+}
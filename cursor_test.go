@@ -0,0 +1,73 @@
+package tryl
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC)
+	resume := ResumeCursor{After: ts, SeenIDs: []string{"evt_1", "evt_2"}}.String()
+
+	tests := []struct {
+		name   string
+		cursor string
+		want   CursorInfo
+	}{
+		{
+			name:   "base64 JSON",
+			cursor: base64.StdEncoding.EncodeToString([]byte(`{"ts":"2026-01-30T10:00:00Z","id":"evt_9"}`)),
+			want:   CursorInfo{Timestamp: ts, ID: "evt_9"},
+		},
+		{
+			name:   "unix milliseconds",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"t":1769767200000,"event_id":"evt_9"}`)),
+			want:   CursorInfo{Timestamp: ts, ID: "evt_9"},
+		},
+		{
+			name:   "timestamp and ID",
+			cursor: "1769767200_evt_9",
+			want:   CursorInfo{Timestamp: ts, ID: "evt_9"},
+		},
+		{
+			name:   "offset",
+			cursor: "cur_40",
+			want:   CursorInfo{Offset: 40},
+		},
+		{
+			name:   "resume cursor",
+			cursor: resume,
+			want:   CursorInfo{Timestamp: ts, ID: "evt_2", Resume: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := DecodeCursor(tt.cursor)
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if !got.Timestamp.Equal(tt.want.Timestamp) || got.ID != tt.want.ID || got.Offset != tt.want.Offset || got.Resume != tt.want.Resume {
+				t.Errorf("DecodeCursor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_Opaque(t *testing.T) {
+	t.Parallel()
+
+	if _, err := DecodeCursor("next_cursor_456"); !errors.Is(err, ErrOpaqueCursor) {
+		t.Errorf("DecodeCursor() error = %v, want ErrOpaqueCursor", err)
+	}
+	var verr *ValidationError
+	if _, err := DecodeCursor(""); !errors.As(err, &verr) {
+		t.Errorf("DecodeCursor(\"\") error = %v, want *ValidationError", err)
+	}
+}
@@ -0,0 +1,100 @@
+package tryl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// clientSeq numbers clients for their default IDs.
+var clientSeq atomic.Uint64
+
+// pprof label keys set on the client's goroutines.
+const (
+	labelClient    = "tryl_client"
+	labelProject   = "tryl_project"
+	labelGoroutine = "tryl_goroutine"
+)
+
+// newClientID returns the default ID of a new client.
+func newClientID() string {
+	return "tryl-" + strconv.FormatUint(clientSeq.Add(1), 10)
+}
+
+// goLabeled runs fn in a new goroutine labeled with the client's ID,
+// project, and role, so CPU, goroutine, and memory profiles can be
+// attributed to the client.
+func (c *Client) goLabeled(ctx context.Context, role string, fn func(ctx context.Context)) {
+	labels := []string{labelClient, c.config.clientID, labelGoroutine, role}
+	if c.config.project != "" {
+		labels = append(labels, labelProject, c.config.project)
+	}
+	go pprof.Do(ctx, pprof.Labels(labels...), fn)
+}
+
+// DumpState writes a human-readable description of the client's internal
+// state to w: its ID and project, as used in pprof labels, the features
+// enabled, queue and cache sizes, and the counters of Stats. It is meant
+// for debugging, and its format may change.
+func (c *Client) DumpState(w io.Writer) error {
+	var b strings.Builder
+	line := func(key string, value any) {
+		fmt.Fprintf(&b, "  %s: %v\n", key, value)
+	}
+
+	fmt.Fprintf(&b, "tryl client %s\n", c.config.clientID)
+	if c.config.project != "" {
+		line("project", c.config.project)
+	}
+	line("version", Version)
+	line("base_url", c.transport.BaseURL)
+	line("closed", c.closed.Load())
+	line("features", strings.Join(c.config.features(), ","))
+	if c.batcher != nil {
+		c.batcher.mu.Lock()
+		stopped := c.batcher.stopped
+		c.batcher.mu.Unlock()
+		line("batch_queue", fmt.Sprintf("%d/%d", len(c.batcher.pending), cap(c.batcher.pending)))
+		line("batcher_stopped", stopped)
+	}
+	if c.compactor != nil {
+		c.compactor.mu.Lock()
+		pending := len(c.compactor.events)
+		c.compactor.mu.Unlock()
+		line("compaction_pending", pending)
+	}
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		entries := c.cache.lru.Len()
+		c.cache.mu.Unlock()
+		line("query_cache_entries", entries)
+	}
+	if c.etags != nil {
+		c.etags.mu.Lock()
+		entries := c.etags.lru.Len()
+		c.etags.mu.Unlock()
+		line("etag_cache_entries", entries)
+	}
+
+	s := c.Stats()
+	line("events_sent", s.EventsSent)
+	line("batches", s.Batches)
+	line("retries", s.Retries)
+	line("dropped", s.Dropped)
+	patterns := make([]string, 0, len(s.SuppressedEvents))
+	for pattern := range s.SuppressedEvents {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		line("suppressed["+pattern+"]", s.SuppressedEvents[pattern])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,70 @@
+package tryl
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_DumpState(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithClientID("billing-client"),
+		WithProjectLabel("billing"),
+		WithBatching(BatchConfig{MaxBatchSize: 10, FlushInterval: time.Hour, MaxPendingEvents: 50}),
+		WithQueryCache(time.Minute, 10),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	var state bytes.Buffer
+	if err := client.DumpState(&state); err != nil {
+		t.Fatalf("DumpState() error = %v", err)
+	}
+	for _, want := range []string{
+		"tryl client billing-client\n",
+		"  project: billing\n",
+		"  features: batching,retry,query_cache\n",
+		"  batch_queue: 0/50\n",
+		"  query_cache_entries: 0\n",
+		"  events_sent: 0\n",
+	} {
+		if !strings.Contains(state.String(), want) {
+			t.Errorf("DumpState() missing %q:\n%s", want, state.String())
+		}
+	}
+
+	// The batcher goroutine may not have started yet.
+	want := []string{`"tryl_client":"billing-client"`, `"tryl_project":"billing"`, `"tryl_goroutine":"batcher"`}
+	var profile string
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		var goroutines bytes.Buffer
+		pprof.Lookup("goroutine").WriteTo(&goroutines, 1)
+		if profile = goroutines.String(); strings.Contains(profile, want[2]) {
+			break
+		}
+	}
+	for _, label := range want {
+		if !strings.Contains(profile, label) {
+			t.Errorf("goroutine profile has no goroutine labeled %s", label)
+		}
+	}
+}
+
+func TestClient_DefaultClientID(t *testing.T) {
+	t.Parallel()
+
+	a, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef")
+	b, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef")
+	if a.config.clientID == b.config.clientID || !strings.HasPrefix(a.config.clientID, "tryl-") {
+		t.Errorf("client IDs = %q, %q, want distinct tryl-N IDs", a.config.clientID, b.config.clientID)
+	}
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithClientID("")); err == nil {
+		t.Error("expected error for empty client ID")
+	}
+}
@@ -0,0 +1,124 @@
+package tryl
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Warning describes a deprecation announced by the API in the headers of
+// a response.
+type Warning struct {
+	// Method and Path identify the request that received the warning.
+	Method string
+	Path   string
+	// Deprecated reports whether the endpoint is deprecated, from the
+	// Deprecation header.
+	Deprecated bool
+	// DeprecatedAt is when the endpoint was or will be deprecated, if the
+	// Deprecation header carries a date.
+	DeprecatedAt time.Time
+	// Sunset is when the endpoint will stop working, from the Sunset
+	// header. It is zero if not announced.
+	Sunset time.Time
+	// Link is the documentation for the deprecation, from a Link header
+	// with rel="deprecation" or rel="sunset".
+	Link string
+	// Message is the text of the X-API-Warn header.
+	Message string
+}
+
+// String formats the warning for logs.
+func (w Warning) String() string {
+	var b strings.Builder
+	b.WriteString("tryl: API warning for " + w.Method + " " + w.Path)
+	if w.Deprecated {
+		b.WriteString(": deprecated")
+		if !w.DeprecatedAt.IsZero() {
+			b.WriteString(" since " + w.DeprecatedAt.Format(time.DateOnly))
+		}
+	}
+	if !w.Sunset.IsZero() {
+		b.WriteString(": sunset on " + w.Sunset.Format(time.DateOnly))
+	}
+	if w.Message != "" {
+		b.WriteString(": " + w.Message)
+	}
+	if w.Link != "" {
+		b.WriteString(" (see " + w.Link + ")")
+	}
+	return b.String()
+}
+
+// linkRelPattern matches a Link header entry for deprecation documentation.
+var linkRelPattern = regexp.MustCompile(`<([^>]+)>\s*;[^,]*rel="?(?:deprecation|sunset)"?`)
+
+// parseWarning extracts a Warning from response headers. ok is false if
+// the response carries no warning.
+func parseWarning(header http.Header) (w Warning, ok bool) {
+	if v := strings.TrimSpace(header.Get("Deprecation")); v != "" && v != "false" {
+		w.Deprecated = true
+		// RFC 9745 uses "@<unix seconds>"; earlier drafts an HTTP date.
+		if rest, ok := strings.CutPrefix(v, "@"); ok {
+			if secs, err := strconv.ParseInt(rest, 10, 64); err == nil {
+				w.DeprecatedAt = time.Unix(secs, 0).UTC()
+			}
+		} else if t, err := http.ParseTime(v); err == nil {
+			w.DeprecatedAt = t
+		}
+	}
+	if t, err := http.ParseTime(header.Get("Sunset")); err == nil {
+		w.Sunset = t
+	}
+	w.Message = strings.TrimSpace(header.Get("X-API-Warn"))
+	for _, link := range header.Values("Link") {
+		if m := linkRelPattern.FindStringSubmatch(link); m != nil {
+			w.Link = m[1]
+			break
+		}
+	}
+	return w, w.Deprecated || !w.Sunset.IsZero() || w.Message != ""
+}
+
+// deprecations reports response warnings to the handler set with
+// WithDeprecationHandler, once per distinct warning and method. The path
+// is not part of the key, since paths carry resource IDs and the set of
+// warnings seen would grow with every resource requested.
+type deprecations struct {
+	onWarn func(Warning)
+
+	mu   sync.Mutex
+	seen map[Warning]bool
+}
+
+// observe reports the warning in resp, if any and not reported before.
+func (d *deprecations) observe(resp *http.Response) {
+	w, ok := parseWarning(resp.Header)
+	if !ok {
+		return
+	}
+	if resp.Request != nil {
+		w.Method, w.Path = resp.Request.Method, resp.Request.URL.Path
+	}
+
+	if d.onWarn == nil {
+		return
+	}
+
+	key := w
+	key.Path = ""
+	d.mu.Lock()
+	if d.seen == nil {
+		d.seen = make(map[Warning]bool)
+	}
+	first := !d.seen[key]
+	d.seen[key] = true
+	d.mu.Unlock()
+
+	if first {
+		d.onWarn(w)
+	}
+}
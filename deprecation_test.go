@@ -0,0 +1,68 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_WithDeprecationHandler(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@1767225600")
+		w.Header().Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+		w.Header().Set("Link", `<https://docs.tryl.dev/migrate/v2>; rel="deprecation"; type="text/html"`)
+		w.Header().Set("X-API-Warn", "use /v2/events")
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	var warnings []Warning
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithDeprecationHandler(func(w Warning) { warnings = append(warnings, w) }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.List(context.Background(), EventFilter{}); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+	}
+	// Another path with the same warning is not reported again.
+	if _, err := client.GetProject(context.Background(), "proj_1"); err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %d, want 1 (repeated warnings are reported once)", len(warnings))
+	}
+	want := Warning{
+		Method:       "GET",
+		Path:         "/v1/events",
+		Deprecated:   true,
+		DeprecatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:       time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		Link:         "https://docs.tryl.dev/migrate/v2",
+		Message:      "use /v2/events",
+	}
+	got := warnings[0]
+	if got.Method != want.Method || got.Path != want.Path || got.Deprecated != want.Deprecated ||
+		!got.DeprecatedAt.Equal(want.DeprecatedAt) || !got.Sunset.Equal(want.Sunset) ||
+		got.Link != want.Link || got.Message != want.Message {
+		t.Errorf("warning = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseWarning_None(t *testing.T) {
+	t.Parallel()
+
+	if w, ok := parseWarning(http.Header{"Deprecation": {"false"}}); ok {
+		t.Errorf("parseWarning() = %+v, want no warning", w)
+	}
+}
@@ -0,0 +1,70 @@
+package tryl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// ErrEnvironmentMismatch is returned when a client configured with
+// WithEnvironmentGuard would operate in another environment.
+var ErrEnvironmentMismatch = errors.New("tryl: environment does not match the environment guard")
+
+// Environment is a project or API key environment. Live and test data are
+// kept apart: a test key can only log to and read test projects.
+type Environment string
+
+// Environments accepted by the API.
+const (
+	// EnvLive is the production environment.
+	EnvLive Environment = "live"
+	// EnvTest is the environment for development and CI.
+	EnvTest Environment = "test"
+)
+
+// Valid reports whether e is a known environment.
+func (e Environment) Valid() bool {
+	return e == EnvLive || e == EnvTest
+}
+
+// validate checks that e is a known environment, or empty when optional.
+func (e Environment) validate(required bool) error {
+	if e == "" && !required {
+		return nil
+	}
+	if !e.Valid() {
+		return &ValidationError{
+			Field:   "environment",
+			Message: fmt.Sprintf("must be live or test (got: %s)", e),
+		}
+	}
+	return nil
+}
+
+// Environment returns the environment of the client's API key, from its
+// actlog_live_ or actlog_test_ prefix. It is empty for clients created
+// with NewManagementClient.
+func (c *Client) Environment() Environment {
+	return keyEnvironment(c.transport.APIKey)
+}
+
+// keyEnvironment returns the environment encoded in an API key's prefix.
+func keyEnvironment(apiKey string) Environment {
+	switch {
+	case validation.IsLiveKey(apiKey):
+		return EnvLive
+	case validation.IsTestKey(apiKey):
+		return EnvTest
+	}
+	return ""
+}
+
+// checkEnvironment enforces WithEnvironmentGuard for env. An empty env,
+// as for session tokens, is unknown and passes.
+func (c *clientConfig) checkEnvironment(env Environment) error {
+	if c.environmentGuard == "" || env == "" || env == c.environmentGuard {
+		return nil
+	}
+	return fmt.Errorf("%w: got %s, expected %s", ErrEnvironmentMismatch, env, c.environmentGuard)
+}
@@ -0,0 +1,72 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClient_Environment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		new  func() (*Client, error)
+		want Environment
+	}{
+		{"test key", func() (*Client, error) { return NewClient("actlog_test_1234567890abcdef1234567890abcdef") }, EnvTest},
+		{"live key", func() (*Client, error) { return NewClient("actlog_live_1234567890abcdef1234567890abcdef") }, EnvLive},
+		{"session token", func() (*Client, error) { return NewManagementClient("session_token") }, ""},
+	}
+
+	for _, tt := range tests {
+		client, err := tt.new()
+		if err != nil {
+			t.Fatalf("%s: failed to create client: %v", tt.name, err)
+		}
+		if got := client.Environment(); got != tt.want {
+			t.Errorf("%s: Environment() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClient_CreateWithInvalidEnvironment(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewManagementClient("session_token", WithBaseURL("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	var verr *ValidationError
+	if _, err := client.CreateProject(ctx, CreateProjectRequest{Name: "Main", Environment: "prod"}); !errors.As(err, &verr) || verr.Field != "environment" {
+		t.Errorf("CreateProject() error = %v, want environment ValidationError", err)
+	}
+	if _, err := client.CreateAPIKey(ctx, "proj_1", CreateAPIKeyRequest{Name: "ci"}); !errors.As(err, &verr) || verr.Field != "environment" {
+		t.Errorf("CreateAPIKey() error = %v, want environment ValidationError", err)
+	}
+}
+
+func TestWithEnvironmentGuard(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewClient("actlog_live_1234567890abcdef1234567890abcdef", WithEnvironmentGuard(EnvTest)); !errors.Is(err, ErrEnvironmentMismatch) {
+		t.Errorf("NewClient() with live key error = %v, want ErrEnvironmentMismatch", err)
+	}
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithEnvironmentGuard(EnvTest)); err != nil {
+		t.Errorf("NewClient() with test key error = %v", err)
+	}
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithEnvironmentGuard("prod")); err == nil {
+		t.Error("expected error for unknown guard environment")
+	}
+
+	client, err := NewManagementClient("session_token", WithBaseURL("http://127.0.0.1:0"), WithEnvironmentGuard(EnvTest))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	_, err = client.CreateProject(context.Background(), CreateProjectRequest{Name: "Main", Environment: EnvLive})
+	if !errors.Is(err, ErrEnvironmentMismatch) {
+		t.Errorf("CreateProject() error = %v, want ErrEnvironmentMismatch", err)
+	}
+}
@@ -3,6 +3,11 @@ package tryl
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
 )
 
 // Error codes returned by the API.
@@ -37,6 +42,17 @@ var (
 
 	// ErrKeyNotFound indicates the requested API key was not found.
 	ErrKeyNotFound = errors.New("tryl: API key not found")
+
+	// ErrNotFound indicates the requested resource was not found.
+	ErrNotFound = errors.New("tryl: not found")
+
+	// ErrClientClosed indicates the client was used after Close.
+	ErrClientClosed = errors.New("tryl: client is closed")
+
+	// ErrResponseTooLarge indicates a response body exceeded the limit set
+	// with WithMaxResponseBytes. It is wrapped in a NetworkError and not
+	// retried.
+	ErrResponseTooLarge = transport.ErrBodyTooLarge
 )
 
 // APIError represents an error response from the Activity Logger API.
@@ -49,6 +65,18 @@ type APIError struct {
 	Message string
 	// RequestID is the unique identifier for the request (for support).
 	RequestID string
+	// Details lists field-level problems reported by the server, if any.
+	Details []FieldDetail
+}
+
+// FieldDetail describes a server-side problem with a single request field.
+type FieldDetail struct {
+	// Field is the path of the offending field (e.g., "metadata.amount").
+	Field string
+	// Code is the machine-readable reason (e.g., "required", "too_long").
+	Code string
+	// Message is the human-readable description.
+	Message string
 }
 
 func (e *APIError) Error() string {
@@ -73,11 +101,37 @@ func (e *APIError) Is(target error) bool {
 		return e.Code == ErrCodeProjectNotFound || (e.HTTPStatus == 404 && e.Code == ErrCodeNotFound)
 	case target == ErrKeyNotFound:
 		return e.Code == ErrCodeKeyNotFound || (e.HTTPStatus == 404 && e.Code == ErrCodeNotFound)
+	case target == ErrNotFound:
+		return e.HTTPStatus == 404 || e.Code == ErrCodeNotFound ||
+			e.Code == ErrCodeProjectNotFound || e.Code == ErrCodeKeyNotFound
 	default:
 		return false
 	}
 }
 
+// DetailsFor returns the field details reported for field.
+// A field also matches details for its nested fields, so "metadata"
+// returns details for "metadata.amount" as well.
+func (e *APIError) DetailsFor(field string) []FieldDetail {
+	var out []FieldDetail
+	for _, d := range e.Details {
+		if d.Field == field || strings.HasPrefix(d.Field, field+".") || strings.HasPrefix(d.Field, field+"[") {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// FieldDetails returns the field-level details of err if it is an
+// *APIError, or nil otherwise.
+func FieldDetails(err error) []FieldDetail {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Details
+	}
+	return nil
+}
+
 // IsRetryable returns true if the error is potentially retryable.
 func (e *APIError) IsRetryable() bool {
 	return e.HTTPStatus >= 500 || e.HTTPStatus == 429
@@ -110,6 +164,59 @@ func IsValidationError(err error) bool {
 	return errors.Is(err, ErrValidation)
 }
 
+// IsNotFound reports whether the error means the requested resource does
+// not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// NotFoundError reports that a project, API key, saved query, or scheduled
+// event does not exist. It is returned by GetProject, GetAPIKey, the Find
+// methods, the saved query methods, and CancelScheduled, and matches ErrNotFound and the resource's
+// sentinel (ErrProjectNotFound or ErrKeyNotFound) with errors.Is.
+type NotFoundError struct {
+	// Resource is "project", "api_key", "saved_query", "scheduled_event", or
+	// "filter_preset".
+	Resource string
+	// ID is the ID or name that was looked up.
+	ID string
+
+	// err is the API error, for lookups by ID.
+	err error
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("tryl: %s %q not found", e.Resource, e.ID)
+}
+
+// Is implements errors.Is support.
+func (e *NotFoundError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return true
+	case ErrProjectNotFound:
+		return e.Resource == "project"
+	case ErrKeyNotFound:
+		return e.Resource == "api_key"
+	}
+	return false
+}
+
+// Unwrap returns the underlying API error, if any.
+func (e *NotFoundError) Unwrap() error {
+	return e.err
+}
+
+// notFound converts a not-found API error into a *NotFoundError and
+// returns other errors unchanged.
+func notFound(err error, resource, id string) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && errors.Is(apiErr, ErrNotFound) {
+		return &NotFoundError{Resource: resource, ID: id, err: err}
+	}
+	return err
+}
+
 // ValidationError represents a client-side validation error.
 // This wraps validation failures from the internal validation package
 // and provides a consistent public error type.
@@ -118,9 +225,18 @@ type ValidationError struct {
 	Field string
 	// Message is the human-readable error message.
 	Message string
+	// Value is the offending value, possibly truncated, when it is useful
+	// for display. It is empty for missing fields.
+	Value string
+
+	// err is the underlying validation failure, if any.
+	err error
 }
 
 func (e *ValidationError) Error() string {
+	if e.Value != "" {
+		return fmt.Sprintf("tryl: validation error: %s: %s (got: %s)", e.Field, e.Message, e.Value)
+	}
 	return fmt.Sprintf("tryl: validation error: %s: %s", e.Field, e.Message)
 }
 
@@ -129,6 +245,47 @@ func (e *ValidationError) Is(target error) bool {
 	return target == ErrValidation
 }
 
+// Unwrap returns the underlying validation failure, if any.
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+// AsValidationError returns the *ValidationError in err's chain, if any.
+// Use it to read Field, Message, and Value instead of parsing error text.
+func AsValidationError(err error) (*ValidationError, bool) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr, true
+	}
+	return nil, false
+}
+
+// IsPermanent reports whether err means the event can never be ingested as
+// sent, either because it failed validation or because the API rejected it
+// with a non-retryable error, so sending it again would not help.
+func IsPermanent(err error) bool {
+	if _, ok := AsValidationError(err); ok {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && !apiErr.IsRetryable()
+}
+
+// newValidationError converts an error from the internal validation
+// package into a *ValidationError.
+func newValidationError(err error) error {
+	var fieldErr *validation.FieldError
+	if errors.As(err, &fieldErr) {
+		return &ValidationError{
+			Field:   fieldErr.Field,
+			Message: fieldErr.Message,
+			Value:   fieldErr.Value,
+			err:     err,
+		}
+	}
+	return fmt.Errorf("validation failed: %w", err)
+}
+
 // IsClientValidationError reports whether the error is a client-side validation error.
 // This distinguishes client-side validation errors from server-side validation errors.
 func IsClientValidationError(err error) bool {
@@ -159,6 +316,29 @@ func (e *NetworkError) IsTemporary() bool {
 	return true
 }
 
+// RetryError wraps the final error of an operation that was retried.
+// Use errors.As to inspect retry details; errors.Is and errors.As also see
+// through it to the underlying error.
+type RetryError struct {
+	// Attempts is the number of attempts made, including the initial request.
+	Attempts int
+	// TotalDelay is the cumulative backoff delay waited between attempts.
+	TotalDelay time.Duration
+	// Reason describes why retrying stopped (e.g., "max retries exceeded").
+	Reason string
+	// Err is the error from the last attempt.
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("tryl: %s after %d attempts (total delay %s): %v",
+		e.Reason, e.Attempts, e.TotalDelay, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
 // IsProjectNotFound reports whether the error indicates a project was not found.
 func IsProjectNotFound(err error) bool {
 	var apiErr *APIError
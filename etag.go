@@ -0,0 +1,94 @@
+package tryl
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// etagCache stores response bodies by request, with their ETags, for
+// conditional requests.
+type etagCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// etagEntry is a cached response body.
+type etagEntry struct {
+	key  string
+	etag string
+	body []byte
+}
+
+func newETagCache(maxEntries int) *etagCache {
+	return &etagCache{
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (ec *etagCache) get(key string) (*etagEntry, bool) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	el, ok := ec.entries[key]
+	if !ok {
+		return nil, false
+	}
+	ec.lru.MoveToFront(el)
+	return el.Value.(*etagEntry), true
+}
+
+func (ec *etagCache) put(entry *etagEntry) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if el, ok := ec.entries[entry.key]; ok {
+		el.Value = entry
+		ec.lru.MoveToFront(el)
+		return
+	}
+	ec.entries[entry.key] = ec.lru.PushFront(entry)
+	if ec.lru.Len() > ec.maxEntries {
+		oldest := ec.lru.Back()
+		ec.lru.Remove(oldest)
+		delete(ec.entries, oldest.Value.(*etagEntry).key)
+	}
+}
+
+// conditionalGet sends a GET request, revalidating a previously cached
+// response with If-None-Match when WithETagCache is set. A 304 Not
+// Modified response is returned as a 200 with the cached body, so callers
+// handle both the same way.
+func (c *Client) conditionalGet(ctx context.Context, req transport.Request) (*transport.Response, error) {
+	if c.etags == nil {
+		return c.transport.Do(ctx, req)
+	}
+
+	key := req.Path + "?" + req.Query.Encode()
+	cached, ok := c.etags.get(key)
+	if ok {
+		req.Headers = map[string]string{"If-None-Match": cached.etag}
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && ok {
+		resp.StatusCode = http.StatusOK
+		resp.Body = cached.body
+		return resp, nil
+	}
+	if resp.StatusCode == http.StatusOK {
+		if etag := resp.Headers.Get("ETag"); etag != "" {
+			c.etags.put(&etagEntry{key: key, etag: etag, body: resp.Body})
+		}
+	}
+	return resp, nil
+}
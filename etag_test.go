@@ -0,0 +1,70 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithETagCache(t *testing.T) {
+	t.Parallel()
+
+	var conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v1"`
+		if strings.HasPrefix(r.URL.Path, "/v1/projects/") {
+			etag = `"p1"`
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		if etag == `"p1"` {
+			w.Write([]byte(`{"id":"proj_1","name":"Main"}`))
+			return
+		}
+		w.Write([]byte(`{"events":[{"id":"evt_1","user_id":"user_1","action":"doc.viewed","timestamp":"2026-01-30T10:00:00Z"}],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithETagCache(8),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		list, err := client.List(ctx, EventFilter{UserID: "user_1"})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(list.Events) != 1 || list.Events[0].ID != "evt_1" {
+			t.Errorf("List() call %d events = %+v, want evt_1", i, list.Events)
+		}
+		project, err := client.GetProject(ctx, "proj_1")
+		if err != nil {
+			t.Fatalf("GetProject() error = %v", err)
+		}
+		if project.Name != "Main" {
+			t.Errorf("GetProject() call %d name = %q, want Main", i, project.Name)
+		}
+	}
+	if conditional != 2 {
+		t.Errorf("304 responses = %d, want 2", conditional)
+	}
+}
+
+func TestWithETagCache_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithETagCache(0)); err == nil {
+		t.Error("expected error for zero max entries")
+	}
+}
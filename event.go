@@ -3,6 +3,7 @@ package tryl
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -10,6 +11,9 @@ import (
 type Event struct {
 	// UserID is the user who performed or is associated with the action. Required.
 	UserID string `json:"user_id"`
+	// TenantID is the tenant or organization the event belongs to, for
+	// multi-tenant applications. Optional.
+	TenantID string `json:"tenant_id,omitempty"`
 	// Action is the type of action performed (e.g., "user.created"). Required.
 	// Must be lowercase alphanumeric with dots or underscores.
 	Action string `json:"action"`
@@ -21,6 +25,35 @@ type Event struct {
 	TargetID string `json:"target_id,omitempty"`
 	// Metadata is additional structured data about the event. Optional.
 	Metadata json.RawMessage `json:"metadata,omitempty"`
+	// Tags are cross-cutting labels (e.g., "billing", "security"). Optional.
+	// At most 10, each lowercase and at most 32 characters.
+	Tags []string `json:"tags,omitempty"`
+	// CorrelationID groups the events of one request or workflow so they
+	// can be listed together. Optional. If empty, it is filled from the
+	// context; see ContextWithCorrelationID and WithCorrelationIDFunc.
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// ParentEventID is the ID of the event this one was spawned by, for
+	// composite operations such as a bulk import. Optional. See
+	// Client.ListChildren.
+	ParentEventID string `json:"parent_event_id,omitempty"`
+	// IdempotencyKey deduplicates retried submissions of the same event. Optional.
+	// LogBatch generates one for each event that does not set it.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// SchemaVersion is the version of the metadata shape for this action. Optional.
+	// Zero means unversioned. See MigrationRegistry for upgrading old shapes.
+	SchemaVersion int `json:"schema_version,omitempty"`
+	// ContentHash is the SHA-256 of the event's content, for integrity
+	// checks with VerifyEventHash. Optional. Set by WithContentHash; see
+	// EventHash.
+	ContentHash string `json:"content_hash,omitempty"`
+	// ChainID and PrevHash link the event into a hash chain. They are set
+	// by Chain.Log and covered by the content hash.
+	ChainID  string `json:"chain_id,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty"`
+	// Timestamp is when the action happened, for backfilling historical
+	// events such as with ImportFile. Optional. If nil, the event is
+	// recorded at the time the API receives it.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
 }
 
 // Getter methods for validation interface compatibility.
@@ -30,6 +63,10 @@ func (e *Event) GetActorID() string         { return e.ActorID }
 func (e *Event) GetTargetType() string      { return e.TargetType }
 func (e *Event) GetTargetID() string        { return e.TargetID }
 func (e *Event) GetMetadata() json.RawMessage { return e.Metadata }
+func (e *Event) GetTags() []string            { return e.Tags }
+func (e *Event) GetCorrelationID() string     { return e.CorrelationID }
+func (e *Event) GetParentEventID() string     { return e.ParentEventID }
+func (e *Event) GetTenantID() string          { return e.TenantID }
 
 // WithMetadata is a helper to set metadata from a map.
 //
@@ -68,6 +105,32 @@ func (e Event) WithMetadataValidated(m map[string]any) (Event, error) {
 	return e, nil
 }
 
+// WithMetadataStruct sets metadata from a struct (or pointer to one),
+// so domain types can be logged directly. Keys follow the fields' json tag
+// names, and a tryl tag controls each field:
+//
+//	type Invoice struct {
+//	    ID       string `json:"id"`
+//	    Note     string `json:"note" tryl:"omitempty"` // left out when empty
+//	    CardLast string `json:"card_last4" tryl:"redact"` // logged as "[REDACTED]"
+//	    Internal string `tryl:"-"` // never logged
+//	}
+//
+// Nested structs are converted with the same rules. Fields are otherwise
+// encoded as by encoding/json.
+func (e Event) WithMetadataStruct(v any) (Event, error) {
+	m, err := structMetadata(v)
+	if err != nil {
+		return e, err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return e, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	e.Metadata = data
+	return e, nil
+}
+
 // SetMetadata sets metadata directly from json.RawMessage.
 // This is useful when you already have validated JSON.
 func (e Event) SetMetadata(metadata json.RawMessage) Event {
@@ -81,12 +144,16 @@ type EventResponse struct {
 	ID string `json:"id"`
 	// Timestamp is when the event was recorded.
 	Timestamp time.Time `json:"timestamp"`
+	// Warnings are non-fatal conditions the server reported for the event.
+	Warnings []ResponseWarning `json:"warnings,omitempty"`
 }
 
 // EventFilter represents query parameters for listing events.
 type EventFilter struct {
 	// UserID filters events by user.
 	UserID string
+	// TenantID filters events by tenant.
+	TenantID string
 	// ActorID filters events by actor.
 	ActorID string
 	// Action filters events by action type.
@@ -114,8 +181,22 @@ type EventFilter struct {
 	// Searches across all text fields in the metadata JSON.
 	MetadataSearch string
 
+	// CorrelationID filters events by correlation ID.
+	CorrelationID string
+	// ParentEventID filters events by parent event.
+	ParentEventID string
+	// GroupID filters events committed together by a Group.
+	GroupID string
+	// ChainID filters events logged to a hash chain.
+	ChainID string
+
+	// Tags filters events that have all of the given tags.
+	Tags []string
+	// TagsAny filters events that have at least one of the given tags.
+	TagsAny []string
+
 	// Cursor is an opaque pagination cursor returned by the previous query.
-	// When set, Offset is ignored (cursor-based pagination takes precedence).
+	// It cannot be combined with Offset.
 	// Cursor-based pagination is more efficient for large result sets.
 	Cursor string
 	// Offset is the number of events to skip (offset-based pagination).
@@ -127,6 +208,20 @@ type EventFilter struct {
 	// Order specifies the sort order: "asc" (oldest first) or "desc" (newest first).
 	// Defaults to "desc" if not specified.
 	Order string
+	// OrderBy is the field events are sorted by: OrderByTimestamp,
+	// OrderByAction, or OrderByUserID. Ties are broken by timestamp.
+	// Defaults to OrderByTimestamp if not specified.
+	OrderBy string
+
+	// SchemaVersion filters events by metadata schema version.
+	// Zero does not filter.
+	SchemaVersion int
+
+	// IncludeTotal requests EventList.Total with cursor-based pagination,
+	// for example to show a page count. The server may return an estimate
+	// for large result sets; check EventList.TotalAccuracy. Counting adds
+	// latency, so request it only when needed (e.g., on the first page).
+	IncludeTotal bool
 }
 
 // EventList represents the response when listing events.
@@ -136,19 +231,66 @@ type EventList struct {
 	// HasMore indicates if there are more events to fetch.
 	HasMore bool `json:"has_more"`
 	// Total is the total count of matching events.
-	// Only populated with offset-based pagination. Omitted with cursor-based pagination.
+	// Populated with offset-based pagination, and with cursor-based
+	// pagination when EventFilter.IncludeTotal is set.
 	Total int `json:"total,omitempty"`
+	// TotalAccuracy reports whether Total is exact or estimated.
+	// Empty when the server did not return a total.
+	TotalAccuracy TotalAccuracy `json:"total_accuracy,omitempty"`
 	// NextCursor is the cursor to use for fetching the next page.
 	// Only populated with cursor-based pagination when HasMore is true.
 	NextCursor string `json:"next_cursor,omitempty"`
+	// Warnings are non-fatal conditions affecting the results, such as
+	// WarningFilterDowngraded or WarningRetentionTruncated.
+	Warnings []ResponseWarning `json:"warnings,omitempty"`
+
+	// resume is the position after this page, set by ListSince and Resume.
+	resume *ResumeCursor
+	// events, if set, is the pool Release returns Events to.
+	events *sync.Pool
+}
+
+// Fields EventFilter.OrderBy can sort by.
+const (
+	OrderByTimestamp = "timestamp"
+	OrderByAction    = "action"
+	OrderByUserID    = "user_id"
+)
+
+// validateOrder checks the Order and OrderBy fields of f.
+func validateOrder(f EventFilter) error {
+	switch f.Order {
+	case "", "asc", "desc":
+	default:
+		return &ValidationError{Field: "order", Message: `must be "asc" or "desc"`, Value: f.Order}
+	}
+	switch f.OrderBy {
+	case "", OrderByTimestamp, OrderByAction, OrderByUserID:
+	default:
+		return &ValidationError{Field: "order_by", Message: "must be timestamp, action, or user_id", Value: f.OrderBy}
+	}
+	return nil
 }
 
+// TotalAccuracy describes how EventList.Total was computed.
+type TotalAccuracy string
+
+const (
+	// TotalExact means Total is an exact count.
+	TotalExact TotalAccuracy = "exact"
+	// TotalEstimated means Total is an approximation, such as a query
+	// planner estimate for a large result set.
+	TotalEstimated TotalAccuracy = "estimated"
+)
+
 // StoredEvent represents an event retrieved from the API.
 type StoredEvent struct {
 	// ID is the unique identifier for the event.
 	ID string `json:"id"`
 	// UserID is the user associated with the event.
 	UserID string `json:"user_id"`
+	// TenantID is the tenant the event belongs to, if any.
+	TenantID string `json:"tenant_id,omitempty"`
 	// Action is the type of action performed.
 	Action string `json:"action"`
 	// ActorID is who performed the action.
@@ -159,19 +301,104 @@ type StoredEvent struct {
 	TargetID string `json:"target_id,omitempty"`
 	// Metadata is additional structured data about the event.
 	Metadata json.RawMessage `json:"metadata,omitempty"`
+	// Tags are the event's labels.
+	Tags []string `json:"tags,omitempty"`
+	// CorrelationID groups the events of one request or workflow.
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// ParentEventID is the ID of the event this one was spawned by.
+	ParentEventID string `json:"parent_event_id,omitempty"`
+	// GroupID is the ID of the Group the event was committed with, if any.
+	GroupID string `json:"group_id,omitempty"`
+	// SchemaVersion is the version of the metadata shape (zero if unversioned).
+	SchemaVersion int `json:"schema_version,omitempty"`
+	// ContentHash is the content hash sent with the event, if any.
+	// See VerifyEventHash.
+	ContentHash string `json:"content_hash,omitempty"`
+	// ChainID is the hash chain the event was logged to, if any.
+	ChainID string `json:"chain_id,omitempty"`
+	// PrevHash is the content hash of the previous event in the chain, or
+	// empty for the first.
+	PrevHash string `json:"prev_hash,omitempty"`
 	// Timestamp is when the event was recorded.
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// BatchItemStatus is the outcome of a single event in a batch.
+type BatchItemStatus string
+
+const (
+	// BatchItemCommitted means the event was stored.
+	BatchItemCommitted BatchItemStatus = "committed"
+	// BatchItemFailed means the event was rejected; see BatchItem.Error.
+	BatchItemFailed BatchItemStatus = "failed"
+)
+
+// BatchItem is the result for one event in a LogBatch call.
+type BatchItem struct {
+	// Index is the position of the event in the slice passed to LogBatch.
+	Index int
+	// Status reports whether the event was committed.
+	Status BatchItemStatus
+	// Response is the created event. It is nil if the event failed.
+	Response *EventResponse
+	// Error describes why the event failed. It is nil if the event was committed.
+	// Server rejections are reported as *APIError.
+	Error error
+}
+
+// BatchResult is the result of a LogBatch call, with one item per event
+// in the original order.
+type BatchResult struct {
+	Items []BatchItem
+}
+
+// Succeeded returns the items that were committed.
+func (r *BatchResult) Succeeded() []BatchItem {
+	return r.filter(BatchItemCommitted)
+}
+
+// Failed returns the items that were rejected.
+func (r *BatchResult) Failed() []BatchItem {
+	return r.filter(BatchItemFailed)
+}
+
+func (r *BatchResult) filter(status BatchItemStatus) []BatchItem {
+	var out []BatchItem
+	for _, item := range r.Items {
+		if item.Status == status {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 // batchRequest is the internal request format for batch operations.
 type batchRequest struct {
 	Events []Event `json:"events"`
+	// GroupID and Atomic are set for Group commits: the server stores all
+	// events with the group ID, or none of them.
+	GroupID string `json:"group_id,omitempty"`
+	Atomic  bool   `json:"atomic,omitempty"`
 }
 
 // batchResponse is the internal response format for batch operations.
 type batchResponse struct {
-	Results []EventResponse    `json:"results"`
-	Errors  []batchResultError `json:"errors"`
+	Results  []EventResponse    `json:"results"`
+	Errors   []batchResultError `json:"errors"`
+	Warnings []ResponseWarning  `json:"warnings,omitempty"`
+}
+
+// committed returns the number of events the response reports as stored.
+// Rejected events have no ID in Results, so they are not counted again for
+// their entries in Errors.
+func (r *batchResponse) committed() int {
+	n := 0
+	for i := range r.Results {
+		if r.Results[i].ID != "" {
+			n++
+		}
+	}
+	return n
 }
 
 // batchResultError represents an error for a specific event in a batch.
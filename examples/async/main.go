@@ -43,32 +43,32 @@ func main() {
 
 	for i, event := range events {
 		wg.Add(1)
-		resultCh := client.LogAsync(ctx, event)
+		pending := client.LogAsync(ctx, event)
 
-		go func(idx int, ch <-chan tryl.AsyncResult) {
+		go func(idx int, p *tryl.PendingEvent) {
 			defer wg.Done()
 
-			result := <-ch
-			if result.Error != nil {
-				log.Printf("Event %d failed: %v", idx, result.Error)
+			resp, err := p.Wait(ctx)
+			if err != nil {
+				log.Printf("Event %d failed: %v", idx, err)
 			} else {
-				log.Printf("Event %d logged: ID=%s", idx, result.Response.ID)
+				log.Printf("Event %d logged: ID=%s", idx, resp.ID)
 			}
-		}(i, resultCh)
+		}(i, pending)
 	}
 
 	wg.Wait()
 
 	log.Println("All async events processed")
 
-	resultCh := client.LogAsync(ctx, tryl.Event{
+	pending := client.LogAsync(ctx, tryl.Event{
 		UserID: "user_fire_and_forget",
 		Action: "notification.sent",
 	})
 
 	select {
-	case result := <-resultCh:
-		if result.Error != nil {
+	case <-pending.Done():
+		if result, _ := pending.Result(); result.Error != nil {
 			log.Printf("Fire-and-forget failed (but we checked): %v", result.Error)
 		} else {
 			log.Printf("Fire-and-forget succeeded: %s", result.Response.ID)
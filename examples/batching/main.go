@@ -49,33 +49,36 @@ func main() {
 
 	for i := 0; i < 25; i++ {
 		wg.Add(1)
-		resultCh := client.LogAsync(ctx, tryl.Event{
+		pending := client.LogAsync(ctx, tryl.Event{
 			UserID: fmt.Sprintf("user_%d", i),
 			Action: "batch.test",
 		})
 
-		go func(idx int, ch <-chan tryl.AsyncResult) {
+		go func(idx int, p *tryl.PendingEvent) {
 			defer wg.Done()
 
-			result := <-ch
+			_, err := p.Wait(ctx)
 			mu.Lock()
 			defer mu.Unlock()
 
-			if result.Error != nil {
+			if err != nil {
 				failCount++
-				log.Printf("Event %d failed: %v", idx, result.Error)
+				log.Printf("Event %d failed: %v", idx, err)
 			} else {
 				successCount++
 			}
-		}(i, resultCh)
+		}(i, pending)
 	}
 
 	log.Println("All events queued, waiting for results...")
 
 	log.Println("Flushing remaining events...")
-	if err := client.Flush(ctx); err != nil {
+	report, err := client.Flush(ctx)
+	if err != nil {
 		log.Printf("Flush error: %v", err)
 	}
+	log.Printf("Flushed %d events in %d batches (%d failed) in %v",
+		report.Sent, report.Batches, report.Failed, report.Duration)
 
 	wg.Wait()
 
@@ -0,0 +1,84 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExpiringKey is an API key reported by CheckExpiringKeys.
+type ExpiringKey struct {
+	// Project is the project the key belongs to.
+	Project Project
+	// Key is the key's metadata.
+	Key APIKey
+	// Expired reports whether the key has already expired.
+	Expired bool
+	// Remaining is the time left until the key expires. It is negative
+	// for expired keys.
+	Remaining time.Duration
+}
+
+// CheckExpiringKeys calls fn for every API key, in every project, that
+// expires within the given duration or has already expired, so a daily
+// job can warn owners or rotate keys before they stop working. Revoked
+// keys and keys without an expiration are skipped.
+//
+// Each project's keys are listed before fn is called for them, so keys
+// created by fn, such as by RotateAPIKey, are not reported in the same
+// run. Errors from fn, and from listing a project's keys, do not stop the
+// scan; they are returned together once every project has been checked.
+// Requires session token authentication (use NewManagementClient).
+//
+//	err := mgmt.CheckExpiringKeys(ctx, 14*24*time.Hour, func(ctx context.Context, k tryl.ExpiringKey) error {
+//	    expiresAt := time.Now().AddDate(0, 0, 90)
+//	    rotated, err := mgmt.RotateAPIKey(ctx, k.Key.ID, tryl.RotateAPIKeyRequest{ExpiresAt: &expiresAt})
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return secrets.Store(k.Project.Name, rotated.NewAPIKey)
+//	})
+func (c *Client) CheckExpiringKeys(ctx context.Context, within time.Duration, fn func(ctx context.Context, key ExpiringKey) error) error {
+	if fn == nil {
+		return &ValidationError{Field: "fn", Message: "is required"}
+	}
+
+	projects, err := c.ListProjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, project := range projects.Projects {
+		keys, err := c.ListAPIKeys(ctx, project.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("project %s: %w", project.ID, err))
+			continue
+		}
+		now := c.config.clock.Now()
+		for _, key := range keys.APIKeys {
+			if key.RevokedAt != nil || key.ExpiresAt == nil {
+				continue
+			}
+			remaining := key.ExpiresAt.Sub(now)
+			if remaining > within {
+				continue
+			}
+			expiring := ExpiringKey{
+				Project:   project,
+				Key:       key,
+				Expired:   key.Status(now) == APIKeyStatusExpired,
+				Remaining: remaining,
+			}
+			if err := fn(ctx, expiring); err != nil {
+				errs = append(errs, fmt.Errorf("key %s: %w", key.ID, err))
+			}
+		}
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_CheckExpiringKeys(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects":
+			w.Write([]byte(`{"projects":[{"id":"proj_1","name":"Billing"},{"id":"proj_2","name":"Search"},{"id":"proj_3","name":"Broken"}]}`))
+		case "/v1/projects/proj_1/keys":
+			w.Write([]byte(`{"api_keys":[` +
+				`{"id":"key_soon","expires_at":"2026-03-05T00:00:00Z"},` +
+				`{"id":"key_later","expires_at":"2026-06-01T00:00:00Z"},` +
+				`{"id":"key_forever"},` +
+				`{"id":"key_revoked","expires_at":"2026-03-02T00:00:00Z","revoked_at":"2026-02-01T00:00:00Z"}]}`))
+		case "/v1/projects/proj_2/keys":
+			w.Write([]byte(`{"api_keys":[{"id":"key_expired","expires_at":"2026-02-20T00:00:00Z"}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":"forbidden","message":"forbidden"}}`))
+		}
+	}))
+	defer server.Close()
+
+	clock := &stepClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL), WithClock(clock))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var got []ExpiringKey
+	err = client.CheckExpiringKeys(context.Background(), 7*24*time.Hour, func(ctx context.Context, k ExpiringKey) error {
+		got = append(got, k)
+		if k.Expired {
+			return errors.New("rotation failed")
+		}
+		return nil
+	})
+
+	if len(got) != 2 {
+		t.Fatalf("CheckExpiringKeys() reported %d keys, want 2: %+v", len(got), got)
+	}
+	if k := got[0]; k.Key.ID != "key_soon" || k.Project.Name != "Billing" || k.Expired || k.Remaining != 4*24*time.Hour {
+		t.Errorf("first key = %+v", k)
+	}
+	if k := got[1]; k.Key.ID != "key_expired" || !k.Expired || k.Remaining >= 0 {
+		t.Errorf("second key = %+v", k)
+	}
+
+	if err == nil || !strings.Contains(err.Error(), "key key_expired: rotation failed") || !strings.Contains(err.Error(), "project proj_3") {
+		t.Errorf("CheckExpiringKeys() error = %v, want the callback and listing errors", err)
+	}
+}
+
+func TestClient_CheckExpiringKeys_NilCallback(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewManagementClient("session_token", WithBaseURL("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if err := client.CheckExpiringKeys(context.Background(), time.Hour, nil); !IsClientValidationError(err) {
+		t.Errorf("CheckExpiringKeys(nil) error = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,240 @@
+package tryl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// defaultExportChunkSize is the number of events per exported object.
+const defaultExportChunkSize = 10000
+
+// ObjectStore stores exported objects. Implement it with your storage SDK;
+// for example, with the AWS SDK for Go v2:
+//
+//	type s3Store struct{ client *s3.Client }
+//
+//	func (s s3Store) PutObject(ctx context.Context, bucket, key string, body io.Reader, contentType string) error {
+//	    _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+//	        Bucket: &bucket, Key: &key, Body: body, ContentType: &contentType,
+//	    })
+//	    return err
+//	}
+//
+// For Google Cloud Storage, copy body into
+// client.Bucket(bucket).Object(key).NewWriter(ctx) and close the writer.
+type ObjectStore interface {
+	PutObject(ctx context.Context, bucket, key string, body io.Reader, contentType string) error
+}
+
+// ExportFormat describes how a chunk of events is encoded into an object.
+type ExportFormat struct {
+	// Name identifies the format in the manifest (e.g., "jsonl").
+	Name string
+	// Extension is appended to object keys (e.g., ".jsonl").
+	Extension string
+	// ContentType is passed to the ObjectStore.
+	ContentType string
+	// Encode writes events to w.
+	Encode func(w io.Writer, events []StoredEvent) error
+}
+
+// Built-in export formats. The trylparquet package provides a Parquet format.
+var (
+	// ExportJSONL writes one JSON event per line.
+	ExportJSONL = ExportFormat{
+		Name:        "jsonl",
+		Extension:   ".jsonl",
+		ContentType: "application/x-ndjson",
+		Encode:      encodeJSONL,
+	}
+	// ExportJSONLGzip writes gzip-compressed JSONL.
+	ExportJSONLGzip = ExportFormat{
+		Name:        "jsonl.gz",
+		Extension:   ".jsonl.gz",
+		ContentType: "application/gzip",
+		Encode: func(w io.Writer, events []StoredEvent) error {
+			gz := gzip.NewWriter(w)
+			if err := encodeJSONL(gz, events); err != nil {
+				return err
+			}
+			return gz.Close()
+		},
+	}
+)
+
+func encodeJSONL(w io.Writer, events []StoredEvent) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportJob describes an archive export to object storage.
+type ExportJob struct {
+	// Store receives the exported objects. Required.
+	Store ObjectStore
+	// Bucket is the destination bucket. Required.
+	Bucket string
+	// Prefix is prepended to every object key (e.g., "audit/2026-01").
+	Prefix string
+	// Range limits the export to events in this time range.
+	// It overrides Filter.StartTime and Filter.EndTime when set.
+	Range TimeRange
+	// Format encodes each chunk. Default: ExportJSONLGzip
+	Format ExportFormat
+	// Filter selects events to export. Cursor and Offset are ignored.
+	Filter EventFilter
+	// ChunkSize is the number of events per object. Default: 10000
+	ChunkSize int
+}
+
+// ExportManifest describes a completed export. It is written as
+// "manifest.json" under the job prefix after every part is stored, so
+// its presence marks the export as complete.
+type ExportManifest struct {
+	Bucket      string       `json:"bucket"`
+	Prefix      string       `json:"prefix"`
+	Format      string       `json:"format"`
+	Start       *time.Time   `json:"start,omitempty"`
+	End         *time.Time   `json:"end,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	TotalEvents int          `json:"total_events"`
+	Parts       []ExportPart `json:"parts"`
+}
+
+// ExportPart is one exported object.
+type ExportPart struct {
+	Key    string `json:"key"`
+	Events int    `json:"events"`
+	Bytes  int    `json:"bytes"`
+	// SHA256 is the hex digest of the object body.
+	SHA256 string `json:"sha256"`
+	// FirstTimestamp and LastTimestamp bound the events in the part.
+	FirstTimestamp time.Time `json:"first_timestamp"`
+	LastTimestamp  time.Time `json:"last_timestamp"`
+}
+
+// ExportToObjectStorage pages through the events selected by job and
+// writes them to object storage in chunks of job.ChunkSize, followed by a
+// manifest. The export runs client-side through List. Parts written
+// before a failure are left in place; rerunning the job overwrites them.
+func (c *Client) ExportToObjectStorage(ctx context.Context, job ExportJob) (*ExportManifest, error) {
+	if job.Store == nil {
+		return nil, &ValidationError{Field: "store", Message: "is required"}
+	}
+	if job.Bucket == "" {
+		return nil, &ValidationError{Field: "bucket", Message: "is required"}
+	}
+	if job.Format.Encode == nil {
+		job.Format = ExportJSONLGzip
+	}
+	if job.ChunkSize <= 0 {
+		job.ChunkSize = defaultExportChunkSize
+	}
+
+	filter := job.Filter
+	filter.Cursor, filter.Offset = "", 0
+	if filter.Limit == 0 {
+		filter.Limit = 100
+	}
+	if !job.Range.Start.IsZero() {
+		filter.StartTime = &job.Range.Start
+	}
+	if !job.Range.End.IsZero() {
+		filter.EndTime = &job.Range.End
+	}
+
+	manifest := &ExportManifest{
+		Bucket:    job.Bucket,
+		Prefix:    job.Prefix,
+		Format:    job.Format.Name,
+		Start:     filter.StartTime,
+		End:       filter.EndTime,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	var chunk []StoredEvent
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		key := path.Join(job.Prefix, fmt.Sprintf("part-%05d%s", len(manifest.Parts), job.Format.Extension))
+		part, err := c.putExportPart(ctx, job, key, chunk)
+		if err != nil {
+			return err
+		}
+		manifest.Parts = append(manifest.Parts, *part)
+		manifest.TotalEvents += part.Events
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for {
+		page, err := c.listFresh(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page.Events {
+			chunk = append(chunk, e)
+			if len(chunk) >= job.ChunkSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !page.HasMore || page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export manifest: %w", err)
+	}
+	key := path.Join(job.Prefix, "manifest.json")
+	if err := job.Store.PutObject(ctx, job.Bucket, key, bytes.NewReader(data), "application/json"); err != nil {
+		return nil, fmt.Errorf("failed to store export manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// putExportPart encodes events and stores them under key.
+func (c *Client) putExportPart(ctx context.Context, job ExportJob, key string, events []StoredEvent) (*ExportPart, error) {
+	var buf bytes.Buffer
+	if err := job.Format.Encode(&buf, events); err != nil {
+		return nil, fmt.Errorf("failed to encode export part %s: %w", key, err)
+	}
+	if buf.Len() == 0 {
+		return nil, errors.New("export format produced an empty object")
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	part := &ExportPart{
+		Key:            key,
+		Events:         len(events),
+		Bytes:          buf.Len(),
+		SHA256:         hex.EncodeToString(sum[:]),
+		FirstTimestamp: events[0].Timestamp,
+		LastTimestamp:  events[len(events)-1].Timestamp,
+	}
+	if err := job.Store.PutObject(ctx, job.Bucket, key, &buf, job.Format.ContentType); err != nil {
+		return nil, fmt.Errorf("failed to store export part %s: %w", key, err)
+	}
+	return part, nil
+}
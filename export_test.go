@@ -0,0 +1,164 @@
+package tryl
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore is an in-memory ObjectStore.
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	keys    []string
+	failKey string
+}
+
+func (s *memoryStore) PutObject(ctx context.Context, bucket, key string, body io.Reader, contentType string) error {
+	if key == s.failKey {
+		return errors.New("access denied")
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.objects == nil {
+		s.objects = map[string][]byte{}
+	}
+	s.objects[bucket+"/"+key] = data
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+// exportServer serves n events in pages of two, following cursors.
+func exportServer(t *testing.T, n int, queries chan<- string) *httptest.Server {
+	t.Helper()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if queries != nil {
+			select {
+			case queries <- r.URL.RawQuery:
+			default:
+			}
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		var page EventList
+		for i := start; i < n && i < start+2; i++ {
+			page.Events = append(page.Events, StoredEvent{
+				ID: fmt.Sprintf("evt_%d", i), UserID: "user_1", Action: "doc.viewed",
+				Timestamp: base.Add(time.Duration(i) * time.Minute),
+			})
+		}
+		if start+2 < n {
+			page.HasMore = true
+			page.NextCursor = strconv.Itoa(start + 2)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ExportToObjectStorage(t *testing.T) {
+	t.Parallel()
+
+	queries := make(chan string, 1)
+	server := exportServer(t, 5, queries)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	store := &memoryStore{}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	manifest, err := client.ExportToObjectStorage(context.Background(), ExportJob{
+		Store:     store,
+		Bucket:    "archive",
+		Prefix:    "audit/2026-01",
+		Range:     TimeRange{Start: start},
+		ChunkSize: 3,
+	})
+	if err != nil {
+		t.Fatalf("ExportToObjectStorage() error = %v", err)
+	}
+
+	if q := <-queries; !bytes.Contains([]byte(q), []byte("start_time=")) {
+		t.Errorf("first query = %q, want start_time from Range", q)
+	}
+	if manifest.TotalEvents != 5 || len(manifest.Parts) != 2 {
+		t.Fatalf("manifest = %+v, want 5 events in 2 parts", manifest)
+	}
+	wantKeys := []string{"audit/2026-01/part-00000.jsonl.gz", "audit/2026-01/part-00001.jsonl.gz", "audit/2026-01/manifest.json"}
+	if fmt.Sprint(store.keys) != fmt.Sprint(wantKeys) {
+		t.Errorf("stored keys = %v, want %v", store.keys, wantKeys)
+	}
+
+	part := manifest.Parts[1]
+	data := store.objects["archive/"+part.Key]
+	sum := sha256.Sum256(data)
+	if part.SHA256 != hex.EncodeToString(sum[:]) || part.Bytes != len(data) {
+		t.Errorf("part checksum/size mismatch: %+v", part)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("part is not gzip: %v", err)
+	}
+	var lines int
+	for scanner := bufio.NewScanner(gz); scanner.Scan(); lines++ {
+	}
+	if lines != 2 || part.Events != 2 {
+		t.Errorf("second part has %d lines (manifest says %d), want 2", lines, part.Events)
+	}
+	if !part.FirstTimestamp.Equal(start.Add(3*time.Minute)) || !part.LastTimestamp.Equal(start.Add(4*time.Minute)) {
+		t.Errorf("part timestamps = %v..%v", part.FirstTimestamp, part.LastTimestamp)
+	}
+
+	var stored ExportManifest
+	if err := json.Unmarshal(store.objects["archive/audit/2026-01/manifest.json"], &stored); err != nil {
+		t.Fatalf("manifest is not JSON: %v", err)
+	}
+	if stored.TotalEvents != 5 || stored.Format != "jsonl.gz" {
+		t.Errorf("stored manifest = %+v", stored)
+	}
+}
+
+func TestClient_ExportToObjectStorage_Errors(t *testing.T) {
+	t.Parallel()
+
+	server := exportServer(t, 3, nil)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.ExportToObjectStorage(context.Background(), ExportJob{Bucket: "archive"}); !IsClientValidationError(err) {
+		t.Errorf("missing store error = %v, want validation error", err)
+	}
+
+	store := &memoryStore{failKey: "part-00001.jsonl"}
+	_, err = client.ExportToObjectStorage(context.Background(), ExportJob{
+		Store: store, Bucket: "archive", Format: ExportJSONL, ChunkSize: 2,
+	})
+	if err == nil {
+		t.Fatal("expected error when a part fails to store")
+	}
+	if _, ok := store.objects["archive/manifest.json"]; ok {
+		t.Error("manifest written despite a failed part")
+	}
+}
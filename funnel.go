@@ -0,0 +1,105 @@
+package tryl
+
+import (
+	"context"
+	"sort"
+)
+
+// FunnelStep is one step of a FunnelResult.
+type FunnelStep struct {
+	// Action is the step's action or action pattern.
+	Action string
+	// Users is the number of users who reached this step after completing
+	// all earlier steps in order.
+	Users int
+	// Conversion is Users divided by the previous step's Users (1 for the
+	// first step with any users).
+	Conversion float64
+	// OverallConversion is Users divided by the first step's Users.
+	OverallConversion float64
+}
+
+// FunnelResult is the outcome of a funnel analysis.
+type FunnelResult struct {
+	Steps []FunnelStep
+}
+
+// Funnel computes how many users performed each of steps in order. Each
+// step is an action or action pattern (e.g., "checkout.*"); a user
+// reaches a step with the first matching event after the event that
+// completed the previous step. events need not be sorted.
+func Funnel(events []StoredEvent, steps []string) *FunnelResult {
+	sorted := make([]StoredEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	reached := map[string]int{} // user ID to number of steps completed
+	for _, e := range sorted {
+		n := reached[e.UserID]
+		if n < len(steps) && MatchesAction(steps[n], e.Action) {
+			reached[e.UserID] = n + 1
+		}
+	}
+
+	result := &FunnelResult{Steps: make([]FunnelStep, len(steps))}
+	for i, action := range steps {
+		step := &result.Steps[i]
+		step.Action = action
+		for _, n := range reached {
+			if n > i {
+				step.Users++
+			}
+		}
+		if step.Users == 0 {
+			continue
+		}
+		step.Conversion, step.OverallConversion = 1, 1
+		if i > 0 {
+			step.Conversion = float64(step.Users) / float64(result.Steps[i-1].Users)
+			step.OverallConversion = float64(step.Users) / float64(result.Steps[0].Users)
+		}
+	}
+	return result
+}
+
+// Funnel lists the events matching filter in r and computes a funnel over
+// them with the package-level Funnel function. The API has no funnel
+// endpoint, so every matching event is fetched; narrow filter where
+// possible. filter's Action, Cursor, and Offset are ignored.
+func (c *Client) Funnel(ctx context.Context, steps []string, r TimeRange, filter EventFilter) (*FunnelResult, error) {
+	if len(steps) == 0 {
+		return nil, &ValidationError{Field: "steps", Message: "must not be empty"}
+	}
+	for _, step := range steps {
+		if err := ValidateActionPattern(step); err != nil {
+			return nil, err
+		}
+	}
+
+	filter.Action, filter.Cursor, filter.Offset = "", "", 0
+	if filter.Limit == 0 {
+		filter.Limit = 100
+	}
+	if !r.Start.IsZero() {
+		filter.StartTime = &r.Start
+	}
+	if !r.End.IsZero() {
+		filter.EndTime = &r.End
+	}
+
+	var events []StoredEvent
+	for {
+		page, err := c.listFresh(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Events...)
+		if !page.HasMore || page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	return Funnel(events, steps), nil
+}
@@ -0,0 +1,88 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func funnelEvents() []StoredEvent {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(min int, user, action string) StoredEvent {
+		return StoredEvent{UserID: user, Action: action, Timestamp: base.Add(time.Duration(min) * time.Minute)}
+	}
+	return []StoredEvent{
+		at(0, "user_1", "cart.viewed"),
+		at(1, "user_1", "checkout.started"),
+		at(2, "user_1", "order.placed"),
+		at(0, "user_2", "cart.viewed"),
+		at(3, "user_2", "checkout.started"),
+		// user_3 places an order before viewing the cart, so only the
+		// first step counts.
+		at(0, "user_3", "order.placed"),
+		at(1, "user_3", "cart.viewed"),
+		at(5, "user_4", "checkout.started"),
+	}
+}
+
+func TestFunnel(t *testing.T) {
+	t.Parallel()
+
+	result := Funnel(funnelEvents(), []string{"cart.viewed", "checkout.*", "order.placed"})
+
+	want := []struct {
+		users      int
+		conversion float64
+		overall    float64
+	}{{3, 1, 1}, {2, 2.0 / 3, 2.0 / 3}, {1, 0.5, 1.0 / 3}}
+	for i, w := range want {
+		got := result.Steps[i]
+		if got.Users != w.users || got.Conversion != w.conversion || got.OverallConversion != w.overall {
+			t.Errorf("step %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	if result := Funnel(nil, []string{"cart.viewed"}); result.Steps[0].Users != 0 || result.Steps[0].Conversion != 0 {
+		t.Errorf("Funnel(nil) = %+v", result)
+	}
+}
+
+func TestClient_Funnel(t *testing.T) {
+	t.Parallel()
+
+	events := funnelEvents()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := EventList{Events: events[:4], HasMore: true, NextCursor: "next"}
+		if r.URL.Query().Get("cursor") == "next" {
+			page = EventList{Events: events[4:]}
+		}
+		if r.URL.Query().Get("action") != "" || r.URL.Query().Get("start_time") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	r := TimeRange{Start: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	result, err := client.Funnel(ctx, []string{"cart.viewed", "order.placed"}, r, EventFilter{Action: "ignored.action"})
+	if err != nil {
+		t.Fatalf("Funnel() error = %v", err)
+	}
+	if result.Steps[0].Users != 3 || result.Steps[1].Users != 1 {
+		t.Errorf("Funnel() = %+v", result)
+	}
+
+	if _, err := client.Funnel(ctx, nil, r, EventFilter{}); !IsClientValidationError(err) {
+		t.Errorf("Funnel() with no steps error = %v, want validation error", err)
+	}
+}
@@ -0,0 +1,172 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// ErrGroupDone is returned when a Group is used after Commit succeeded or
+// Rollback was called.
+var ErrGroupDone = errors.New("tryl: group already committed or rolled back")
+
+// Group collects events of a multi-step operation so they appear in the
+// log all together or not at all. Events are buffered client-side and sent
+// by Commit as one atomic batch sharing the group's ID, so a group holds at
+// most 100 events. A Group is safe for concurrent use.
+//
+//	group := client.BeginGroup(ctx)
+//	group.Add(tryl.Event{UserID: uid, Action: "order.created", TargetID: orderID})
+//	group.Add(tryl.Event{UserID: uid, Action: "payment.captured", TargetID: paymentID})
+//	if err := tx.Commit(); err != nil {
+//	    group.Rollback()
+//	    return err
+//	}
+//	_, err := group.Commit(ctx)
+type Group struct {
+	client *Client
+	ctx    context.Context
+	id     string
+
+	mu     sync.Mutex
+	events []Event
+	done   bool
+}
+
+// BeginGroup starts a Group. ctx is used to fill correlation IDs of added
+// events; see ContextWithCorrelationID.
+func (c *Client) BeginGroup(ctx context.Context) *Group {
+	return &Group{client: c, ctx: ctx, id: "grp_" + newIdempotencyKey()}
+}
+
+// ID returns the group ID the events are stored with. Use it with
+// EventFilter.GroupID to list them.
+func (g *Group) ID() string {
+	return g.id
+}
+
+// Add validates event and adds it to the group. It returns a
+// *ValidationError for an invalid event or a full group, and ErrGroupDone
+// after Commit or Rollback.
+func (g *Group) Add(event Event) error {
+	g.client.correlate(g.ctx, &event)
+	if err := g.client.validateEvent(&event); err != nil {
+		return err
+	}
+	if event.IdempotencyKey == "" {
+		event.IdempotencyKey = newIdempotencyKey()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done {
+		return ErrGroupDone
+	}
+	if len(g.events) >= maxBatchSize {
+		return &ValidationError{
+			Field:   "events",
+			Message: fmt.Sprintf("a group holds at most %d events", maxBatchSize),
+		}
+	}
+	g.events = append(g.events, event)
+	return nil
+}
+
+// Len returns the number of events added.
+func (g *Group) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.events)
+}
+
+// Commit sends the group's events as one atomic batch and returns their
+// responses in the order added. If the server rejects any event, none are
+// stored and the error describes the first rejection. After a failed
+// Commit the group stays open, so Commit may be called again; retries do
+// not duplicate events. Committing an empty group is a no-op.
+func (g *Group) Commit(ctx context.Context) ([]EventResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done {
+		return nil, ErrGroupDone
+	}
+	if len(g.events) == 0 {
+		g.done = true
+		return []EventResponse{}, nil
+	}
+
+	var results []EventResponse
+	err := g.client.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := g.client.doCommitGroup(ctx, g.id, g.events)
+		if err != nil {
+			return err
+		}
+		results = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.done = true
+	return results, nil
+}
+
+// Rollback discards the group's events. It is a no-op after Commit.
+func (g *Group) Rollback() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.done = true
+	g.events = nil
+}
+
+// doCommitGroup performs the atomic batch request without retries.
+func (c *Client) doCommitGroup(ctx context.Context, groupID string, events []Event) ([]EventResponse, error) {
+	if c.config.dryRun {
+		results := make([]EventResponse, len(events))
+		for i := range events {
+			results[i] = *c.dryRunResponse()
+		}
+		return results, nil
+	}
+
+	body, err := c.batchBody(batchRequest{Events: events, GroupID: groupID, Atomic: true})
+	if err != nil {
+		return nil, err
+	}
+	req := transport.Request{
+		Method: "POST",
+		Path:   "/v1/events/batch",
+		Body:   body,
+	}
+
+	// Groups bypass the agent, which cannot commit them atomically.
+	resp, err := c.ingestDirect(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var batchResp batchResponse
+	if err := json.Unmarshal(resp.Body, &batchResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	batchResp.reportWarnings(c, req)
+	if len(batchResp.Errors) > 0 {
+		e := batchResp.Errors[0]
+		return nil, fmt.Errorf("group event %d rejected: %w", e.Index, batchItemError(e))
+	}
+	if len(batchResp.Results) != len(events) {
+		return nil, fmt.Errorf("failed to parse response: got %d results for %d events", len(batchResp.Results), len(events))
+	}
+	c.stats.batches.Add(1)
+	c.stats.eventsSent.Add(uint64(len(events)))
+
+	return batchResp.Results, nil
+}
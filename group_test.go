@@ -0,0 +1,128 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGroup_Commit(t *testing.T) {
+	t.Parallel()
+
+	var got batchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"results": []EventResponse{{ID: "evt_1"}, {ID: "evt_2"}}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	group := client.BeginGroup(ctx)
+	if err := group.Add(Event{UserID: "user_1", Action: "order.created"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := group.Add(Event{UserID: "user_1", Action: "payment.captured"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	var verr *ValidationError
+	if err := group.Add(Event{Action: "payment.captured"}); !errors.As(err, &verr) {
+		t.Errorf("Add(invalid) error = %v, want *ValidationError", err)
+	}
+
+	results, err := group.Commit(ctx)
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if len(results) != 2 || results[1].ID != "evt_2" {
+		t.Errorf("Commit() = %v, want 2 results", results)
+	}
+	if !got.Atomic || got.GroupID != group.ID() || len(got.Events) != 2 {
+		t.Errorf("request = %+v, want atomic batch of 2 with group %s", got, group.ID())
+	}
+	if got.Events[0].IdempotencyKey == "" {
+		t.Error("group events were sent without idempotency keys")
+	}
+
+	if err := group.Add(Event{UserID: "user_1", Action: "order.shipped"}); !errors.Is(err, ErrGroupDone) {
+		t.Errorf("Add() after Commit error = %v, want ErrGroupDone", err)
+	}
+	if _, err := group.Commit(ctx); !errors.Is(err, ErrGroupDone) {
+		t.Errorf("second Commit() error = %v, want ErrGroupDone", err)
+	}
+}
+
+func TestGroup_CommitRejected(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"validation_error","message":"events[1]: action is reserved"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"results": []EventResponse{{ID: "evt_1"}, {ID: "evt_2"}}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	group := client.BeginGroup(ctx)
+	group.Add(Event{UserID: "user_1", Action: "order.created"})
+	group.Add(Event{UserID: "user_1", Action: "system.reset"})
+
+	var apiErr *APIError
+	if _, err := group.Commit(ctx); !errors.As(err, &apiErr) {
+		t.Fatalf("Commit() error = %v, want *APIError", err)
+	}
+	if group.Len() != 2 {
+		t.Errorf("Len() after failed Commit = %d, want 2", group.Len())
+	}
+	if _, err := group.Commit(ctx); err != nil {
+		t.Errorf("Commit() retry error = %v", err)
+	}
+}
+
+func TestGroup_Rollback(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	group := client.BeginGroup(ctx)
+	for i := 0; i < maxBatchSize; i++ {
+		if err := group.Add(Event{UserID: "user_1", Action: "row.deleted"}); err != nil {
+			t.Fatalf("Add() #%d error = %v", i, err)
+		}
+	}
+	var verr *ValidationError
+	if err := group.Add(Event{UserID: "user_1", Action: "row.deleted"}); !errors.As(err, &verr) {
+		t.Errorf("Add() to full group error = %v, want *ValidationError", err)
+	}
+
+	group.Rollback()
+	if group.Len() != 0 {
+		t.Errorf("Len() after Rollback = %d, want 0", group.Len())
+	}
+	if _, err := group.Commit(ctx); !errors.Is(err, ErrGroupDone) {
+		t.Errorf("Commit() after Rollback error = %v, want ErrGroupDone", err)
+	}
+}
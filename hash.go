@@ -0,0 +1,119 @@
+package tryl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrContentHashMissing is returned by VerifyEventHash for events stored
+// without a content hash.
+var ErrContentHashMissing = errors.New("tryl: event has no content hash")
+
+// ErrContentHashMismatch is returned by VerifyEventHash when an event's
+// content does not match its content hash.
+var ErrContentHashMismatch = errors.New("tryl: event content does not match its content hash")
+
+// hashedContent is the part of an event covered by its content hash.
+// Server-assigned fields (ID, timestamp, group) and the idempotency key are
+// excluded.
+type hashedContent struct {
+	UserID        string          `json:"user_id"`
+	TenantID      string          `json:"tenant_id,omitempty"`
+	Action        string          `json:"action"`
+	ActorID       string          `json:"actor_id,omitempty"`
+	TargetType    string          `json:"target_type,omitempty"`
+	TargetID      string          `json:"target_id,omitempty"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	Tags          []string        `json:"tags,omitempty"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	ParentEventID string          `json:"parent_event_id,omitempty"`
+	SchemaVersion int             `json:"schema_version,omitempty"`
+	ChainID       string          `json:"chain_id,omitempty"`
+	PrevHash      string          `json:"prev_hash,omitempty"`
+}
+
+// EventHash returns the content hash of event: the hex-encoded SHA-256 of
+// the canonical JSON (see CanonicalizeJSON) of its user, tenant, action, actor,
+// target, metadata, tags, correlation and parent IDs, schema version, and
+// chain link.
+// WithContentHash sets Event.ContentHash to this value.
+func EventHash(event Event) (string, error) {
+	return contentHash(hashedContent{
+		UserID:        event.UserID,
+		TenantID:      event.TenantID,
+		Action:        event.Action,
+		ActorID:       event.ActorID,
+		TargetType:    event.TargetType,
+		TargetID:      event.TargetID,
+		Metadata:      event.Metadata,
+		Tags:          event.Tags,
+		CorrelationID: event.CorrelationID,
+		ParentEventID: event.ParentEventID,
+		SchemaVersion: event.SchemaVersion,
+		ChainID:       event.ChainID,
+		PrevHash:      event.PrevHash,
+	})
+}
+
+// VerifyEventHash recomputes the content hash of a stored event and
+// compares it with StoredEvent.ContentHash, detecting events altered after
+// they were logged or corrupted in transit. It returns nil if they match,
+// ErrContentHashMissing if the event has no hash, and
+// ErrContentHashMismatch otherwise. Verify events as returned by the API:
+// metadata upgraded by WithMigrations no longer matches.
+func VerifyEventHash(event StoredEvent) error {
+	if event.ContentHash == "" {
+		return ErrContentHashMissing
+	}
+	sum, err := contentHash(hashedContent{
+		UserID:        event.UserID,
+		TenantID:      event.TenantID,
+		Action:        event.Action,
+		ActorID:       event.ActorID,
+		TargetType:    event.TargetType,
+		TargetID:      event.TargetID,
+		Metadata:      event.Metadata,
+		Tags:          event.Tags,
+		CorrelationID: event.CorrelationID,
+		ParentEventID: event.ParentEventID,
+		SchemaVersion: event.SchemaVersion,
+		ChainID:       event.ChainID,
+		PrevHash:      event.PrevHash,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrContentHashMismatch, err)
+	}
+	if sum != event.ContentHash {
+		return ErrContentHashMismatch
+	}
+	return nil
+}
+
+// hashContent sets event's content hash when WithContentHash is set.
+// Events whose metadata is not valid JSON, which can only be sent with
+// validation off, are left without a hash.
+func (c *Client) hashContent(event *Event) {
+	if !c.config.contentHash {
+		return
+	}
+	if sum, err := EventHash(*event); err == nil {
+		event.ContentHash = sum
+	}
+}
+
+// contentHash hashes the canonical JSON of content.
+func contentHash(content hashedContent) (string, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	canonical, err := CanonicalizeJSON(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
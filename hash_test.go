@@ -0,0 +1,38 @@
+package tryl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEventHash(t *testing.T) {
+	t.Parallel()
+
+	a := Event{UserID: "user_1", Action: "doc.updated", Metadata: json.RawMessage(`{"b": 2, "a": 1.0}`), IdempotencyKey: "k1"}
+	b := Event{UserID: "user_1", Action: "doc.updated", Metadata: json.RawMessage(`{"a":1,"b":2}`), IdempotencyKey: "k2"}
+	ha, err := EventHash(a)
+	if err != nil {
+		t.Fatalf("EventHash() error = %v", err)
+	}
+	if hb, _ := EventHash(b); ha != hb || len(ha) != 64 {
+		t.Errorf("EventHash() = %s and %s, want equal SHA-256 hex digests", ha, hb)
+	}
+	b.TargetID = "doc_1"
+	if hb, _ := EventHash(b); ha == hb {
+		t.Error("EventHash() unchanged after changing TargetID")
+	}
+
+	stored := StoredEvent{ID: "evt_1", UserID: a.UserID, Action: a.Action, Metadata: a.Metadata, ContentHash: ha}
+	if err := VerifyEventHash(stored); err != nil {
+		t.Errorf("VerifyEventHash() error = %v", err)
+	}
+	stored.Metadata = json.RawMessage(`{"a":1,"b":3}`)
+	if err := VerifyEventHash(stored); !errors.Is(err, ErrContentHashMismatch) {
+		t.Errorf("VerifyEventHash(tampered) error = %v, want ErrContentHashMismatch", err)
+	}
+	stored.ContentHash = ""
+	if err := VerifyEventHash(stored); !errors.Is(err, ErrContentHashMissing) {
+		t.Errorf("VerifyEventHash(no hash) error = %v, want ErrContentHashMissing", err)
+	}
+}
@@ -0,0 +1,98 @@
+package tryl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Actor is the identity an event is attributed to, as read from an
+// identity provider's claims by ActorFromClaims.
+type Actor struct {
+	// UserID is the authenticated user.
+	UserID string
+	// ActorID is who acted: UserID, or the impersonating user when the
+	// claims carry an "act" (RFC 8693 actor) claim.
+	ActorID string
+	// TenantID is the user's organization or tenant, if the claims carry
+	// one.
+	TenantID string
+}
+
+// Apply returns event with its empty UserID, ActorID, and TenantID set
+// from a:
+//
+//	actor, err := tryl.ActorFromClaims(claims)
+//	...
+//	client.Log(ctx, actor.Apply(tryl.Event{Action: "document.shared", TargetID: doc.ID}))
+func (a Actor) Apply(event Event) Event {
+	if event.UserID == "" {
+		event.UserID = a.UserID
+	}
+	if event.ActorID == "" {
+		event.ActorID = a.ActorID
+	}
+	if event.TenantID == "" {
+		event.TenantID = a.TenantID
+	}
+	return event
+}
+
+// tenantClaims are the claims ActorFromClaims reads the tenant from, in
+// order: Auth0, Clerk, and WorkOS organizations, Azure AD tenants, and
+// generic tenant claims.
+var tenantClaims = []string{"org_id", "tid", "tenant_id", "organization_id"}
+
+// ActorFromClaims builds an Actor from the claims of a verified JWT or
+// OIDC ID token, such as those decoded by go-oidc's IDToken.Claims or a
+// JWT middleware. UserID is the "sub" claim. TenantID is read from the
+// "org_id", "tid", "tenant_id", or "organization_id" claim, a Stytch B2B
+// session's "https://stytch.com/organization" claim, or a Firebase
+// token's "firebase.tenant" claim. An "act" claim sets ActorID to the
+// impersonating subject.
+//
+// ActorFromClaims does not verify anything; pass only claims of tokens
+// your authentication layer has verified. Claims without a subject are
+// reported as a *ValidationError.
+func ActorFromClaims(claims map[string]any) (Actor, error) {
+	sub := claimString(claims["sub"])
+	if sub == "" {
+		return Actor{}, &ValidationError{Field: "user_id", Message: "claims have no sub claim"}
+	}
+	actor := Actor{UserID: sub, ActorID: sub}
+
+	if act, ok := claims["act"].(map[string]any); ok {
+		if id := claimString(act["sub"]); id != "" {
+			actor.ActorID = id
+		}
+	}
+
+	for _, name := range tenantClaims {
+		if id := claimString(claims[name]); id != "" {
+			actor.TenantID = id
+			return actor, nil
+		}
+	}
+	if org, ok := claims["https://stytch.com/organization"].(map[string]any); ok {
+		actor.TenantID = claimString(org["organization_id"])
+	} else if firebase, ok := claims["firebase"].(map[string]any); ok {
+		actor.TenantID = claimString(firebase["tenant"])
+	}
+	return actor, nil
+}
+
+// claimString returns a string or numeric claim as a string, and "" for
+// other values.
+func claimString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case int, int64, uint64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package tryl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestActorFromClaims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+		want   Actor
+	}{
+		{"subject only", map[string]any{"sub": "user_1"}, Actor{UserID: "user_1", ActorID: "user_1"}},
+		{"auth0 organization", map[string]any{"sub": "auth0|42", "org_id": "org_7"}, Actor{UserID: "auth0|42", ActorID: "auth0|42", TenantID: "org_7"}},
+		{"azure tenant", map[string]any{"sub": "u", "tid": "tenant-guid"}, Actor{UserID: "u", ActorID: "u", TenantID: "tenant-guid"}},
+		{"numeric tenant", map[string]any{"sub": "u", "tenant_id": float64(1234)}, Actor{UserID: "u", ActorID: "u", TenantID: "1234"}},
+		{"stytch b2b", map[string]any{
+			"sub":                             "member-live-1",
+			"https://stytch.com/organization": map[string]any{"organization_id": "organization-live-1"},
+		}, Actor{UserID: "member-live-1", ActorID: "member-live-1", TenantID: "organization-live-1"}},
+		{"firebase tenant", map[string]any{"sub": "u", "firebase": map[string]any{"tenant": "tenant-a"}}, Actor{UserID: "u", ActorID: "u", TenantID: "tenant-a"}},
+		{"impersonation", map[string]any{"sub": "user_1", "act": map[string]any{"sub": "support_9"}}, Actor{UserID: "user_1", ActorID: "support_9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ActorFromClaims(tt.claims)
+			if err != nil {
+				t.Fatalf("ActorFromClaims() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ActorFromClaims() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	_, err := ActorFromClaims(map[string]any{"email": "a@example.com"})
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) || valErr.Field != "user_id" {
+		t.Errorf("ActorFromClaims(no sub) error = %v, want a user_id ValidationError", err)
+	}
+}
+
+func TestActor_Apply(t *testing.T) {
+	t.Parallel()
+
+	actor := Actor{UserID: "user_1", ActorID: "support_9", TenantID: "org_7"}
+	got := actor.Apply(Event{Action: "doc.viewed", TenantID: "org_override"})
+	want := Event{Action: "doc.viewed", UserID: "user_1", ActorID: "support_9", TenantID: "org_override"}
+	if got.UserID != want.UserID || got.ActorID != want.ActorID || got.TenantID != want.TenantID || got.Action != want.Action {
+		t.Errorf("Apply() = %+v, want %+v", got, want)
+	}
+}
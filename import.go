@@ -0,0 +1,413 @@
+package tryl
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ImportFormat is the file format read by ImportFile.
+type ImportFormat string
+
+const (
+	// ImportJSONL reads one JSON event per line, using the Event JSON
+	// fields. An RFC 3339 "timestamp" is kept as Event.Timestamp.
+	ImportJSONL ImportFormat = "jsonl"
+	// ImportCSV reads a CSV file with a header row. See ColumnMapping.
+	ImportCSV ImportFormat = "csv"
+)
+
+// ColumnMapping maps CSV header names to event fields.
+// Empty fields use the default column name shown in each comment.
+type ColumnMapping struct {
+	UserID     string // "user_id"
+	Action     string // "action"
+	ActorID    string // "actor_id"
+	TargetType string // "target_type"
+	TargetID   string // "target_id"
+	// Timestamp is a column holding when the action happened, kept as
+	// Event.Timestamp. Rows where it is empty are recorded at import time.
+	// Default: "timestamp"
+	Timestamp string
+	// TimestampLayout is the time.Parse layout of the Timestamp column.
+	// Values without a time zone are read as UTC. Default: time.RFC3339
+	TimestampLayout string
+	// Metadata is a column holding a JSON object. Default: "metadata"
+	Metadata string
+	// MetadataColumns are copied into metadata as string values, keyed by
+	// column name. They are merged over the Metadata column.
+	MetadataColumns []string
+}
+
+// withDefaults returns m with empty column names set to their defaults.
+func (m ColumnMapping) withDefaults() ColumnMapping {
+	set := func(field *string, def string) {
+		if *field == "" {
+			*field = def
+		}
+	}
+	set(&m.UserID, "user_id")
+	set(&m.Action, "action")
+	set(&m.ActorID, "actor_id")
+	set(&m.TargetType, "target_type")
+	set(&m.TargetID, "target_id")
+	set(&m.Timestamp, "timestamp")
+	set(&m.TimestampLayout, time.RFC3339)
+	set(&m.Metadata, "metadata")
+	return m
+}
+
+// ImportOption configures ImportFile.
+type ImportOption func(*importConfig) error
+
+// importConfig holds ImportFile settings.
+type importConfig struct {
+	mapping    ColumnMapping
+	checkpoint string
+	rate       float64
+	batchSize  int
+}
+
+// WithColumnMapping sets how CSV columns map to event fields.
+func WithColumnMapping(m ColumnMapping) ImportOption {
+	return func(c *importConfig) error {
+		c.mapping = m
+		return nil
+	}
+}
+
+// WithCheckpointFile makes an import resumable. After each batch the
+// number of processed records is saved to path; a later ImportFile call
+// with the same checkpoint skips those records. Failed records count as
+// processed and are not retried on resume.
+func WithCheckpointFile(path string) ImportOption {
+	return func(c *importConfig) error {
+		if path == "" {
+			return errors.New("checkpoint path cannot be empty")
+		}
+		c.checkpoint = path
+		return nil
+	}
+}
+
+// WithImportRateLimit caps the import at eventsPerSecond.
+// Default: unlimited
+func WithImportRateLimit(eventsPerSecond float64) ImportOption {
+	return func(c *importConfig) error {
+		if eventsPerSecond <= 0 {
+			return errors.New("rate limit must be positive")
+		}
+		c.rate = eventsPerSecond
+		return nil
+	}
+}
+
+// WithImportBatchSize sets the number of records sent per batch request.
+// Default: 100
+func WithImportBatchSize(n int) ImportOption {
+	return func(c *importConfig) error {
+		if n <= 0 || n > maxBatchSize {
+			return errors.New("batch size must be between 1 and 100")
+		}
+		c.batchSize = n
+		return nil
+	}
+}
+
+// ImportReport summarizes an ImportFile run.
+type ImportReport struct {
+	// Skipped is the number of records skipped because of a checkpoint.
+	Skipped int
+	// Read is the number of records read in this run.
+	Read int
+	// Succeeded is the number of events committed in this run.
+	Succeeded int
+	// Failures lists records that could not be parsed or were rejected.
+	Failures []ImportFailure
+}
+
+// ImportFailure describes a record that was not imported.
+type ImportFailure struct {
+	// Record is the 1-based position of the record in the file, not
+	// counting the CSV header or blank lines.
+	Record int
+	// Err is the parse, validation, or API error.
+	Err error
+}
+
+// importCheckpoint is the on-disk checkpoint format.
+type importCheckpoint struct {
+	Records int `json:"records"`
+}
+
+// ImportFile loads historical events from a JSONL or CSV file and sends
+// them in batches. Malformed or rejected records are reported in the
+// ImportReport and do not stop the import. An error is returned only if
+// the file cannot be read or ctx is done; the report covers the records
+// processed up to that point.
+func (c *Client) ImportFile(ctx context.Context, path string, format ImportFormat, opts ...ImportOption) (*ImportReport, error) {
+	config := importConfig{batchSize: maxBatchSize}
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return nil, fmt.Errorf("invalid option: %w", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer f.Close()
+
+	var next func() (Event, error)
+	switch format {
+	case ImportJSONL:
+		next = jsonlReader(f)
+	case ImportCSV:
+		next, err = csvReader(f, config.mapping.withDefaults())
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+
+	done, err := readCheckpoint(config.checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{}
+	var batch []Event
+	var batchRecords []int
+	record := 0
+	pace := time.Now()
+
+	flush := func() error {
+		if len(batch) > 0 {
+			if config.rate > 0 {
+				if err := sleepUntil(ctx, pace); err != nil {
+					return err
+				}
+				pace = pace.Add(time.Duration(float64(len(batch)) / config.rate * float64(time.Second)))
+			}
+
+			result, err := c.LogMany(ctx, batch, WithChunkSize(len(batch)), WithConcurrency(1))
+			if err != nil {
+				return err
+			}
+			for i, item := range result.Items {
+				if item.Status == BatchItemCommitted {
+					report.Succeeded++
+				} else {
+					report.Failures = append(report.Failures, ImportFailure{Record: batchRecords[i], Err: item.Error})
+				}
+			}
+			batch, batchRecords = batch[:0], batchRecords[:0]
+		}
+		return writeCheckpoint(config.checkpoint, record)
+	}
+
+	for {
+		event, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		record++
+		if record <= done {
+			report.Skipped++
+			continue
+		}
+		report.Read++
+
+		var parseErr *importParseError
+		if errors.As(err, &parseErr) {
+			report.Failures = append(report.Failures, ImportFailure{Record: record, Err: err})
+		} else if err != nil {
+			return report, fmt.Errorf("failed to read import file: %w", err)
+		} else {
+			batch = append(batch, event)
+			batchRecords = append(batchRecords, record)
+		}
+
+		if len(batch) >= config.batchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// importParseError is a malformed record; the import continues past it.
+type importParseError struct {
+	err error
+}
+
+func (e *importParseError) Error() string {
+	return fmt.Sprintf("malformed record: %v", e.err)
+}
+
+func (e *importParseError) Unwrap() error {
+	return e.err
+}
+
+// jsonlReader returns a function reading one event per non-blank line.
+func jsonlReader(r io.Reader) func() (Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	return func() (Event, error) {
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			var event Event
+			if err := json.Unmarshal(line, &event); err != nil {
+				return Event{}, &importParseError{err}
+			}
+			return event, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return Event{}, err
+		}
+		return Event{}, io.EOF
+	}
+}
+
+// csvReader reads the header row and returns a function mapping each
+// following row to an event.
+func csvReader(r io.Reader, m ColumnMapping) (func() (Event, error), error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range m.MetadataColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header has no metadata column %q", name)
+		}
+	}
+
+	return func() (Event, error) {
+		row, err := reader.Read()
+		if err != nil {
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				return Event{}, &importParseError{err}
+			}
+			return Event{}, err
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		event := Event{
+			UserID:     get(m.UserID),
+			Action:     get(m.Action),
+			ActorID:    get(m.ActorID),
+			TargetType: get(m.TargetType),
+			TargetID:   get(m.TargetID),
+		}
+
+		if raw := get(m.Timestamp); raw != "" {
+			ts, err := time.Parse(m.TimestampLayout, raw)
+			if err != nil {
+				return Event{}, &importParseError{fmt.Errorf("column %q: %w", m.Timestamp, err)}
+			}
+			event.Timestamp = &ts
+		}
+
+		metadata := map[string]any{}
+		if raw := get(m.Metadata); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+				return Event{}, &importParseError{fmt.Errorf("column %q: %w", m.Metadata, err)}
+			}
+		}
+		for _, name := range m.MetadataColumns {
+			metadata[name] = get(name)
+		}
+		if len(metadata) > 0 {
+			data, err := json.Marshal(metadata)
+			if err != nil {
+				return Event{}, &importParseError{err}
+			}
+			event.Metadata = data
+		}
+		return event, nil
+	}, nil
+}
+
+// readCheckpoint returns the number of records already processed.
+// A missing checkpoint file means none.
+func readCheckpoint(path string) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp importCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return 0, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return cp.Records, nil
+}
+
+// writeCheckpoint atomically records that records have been processed.
+func writeCheckpoint(path string, records int) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(importCheckpoint{Records: records})
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// sleepUntil waits until t or until ctx is done.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("context cancelled: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,203 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// importServer records every event it receives and accepts them all.
+func importServer(t *testing.T) (*httptest.Server, func() []Event) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		received = append(received, req.Events...)
+		mu.Unlock()
+
+		resp := batchResponse{Results: make([]EventResponse, len(req.Events))}
+		for i := range req.Events {
+			resp.Results[i] = EventResponse{ID: "evt_imported", Timestamp: time.Now()}
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Event(nil), received...)
+	}
+}
+
+func writeImportFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClient_ImportFile_CSV(t *testing.T) {
+	t.Parallel()
+
+	server, received := importServer(t)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	path := writeImportFile(t, "audit.csv", "who,what,ip,extra\n"+
+		"user_1,user.login,10.0.0.1,\"{\"\"source\"\":\"\"legacy\"\"}\"\n"+
+		"user_2,Bad Action,10.0.0.2,\n"+
+		"user_3,user.logout,10.0.0.3,\n")
+
+	report, err := client.ImportFile(context.Background(), path, ImportCSV,
+		WithColumnMapping(ColumnMapping{
+			UserID:          "who",
+			Action:          "what",
+			Metadata:        "extra",
+			MetadataColumns: []string{"ip"},
+		}))
+	if err != nil {
+		t.Fatalf("ImportFile() error = %v", err)
+	}
+
+	if report.Read != 3 || report.Succeeded != 2 {
+		t.Errorf("report = %+v, want 3 read and 2 succeeded", report)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Record != 2 || !IsClientValidationError(report.Failures[0].Err) {
+		t.Errorf("Failures = %+v, want record 2 with a validation error", report.Failures)
+	}
+
+	events := received()
+	if len(events) != 2 {
+		t.Fatalf("server received %d events, want 2", len(events))
+	}
+	var metadata map[string]any
+	json.Unmarshal(events[0].Metadata, &metadata)
+	if events[0].UserID != "user_1" || metadata["ip"] != "10.0.0.1" || metadata["source"] != "legacy" {
+		t.Errorf("first event = %+v (metadata %v)", events[0], metadata)
+	}
+}
+
+func TestClient_ImportFile_JSONLCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	server, received := importServer(t)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	path := writeImportFile(t, "events.jsonl",
+		`{"user_id":"user_1","action":"doc.created"}`+"\n"+
+			`not json`+"\n"+
+			"\n"+
+			`{"user_id":"user_3","action":"doc.created"}`+"\n")
+	checkpoint := filepath.Join(t.TempDir(), "import.checkpoint")
+
+	report, err := client.ImportFile(context.Background(), path, ImportJSONL,
+		WithCheckpointFile(checkpoint),
+		WithImportBatchSize(1))
+	if err != nil {
+		t.Fatalf("ImportFile() error = %v", err)
+	}
+	if report.Read != 3 || report.Succeeded != 2 || len(report.Failures) != 1 || report.Failures[0].Record != 2 {
+		t.Errorf("report = %+v, want 3 read, 2 succeeded, record 2 failed", report)
+	}
+
+	// Resuming skips everything already processed.
+	report, err = client.ImportFile(context.Background(), path, ImportJSONL, WithCheckpointFile(checkpoint))
+	if err != nil {
+		t.Fatalf("resumed ImportFile() error = %v", err)
+	}
+	if report.Skipped != 3 || report.Read != 0 {
+		t.Errorf("resumed report = %+v, want 3 skipped and 0 read", report)
+	}
+	if got := len(received()); got != 2 {
+		t.Errorf("server received %d events, want 2", got)
+	}
+}
+
+func TestClient_ImportFile_Timestamps(t *testing.T) {
+	t.Parallel()
+
+	server, received := importServer(t)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	want := time.Date(2019, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	csvPath := writeImportFile(t, "audit.csv", "user_id,action,happened\n"+
+		"user_1,user.login,2019-03-14 09:30:00\n"+
+		"user_2,user.login,\n"+
+		"user_3,user.login,14/03/2019\n")
+	report, err := client.ImportFile(context.Background(), csvPath, ImportCSV,
+		WithColumnMapping(ColumnMapping{Timestamp: "happened", TimestampLayout: time.DateTime}))
+	if err != nil {
+		t.Fatalf("ImportFile(CSV) error = %v", err)
+	}
+	if report.Succeeded != 2 || len(report.Failures) != 1 || report.Failures[0].Record != 3 {
+		t.Errorf("report = %+v, want 2 succeeded and record 3 failed", report)
+	}
+
+	jsonlPath := writeImportFile(t, "events.jsonl", `{"user_id":"user_4","action":"user.login","timestamp":"2019-03-14T09:30:00Z"}`+"\n")
+	if _, err := client.ImportFile(context.Background(), jsonlPath, ImportJSONL); err != nil {
+		t.Fatalf("ImportFile(JSONL) error = %v", err)
+	}
+
+	events := received()
+	if len(events) != 3 {
+		t.Fatalf("server received %d events, want 3", len(events))
+	}
+	for _, i := range []int{0, 2} {
+		if events[i].Timestamp == nil || !events[i].Timestamp.Equal(want) {
+			t.Errorf("%s timestamp = %v, want %v", events[i].UserID, events[i].Timestamp, want)
+		}
+	}
+	if events[1].Timestamp != nil {
+		t.Errorf("%s timestamp = %v, want nil for an empty column", events[1].UserID, events[1].Timestamp)
+	}
+}
+
+func TestClient_ImportFile_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	server, _ := importServer(t)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	path := writeImportFile(t, "events.jsonl",
+		`{"user_id":"user_1","action":"doc.created"}`+"\n"+
+			`{"user_id":"user_2","action":"doc.created"}`+"\n"+
+			`{"user_id":"user_3","action":"doc.created"}`+"\n")
+
+	start := time.Now()
+	_, err = client.ImportFile(context.Background(), path, ImportJSONL,
+		WithImportBatchSize(1),
+		WithImportRateLimit(20))
+	if err != nil {
+		t.Fatalf("ImportFile() error = %v", err)
+	}
+	// Three single-event batches at 20/s: the third starts after ~100ms.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("import took %v, want rate limiting to slow it down", elapsed)
+	}
+}
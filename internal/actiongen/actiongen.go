@@ -0,0 +1,167 @@
+// Package actiongen generates Go source for an action taxonomy.
+//
+// A registry is a YAML or JSON document listing the allowed actions and,
+// optionally, the shape of each action's metadata:
+//
+//	package: audit
+//	actions:
+//	  - name: user.created
+//	    description: A new user signed up.
+//	    metadata:
+//	      email: string
+//	      seats: int
+//
+// YAML registries use block-style mappings and sequences with plain or
+// quoted scalars; anchors, tags, and block scalars are not supported.
+//
+// For each action the generated file contains an Action<Name> constant and,
+// when metadata is declared, a <Name>Metadata struct. It also declares a
+// Taxonomy variable for use with tryl.WithTaxonomy.
+package actiongen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// Registry is the parsed form of a taxonomy registry file.
+type Registry struct {
+	Package string   `json:"package"`
+	Actions []Action `json:"actions"`
+}
+
+// Action is a single registry entry.
+type Action struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// goTypes maps registry metadata types to Go types.
+var goTypes = map[string]string{
+	"string":  "string",
+	"int":     "int",
+	"int64":   "int64",
+	"float":   "float64",
+	"bool":    "bool",
+	"time":    "time.Time",
+	"any":     "any",
+	"strings": "[]string",
+}
+
+// Parse decodes and validates a registry document. Documents starting
+// with "{" are decoded as JSON, others as YAML.
+func Parse(data []byte) (*Registry, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		doc, err := decodeYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse registry: %w", err)
+		}
+		data = doc
+	}
+
+	var reg Registry
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("failed to parse registry: %w", err)
+	}
+	if reg.Package == "" {
+		return nil, fmt.Errorf("registry: package is required")
+	}
+
+	seen := make(map[string]bool, len(reg.Actions))
+	for _, a := range reg.Actions {
+		if err := validation.ValidateAction(a.Name); err != nil {
+			return nil, fmt.Errorf("registry: invalid action %q: %w", a.Name, err)
+		}
+		if seen[a.Name] {
+			return nil, fmt.Errorf("registry: duplicate action %q", a.Name)
+		}
+		seen[a.Name] = true
+		for field, typ := range a.Metadata {
+			if _, ok := goTypes[typ]; !ok {
+				return nil, fmt.Errorf("registry: action %q field %q has unknown type %q", a.Name, field, typ)
+			}
+		}
+	}
+	return &reg, nil
+}
+
+// Generate renders formatted Go source for the registry.
+func Generate(reg *Registry) ([]byte, error) {
+	actions := append([]Action(nil), reg.Actions...)
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+
+	needsTime := false
+	for _, a := range actions {
+		for _, typ := range a.Metadata {
+			if typ == "time" {
+				needsTime = true
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by tryl-actiongen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", reg.Package)
+	buf.WriteString("import (\n")
+	if needsTime {
+		buf.WriteString("\t\"time\"\n\n")
+	}
+	buf.WriteString("\t\"github.com/joshuawatkins04/tryl_sdk\"\n)\n\n")
+
+	buf.WriteString("// Registered actions.\nconst (\n")
+	for _, a := range actions {
+		if a.Description != "" {
+			fmt.Fprintf(&buf, "\t// Action%s: %s\n", camel(a.Name), a.Description)
+		}
+		fmt.Fprintf(&buf, "\tAction%s = %q\n", camel(a.Name), a.Name)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// Taxonomy contains every registered action. Pass it to tryl.WithTaxonomy.\n")
+	buf.WriteString("var Taxonomy = tryl.MustTaxonomy(\n")
+	for _, a := range actions {
+		fmt.Fprintf(&buf, "\ttryl.ActionDef{Name: Action%s, Description: %q},\n", camel(a.Name), a.Description)
+	}
+	buf.WriteString(")\n")
+
+	for _, a := range actions {
+		if len(a.Metadata) == 0 {
+			continue
+		}
+		fields := make([]string, 0, len(a.Metadata))
+		for f := range a.Metadata {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+
+		name := camel(a.Name) + "Metadata"
+		fmt.Fprintf(&buf, "\n// %s is the metadata for %q events.\n", name, a.Name)
+		fmt.Fprintf(&buf, "type %s struct {\n", name)
+		for _, f := range fields {
+			fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", camel(f), goTypes[a.Metadata[f]], f+",omitempty")
+		}
+		buf.WriteString("}\n")
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated source: %w", err)
+	}
+	return out, nil
+}
+
+// camel converts "user.profile_updated" to "UserProfileUpdated".
+func camel(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package actiongen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	reg, err := Parse([]byte(`{
+		"package": "audit",
+		"actions": [
+			{"name": "user.created", "description": "A new user signed up.", "metadata": {"email": "string", "signed_up_at": "time"}},
+			{"name": "org.member_added"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	src, err := Generate(reg)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"package audit",
+		`ActionUserCreated = "user.created"`,
+		`ActionOrgMemberAdded = "org.member_added"`,
+		"type UserCreatedMetadata struct",
+		"SignedUpAt time.Time `json:\"signed_up_at,omitempty\"`",
+		"var Taxonomy = tryl.MustTaxonomy(",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated source missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"missing package":  `{"actions": [{"name": "user.created"}]}`,
+		"invalid action":   `{"package": "p", "actions": [{"name": "User Created"}]}`,
+		"duplicate action": `{"package": "p", "actions": [{"name": "a.b"}, {"name": "a.b"}]}`,
+		"unknown type":     `{"package": "p", "actions": [{"name": "a.b", "metadata": {"x": "uuid"}}]}`,
+	}
+
+	for name, doc := range tests {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("%s: Parse() error = nil, want error", name)
+		}
+	}
+}
+
+func TestParse_YAML(t *testing.T) {
+	t.Parallel()
+
+	want, err := Parse([]byte(`{
+		"package": "audit",
+		"actions": [
+			{"name": "user.created", "description": "A new user: signed up.", "metadata": {"email": "string", "tags": "strings"}},
+			{"name": "org.member_added", "description": "It's # not a comment"},
+			{"name": "org.deleted", "metadata": {"reason": "string"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Parse(JSON) error = %v", err)
+	}
+
+	got, err := Parse([]byte(`---
+# Actions emitted by the billing service.
+package: audit
+actions:
+  - name: user.created
+    description: "A new user: signed up."  # quoted because of the colon
+    metadata:
+      email: string
+      tags: strings
+  -   name: org.member_added
+      description: 'It''s # not a comment'
+  - name: org.deleted
+    metadata: {"reason": "string"}
+`))
+	if err != nil {
+		t.Fatalf("Parse(YAML) error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse(YAML) = %+v, want %+v", got, want)
+	}
+}
+
+func TestParse_InvalidYAML(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"tab indentation":   "package: p\nactions:\n\t- name: a.b\n",
+		"bad indentation":   "package: p\n  actions: []\n",
+		"missing colon":     "package: p\nactions\n",
+		"duplicate key":     "package: p\npackage: q\n",
+		"block scalar":      "package: p\nactions:\n  - name: a.b\n    description: |\n      text\n",
+		"unterminated":      "package: 'p\n",
+		"invalid flow":      "package: p\nactions: [name: a.b]\n",
+		"invalid yaml type": "package: p\nactions:\n  - name: a.b\n    metadata:\n      x: uuid\n",
+	}
+
+	for name, doc := range tests {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("%s: Parse() error = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package actiongen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// yamlLine is a line of a YAML document with its comment and indentation
+// removed.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// decodeYAML converts the block-style YAML used by registry files to JSON,
+// so both formats share one decoder. It supports nested mappings and
+// sequences, plain and quoted scalars, comments, and flow collections
+// written as JSON; anchors, tags, and block scalars are rejected. Every
+// plain scalar is a string, which is all a registry contains.
+func decodeYAML(data []byte) ([]byte, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		text := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		indent := len(raw) - len(text)
+		text = strings.TrimRight(stripComment(text), " \t")
+		if text == "" || indent == 0 && text == "---" {
+			continue
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: indent, text: text})
+	}
+	if len(lines) == 0 {
+		return []byte("{}"), nil
+	}
+
+	p := &yamlParser{lines: lines}
+	v, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return json.Marshal(v)
+}
+
+// stripComment removes a trailing "# comment" that is not inside a quoted
+// scalar.
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\',
+			quote == '\'' && c == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && scalarStart(text[:i]):
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// scalarStart reports whether a scalar may start after prefix, so that a
+// quote there opens a quoted scalar rather than being part of a plain one.
+func scalarStart(prefix string) bool {
+	prefix = strings.TrimRight(prefix, " ")
+	return prefix == "" || strings.HasSuffix(prefix, ":") || strings.HasSuffix(prefix, "-")
+}
+
+// yamlParser parses the lines of a document into nested maps, slices, and
+// strings.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence whose entries start at indent.
+func (p *yamlParser) block(indent int) (any, error) {
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	m := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		key, value, ok := splitKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+
+		if value != "" {
+			v, err := scalar(line.num, value)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+		// The value is the block on the following lines, which is indented
+		// further or is a sequence at the key's indentation.
+		m[key] = nil
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || next.indent == indent && isSequenceItem(next.text) {
+				v, err := p.block(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+			}
+		}
+	}
+	return m, nil
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || line.indent == indent && !isSequenceItem(line.text) {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+
+		rest := line.text[1:]
+		content := strings.TrimLeft(rest, " ")
+		if content == "" {
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				v, err := p.block(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, v)
+			} else {
+				items = append(items, nil)
+			}
+			continue
+		}
+
+		if _, _, ok := splitKey(content); ok {
+			// "- key: value" starts a mapping whose keys align with key.
+			col := indent + 1 + len(rest) - len(content)
+			p.lines[p.pos] = yamlLine{num: line.num, indent: col, text: content}
+			v, err := p.mapping(col)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+			continue
+		}
+
+		v, err := scalar(line.num, content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+		p.pos++
+	}
+	return items, nil
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" or "key:". It reports false for anything
+// else, including quoted and flow scalars containing ": ".
+func splitKey(text string) (key, value string, ok bool) {
+	if strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'") ||
+		strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[") {
+		return "", "", false
+	}
+	i := strings.Index(text, ": ")
+	switch {
+	case i >= 0:
+		value = strings.TrimLeft(text[i+2:], " ")
+	case strings.HasSuffix(text, ":"):
+		i = len(text) - 1
+	default:
+		return "", "", false
+	}
+	key = strings.TrimRight(text[:i], " ")
+	return key, value, key != ""
+}
+
+// scalar decodes a scalar value on line num.
+func scalar(num int, s string) (any, error) {
+	switch s[0] {
+	case '"':
+		var v string
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("line %d: invalid double-quoted string %s", num, s)
+		}
+		return v, nil
+	case '\'':
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return nil, fmt.Errorf("line %d: unterminated single-quoted string %s", num, s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case '{', '[':
+		var v any
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("line %d: flow collections must be valid JSON: %w", num, err)
+		}
+		return v, nil
+	case '&', '*', '!', '|', '>':
+		return nil, fmt.Errorf("line %d: anchors, tags, and block scalars are not supported", num)
+	}
+	return s, nil
+}
@@ -0,0 +1,471 @@
+// Package agent implements the local forwarding agent run by
+// cmd/tryl-agent.
+//
+// The agent accepts the SDK's ingestion requests (POST /v1/events and
+// POST /v1/events/batch) from clients configured with tryl.WithAgent,
+// acknowledges them once the events are queued, and forwards them to the
+// API in batches with its own tryl.Client, and so with its API key, not
+// the callers'. Set Config.APIKey to refuse requests made with any other
+// key. Events that do not fit in the memory queue are spilled to disk, as
+// is the queue on shutdown; spooled events are sent after a restart. A
+// spool file is deleted only once the API has acknowledged all of its
+// events, so a crash while sending them resends them rather than losing
+// them. Every event is given an IdempotencyKey when queued, so batches
+// resent after a failure are not stored twice.
+package agent
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+	"github.com/joshuawatkins04/tryl_sdk/trylprom"
+)
+
+// Config configures an Agent.
+type Config struct {
+	// Client sends events to the API. Its retry settings apply to each
+	// batch.
+	Client *tryl.Client
+	// APIKey, if set, is the only API key accepted from callers, normally
+	// Client's. Events are always sent with Client's key, so without it an
+	// application configured with another project's key would have its
+	// events stored in Client's project.
+	APIKey string
+	// SpoolDir, if set, is the directory events are spilled to when the
+	// memory queue is full and saved to on shutdown. Without it, requests
+	// are refused while the queue is full and Run drops unsent events.
+	SpoolDir string
+	// MaxQueue is the number of events held in memory. Default: 10000.
+	MaxQueue int
+	// BatchSize is the number of events sent per batch. Default: 100.
+	BatchSize int
+	// FlushInterval is how often queued events are sent. Default: 1 second.
+	FlushInterval time.Duration
+	// Logf, if set, logs delivery failures and rejected events.
+	Logf func(format string, args ...any)
+}
+
+// errQueueFull is returned by enqueue when events fit neither in memory
+// nor on disk.
+var errQueueFull = errors.New("agent queue is full")
+
+// spoolPrefix and spoolSuffix name spool files; the sequence number
+// between them orders them oldest first.
+const (
+	spoolPrefix = "spool-"
+	spoolSuffix = ".jsonl"
+)
+
+// Agent queues events received over HTTP and forwards them to the API.
+type Agent struct {
+	config  Config
+	metrics http.Handler
+
+	mu    sync.Mutex
+	queue []tryl.Event
+	// spool lists closed spool files, oldest first. spill is the file
+	// being written, with spilled events in it.
+	spool   []string
+	spill   *os.File
+	spilled int
+	seq     int
+	// loaded is the spool file whose events are at the front of the
+	// queue, and unacked the number of them not yet sent. It is deleted
+	// once they all are.
+	loaded  string
+	unacked int
+}
+
+// New creates an Agent, loading events spooled by a previous run.
+func New(config Config) (*Agent, error) {
+	if config.Client == nil {
+		return nil, errors.New("client is required")
+	}
+	if config.MaxQueue <= 0 {
+		config.MaxQueue = 10000
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+
+	a := &Agent{config: config}
+	a.metrics = trylprom.Handler(config.Client,
+		trylprom.WithGauge("agent_queued_events", "Events queued in the agent's memory.", func() float64 {
+			queued, _ := a.Len()
+			return float64(queued)
+		}),
+		trylprom.WithGauge("agent_spool_files", "Spool files waiting to be sent.", func() float64 {
+			_, spooled := a.Len()
+			return float64(spooled)
+		}),
+	)
+	if config.SpoolDir == "" {
+		return a, nil
+	}
+	if err := os.MkdirAll(config.SpoolDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	names, err := filepath.Glob(filepath.Join(config.SpoolDir, spoolPrefix+"*"+spoolSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	a.spool = names
+	if len(names) > 0 {
+		fmt.Sscanf(strings.TrimPrefix(filepath.Base(names[len(names)-1]), spoolPrefix), "%d", &a.seq)
+	}
+	a.refill()
+	return a, nil
+}
+
+// Len returns the number of events in memory and the number of spool
+// files waiting to be sent.
+func (a *Agent) Len() (queued, spooled int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	spooled = len(a.spool)
+	if a.spill != nil {
+		spooled++
+	}
+	return len(a.queue), spooled
+}
+
+// ServeHTTP handles the SDK's ingestion requests, GET /healthz, and
+// GET /metrics, which serves the agent's client and queue metrics in the
+// Prometheus text format.
+func (a *Agent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && !a.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid_api_key", "the agent forwards events with its own API key, which this request's key does not match")
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/events":
+		var event tryl.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid event: "+err.Error())
+			return
+		}
+		responses, ok := a.accept(w, []tryl.Event{event})
+		if ok {
+			writeJSON(w, http.StatusAccepted, responses[0])
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/events/batch":
+		var req struct {
+			Events []tryl.Event `json:"events"`
+			Atomic bool         `json:"atomic"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid batch: "+err.Error())
+			return
+		}
+		if req.Atomic {
+			writeError(w, http.StatusBadRequest, "invalid_request", "atomic groups must be sent to the API")
+			return
+		}
+		responses, ok := a.accept(w, req.Events)
+		if ok {
+			writeJSON(w, http.StatusAccepted, map[string]any{"results": responses, "errors": []any{}})
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/healthz":
+		queued, spooled := a.Len()
+		writeJSON(w, http.StatusOK, map[string]int{"queued": queued, "spool_files": spooled})
+	case r.Method == http.MethodGet && r.URL.Path == "/metrics":
+		a.metrics.ServeHTTP(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "the agent only accepts events; send other requests to the API")
+	}
+}
+
+// authorized reports whether r carries the API key the agent accepts.
+func (a *Agent) authorized(r *http.Request) bool {
+	if a.config.APIKey == "" {
+		return true
+	}
+	key, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(key), []byte(a.config.APIKey)) == 1
+}
+
+// accept queues events and returns their responses, writing an error
+// response and returning false if they cannot be queued.
+func (a *Agent) accept(w http.ResponseWriter, events []tryl.Event) ([]tryl.EventResponse, bool) {
+	now := time.Now().UTC()
+	responses := make([]tryl.EventResponse, len(events))
+	for i := range events {
+		if events[i].IdempotencyKey == "" {
+			events[i].IdempotencyKey = newKey()
+		}
+		responses[i] = tryl.EventResponse{ID: "agent_" + events[i].IdempotencyKey, Timestamp: now}
+	}
+	if err := a.enqueue(events); err != nil {
+		if errors.Is(err, errQueueFull) {
+			writeError(w, http.StatusServiceUnavailable, "queue_full", err.Error())
+		} else {
+			writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		}
+		return nil, false
+	}
+	return responses, true
+}
+
+// enqueue adds events to the memory queue, spilling them to disk if they
+// do not fit.
+func (a *Agent) enqueue(events []tryl.Event) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Once events are spilled, later ones are spilled too, so they are
+	// sent in order.
+	if len(a.spool) == 0 && a.spill == nil && len(a.queue)+len(events) <= a.config.MaxQueue {
+		a.queue = append(a.queue, events...)
+		return nil
+	}
+	if a.config.SpoolDir == "" {
+		return errQueueFull
+	}
+	return a.spillEvents(events)
+}
+
+// spillEvents appends events to the current spool file, starting a new
+// one when it holds MaxQueue events. a.mu must be held.
+func (a *Agent) spillEvents(events []tryl.Event) error {
+	if a.spill == nil {
+		a.seq++
+		f, err := os.OpenFile(a.spoolName(a.seq), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create spool file: %w", err)
+		}
+		a.spill, a.spilled = f, 0
+	}
+
+	w := bufio.NewWriter(a.spill)
+	enc := json.NewEncoder(w)
+	for i := range events {
+		if err := enc.Encode(events[i]); err != nil {
+			return fmt.Errorf("failed to spool event: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to spool events: %w", err)
+	}
+	a.spilled += len(events)
+	if a.spilled >= a.config.MaxQueue {
+		return a.closeSpill()
+	}
+	return nil
+}
+
+// closeSpill closes the current spool file and adds it to the spool.
+// a.mu must be held.
+func (a *Agent) closeSpill() error {
+	if a.spill == nil {
+		return nil
+	}
+	err := a.spill.Close()
+	a.spool = append(a.spool, a.spill.Name())
+	a.spill = nil
+	return err
+}
+
+// spoolName returns the path of spool file seq.
+func (a *Agent) spoolName(seq int) string {
+	return filepath.Join(a.config.SpoolDir, fmt.Sprintf("%s%020d%s", spoolPrefix, seq, spoolSuffix))
+}
+
+// refill loads the oldest spool file into the memory queue once it is
+// empty, closing the current spool file if it is the only one. The file
+// is kept until ack reports its events sent. a.mu must be held.
+func (a *Agent) refill() {
+	if len(a.queue) > 0 {
+		return
+	}
+	if len(a.spool) == 0 {
+		if a.closeSpill() != nil || len(a.spool) == 0 {
+			return
+		}
+	}
+
+	name := a.spool[0]
+	a.spool = a.spool[1:]
+	events, err := readSpool(name)
+	if err != nil {
+		a.logf("tryl-agent: reading %s: %v", name, err)
+	}
+	a.queue = append(a.queue, events...)
+	a.loaded, a.unacked = name, len(events)
+	a.ack(0)
+}
+
+// ack records that the first n events of the queue were sent, deleting
+// the spool file they were loaded from once all of its events are. a.mu
+// must be held.
+func (a *Agent) ack(n int) {
+	if a.loaded == "" {
+		return
+	}
+	a.unacked -= n
+	if a.unacked <= 0 {
+		os.Remove(a.loaded)
+		a.loaded, a.unacked = "", 0
+	}
+}
+
+// readSpool returns the events in a spool file. If the file ends in a
+// truncated line, left by a crash while spilling, the events before it are
+// returned with the error.
+func readSpool(name string) ([]tryl.Event, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []tryl.Event
+	dec := json.NewDecoder(f)
+	for {
+		var event tryl.Event
+		if err := dec.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return events, nil
+			}
+			return events, err
+		}
+		events = append(events, event)
+	}
+}
+
+// Run sends queued events until ctx is cancelled, then makes a last
+// attempt to send them and spools what remains.
+func (a *Agent) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.Flush(ctx)
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := a.Flush(flushCtx)
+			cancel()
+			if err == nil {
+				return nil
+			}
+			return a.save()
+		}
+	}
+}
+
+// Flush sends queued and spooled events until none remain or a batch
+// request fails, and returns the failure.
+func (a *Agent) Flush(ctx context.Context) error {
+	for {
+		a.mu.Lock()
+		a.refill()
+		batch := a.queue[:min(len(a.queue), a.config.BatchSize)]
+		batch = append([]tryl.Event(nil), batch...)
+		a.mu.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		result, err := a.config.Client.LogBatch(ctx, batch)
+		if err != nil {
+			a.logf("tryl-agent: sending %d events: %v", len(batch), err)
+			return err
+		}
+		for _, item := range result.Failed() {
+			a.logf("tryl-agent: event %s rejected: %v", batch[item.Index].IdempotencyKey, item.Error)
+		}
+
+		// Only Flush removes events, so the batch is still at the front.
+		a.mu.Lock()
+		a.queue = a.queue[len(batch):]
+		a.ack(len(batch))
+		a.mu.Unlock()
+	}
+}
+
+// save writes the memory queue to the spool, ahead of spooled events, so
+// it is sent after a restart. Without a spool directory the events are
+// dropped.
+func (a *Agent) save() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.closeSpill(); err != nil {
+		return err
+	}
+	if len(a.queue) == 0 {
+		return nil
+	}
+	if a.config.SpoolDir == "" {
+		a.logf("tryl-agent: dropping %d unsent events", len(a.queue))
+		a.queue = nil
+		return nil
+	}
+
+	// Sequence 0 sorts before every spool file written by spillEvents.
+	f, err := os.CreateTemp(a.config.SpoolDir, "save-")
+	if err != nil {
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for i := range a.queue {
+		enc.Encode(a.queue[i])
+	}
+	if err := errors.Join(w.Flush(), f.Close()); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	if err := os.Rename(f.Name(), a.spoolName(0)); err != nil {
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	// The saved queue includes the unsent events of the loaded file, which
+	// the rename replaced if it was an earlier save.
+	if a.loaded != "" && a.loaded != a.spoolName(0) {
+		os.Remove(a.loaded)
+	}
+	a.queue, a.loaded, a.unacked = nil, "", 0
+	return nil
+}
+
+func (a *Agent) logf(format string, args ...any) {
+	if a.config.Logf != nil {
+		a.config.Logf(format, args...)
+	}
+}
+
+func newKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{"error": map[string]string{"code": code, "message": message}})
+}
@@ -0,0 +1,257 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+// fakeAPI records the events of batch requests, failing them while down
+// is set.
+type fakeAPI struct {
+	down atomic.Bool
+
+	mu     sync.Mutex
+	events []tryl.Event
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.down.Load() || r.URL.Path != "/v1/events/batch" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"invalid_request","message":"down"}}`))
+		return
+	}
+	var req struct {
+		Events []tryl.Event `json:"events"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	f.mu.Lock()
+	f.events = append(f.events, req.Events...)
+	f.mu.Unlock()
+
+	results := make([]tryl.EventResponse, len(req.Events))
+	for i := range results {
+		results[i].ID = "evt_1"
+	}
+	json.NewEncoder(w).Encode(map[string]any{"results": results})
+}
+
+func (f *fakeAPI) actions() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, e := range f.events {
+		out = append(out, e.Action)
+	}
+	return out
+}
+
+func newAgent(t *testing.T, api *fakeAPI, config Config) *Agent {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	client, err := tryl.NewClient(testKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Client = client
+	a, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return a
+}
+
+func TestAgent_Forward(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeAPI{}
+	a := newAgent(t, api, Config{})
+	agentServer := httptest.NewServer(a)
+	defer agentServer.Close()
+
+	client, err := tryl.NewClient(testKey, tryl.WithAgent(agentServer.URL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := client.Log(ctx, tryl.Event{UserID: "user_1", Action: "doc.viewed"})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if !strings.HasPrefix(resp.ID, "agent_") {
+		t.Errorf("Log() ID = %q, want an agent ID", resp.ID)
+	}
+	result, err := client.LogBatch(ctx, []tryl.Event{
+		{UserID: "user_1", Action: "doc.edited"},
+		{UserID: "user_1", Action: "doc.shared"},
+	})
+	if err != nil || len(result.Succeeded()) != 2 {
+		t.Fatalf("LogBatch() = %+v, %v", result, err)
+	}
+	if got := api.actions(); len(got) != 0 {
+		t.Fatalf("API received %v before Flush", got)
+	}
+
+	if err := a.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := strings.Join(api.actions(), ","); got != "doc.viewed,doc.edited,doc.shared" {
+		t.Errorf("API received %s", got)
+	}
+	if queued, spooled := a.Len(); queued != 0 || spooled != 0 {
+		t.Errorf("Len() = %d, %d after Flush", queued, spooled)
+	}
+
+	metrics, err := http.Get(agentServer.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer metrics.Body.Close()
+	body, _ := io.ReadAll(metrics.Body)
+	for _, want := range []string{"tryl_events_sent_total 3\n", "tryl_agent_queued_events 0\n"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestAgent_SpillAndRestart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	api := &fakeAPI{}
+	api.down.Store(true)
+	a := newAgent(t, api, Config{SpoolDir: dir, MaxQueue: 2, BatchSize: 2})
+
+	for _, action := range []string{"a.one", "a.two", "a.three", "a.four", "a.five"} {
+		if err := a.enqueue([]tryl.Event{{UserID: "user_1", Action: action}}); err != nil {
+			t.Fatalf("enqueue(%s) error = %v", action, err)
+		}
+	}
+	if queued, spooled := a.Len(); queued != 2 || spooled != 2 {
+		t.Errorf("Len() = %d, %d, want 2 queued and 2 spool files", queued, spooled)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := a.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	names, _ := filepath.Glob(filepath.Join(dir, spoolPrefix+"*"))
+	if len(names) != 3 {
+		t.Fatalf("spool files after shutdown = %v, want 3", names)
+	}
+
+	api.down.Store(false)
+	restarted := newAgent(t, api, Config{SpoolDir: dir, MaxQueue: 2, BatchSize: 2})
+	if err := restarted.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := strings.Join(api.actions(), ","); got != "a.one,a.two,a.three,a.four,a.five" {
+		t.Errorf("API received %s", got)
+	}
+	if names, _ := os.ReadDir(dir); len(names) != 0 {
+		t.Errorf("spool directory not empty after Flush: %v", names)
+	}
+}
+
+func TestAgent_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	a := newAgent(t, &fakeAPI{}, Config{MaxQueue: 1})
+	server := httptest.NewServer(a)
+	defer server.Close()
+
+	for i, status := range []int{http.StatusAccepted, http.StatusServiceUnavailable} {
+		resp, err := http.Post(server.URL+"/v1/events", "application/json", strings.NewReader(`{"user_id":"user_1","action":"doc.viewed"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("request %d status = %d, want %d", i, resp.StatusCode, status)
+		}
+	}
+}
+
+func TestAgent_SpoolKeptUntilSent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	api := &fakeAPI{}
+	api.down.Store(true)
+	a := newAgent(t, api, Config{SpoolDir: dir, MaxQueue: 1, BatchSize: 1})
+	for _, action := range []string{"a.one", "a.two", "a.three"} {
+		if err := a.enqueue([]tryl.Event{{UserID: "user_1", Action: action}}); err != nil {
+			t.Fatalf("enqueue(%s) error = %v", action, err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := a.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Loading a spool file into memory does not delete it, so its events
+	// survive another failure.
+	restarted := newAgent(t, api, Config{SpoolDir: dir, MaxQueue: 1, BatchSize: 1})
+	if err := restarted.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() error = nil while the API is down")
+	}
+	names, _ := filepath.Glob(filepath.Join(dir, spoolPrefix+"*"))
+	if len(names) != 3 {
+		t.Fatalf("spool files after a failed send = %v, want 3", names)
+	}
+
+	api.down.Store(false)
+	if err := restarted.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := strings.Join(api.actions(), ","); got != "a.one,a.two,a.three" {
+		t.Errorf("API received %s", got)
+	}
+	if names, _ := os.ReadDir(dir); len(names) != 0 {
+		t.Errorf("spool directory not empty after Flush: %v", names)
+	}
+}
+
+func TestAgent_APIKey(t *testing.T) {
+	t.Parallel()
+
+	a := newAgent(t, &fakeAPI{}, Config{APIKey: testKey})
+	server := httptest.NewServer(a)
+	defer server.Close()
+
+	for key, status := range map[string]int{
+		testKey: http.StatusAccepted,
+		"actlog_test_ffffffffffffffffffffffffffffffffffffffff": http.StatusUnauthorized,
+	} {
+		client, err := tryl.NewClient(key, tryl.WithAgent(server.URL), tryl.WithRetry(tryl.RetryConfig{MaxAttempts: 1}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.Log(context.Background(), tryl.Event{UserID: "user_1", Action: "doc.viewed"})
+		var apiErr *tryl.APIError
+		switch {
+		case status == http.StatusAccepted && err != nil:
+			t.Errorf("Log() with the agent's key error = %v", err)
+		case status == http.StatusUnauthorized && (!errors.As(err, &apiErr) || apiErr.HTTPStatus != status):
+			t.Errorf("Log() with another key error = %v, want a 401 APIError", err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Package naming converts Go identifiers to the names used in actions.
+package naming
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts an identifier to snake_case ("InvoiceItem" becomes
+// "invoice_item", "HTTPLog" becomes "http_log"), dropping characters that
+// are not valid in actions.
+func SnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case r < unicode.MaxASCII && (unicode.IsLower(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case r == '_':
+			b.WriteRune(r)
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,22 @@
+package naming
+
+import "testing"
+
+func TestSnakeCase(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"Invoice":      "invoice",
+		"InvoiceItem":  "invoice_item",
+		"HTTPLog":      "http_log",
+		"UserID":       "user_id",
+		"getUser":      "get_user",
+		"Update-Plan!": "update_plan",
+		"_Private":     "private",
+	}
+	for in, want := range tests {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 )
 
 // Request represents an HTTP request to be made.
@@ -26,6 +28,21 @@ type Response struct {
 	Body       []byte
 	Headers    http.Header
 	RequestID  string
+
+	// buf holds Body when it was read into a buffer from pool.
+	buf  *bytes.Buffer
+	pool *sync.Pool
+}
+
+// Release returns the buffer holding Body to the transport's pool, so
+// Body must not be used afterwards. It is a no-op for responses not read
+// into a pooled buffer.
+func (r *Response) Release() {
+	if r.buf == nil {
+		return
+	}
+	r.pool.Put(r.buf)
+	r.buf, r.Body = nil, nil
 }
 
 // Transport handles HTTP communication with the API.
@@ -34,6 +51,20 @@ type Transport struct {
 	HTTPClient HTTPDoer
 	APIKey     string
 	UserAgent  string
+	// SDKHeader, if set, is sent as the X-Tryl-SDK header.
+	SDKHeader string
+	// OnResponse, if set, is called with every HTTP response before its
+	// body is read.
+	OnResponse func(resp *http.Response)
+	// Custom, if set, sends every request made by Do in place of
+	// HTTPClient. Open is not supported with a custom transport.
+	Custom Doer
+	// MaxBodyBytes, if positive, is the largest response body Do reads;
+	// larger bodies fail with ErrBodyTooLarge.
+	MaxBodyBytes int64
+	// Buffers, if set, pools the *bytes.Buffer values response bodies are
+	// read into. See Response.Release.
+	Buffers *sync.Pool
 }
 
 // HTTPDoer is an interface for HTTP operations.
@@ -41,11 +72,29 @@ type HTTPDoer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// Doer sends API requests.
+type Doer interface {
+	Do(ctx context.Context, req Request) (*Response, error)
+}
+
+// ErrOpenUnsupported is returned by Open when Custom is set.
+var ErrOpenUnsupported = errors.New("streaming requests are not supported by a custom transport")
+
+// ErrBodyTooLarge is returned by Do when a response body exceeds
+// MaxBodyBytes. It is not temporary, so requests failing with it are not
+// retried.
+var ErrBodyTooLarge error = permanentError("tryl: response body exceeds the size limit")
+
+// permanentError is an error that reports it is not temporary.
+type permanentError string
+
+func (e permanentError) Error() string   { return string(e) }
+func (e permanentError) Temporary() bool { return false }
+
 // Do executes an HTTP request and returns the response.
 func (t *Transport) Do(ctx context.Context, req Request) (*Response, error) {
-	fullURL := t.BaseURL + req.Path
-	if len(req.Query) > 0 {
-		fullURL += "?" + req.Query.Encode()
+	if t.Custom != nil {
+		return t.doCustom(ctx, req)
 	}
 
 	var bodyReader io.Reader
@@ -57,47 +106,158 @@ func (t *Transport) Do(ctx context.Context, req Request) (*Response, error) {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, req.Method, fullURL, bodyReader)
+	httpReq, err := t.newRequest(ctx, req, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("Authorization", "Bearer "+t.APIKey)
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("User-Agent", t.UserAgent)
+	resp, err := t.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	t.observe(resp)
 
-	for key, value := range req.Headers {
-		httpReq.Header.Set(key, value)
+	out := &Response{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+	if err := t.readBody(out, resp.Body); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// readBody reads body into resp, from a pooled buffer if Buffers is set,
+// enforcing MaxBodyBytes.
+func (t *Transport) readBody(resp *Response, body io.Reader) error {
+	if t.MaxBodyBytes > 0 {
+		body = io.LimitReader(body, t.MaxBodyBytes+1)
+	}
+
+	var err error
+	if t.Buffers != nil {
+		buf := t.Buffers.Get().(*bytes.Buffer)
+		buf.Reset()
+		_, err = buf.ReadFrom(body)
+		resp.Body, resp.buf, resp.pool = buf.Bytes(), buf, t.Buffers
+	} else {
+		resp.Body, err = io.ReadAll(body)
+	}
+	if err != nil {
+		resp.Release()
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if t.MaxBodyBytes > 0 && int64(len(resp.Body)) > t.MaxBodyBytes {
+		resp.Release()
+		return ErrBodyTooLarge
+	}
+	return nil
+}
+
+// doCustom sends req with Custom. OnResponse sees a response synthesized
+// from the one Custom returns.
+func (t *Transport) doCustom(ctx context.Context, req Request) (*Response, error) {
+	resp, err := t.Custom.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	if t.MaxBodyBytes > 0 && int64(len(resp.Body)) > t.MaxBodyBytes {
+		return nil, ErrBodyTooLarge
+	}
+	if resp.Headers == nil {
+		resp.Headers = http.Header{}
+	}
+	if resp.RequestID == "" {
+		resp.RequestID = resp.Headers.Get("X-Request-ID")
+	}
+
+	if t.OnResponse != nil {
+		httpResp := &http.Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Headers,
+			Request: &http.Request{
+				Method: req.Method,
+				URL:    &url.URL{Path: req.Path, RawQuery: req.Query.Encode()},
+				Header: http.Header{},
+			},
+		}
+		t.observe(httpResp)
+	}
+	return resp, nil
+}
+
+// Open starts a request whose body is streamed from body and returns the
+// response as soon as its headers arrive, without reading the body.
+// req.Body is ignored. The caller must close the response body.
+func (t *Transport) Open(ctx context.Context, req Request, body io.Reader) (*http.Response, error) {
+	if t.Custom != nil {
+		return nil, ErrOpenUnsupported
+	}
+	httpReq, err := t.newRequest(ctx, req, body)
+	if err != nil {
+		return nil, err
 	}
 
 	resp, err := t.HTTPClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
-	defer resp.Body.Close()
+	t.observe(resp)
+	return resp, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// observe passes the response to OnResponse.
+func (t *Transport) observe(resp *http.Response) {
+	if t.OnResponse != nil {
+		t.OnResponse(resp)
+	}
+}
+
+// newRequest builds an authenticated HTTP request.
+func (t *Transport) newRequest(ctx context.Context, req Request, body io.Reader) (*http.Request, error) {
+	fullURL := t.BaseURL + req.Path
+	if len(req.Query) > 0 {
+		fullURL += "?" + req.Query.Encode()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, fullURL, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	return &Response{
-		StatusCode: resp.StatusCode,
-		Body:       body,
-		Headers:    resp.Header,
-		RequestID:  resp.Header.Get("X-Request-ID"),
-	}, nil
+	httpReq.Header.Set("Authorization", "Bearer "+t.APIKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", t.UserAgent)
+	if t.SDKHeader != "" {
+		httpReq.Header.Set("X-Tryl-SDK", t.SDKHeader)
+	}
+
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	return httpReq, nil
 }
 
 // ErrorResponse is the API error response format.
 type ErrorResponse struct {
 	Error struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code    string        `json:"code"`
+		Message string        `json:"message"`
+		Details []ErrorDetail `json:"details"`
 	} `json:"error"`
 }
 
+// ErrorDetail is a field-level entry in an API error response.
+type ErrorDetail struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // ParseError parses an error response from the API.
 func ParseError(resp *Response) *ErrorResponse {
 	var errResp ErrorResponse
@@ -0,0 +1,453 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes (RFC 6455 section 5.2).
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// wsGUID is the handshake key suffix defined by RFC 6455.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxFrameSize bounds incoming frames to protect against malformed peers.
+const maxFrameSize = 16 << 20
+
+// ErrWebSocketClosed is returned for calls made after the connection closed.
+var ErrWebSocketClosed = errors.New("websocket connection closed")
+
+// wsRequest is the envelope for a request sent over the WebSocket.
+type wsRequest struct {
+	ID     uint64 `json:"id"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   any    `json:"body,omitempty"`
+}
+
+// wsMessage is any frame received from the server: either a reply to a
+// request (ID set) or a control message such as backpressure.
+type wsMessage struct {
+	ID        uint64          `json:"id"`
+	Type      string          `json:"type,omitempty"`
+	Status    int             `json:"status"`
+	RequestID string          `json:"request_id,omitempty"`
+	Body      json.RawMessage `json:"body,omitempty"`
+	PauseMS   int64           `json:"pause_ms,omitempty"`
+}
+
+// WebSocket multiplexes API requests over one persistent WebSocket
+// connection to /v1/ws. The connection is dialed lazily and redialed after
+// failures. Replies are correlated to requests by ID; the server may also
+// send {"type":"backpressure","pause_ms":N} to pause new requests.
+type WebSocket struct {
+	BaseURL   string
+	APIKey    string
+	UserAgent string
+	// SDKHeader, if set, is sent as the X-Tryl-SDK header of the handshake.
+	SDKHeader string
+	// Dialer dials the underlying connection. Default: net.Dialer.
+	Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+	// OnBackpressure is called when the server asks producers to pause (optional).
+	OnBackpressure func(pause time.Duration)
+
+	mu         sync.Mutex
+	conn       *wsConn
+	nextID     uint64
+	pending    map[uint64]chan wsMessage
+	pauseUntil time.Time
+}
+
+// Do sends req over the WebSocket and waits for the matching reply.
+// The reply is returned in the same form as an HTTP response.
+func (ws *WebSocket) Do(ctx context.Context, req Request) (*Response, error) {
+	if err := ws.waitForBackpressure(ctx); err != nil {
+		return nil, err
+	}
+
+	conn, err := ws.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.mu.Lock()
+	ws.nextID++
+	id := ws.nextID
+	replyCh := make(chan wsMessage, 1)
+	ws.pending[id] = replyCh
+	ws.mu.Unlock()
+
+	defer func() {
+		ws.mu.Lock()
+		delete(ws.pending, id)
+		ws.mu.Unlock()
+	}()
+
+	envelope := wsRequest{ID: id, Method: req.Method, Path: req.Path, Body: req.Body}
+	if len(req.Query) > 0 {
+		envelope.Query = req.Query.Encode()
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	if err := conn.writeFrame(opText, data); err != nil {
+		// The connection is gone; report it as closed so the call is retried
+		// on a fresh connection.
+		ws.drop(conn, err)
+		return nil, fmt.Errorf("failed to execute request: %w: %v", ErrWebSocketClosed, err)
+	}
+
+	select {
+	case reply, ok := <-replyCh:
+		if !ok {
+			return nil, fmt.Errorf("failed to execute request: %w", ErrWebSocketClosed)
+		}
+		return &Response{
+			StatusCode: reply.Status,
+			Body:       reply.Body,
+			Headers:    http.Header{},
+			RequestID:  reply.RequestID,
+		}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close closes the connection, failing any in-flight requests.
+func (ws *WebSocket) Close() error {
+	ws.mu.Lock()
+	conn := ws.conn
+	ws.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	conn.writeFrame(opClose, nil)
+	ws.drop(conn, ErrWebSocketClosed)
+	return nil
+}
+
+// waitForBackpressure blocks while the server has asked producers to pause.
+func (ws *WebSocket) waitForBackpressure(ctx context.Context) error {
+	ws.mu.Lock()
+	wait := time.Until(ws.pauseUntil)
+	ws.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// connect returns the live connection, dialing a new one if needed.
+func (ws *WebSocket) connect(ctx context.Context) (*wsConn, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.conn != nil {
+		return ws.conn, nil
+	}
+
+	conn, err := ws.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	ws.conn = conn
+	if ws.pending == nil {
+		ws.pending = make(map[uint64]chan wsMessage)
+	}
+	go ws.readLoop(conn)
+	return conn, nil
+}
+
+// drop discards conn and fails every pending request on it.
+func (ws *WebSocket) drop(conn *wsConn, _ error) {
+	ws.mu.Lock()
+	if ws.conn != conn {
+		ws.mu.Unlock()
+		return
+	}
+	ws.conn = nil
+	pending := ws.pending
+	ws.pending = make(map[uint64]chan wsMessage)
+	ws.mu.Unlock()
+
+	conn.netConn.Close()
+	for _, ch := range pending {
+		close(ch)
+	}
+}
+
+// readLoop dispatches replies and control messages until the connection fails.
+func (ws *WebSocket) readLoop(conn *wsConn) {
+	for {
+		op, payload, err := conn.readFrame()
+		if err != nil {
+			ws.drop(conn, err)
+			return
+		}
+
+		switch op {
+		case opPing:
+			conn.writeFrame(opPong, payload)
+		case opClose:
+			ws.drop(conn, ErrWebSocketClosed)
+			return
+		case opText:
+			var msg wsMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				continue
+			}
+			if msg.Type == "backpressure" {
+				pause := time.Duration(msg.PauseMS) * time.Millisecond
+				ws.mu.Lock()
+				ws.pauseUntil = time.Now().Add(pause)
+				ws.mu.Unlock()
+				if ws.OnBackpressure != nil {
+					ws.OnBackpressure(pause)
+				}
+				continue
+			}
+
+			// Deliver under the lock so drop cannot close the channel
+			// first, and forget the request so a duplicate reply is ignored.
+			ws.mu.Lock()
+			if ch, ok := ws.pending[msg.ID]; ok {
+				delete(ws.pending, msg.ID)
+				select {
+				case ch <- msg:
+				default:
+				}
+			}
+			ws.mu.Unlock()
+		}
+	}
+}
+
+// dial opens the connection and performs the opening handshake.
+func (ws *WebSocket) dial(ctx context.Context) (*wsConn, error) {
+	u, err := url.Parse(ws.BaseURL + "/v1/ws")
+	if err != nil {
+		return nil, err
+	}
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+	host := u.Host
+	if u.Port() == "" {
+		if secure {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	dialer := ws.Dialer
+	if dialer == nil {
+		dialer = (&net.Dialer{}).DialContext
+	}
+	netConn, err := dialer(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if secure {
+		tlsConn := tls.Client(netConn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+		netConn = tlsConn
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Path: u.Path},
+		Host:   u.Host,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+			"Authorization":         {"Bearer " + ws.APIKey},
+			"User-Agent":            {ws.UserAgent},
+		},
+	}
+	if ws.SDKHeader != "" {
+		req.Header.Set("X-Tryl-SDK", ws.SDKHeader)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+		defer netConn.SetDeadline(time.Time{})
+	}
+	if err := req.Write(netConn); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		netConn.Close()
+		return nil, fmt.Errorf("websocket handshake failed: HTTP %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		netConn.Close()
+		return nil, errors.New("websocket handshake failed: invalid Sec-WebSocket-Accept")
+	}
+
+	return &wsConn{netConn: netConn, br: br, client: true}, nil
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value for a handshake key.
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// wsConn implements WebSocket framing over a net.Conn.
+type wsConn struct {
+	netConn net.Conn
+	br      *bufio.Reader
+	// client is true for client connections, whose frames must be masked.
+	client bool
+
+	writeMu sync.Mutex
+}
+
+// writeFrame writes a single unfragmented frame.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	header := []byte{0x80 | op, 0}
+	n := len(payload)
+	switch {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	data := payload
+	if c.client {
+		header[1] |= 0x80
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		header = append(header, mask[:]...)
+		data = make([]byte, n)
+		for i := range payload {
+			data[i] = payload[i] ^ mask[i%4]
+		}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.netConn.Write(header); err != nil {
+		return err
+	}
+	_, err := c.netConn.Write(data)
+	return err
+}
+
+// readFrame reads one message, reassembling fragmented frames.
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var message []byte
+	var messageOp byte
+
+	for {
+		var head [2]byte
+		if _, err := io.ReadFull(c.br, head[:]); err != nil {
+			return 0, nil, err
+		}
+		fin := head[0]&0x80 != 0
+		op := head[0] & 0x0F
+		masked := head[1]&0x80 != 0
+		n := uint64(head[1] & 0x7F)
+
+		switch n {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return 0, nil, err
+			}
+			n = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return 0, nil, err
+			}
+			n = binary.BigEndian.Uint64(ext[:])
+		}
+		if n > maxFrameSize || uint64(len(message))+n > maxFrameSize {
+			return 0, nil, errors.New("websocket frame too large")
+		}
+
+		var mask [4]byte
+		if masked {
+			if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+				return 0, nil, err
+			}
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			return 0, nil, err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+
+		// Control frames may be interleaved with fragments and are never fragmented.
+		if op >= opClose {
+			return op, payload, nil
+		}
+		if op != 0 {
+			messageOp = op
+		}
+		message = append(message, payload...)
+		if fin {
+			return messageOp, message, nil
+		}
+	}
+}
@@ -12,8 +12,18 @@ var (
 	ErrAPIKeyInvalidFormat = errors.New("API key must start with actlog_live_ or actlog_test_")
 	// ErrAPIKeyTooShort indicates the API key is too short.
 	ErrAPIKeyTooShort = errors.New("API key must be at least 44 characters")
+
+	// ErrServiceAccountTokenInvalidFormat indicates the service account
+	// token format is invalid.
+	ErrServiceAccountTokenInvalidFormat = errors.New("service account token must start with actlog_sa_")
+	// ErrServiceAccountTokenTooShort indicates the service account token is
+	// too short.
+	ErrServiceAccountTokenTooShort = errors.New("service account token must be at least 42 characters")
 )
 
+// ServiceAccountTokenPrefix starts every service account token.
+const ServiceAccountTokenPrefix = "actlog_sa_"
+
 // ValidateAPIKey validates the API key format according to server-side rules.
 // The API key must:
 // - Start with "actlog_live_" or "actlog_test_"
@@ -47,3 +57,21 @@ func IsLiveKey(apiKey string) bool {
 func IsTestKey(apiKey string) bool {
 	return strings.HasPrefix(apiKey, "actlog_test_")
 }
+
+// ValidateServiceAccountToken validates the service account token format.
+// The token must:
+// - Start with "actlog_sa_"
+// - Be at least 42 characters long (prefix 10 chars + 32 random chars)
+//
+// Returns nil if valid, or a descriptive error if invalid.
+func ValidateServiceAccountToken(token string) error {
+	if !strings.HasPrefix(token, ServiceAccountTokenPrefix) {
+		return ErrServiceAccountTokenInvalidFormat
+	}
+
+	if len(token) < 42 {
+		return ErrServiceAccountTokenTooShort
+	}
+
+	return nil
+}
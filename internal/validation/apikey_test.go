@@ -164,3 +164,46 @@ func TestIsTestKey(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateServiceAccountToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:    "valid token",
+			token:   "actlog_sa_1234567890abcdef1234567890abcdef",
+			wantErr: nil,
+		},
+		{
+			name:    "empty token",
+			token:   "",
+			wantErr: ErrServiceAccountTokenInvalidFormat,
+		},
+		{
+			name:    "API key",
+			token:   "actlog_live_1234567890abcdef1234567890abcdef",
+			wantErr: ErrServiceAccountTokenInvalidFormat,
+		},
+		{
+			name:    "token too short",
+			token:   "actlog_sa_123",
+			wantErr: ErrServiceAccountTokenTooShort,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateServiceAccountToken(tt.token)
+			if err != tt.wantErr {
+				t.Errorf("ValidateServiceAccountToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -13,6 +13,18 @@ var actionRegexp = regexp.MustCompile(`^[a-z][a-z0-9_.]*[a-z0-9]$`)
 
 const maxFieldLength = 255
 
+// SystemUserPrefix marks user IDs of system processes, such as
+// "system:billing-cron". The name after the prefix is required.
+const SystemUserPrefix = "system:"
+
+// tagRegexp matches the server-side tag format.
+var tagRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+const (
+	maxTagLength = 32
+	maxTags      = 10
+)
+
 // FieldError represents a validation error for a specific field.
 type FieldError struct {
 	Field   string
@@ -39,6 +51,30 @@ type EventValidator interface {
 	GetMetadata() json.RawMessage
 }
 
+// CorrelatedEvent is implemented by events that carry a correlation ID.
+// ValidateEvent checks the correlation ID of events that implement it.
+type CorrelatedEvent interface {
+	GetCorrelationID() string
+}
+
+// LinkedEvent is implemented by events that can reference a parent event.
+// ValidateEvent checks the parent ID of events that implement it.
+type LinkedEvent interface {
+	GetParentEventID() string
+}
+
+// TenantEvent is implemented by events that carry a tenant ID.
+// ValidateEvent checks the tenant ID of events that implement it.
+type TenantEvent interface {
+	GetTenantID() string
+}
+
+// TaggedEvent is implemented by events that carry tags. ValidateEvent
+// checks the tags of events that implement it.
+type TaggedEvent interface {
+	GetTags() []string
+}
+
 // ValidateEvent validates an event according to server-side rules.
 // Server validation source: internal/models/event.go:129-168
 //
@@ -55,6 +91,9 @@ func ValidateEvent(e EventValidator) error {
 			Value:   truncateForDisplay(e.GetUserID()),
 		}
 	}
+	if e.GetUserID() == SystemUserPrefix {
+		return &FieldError{Field: "user_id", Message: "system user requires a name", Value: e.GetUserID()}
+	}
 
 	// Action validation (required)
 	if e.GetAction() == "" {
@@ -111,6 +150,65 @@ func ValidateEvent(e EventValidator) error {
 		}
 	}
 
+	if tenant, ok := e.(TenantEvent); ok && len(tenant.GetTenantID()) > maxFieldLength {
+		return &FieldError{
+			Field:   "tenant_id",
+			Message: fmt.Sprintf("must be %d characters or less", maxFieldLength),
+			Value:   truncateForDisplay(tenant.GetTenantID()),
+		}
+	}
+
+	if correlated, ok := e.(CorrelatedEvent); ok && len(correlated.GetCorrelationID()) > maxFieldLength {
+		return &FieldError{
+			Field:   "correlation_id",
+			Message: fmt.Sprintf("must be %d characters or less", maxFieldLength),
+			Value:   truncateForDisplay(correlated.GetCorrelationID()),
+		}
+	}
+
+	if linked, ok := e.(LinkedEvent); ok && len(linked.GetParentEventID()) > maxFieldLength {
+		return &FieldError{
+			Field:   "parent_event_id",
+			Message: fmt.Sprintf("must be %d characters or less", maxFieldLength),
+			Value:   truncateForDisplay(linked.GetParentEventID()),
+		}
+	}
+
+	if tagged, ok := e.(TaggedEvent); ok {
+		if err := ValidateTags("tags", tagged.GetTags()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateTags validates a list of tags: at most 10, each lowercase
+// alphanumeric with dots, dashes, or underscores and at most 32
+// characters. field names the field in errors.
+func ValidateTags(field string, tags []string) error {
+	if len(tags) > maxTags {
+		return &FieldError{
+			Field:   field,
+			Message: fmt.Sprintf("must contain %d tags or fewer (got %d)", maxTags, len(tags)),
+		}
+	}
+	for _, tag := range tags {
+		if len(tag) > maxTagLength {
+			return &FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("tags must be %d characters or less", maxTagLength),
+				Value:   truncateForDisplay(tag),
+			}
+		}
+		if !tagRegexp.MatchString(tag) {
+			return &FieldError{
+				Field:   field,
+				Message: "tags must be lowercase alphanumeric with dots, dashes, or underscores (e.g., 'billing', 'pii')",
+				Value:   tag,
+			}
+		}
+	}
 	return nil
 }
 
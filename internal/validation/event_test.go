@@ -14,6 +14,7 @@ type mockEvent struct {
 	TargetType string
 	TargetID   string
 	Metadata   json.RawMessage
+	TenantID   string
 }
 
 func (m *mockEvent) GetUserID() string          { return m.UserID }
@@ -22,6 +23,7 @@ func (m *mockEvent) GetActorID() string         { return m.ActorID }
 func (m *mockEvent) GetTargetType() string      { return m.TargetType }
 func (m *mockEvent) GetTargetID() string        { return m.TargetID }
 func (m *mockEvent) GetMetadata() json.RawMessage { return m.Metadata }
+func (m *mockEvent) GetTenantID() string          { return m.TenantID }
 
 func TestValidateEvent(t *testing.T) {
 	t.Parallel()
@@ -52,6 +54,33 @@ func TestValidateEvent(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "tenant_id too long",
+			event: &mockEvent{
+				UserID:   "user_123",
+				Action:   "user.created",
+				TenantID: strings.Repeat("t", 256),
+			},
+			wantErr:   true,
+			wantField: "tenant_id",
+		},
+		{
+			name: "system user",
+			event: &mockEvent{
+				UserID: "system:billing-cron",
+				Action: "invoice.generated",
+			},
+			wantErr: false,
+		},
+		{
+			name: "system user without name",
+			event: &mockEvent{
+				UserID: "system:",
+				Action: "invoice.generated",
+			},
+			wantErr:   true,
+			wantField: "user_id",
+		},
 		{
 			name: "missing user_id",
 			event: &mockEvent{
@@ -366,6 +395,38 @@ func TestValidateAction(t *testing.T) {
 	}
 }
 
+func TestValidateTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tags    []string
+		wantErr bool
+	}{
+		{name: "none", tags: nil},
+		{name: "valid", tags: []string{"billing", "security", "pii", "q4-2026", "team.payments"}},
+		{name: "uppercase", tags: []string{"Billing"}, wantErr: true},
+		{name: "empty tag", tags: []string{""}, wantErr: true},
+		{name: "space", tags: []string{"two words"}, wantErr: true},
+		{name: "too long", tags: []string{strings.Repeat("a", 33)}, wantErr: true},
+		{name: "max length", tags: []string{strings.Repeat("a", 32)}},
+		{name: "too many", tags: strings.Fields("a b c d e f g h i j k"), wantErr: true},
+		{name: "max count", tags: strings.Fields("a b c d e f g h i j")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateTags("tags", tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestFieldError_Error(t *testing.T) {
 	t.Parallel()
 
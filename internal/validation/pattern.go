@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateActionPattern validates an action filter that may contain "*"
+// wildcards (e.g., "user.*", "*.created").
+// A pattern without wildcards must be a valid action. Each wildcard stands
+// in for an action fragment, so "user.*" and "*.created" are accepted while
+// "User.*" and "user-*" are not. Consecutive wildcards are rejected.
+func ValidateActionPattern(pattern string) error {
+	if pattern == "" {
+		return &FieldError{Field: "action", Message: "is required"}
+	}
+	if len(pattern) > maxFieldLength {
+		return &FieldError{
+			Field:   "action",
+			Message: fmt.Sprintf("must be %d characters or less", maxFieldLength),
+			Value:   truncateForDisplay(pattern),
+		}
+	}
+	if !strings.Contains(pattern, "*") {
+		return ValidateAction(pattern)
+	}
+	if strings.Contains(pattern, "**") {
+		return &FieldError{
+			Field:   "action",
+			Message: "must not contain consecutive wildcards",
+			Value:   pattern,
+		}
+	}
+	if !actionRegexp.MatchString(strings.ReplaceAll(pattern, "*", "xx")) {
+		return &FieldError{
+			Field:   "action",
+			Message: "must be lowercase alphanumeric with dots, underscores, or '*' wildcards (e.g., 'user.*', '*.created')",
+			Value:   pattern,
+		}
+	}
+	return nil
+}
+
+// MatchAction reports whether action matches pattern.
+// A "*" in the pattern matches any sequence of characters, including dots.
+// A pattern without wildcards must equal the action exactly.
+func MatchAction(pattern, action string) bool {
+	p, a := 0, 0
+	star, mark := -1, 0
+
+	for a < len(action) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, a
+			p++
+		case p < len(pattern) && pattern[p] == action[a]:
+			p++
+			a++
+		case star >= 0:
+			// Backtrack: let the last wildcard absorb one more character.
+			p = star + 1
+			mark++
+			a = mark
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
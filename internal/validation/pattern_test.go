@@ -0,0 +1,63 @@
+package validation
+
+import "testing"
+
+func TestValidateActionPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "user.created", wantErr: false},
+		{pattern: "user.*", wantErr: false},
+		{pattern: "*.created", wantErr: false},
+		{pattern: "*", wantErr: false},
+		{pattern: "org.*.added", wantErr: false},
+		{pattern: "", wantErr: true},
+		{pattern: "User.*", wantErr: true},
+		{pattern: "user-*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.pattern, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateActionPattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateActionPattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchAction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		action  string
+		want    bool
+	}{
+		{pattern: "user.created", action: "user.created", want: true},
+		{pattern: "user.created", action: "user.deleted", want: false},
+		{pattern: "user.*", action: "user.created", want: true},
+		{pattern: "user.*", action: "user.profile.updated", want: true},
+		{pattern: "user.*", action: "org.created", want: false},
+		{pattern: "*.created", action: "org.created", want: true},
+		{pattern: "*.created", action: "org.created_at", want: false},
+		{pattern: "*", action: "anything.goes", want: true},
+		{pattern: "org.*.added", action: "org.member.added", want: true},
+		{pattern: "org.*.added", action: "org.member.removed", want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.pattern+"/"+tt.action, func(t *testing.T) {
+			t.Parallel()
+			if got := MatchAction(tt.pattern, tt.action); got != tt.want {
+				t.Errorf("MatchAction(%q, %q) = %v, want %v", tt.pattern, tt.action, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package tryl
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// validateKeyRestrictions checks the IP ranges and origins of a key
+// request, so a typo does not leave a key unusable or unrestricted.
+func validateKeyRestrictions(cidrs, origins []string) error {
+	for _, cidr := range cidrs {
+		if _, err := netip.ParsePrefix(cidr); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(cidr); err == nil {
+			continue
+		}
+		return &ValidationError{
+			Field:   "allowed_cidrs",
+			Message: "must be an IP address or CIDR range (e.g., '203.0.113.0/24')",
+			Value:   cidr,
+		}
+	}
+	for _, origin := range origins {
+		if !validOrigin(origin) {
+			return &ValidationError{
+				Field:   "allowed_origins",
+				Message: "must be an http or https origin without a path (e.g., 'https://app.example.com', 'https://*.example.com')",
+				Value:   origin,
+			}
+		}
+	}
+	return nil
+}
+
+// validOrigin reports whether origin is a scheme and host, optionally
+// with a port and a leading "*." wildcard subdomain.
+func validOrigin(origin string) bool {
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return u.Hostname() != "" && u.User == nil && u.Path == "" && u.RawQuery == "" && u.Fragment == "" &&
+		!strings.Contains(u.Host, "*") && !strings.HasSuffix(origin, "?") && !strings.HasSuffix(origin, "#")
+}
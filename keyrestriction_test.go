@@ -0,0 +1,69 @@
+package tryl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateKeyRestrictions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cidrs   []string
+		origins []string
+		field   string
+	}{
+		{name: "valid", cidrs: []string{"203.0.113.0/24", "2001:db8::/32", "198.51.100.7"}, origins: []string{"https://app.example.com", "http://localhost:3000", "https://*.example.com"}},
+		{name: "bad CIDR", cidrs: []string{"203.0.113.0/33"}, field: "allowed_cidrs"},
+		{name: "hostname", cidrs: []string{"example.com"}, field: "allowed_cidrs"},
+		{name: "origin with path", origins: []string{"https://app.example.com/login"}, field: "allowed_origins"},
+		{name: "origin without scheme", origins: []string{"app.example.com"}, field: "allowed_origins"},
+		{name: "ftp origin", origins: []string{"ftp://example.com"}, field: "allowed_origins"},
+		{name: "inner wildcard", origins: []string{"https://app.*.example.com"}, field: "allowed_origins"},
+		{name: "trailing query", origins: []string{"https://app.example.com?"}, field: "allowed_origins"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateKeyRestrictions(tt.cidrs, tt.origins)
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("validateKeyRestrictions() error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := AsValidationError(err)
+			if !ok || validationErr.Field != tt.field {
+				t.Errorf("validateKeyRestrictions() error = %v, want a %s *ValidationError", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestUpdateAPIKeyRequest_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	name := "renamed"
+	tests := []struct {
+		req  UpdateAPIKeyRequest
+		want string
+	}{
+		{UpdateAPIKeyRequest{Name: &name}, `{"name":"renamed"}`},
+		{UpdateAPIKeyRequest{AllowedCIDRs: []string{"10.0.0.0/8"}}, `{"allowed_cidrs":["10.0.0.0/8"]}`},
+		{UpdateAPIKeyRequest{AllowedOrigins: []string{}}, `{"allowed_origins":[]}`},
+		{UpdateAPIKeyRequest{Scopes: []string{}}, `{"scopes":[]}`},
+		{UpdateAPIKeyRequest{Scopes: []string{ScopeEventsRead}}, `{"scopes":["events:read"]}`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.req)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// KeyUsage is the traffic of an API key within a period, as returned by
+// GetKeyUsage.
+type KeyUsage struct {
+	// KeyID is the API key.
+	KeyID string `json:"key_id"`
+	// Requests is the number of requests made with the key in the period.
+	Requests int `json:"requests"`
+	// Errors is the number of those requests that failed with a 4xx or 5xx
+	// status.
+	Errors int `json:"errors"`
+	// LastUsedAt is when the key was last used, which may be before the
+	// period (nil if never used).
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// Endpoints breaks the requests down by endpoint, most used first.
+	Endpoints []EndpointUsage `json:"endpoints"`
+}
+
+// EndpointUsage is the traffic of an API key to one endpoint.
+type EndpointUsage struct {
+	// Endpoint is the method and path template, such as "POST /v1/events".
+	Endpoint string `json:"endpoint"`
+	// Requests is the number of requests to the endpoint in the period.
+	Requests int `json:"requests"`
+	// Errors is the number of those requests that failed.
+	Errors int `json:"errors"`
+	// LastUsedAt is when the key last called the endpoint.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// ErrorRate returns the fraction of requests that failed, or 0 if there
+// were none.
+func (u *KeyUsage) ErrorRate() float64 {
+	return errorRate(u.Errors, u.Requests)
+}
+
+// UnusedSince reports whether the key has not been used since t, making
+// it a candidate for revocation.
+//
+//	if usage.UnusedSince(time.Now().AddDate(0, 0, -90)) {
+//	    err = mgmt.RevokeAPIKey(ctx, usage.KeyID)
+//	}
+func (u *KeyUsage) UnusedSince(t time.Time) bool {
+	return u.LastUsedAt == nil || u.LastUsedAt.Before(t)
+}
+
+// ErrorRate returns the fraction of requests to the endpoint that failed,
+// or 0 if there were none.
+func (u *EndpointUsage) ErrorRate() float64 {
+	return errorRate(u.Errors, u.Requests)
+}
+
+func errorRate(errors, requests int) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(errors) / float64(requests)
+}
+
+// GetKeyUsage retrieves request counts, errors, and last use by endpoint
+// for an API key within period. A zero side of period is open.
+// Requires session token authentication (use NewManagementClient).
+// If the key does not exist, the error is a *NotFoundError.
+//
+//	usage, err := mgmt.GetKeyUsage(ctx, keyID, tryl.Last(30*24*time.Hour))
+func (c *Client) GetKeyUsage(ctx context.Context, keyID string, period TimeRange) (*KeyUsage, error) {
+	if keyID == "" {
+		return nil, &ValidationError{Field: "key_id", Message: "is required"}
+	}
+	if !period.Start.IsZero() && !period.End.IsZero() && period.End.Before(period.Start) {
+		return nil, &ValidationError{Field: "period", Message: "end is before start"}
+	}
+
+	var resp *KeyUsage
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doGetKeyUsage(ctx, keyID, period)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, notFound(err, "api_key", keyID)
+	}
+	return resp, nil
+}
+
+// doGetKeyUsage performs the key usage request without retries.
+func (c *Client) doGetKeyUsage(ctx context.Context, keyID string, period TimeRange) (*KeyUsage, error) {
+	query := url.Values{}
+	filter := EventFilter{}.WithRange(period)
+	if start := c.toServerTime(filter.StartTime); start != nil {
+		query.Set("start_time", formatTime(*start))
+	}
+	if end := c.toServerTime(filter.EndTime); end != nil {
+		query.Set("end_time", formatTime(*end))
+	}
+
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/keys/%s/usage", url.PathEscape(keyID)),
+		Query:  query,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var usage KeyUsage
+	if err := json.Unmarshal(resp.Body, &usage); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if usage.Endpoints == nil {
+		usage.Endpoints = []EndpointUsage{}
+	}
+
+	return &usage, nil
+}
@@ -0,0 +1,70 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_GetKeyUsage(t *testing.T) {
+	t.Parallel()
+
+	var path, start string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, start = r.URL.Path, r.URL.Query().Get("start_time")
+		if r.URL.Path == "/v1/keys/key_missing/usage" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"key_not_found","message":"API key not found"}}`))
+			return
+		}
+		w.Write([]byte(`{"key_id":"key_1","requests":200,"errors":10,"last_used_at":"2026-03-04T09:00:00Z",` +
+			`"endpoints":[{"endpoint":"POST /v1/events","requests":150,"errors":10,"last_used_at":"2026-03-04T09:00:00Z"},` +
+			`{"endpoint":"GET /v1/events","requests":50,"errors":0}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	usage, err := client.GetKeyUsage(ctx, "key_1", TimeRange{Start: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("GetKeyUsage() error = %v", err)
+	}
+	if path != "/v1/keys/key_1/usage" || start != "2026-03-01T00:00:00Z" {
+		t.Errorf("request = %s start_time=%q", path, start)
+	}
+	if usage.Requests != 200 || usage.ErrorRate() != 0.05 || len(usage.Endpoints) != 2 {
+		t.Errorf("GetKeyUsage() = %+v", usage)
+	}
+	if rate := usage.Endpoints[0].ErrorRate(); rate != float64(10)/150 {
+		t.Errorf("endpoint ErrorRate() = %v", rate)
+	}
+	if usage.UnusedSince(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("UnusedSince(before last use) = true")
+	}
+	if !usage.UnusedSince(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Error("UnusedSince(after last use) = false")
+	}
+
+	if _, err := client.GetKeyUsage(ctx, "key_missing", TimeRange{}); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetKeyUsage(missing) error = %v, want ErrKeyNotFound", err)
+	}
+	if _, err := client.GetKeyUsage(ctx, "", TimeRange{}); !IsClientValidationError(err) {
+		t.Errorf("GetKeyUsage() without a key ID error = %v", err)
+	}
+}
+
+func TestKeyUsage_NeverUsed(t *testing.T) {
+	t.Parallel()
+
+	usage := &KeyUsage{KeyID: "key_1"}
+	if usage.ErrorRate() != 0 || !usage.UnusedSince(time.Now()) {
+		t.Errorf("unused key: ErrorRate() = %v, UnusedSince() = %v", usage.ErrorRate(), usage.UnusedSince(time.Now()))
+	}
+}
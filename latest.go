@@ -0,0 +1,86 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// LatestPerUser retrieves the most recent event matching filter for each
+// user, such as every user's last login for a "last activity" column, in
+// one request instead of one List per user. The returned list holds one
+// event per user, sorted and paginated by filter like List.
+func (c *Client) LatestPerUser(ctx context.Context, filter EventFilter) (*EventList, error) {
+	return c.latest(ctx, "user_id", filter)
+}
+
+// LatestPerTarget retrieves the most recent event matching filter for each
+// target, identified by its TargetType and TargetID. Events without a
+// target are not included. The returned list holds one event per target,
+// sorted and paginated by filter like List.
+func (c *Client) LatestPerTarget(ctx context.Context, filter EventFilter) (*EventList, error) {
+	return c.latest(ctx, "target", filter)
+}
+
+// latest lists the most recent event matching filter in each group.
+func (c *Client) latest(ctx context.Context, groupBy string, filter EventFilter) (*EventList, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	var resp *EventList
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doLatest(ctx, groupBy, filter)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doLatest performs a group-by latest request without retries.
+func (c *Client) doLatest(ctx context.Context, groupBy string, filter EventFilter) (*EventList, error) {
+	query, err := c.listQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	query.Set("group_by", groupBy)
+
+	req := transport.Request{
+		Method: "GET",
+		Path:   "/v1/events/latest",
+		Query:  query,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var eventList EventList
+	if err := json.Unmarshal(resp.Body, &eventList); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	c.reportWarnings(req, eventList.Warnings)
+
+	if c.config.migrations != nil {
+		if err := c.config.migrations.UpgradeList(&eventList); err != nil {
+			return nil, err
+		}
+	}
+
+	return &eventList, nil
+}
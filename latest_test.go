@@ -0,0 +1,40 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_LatestPerUser(t *testing.T) {
+	t.Parallel()
+
+	var path, groupBy, action string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, groupBy, action = r.URL.Path, r.URL.Query().Get("group_by"), r.URL.Query().Get("action")
+		w.Write([]byte(`{"events":[{"id":"evt_2","user_id":"u1","action":"auth.login"},{"id":"evt_5","user_id":"u2","action":"auth.login"}],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	list, err := client.LatestPerUser(ctx, EventFilter{Action: "auth.login"})
+	if err != nil {
+		t.Fatalf("LatestPerUser() error = %v", err)
+	}
+	if path != "/v1/events/latest" || groupBy != "user_id" || action != "auth.login" {
+		t.Errorf("request = %s group_by=%s action=%s", path, groupBy, action)
+	}
+	if len(list.Events) != 2 || list.Events[1].ID != "evt_5" {
+		t.Errorf("LatestPerUser() = %+v", list.Events)
+	}
+
+	if _, err := client.LatestPerTarget(ctx, EventFilter{}); err != nil || groupBy != "target" {
+		t.Errorf("LatestPerTarget() error = %v, group_by = %q", err, groupBy)
+	}
+	if _, err := client.LatestPerTarget(ctx, EventFilter{OrderBy: "size"}); err == nil {
+		t.Error("LatestPerTarget() with an invalid OrderBy succeeded")
+	}
+}
@@ -1,6 +1,8 @@
 package tryl
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -11,20 +13,26 @@ type Project struct {
 	ID string `json:"id"`
 	// Name is the human-readable project name.
 	Name string `json:"name"`
-	// Environment indicates the project environment ("live" or "test").
-	Environment string `json:"environment"`
+	// Environment indicates the project environment (EnvLive or EnvTest).
+	Environment Environment `json:"environment"`
 	// CreatedAt is when the project was created.
 	CreatedAt time.Time `json:"created_at"`
 	// UpdatedAt is when the project was last updated.
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ImportID returns the project's stable identifier for infrastructure
+// tools such as Terraform. It is the project ID.
+func (p *Project) ImportID() string {
+	return p.ID
+}
+
 // CreateProjectRequest represents the request to create a new project.
 type CreateProjectRequest struct {
 	// Name is the human-readable project name (required).
 	Name string `json:"name"`
-	// Environment indicates the project environment: "live" or "test" (required).
-	Environment string `json:"environment"`
+	// Environment indicates the project environment: EnvLive or EnvTest (required).
+	Environment Environment `json:"environment"`
 }
 
 // CreateProjectResponse represents the response after creating a project.
@@ -52,13 +60,19 @@ type APIKey struct {
 	ProjectID string `json:"project_id"`
 	// Name is a human-readable name for this key.
 	Name string `json:"name"`
-	// Environment indicates if this is a "live" or "test" key.
-	Environment string `json:"environment"`
+	// Environment indicates if this is a live or test key.
+	Environment Environment `json:"environment"`
 	// Prefix is the visible prefix of the key (e.g., "actlog_live_abc...").
 	// This allows identifying keys without exposing the full value.
 	Prefix string `json:"prefix"`
 	// Scopes defines the permissions granted to this key.
 	Scopes []string `json:"scopes"`
+	// AllowedCIDRs restricts the key to requests from these IP ranges
+	// (empty = any address).
+	AllowedCIDRs []string `json:"allowed_cidrs,omitempty"`
+	// AllowedOrigins restricts the key to browser requests from these
+	// origins (empty = any origin).
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
 	// CreatedAt is when the key was created.
 	CreatedAt time.Time `json:"created_at"`
 	// LastUsedAt is when the key was last used (nil if never used).
@@ -69,14 +83,43 @@ type APIKey struct {
 	RevokedAt *time.Time `json:"revoked_at,omitempty"`
 }
 
+// ImportID returns the key's stable identifier for infrastructure tools
+// such as Terraform, in the form "<project_id>/<key_id>".
+// ParseAPIKeyImportID reverses it.
+func (k *APIKey) ImportID() string {
+	return k.ProjectID + "/" + k.ID
+}
+
+// ParseAPIKeyImportID splits an ID returned by APIKey.ImportID into its
+// project and key IDs.
+func ParseAPIKeyImportID(id string) (projectID, keyID string, err error) {
+	projectID, keyID, ok := strings.Cut(id, "/")
+	if !ok || projectID == "" || keyID == "" || strings.Contains(keyID, "/") {
+		return "", "", &ValidationError{
+			Field:   "import_id",
+			Message: "must be in the form <project_id>/<key_id>",
+			Value:   id,
+		}
+	}
+	return projectID, keyID, nil
+}
+
 // CreateAPIKeyRequest represents the request to create a new API key.
 type CreateAPIKeyRequest struct {
 	// Name is a human-readable name for the key (required).
 	Name string `json:"name"`
-	// Environment indicates if this is a "live" or "test" key (required).
-	Environment string `json:"environment"`
+	// Environment indicates if this is a live or test key (required).
+	Environment Environment `json:"environment"`
 	// Scopes defines the permissions for this key (optional, defaults to all scopes).
+	// Use WriteScope to restrict logging to an action namespace.
 	Scopes []string `json:"scopes,omitempty"`
+	// AllowedCIDRs restricts the key to requests from these IP ranges, in
+	// CIDR notation or as single addresses (optional, nil = any address).
+	AllowedCIDRs []string `json:"allowed_cidrs,omitempty"`
+	// AllowedOrigins restricts the key to browser requests whose Origin or
+	// Referer is one of these origins, such as "https://app.example.com" or
+	// "https://*.example.com" (optional, nil = any origin).
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
 	// ExpiresAt sets an expiration time for the key (optional, nil = no expiration).
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
@@ -91,6 +134,46 @@ type CreateAPIKeyResponse struct {
 	APIKey string `json:"api_key"`
 }
 
+// UpdateAPIKeyRequest represents the request to modify an existing API key.
+// Only fields that are set are changed; nil fields are left untouched.
+type UpdateAPIKeyRequest struct {
+	// Name is a new human-readable name for the key (optional).
+	Name *string `json:"name,omitempty"`
+	// Scopes replaces the permissions granted to the key (optional).
+	// An empty, non-nil slice clears the scopes, granting every scope.
+	Scopes []string `json:"scopes"`
+	// AllowedCIDRs replaces the IP ranges the key may be used from
+	// (optional). An empty, non-nil slice removes the restriction.
+	AllowedCIDRs []string `json:"allowed_cidrs"`
+	// AllowedOrigins replaces the origins the key may be used from
+	// (optional). An empty, non-nil slice removes the restriction.
+	AllowedOrigins []string `json:"allowed_origins"`
+	// ExpiresAt sets a new expiration time for the key (optional).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// MarshalJSON encodes the request, leaving out nil scopes and
+// restrictions and keeping empty ones, which clear them.
+func (r UpdateAPIKeyRequest) MarshalJSON() ([]byte, error) {
+	type request UpdateAPIKeyRequest
+	out := struct {
+		request
+		Scopes         *[]string `json:"scopes,omitempty"`
+		AllowedCIDRs   *[]string `json:"allowed_cidrs,omitempty"`
+		AllowedOrigins *[]string `json:"allowed_origins,omitempty"`
+	}{request: request(r)}
+	if r.Scopes != nil {
+		out.Scopes = &r.Scopes
+	}
+	if r.AllowedCIDRs != nil {
+		out.AllowedCIDRs = &r.AllowedCIDRs
+	}
+	if r.AllowedOrigins != nil {
+		out.AllowedOrigins = &r.AllowedOrigins
+	}
+	return json.Marshal(out)
+}
+
 // RotateAPIKeyRequest represents the request to rotate an existing API key.
 // Rotation creates a new key and revokes the old one.
 type RotateAPIKeyRequest struct {
@@ -112,8 +195,113 @@ type RotateAPIKeyResponse struct {
 	OldKeyRevokedAt time.Time `json:"old_key_revoked_at"`
 }
 
+// APIKeyStatus describes the lifecycle state of an API key.
+type APIKeyStatus string
+
+// API key statuses accepted by APIKeyFilter.Status.
+const (
+	// APIKeyStatusActive matches keys that are neither revoked nor expired.
+	APIKeyStatusActive APIKeyStatus = "active"
+	// APIKeyStatusRevoked matches keys that have been revoked.
+	APIKeyStatusRevoked APIKeyStatus = "revoked"
+	// APIKeyStatusExpired matches keys whose expiration time has passed.
+	APIKeyStatusExpired APIKeyStatus = "expired"
+)
+
+// Status reports the lifecycle state of the key at the given time.
+// Revocation takes precedence over expiration.
+func (k *APIKey) Status(now time.Time) APIKeyStatus {
+	if k.RevokedAt != nil {
+		return APIKeyStatusRevoked
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(now) {
+		return APIKeyStatusExpired
+	}
+	return APIKeyStatusActive
+}
+
+// APIKeyFilter represents query parameters for listing API keys.
+// All fields are optional; zero values do not filter.
+type APIKeyFilter struct {
+	// Environment filters keys by environment.
+	Environment Environment
+	// Status filters keys by lifecycle state.
+	Status APIKeyStatus
+	// Scope filters keys that have been granted the given scope (e.g., "events:write").
+	Scope string
+}
+
 // APIKeyList represents a list of API keys for a project.
 type APIKeyList struct {
 	// APIKeys is the array of API key metadata.
 	APIKeys []APIKey `json:"api_keys"`
 }
+
+// Management audit actions recorded by the API.
+const (
+	AuditActionProjectCreated = "project.created"
+	AuditActionProjectDeleted = "project.deleted"
+	AuditActionKeyCreated     = "api_key.created"
+	AuditActionKeyUpdated     = "api_key.updated"
+	AuditActionKeyRotated     = "api_key.rotated"
+	AuditActionKeyRevoked     = "api_key.revoked"
+)
+
+// AuditRecord represents a single management action, such as creating a
+// project or rotating an API key.
+type AuditRecord struct {
+	// ID is the unique identifier for the audit record.
+	ID string `json:"id"`
+	// ActorID is the user who performed the action.
+	ActorID string `json:"actor_id"`
+	// ActorEmail is the email of the user who performed the action, if known.
+	ActorEmail string `json:"actor_email,omitempty"`
+	// Action is the management action performed (e.g., "api_key.rotated").
+	Action string `json:"action"`
+	// ResourceType is the kind of resource affected ("project" or "api_key").
+	ResourceType string `json:"resource_type"`
+	// ResourceID is the identifier of the affected resource.
+	ResourceID string `json:"resource_id"`
+	// ProjectID is the project the resource belongs to.
+	ProjectID string `json:"project_id,omitempty"`
+	// IPAddress is the client address the action originated from.
+	IPAddress string `json:"ip_address,omitempty"`
+	// Details holds action-specific data (e.g., changed fields).
+	Details json.RawMessage `json:"details,omitempty"`
+	// CreatedAt is when the action occurred.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditFilter represents query parameters for listing management audit records.
+type AuditFilter struct {
+	// ProjectID filters records by project.
+	ProjectID string
+	// ActorID filters records by the user who performed the action.
+	ActorID string
+	// Action filters records by management action (e.g., AuditActionKeyRevoked).
+	Action string
+	// ResourceType filters records by resource type ("project" or "api_key").
+	ResourceType string
+	// ResourceID filters records by resource ID.
+	ResourceID string
+
+	// StartTime filters records occurring at or after this time (inclusive).
+	StartTime *time.Time
+	// EndTime filters records occurring at or before this time (inclusive).
+	EndTime *time.Time
+
+	// Cursor is an opaque pagination cursor returned by the previous query.
+	Cursor string
+	// Limit is the maximum number of records to return (max 100).
+	Limit int
+}
+
+// AuditRecordList represents the response when listing management audit records.
+type AuditRecordList struct {
+	// Records is the list of audit records matching the filter.
+	Records []AuditRecord `json:"records"`
+	// HasMore indicates if there are more records to fetch.
+	HasMore bool `json:"has_more"`
+	// NextCursor is the cursor to use for fetching the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
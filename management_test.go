@@ -3,6 +3,7 @@ package tryl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -530,3 +531,299 @@ func TestClient_RotateAPIKey(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ListAPIKeys_Filter(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("environment"); got != "live" {
+			t.Errorf("environment = %q, want live", got)
+		}
+		if got := q.Get("status"); got != "revoked" {
+			t.Errorf("status = %q, want revoked", got)
+		}
+		if got := q.Get("scope"); got != "events:write" {
+			t.Errorf("scope = %q, want events:write", got)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"api_keys":[]}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+	_, err := client.ListAPIKeys(context.Background(), "proj_test123", APIKeyFilter{
+		Environment: "live",
+		Status:      APIKeyStatusRevoked,
+		Scope:       "events:write",
+	})
+	if err != nil {
+		t.Fatalf("ListAPIKeys() error = %v", err)
+	}
+
+	_, err = client.ListAPIKeys(context.Background(), "proj_test123", APIKeyFilter{Status: "stale"})
+	if !IsClientValidationError(err) {
+		t.Errorf("ListAPIKeys() with unknown status error = %v, want ValidationError", err)
+	}
+}
+
+func TestAPIKey_Status(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		key  APIKey
+		want APIKeyStatus
+	}{
+		{name: "active", key: APIKey{}, want: APIKeyStatusActive},
+		{name: "not yet expired", key: APIKey{ExpiresAt: &future}, want: APIKeyStatusActive},
+		{name: "expired", key: APIKey{ExpiresAt: &past}, want: APIKeyStatusExpired},
+		{name: "revoked and expired", key: APIKey{RevokedAt: &past, ExpiresAt: &past}, want: APIKeyStatusRevoked},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.key.Status(now); got != tt.want {
+				t.Errorf("Status() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/keys/key_test123" {
+			t.Errorf("expected path /v1/keys/key_test123, got %s", r.URL.Path)
+		}
+		if r.Method != "PATCH" {
+			t.Errorf("expected PATCH method, got %s", r.Method)
+		}
+
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["name"] != "Renamed Key" {
+			t.Errorf("name = %v, want Renamed Key", body["name"])
+		}
+		if _, ok := body["expires_at"]; ok {
+			t.Error("expires_at should be omitted when unset")
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(APIKey{ID: "key_test123", Name: "Renamed Key"})
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+
+	name := "Renamed Key"
+	result, err := client.UpdateAPIKey(context.Background(), "key_test123", UpdateAPIKeyRequest{Name: &name})
+	if err != nil {
+		t.Fatalf("UpdateAPIKey() error = %v", err)
+	}
+	if result.Name != name {
+		t.Errorf("UpdateAPIKey() name = %v, want %v", result.Name, name)
+	}
+
+	_, err = client.UpdateAPIKey(context.Background(), "key_test123", UpdateAPIKeyRequest{})
+	if !IsClientValidationError(err) {
+		t.Errorf("UpdateAPIKey() with empty request error = %v, want ValidationError", err)
+	}
+}
+
+func TestClient_ListManagementAudit(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audit" {
+			t.Errorf("expected path /v1/audit, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("action"); got != AuditActionKeyRevoked {
+			t.Errorf("action = %q, want %q", got, AuditActionKeyRevoked)
+		}
+		if got := r.URL.Query().Get("project_id"); got != "proj_test123" {
+			t.Errorf("project_id = %q, want proj_test123", got)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"records":[{"id":"aud_1","actor_id":"usr_1","action":"api_key.revoked","resource_type":"api_key","resource_id":"key_1","created_at":"2026-01-30T10:00:00Z"}],"has_more":true,"next_cursor":"c2"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+	result, err := client.ListManagementAudit(context.Background(), AuditFilter{
+		ProjectID: "proj_test123",
+		Action:    AuditActionKeyRevoked,
+	})
+	if err != nil {
+		t.Fatalf("ListManagementAudit() error = %v", err)
+	}
+
+	if len(result.Records) != 1 || result.Records[0].ResourceID != "key_1" {
+		t.Errorf("ListManagementAudit() records = %+v", result.Records)
+	}
+	if result.NextCursor != "c2" {
+		t.Errorf("NextCursor = %q, want c2", result.NextCursor)
+	}
+}
+
+func TestClient_GetProject(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		if r.URL.Path != "/v1/projects/proj_test123" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"project_not_found","message":"project not found"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(Project{ID: "proj_test123", Name: "Test Project", Environment: "test"})
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+
+	project, err := client.GetProject(context.Background(), "proj_test123")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if project.Name != "Test Project" || project.ImportID() != "proj_test123" {
+		t.Errorf("GetProject() = %+v", project)
+	}
+
+	_, err = client.GetProject(context.Background(), "proj_missing")
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) || notFoundErr.Resource != "project" || notFoundErr.ID != "proj_missing" {
+		t.Fatalf("GetProject() error = %v, want *NotFoundError", err)
+	}
+	var apiErr *APIError
+	if !IsNotFound(err) || !errors.Is(err, ErrProjectNotFound) || errors.Is(err, ErrKeyNotFound) || !errors.As(err, &apiErr) {
+		t.Errorf("GetProject() error %v does not match the expected sentinels", err)
+	}
+}
+
+func TestClient_GetAPIKey(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/keys/key_123" {
+			json.NewEncoder(w).Encode(APIKey{ID: "key_123", ProjectID: "proj_1", Name: "ci"})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+
+	key, err := client.GetAPIKey(context.Background(), "key_123")
+	if err != nil {
+		t.Fatalf("GetAPIKey() error = %v", err)
+	}
+	if key.ImportID() != "proj_1/key_123" {
+		t.Errorf("ImportID() = %q, want proj_1/key_123", key.ImportID())
+	}
+
+	if _, err := client.GetAPIKey(context.Background(), "key_gone"); !errors.Is(err, ErrKeyNotFound) || !IsNotFound(err) {
+		t.Errorf("GetAPIKey() error = %v, want key not found", err)
+	}
+}
+
+func TestParseAPIKeyImportID(t *testing.T) {
+	t.Parallel()
+
+	projectID, keyID, err := ParseAPIKeyImportID("proj_1/key_123")
+	if err != nil || projectID != "proj_1" || keyID != "key_123" {
+		t.Errorf("ParseAPIKeyImportID() = %q, %q, %v", projectID, keyID, err)
+	}
+	for _, id := range []string{"", "key_123", "/key_123", "proj_1/", "a/b/c"} {
+		if _, _, err := ParseAPIKeyImportID(id); !IsClientValidationError(err) {
+			t.Errorf("ParseAPIKeyImportID(%q) error = %v, want validation error", id, err)
+		}
+	}
+}
+
+func TestClient_CreateProjectIfNotExists(t *testing.T) {
+	t.Parallel()
+
+	var creates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			creates++
+			var req CreateProjectRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(CreateProjectResponse{
+				Project: Project{ID: "proj_new", Name: req.Name, Environment: req.Environment},
+				APIKey:  "actlog_test_1234567890abcdef1234567890abcdef",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(ProjectList{Projects: []Project{
+			{ID: "proj_existing", Name: "billing", Environment: "live"},
+		}})
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+
+	resp, created, err := client.CreateProjectIfNotExists(context.Background(), CreateProjectRequest{Name: "billing", Environment: "live"})
+	if err != nil || created || resp.Project.ID != "proj_existing" || resp.APIKey != "" {
+		t.Errorf("existing project: resp = %+v, created = %v, err = %v", resp, created, err)
+	}
+
+	resp, created, err = client.CreateProjectIfNotExists(context.Background(), CreateProjectRequest{Name: "billing", Environment: "test"})
+	if err != nil || !created || resp.Project.ID != "proj_new" || resp.APIKey == "" {
+		t.Errorf("new project: resp = %+v, created = %v, err = %v", resp, created, err)
+	}
+	if creates != 1 {
+		t.Errorf("server saw %d creates, want 1", creates)
+	}
+}
+
+func TestClient_CreateAPIKeyIfNotExists(t *testing.T) {
+	t.Parallel()
+
+	revoked := time.Now().Add(-time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(CreateAPIKeyResponse{
+				APIKeyMetadata: APIKey{ID: "key_new", Name: "ci"},
+				APIKey:         "actlog_live_1234567890abcdef1234567890abcdef",
+			})
+			return
+		}
+		if r.URL.Query().Get("status") != "active" {
+			t.Errorf("status filter = %q, want active", r.URL.Query().Get("status"))
+		}
+		json.NewEncoder(w).Encode(APIKeyList{APIKeys: []APIKey{
+			{ID: "key_old", Name: "ci", RevokedAt: &revoked},
+			{ID: "key_deploy", Name: "deploy"},
+		}})
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+
+	resp, created, err := client.CreateAPIKeyIfNotExists(context.Background(), "proj_1", CreateAPIKeyRequest{Name: "deploy", Environment: "live"})
+	if err != nil || created || resp.APIKeyMetadata.ID != "key_deploy" {
+		t.Errorf("existing key: resp = %+v, created = %v, err = %v", resp, created, err)
+	}
+
+	resp, created, err = client.CreateAPIKeyIfNotExists(context.Background(), "proj_1", CreateAPIKeyRequest{Name: "ci", Environment: "live"})
+	if err != nil || !created || resp.APIKeyMetadata.ID != "key_new" {
+		t.Errorf("revoked key should be replaced: resp = %+v, created = %v, err = %v", resp, created, err)
+	}
+}
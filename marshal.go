@@ -0,0 +1,78 @@
+package tryl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// encodedBatchRequest is a batchRequest whose events were encoded by the
+// WithEventMarshaler function.
+type encodedBatchRequest struct {
+	Events  []json.RawMessage `json:"events"`
+	GroupID string            `json:"group_id,omitempty"`
+	Atomic  bool              `json:"atomic,omitempty"`
+}
+
+// encodedScheduleRequest is a scheduleRequest whose event was encoded by
+// the WithEventMarshaler function.
+type encodedScheduleRequest struct {
+	Event       json.RawMessage `json:"event"`
+	ScheduledAt time.Time       `json:"scheduled_at"`
+}
+
+// eventBody returns the request body for event: the event itself, or its
+// encoding by the WithEventMarshaler function.
+func (c *Client) eventBody(event Event) (any, error) {
+	if c.config.eventMarshaler == nil {
+		return event, nil
+	}
+	return c.marshalEvent(event)
+}
+
+// batchBody returns the request body for a batch, encoding its events
+// with the WithEventMarshaler function if set.
+func (c *Client) batchBody(req batchRequest) (any, error) {
+	if c.config.eventMarshaler == nil {
+		return req, nil
+	}
+	encoded := encodedBatchRequest{
+		Events:  make([]json.RawMessage, len(req.Events)),
+		GroupID: req.GroupID,
+		Atomic:  req.Atomic,
+	}
+	for i, event := range req.Events {
+		data, err := c.marshalEvent(event)
+		if err != nil {
+			return nil, fmt.Errorf("event at index %d: %w", i, err)
+		}
+		encoded.Events[i] = data
+	}
+	return encoded, nil
+}
+
+// scheduleBody returns the request body for LogAt, encoding its event
+// with the WithEventMarshaler function if set.
+func (c *Client) scheduleBody(req scheduleRequest) (any, error) {
+	if c.config.eventMarshaler == nil {
+		return req, nil
+	}
+	data, err := c.marshalEvent(req.Event)
+	if err != nil {
+		return nil, err
+	}
+	return encodedScheduleRequest{Event: data, ScheduledAt: req.ScheduledAt}, nil
+}
+
+// marshalEvent encodes event with the WithEventMarshaler function.
+func (c *Client) marshalEvent(event Event) (json.RawMessage, error) {
+	data, err := c.config.eventMarshaler(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if !json.Valid(data) {
+		return nil, errors.New("failed to marshal event: event marshaler returned invalid JSON")
+	}
+	return data, nil
+}
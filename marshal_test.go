@@ -0,0 +1,86 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithEventMarshaler(t *testing.T) {
+	t.Parallel()
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if strings.HasSuffix(r.URL.Path, "/batch") {
+			w.Write([]byte(`{"results":[{"id":"evt_1"},{"id":"evt_2"}]}`))
+			return
+		}
+		w.Write([]byte(`{"id":"evt_1"}`))
+	}))
+	defer server.Close()
+
+	envelope := func(e Event) ([]byte, error) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		fields["service"] = "billing"
+		return json.Marshal(fields)
+	}
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithEventMarshaler(envelope),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	event := Event{UserID: "user_1", Action: "invoice.paid"}
+	if _, err := client.Log(ctx, event); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if _, err := client.LogBatch(ctx, []Event{event, event}); err != nil {
+		t.Fatalf("LogBatch() error = %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("requests = %d, want 2", len(bodies))
+	}
+	if !strings.Contains(bodies[0], `"service":"billing"`) {
+		t.Errorf("Log body = %s, want envelope field", bodies[0])
+	}
+	if strings.Count(bodies[1], `"service":"billing"`) != 2 {
+		t.Errorf("LogBatch body = %s, want envelope field on each event", bodies[1])
+	}
+}
+
+func TestClient_WithEventMarshalerError(t *testing.T) {
+	t.Parallel()
+
+	errEncode := errors.New("encode failed")
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL("http://127.0.0.1:0"),
+		WithEventMarshaler(func(Event) ([]byte, error) { return nil, errEncode }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Log(context.Background(), Event{UserID: "user_1", Action: "invoice.paid"})
+	if !errors.Is(err, errEncode) {
+		t.Errorf("Log() error = %v, want the marshaler's error", err)
+	}
+}
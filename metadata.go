@@ -0,0 +1,272 @@
+package tryl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxMetadataBytes is the API's limit on the size of an event's
+// encoded metadata.
+const DefaultMaxMetadataBytes = 64 << 10
+
+// truncatedSuffix marks string values shortened by MetadataTruncate.
+const truncatedSuffix = "...[truncated]"
+
+// minTruncatedLength is the shortest a string value is cut to by
+// MetadataTruncate before it moves on to dropping keys.
+const minTruncatedLength = 64
+
+// MetadataSizePolicy selects what the client does with events whose
+// metadata exceeds the size limit; see WithMetadataSizePolicy.
+type MetadataSizePolicy int
+
+const (
+	// MetadataReject fails the event with a *ValidationError, subject to
+	// the validation mode. This is the default.
+	MetadataReject MetadataSizePolicy = iota
+	// MetadataTruncate shortens the longest top-level string values,
+	// marking each with a "...[truncated]" suffix, and sets a top-level
+	// "_truncated": true. If that is not enough, it drops keys as
+	// MetadataDropLargestKeys does.
+	MetadataTruncate
+	// MetadataDropLargestKeys removes top-level keys, largest value first,
+	// and lists them in a top-level "_dropped_keys" array.
+	MetadataDropLargestKeys
+)
+
+// fitMetadata applies the client's metadata size policy to event. Metadata
+// that is not a JSON object cannot be reduced and is left for checkEvent
+// to reject.
+func (c *Client) fitMetadata(event *Event) {
+	limit := c.config.maxMetadataBytes
+	if c.config.metadataPolicy == MetadataReject || len(event.Metadata) <= limit {
+		return
+	}
+	obj, err := metadataObject(event.Metadata)
+	if err != nil {
+		return
+	}
+
+	if c.config.metadataPolicy == MetadataTruncate {
+		obj["_truncated"] = json.RawMessage("true")
+		if data, ok := truncateStrings(obj, limit); ok {
+			event.Metadata = data
+			return
+		}
+	}
+	if data, ok := dropLargestKeys(obj, limit); ok {
+		event.Metadata = data
+	}
+}
+
+// truncateStrings shortens the longest string values of obj until its
+// encoding fits in limit bytes.
+func truncateStrings(obj map[string]json.RawMessage, limit int) (json.RawMessage, bool) {
+	strs := map[string]string{}
+	for k, v := range obj {
+		var s string
+		if json.Unmarshal(v, &s) == nil && len(s) > minTruncatedLength+len(truncatedSuffix) {
+			strs[k] = s
+		}
+	}
+
+	for {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, false
+		}
+		if len(data) <= limit {
+			return data, true
+		}
+		longest := ""
+		for k, s := range strs {
+			if longest == "" || len(s) > len(strs[longest]) || (len(s) == len(strs[longest]) && k < longest) {
+				longest = k
+			}
+		}
+		if longest == "" {
+			return nil, false
+		}
+
+		// Cut by the excess, but no shorter than minTruncatedLength. The
+		// encoded length may exceed the raw length (escapes), so the loop
+		// re-measures after each cut.
+		s := strs[longest]
+		n := max(minTruncatedLength, len(s)-(len(data)-limit)-len(truncatedSuffix))
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		delete(strs, longest)
+		obj[longest], _ = json.Marshal(s[:n] + truncatedSuffix)
+	}
+}
+
+// dropLargestKeys removes the keys of obj with the largest values until
+// its encoding fits in limit bytes.
+func dropLargestKeys(obj map[string]json.RawMessage, limit int) (json.RawMessage, bool) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		if k != "_truncated" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(obj[keys[i]]) != len(obj[keys[j]]) {
+			return len(obj[keys[i]]) > len(obj[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+
+	var dropped []string
+	for _, k := range keys {
+		delete(obj, k)
+		dropped = append(dropped, k)
+		obj["_dropped_keys"], _ = json.Marshal(dropped)
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, false
+		}
+		if len(data) <= limit {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// metadataSizeError reports metadata over the limit.
+func metadataSizeError(size, limit int) *ValidationError {
+	return &ValidationError{
+		Field:   "metadata",
+		Message: fmt.Sprintf("must be %d bytes or less (got %d)", limit, size),
+	}
+}
+
+// redactedValue replaces fields tagged tryl:"redact".
+const redactedValue = "[REDACTED]"
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// structMetadata converts a struct to a metadata object honoring tryl tags.
+func structMetadata(v any) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, &ValidationError{Field: "metadata", Message: fmt.Sprintf("must be a struct, got %T", v)}
+	}
+	m := map[string]any{}
+	structFields(rv, m)
+	return m, nil
+}
+
+// structFields adds the exported fields of the struct rv to m. Fields of
+// exported embedded structs without a name are promoted, as in
+// encoding/json.
+func structFields(rv reflect.Value, m map[string]any) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, jsonOmit := jsonFieldName(f)
+		opts := strings.Split(f.Tag.Get("tryl"), ",")
+		if name == "-" || opts[0] == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+
+		if f.Anonymous && name == "" {
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				structFields(fv, m)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		omitempty, redact := jsonOmit, false
+		for _, opt := range opts {
+			switch opt {
+			case "omitempty":
+				omitempty = true
+			case "redact":
+				redact = true
+			}
+		}
+		if omitempty && fv.IsZero() {
+			continue
+		}
+		if redact {
+			m[name] = redactedValue
+			continue
+		}
+		m[name] = metadataValue(fv)
+	}
+}
+
+// jsonFieldName returns the name and omitempty option of the field's json
+// tag.
+func jsonFieldName(f reflect.StructField) (name string, omitempty bool) {
+	parts := strings.Split(f.Tag.Get("json"), ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty
+}
+
+// metadataValue converts nested structs, including those in slices and
+// maps, with structFields; other values are returned for encoding/json.
+func metadataValue(rv reflect.Value) any {
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Type().Implements(jsonMarshalerType) {
+		return rv.Interface()
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return metadataValue(rv.Elem())
+	case reflect.Struct:
+		if reflect.PointerTo(rv.Type()).Implements(jsonMarshalerType) {
+			return rv.Interface()
+		}
+		m := map[string]any{}
+		structFields(rv, m)
+		return m
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && (rv.IsNil() || rv.Type().Elem().Kind() == reflect.Uint8) {
+			return rv.Interface()
+		}
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = metadataValue(rv.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if rv.IsNil() || rv.Type().Key().Kind() != reflect.String {
+			return rv.Interface()
+		}
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = metadataValue(iter.Value())
+		}
+		return out
+	}
+	return rv.Interface()
+}
@@ -0,0 +1,102 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestClient_MetadataSizePolicy(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 400)
+	metadata, _ := json.Marshal(map[string]any{"note": long, "id": 42, "tags": []string{"a", "b"}})
+	event := Event{UserID: "user_1", Action: "doc.updated", Metadata: metadata}
+	ctx := context.Background()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithDryRun(), WithMaxMetadataBytes(200))
+	_, err := client.Log(ctx, event)
+	if verr, ok := AsValidationError(err); !ok || verr.Field != "metadata" {
+		t.Errorf("Log() with MetadataReject error = %v, want metadata validation error", err)
+	}
+
+	tests := []struct {
+		policy MetadataSizePolicy
+		check  func(map[string]any) bool
+	}{
+		{MetadataTruncate, func(m map[string]any) bool {
+			note, _ := m["note"].(string)
+			return m["_truncated"] == true && strings.HasSuffix(note, truncatedSuffix) && m["id"] == float64(42)
+		}},
+		{MetadataDropLargestKeys, func(m map[string]any) bool {
+			dropped, _ := m["_dropped_keys"].([]any)
+			return m["note"] == nil && len(dropped) == 1 && dropped[0] == "note" && m["id"] == float64(42)
+		}},
+	}
+	for _, tt := range tests {
+		client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+			WithDryRun(), WithMaxMetadataBytes(200), WithMetadataSizePolicy(tt.policy))
+		e := event
+		if err := client.validateEvent(&e); err != nil {
+			t.Errorf("policy %d: validateEvent() error = %v", tt.policy, err)
+			continue
+		}
+		var got map[string]any
+		json.Unmarshal(e.Metadata, &got)
+		if len(e.Metadata) > 200 || !tt.check(got) {
+			t.Errorf("policy %d: metadata = %s (%d bytes)", tt.policy, e.Metadata, len(e.Metadata))
+		}
+	}
+
+	client, _ = NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithDryRun(), WithMaxMetadataBytes(200), WithMetadataSizePolicy(MetadataTruncate))
+	array, _ := json.Marshal([]string{long})
+	if _, err := client.Log(ctx, Event{UserID: "user_1", Action: "doc.updated", Metadata: array}); !IsClientValidationError(err) {
+		t.Errorf("Log() with oversized array metadata error = %v, want validation error", err)
+	}
+}
+
+func TestEvent_WithMetadataStruct(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City   string `json:"city"`
+		Street string `json:"street" tryl:"redact"`
+	}
+	type Audit struct {
+		Source string `json:"source"`
+	}
+	type Customer struct {
+		Audit
+		ID        string    `json:"id"`
+		Email     string    `json:"email" tryl:"redact"`
+		Note      string    `json:"note" tryl:"omitempty"`
+		Password  string    `tryl:"-"`
+		Addresses []Address `json:"addresses"`
+		Plan      string
+		internal  string
+	}
+
+	event, err := Event{UserID: "user_1", Action: "customer.updated"}.WithMetadataStruct(&Customer{
+		Audit:     Audit{Source: "admin"},
+		ID:        "cus_1",
+		Email:     "jane@example.com",
+		Password:  "hunter2",
+		Addresses: []Address{{City: "Paris", Street: "1 Rue de Rivoli"}},
+		Plan:      "pro",
+		internal:  "x",
+	})
+	if err != nil {
+		t.Fatalf("WithMetadataStruct() error = %v", err)
+	}
+
+	want := `{"Plan":"pro","addresses":[{"city":"Paris","street":"[REDACTED]"}],"email":"[REDACTED]","id":"cus_1","source":"admin"}`
+	if string(event.Metadata) != want {
+		t.Errorf("metadata = %s, want %s", event.Metadata, want)
+	}
+
+	if _, err := (Event{}).WithMetadataStruct("not a struct"); !IsClientValidationError(err) {
+		t.Errorf("WithMetadataStruct(string) error = %v, want validation error", err)
+	}
+}
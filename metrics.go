@@ -0,0 +1,48 @@
+package tryl
+
+import "sync/atomic"
+
+// Stats reports client-side counters, for export to a metrics system.
+// See the trylprom package for a Prometheus endpoint.
+type Stats struct {
+	// SuppressedEvents is the number of events suppressed by each action
+	// budget, keyed by the pattern passed to WithActionBudget.
+	SuppressedEvents map[string]uint64
+	// EventsSent is the number of events the API committed from Log,
+	// LogBatch, batching, and Group commits. With WithAgent, it counts
+	// events the agent accepted.
+	EventsSent uint64
+	// Batches is the number of successful batch requests.
+	Batches uint64
+	// Retries is the number of retried attempts of any request.
+	Retries uint64
+	// Dropped is the number of events queued for batching that were not
+	// sent: removed by BatchConfig.BeforeSend, rejected by the API, or in a
+	// batch request that failed.
+	Dropped uint64
+	// QueueDepth is the number of events waiting in the batching queue.
+	QueueDepth int
+}
+
+// stats holds the delivery counters reported by Client.Stats.
+type stats struct {
+	eventsSent atomic.Uint64
+	batches    atomic.Uint64
+	retries    atomic.Uint64
+	dropped    atomic.Uint64
+}
+
+// Stats returns the client's counters since it was created.
+func (c *Client) Stats() Stats {
+	s := Stats{
+		SuppressedEvents: c.budgets.suppressed(),
+		EventsSent:       c.stats.eventsSent.Load(),
+		Batches:          c.stats.batches.Load(),
+		Retries:          c.stats.retries.Load(),
+		Dropped:          c.stats.dropped.Load(),
+	}
+	if c.batcher != nil {
+		s.QueueDepth = len(c.batcher.pending)
+	}
+	return s
+}
@@ -1,10 +1,14 @@
 package tryl
 
 import (
+	"context"
 	"errors"
-	"net/http"
+	"fmt"
+	"net"
 	"strings"
 	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
 )
 
 const (
@@ -13,9 +17,7 @@ const (
 )
 
 // HTTPDoer is an interface for HTTP operations (for testing).
-type HTTPDoer interface {
-	Do(req *http.Request) (*http.Response, error)
-}
+type HTTPDoer = transport.HTTPDoer
 
 // Option configures the Client.
 type Option func(*clientConfig) error
@@ -24,22 +26,83 @@ type Option func(*clientConfig) error
 type clientConfig struct {
 	baseURL     string
 	httpClient  HTTPDoer
+	transport   Transport
 	retryConfig *RetryConfig
 	batchConfig *BatchConfig
 	userAgent   string
 	timeout     time.Duration
+	dryRun      bool
+	taxonomy    *Taxonomy
+	migrations  *MigrationRegistry
+	webSocket   bool
+
+	skewCorrection bool
+	skewWarnAt     time.Duration
+	onSkewWarn     func(skew time.Duration)
+
+	asyncErrorHandler   func(Event, error)
+	correlationFunc     func(ctx context.Context) string
+	actionNormalization actionNormalization
+	validation          ValidationMode
+	onValidationWarning func(Event, error)
+	maxMetadataBytes    int
+	metadataPolicy      MetadataSizePolicy
+	canonicalMetadata   bool
+	contentHash         bool
+	queryCacheTTL       time.Duration
+	queryCacheEntries   int
+	etagEntries         int
+	environmentGuard    Environment
+	connMaxLifetime     time.Duration
+	dialTimeout         time.Duration
+	dialer              func(ctx context.Context, network, addr string) (net.Conn, error)
+	socketPath          string
+	agentURL            string
+	clientID            string
+	maxResponseBytes    int64
+	reusableBuffers     bool
+	changeStore         ChangeStore
+	project             string
+	perTryTimeout       time.Duration
+	overallTimeout      time.Duration
+	noSDKHeader         bool
+	onDeprecation       func(Warning)
+	eventMarshaler      func(Event) ([]byte, error)
+	actionBudgets       map[string]Rate
+	compaction          *CompactionConfig
+	retentionWarnings   bool
+	onWarning           func(ResponseWarning)
+	clock               Clock
+	rand                Rand
 }
 
+// actionNormalization is the mode set by WithActionNormalization and
+// WithStrictActionNormalization.
+type actionNormalization int
+
+const (
+	actionNormalizationOff actionNormalization = iota
+	actionNormalizationRewrite
+	actionNormalizationStrict
+)
+
 // newDefaultConfig returns the default client configuration.
 func newDefaultConfig() *clientConfig {
 	return &clientConfig{
-		baseURL:     defaultBaseURL,
-		timeout:     defaultTimeout,
-		retryConfig: defaultRetryConfig(),
+		baseURL:          defaultBaseURL,
+		timeout:          defaultTimeout,
+		retryConfig:      defaultRetryConfig(),
+		maxMetadataBytes: DefaultMaxMetadataBytes,
+		changeStore:      NewMemoryChangeStore(defaultChangeStoreEntries),
+		clock:            systemClock{},
+		rand:             globalRand{},
 	}
 }
 
-// WithBaseURL sets a custom API base URL.
+// WithBaseURL sets a custom API base URL. A URL of the form
+// "unix:///var/run/tryl.sock" reaches the API over that Unix domain
+// socket instead of TCP, as for a collector deployed as a sidecar; it
+// cannot be combined with WithHTTPClient.
 // Default: "https://tryl.fly.dev"
 func WithBaseURL(url string) Option {
 	return func(c *clientConfig) error {
@@ -63,7 +126,9 @@ func WithHTTPClient(client HTTPDoer) Option {
 	}
 }
 
-// WithTimeout sets the request timeout.
+// WithTimeout sets the timeout of each HTTP request made by the default
+// HTTP client. Prefer WithPerTryTimeout and WithOverallTimeout, which also
+// apply to clients set with WithHTTPClient.
 // Default: 10 seconds
 func WithTimeout(d time.Duration) Option {
 	return func(c *clientConfig) error {
@@ -75,6 +140,67 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithDialTimeout bounds how long the default HTTP client waits to
+// establish a connection, so an unreachable backend fails fast while slow
+// responses are still governed by the request timeouts.
+// Default: 30 seconds, as for http.DefaultTransport
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *clientConfig) error {
+		if d <= 0 {
+			return errors.New("dial timeout must be positive")
+		}
+		c.dialTimeout = d
+		return nil
+	}
+}
+
+// WithDialer sets the function the default HTTP client and the WebSocket
+// connection use to dial the API, for example to route connections
+// through a proxy or a custom network stack. With a unix:// base URL it is
+// called with the network "unix" and the socket path. WithDialTimeout
+// bounds each call. It has no effect on clients set with WithHTTPClient.
+// Default: net.Dialer
+func WithDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(c *clientConfig) error {
+		if dial == nil {
+			return errors.New("dialer cannot be nil")
+		}
+		c.dialer = dial
+		return nil
+	}
+}
+
+// WithPerTryTimeout bounds each attempt of a request, so a hung attempt
+// is abandoned and retried rather than consuming the whole budget.
+// Default: no limit beyond WithTimeout
+func WithPerTryTimeout(d time.Duration) Option {
+	return func(c *clientConfig) error {
+		if d <= 0 {
+			return errors.New("per-try timeout must be positive")
+		}
+		c.perTryTimeout = d
+		return nil
+	}
+}
+
+// WithOverallTimeout bounds each operation as a whole, including all
+// retries and the backoff delays between them. Combine it with
+// WithPerTryTimeout to give a retried request a generous total budget
+// while each attempt fails fast:
+//
+//	tryl.WithPerTryTimeout(2*time.Second), tryl.WithOverallTimeout(30*time.Second)
+//
+// Default: no limit beyond the context's deadline
+func WithOverallTimeout(d time.Duration) Option {
+	return func(c *clientConfig) error {
+		if d <= 0 {
+			return errors.New("overall timeout must be positive")
+		}
+		c.overallTimeout = d
+		return nil
+	}
+}
+
 // WithRetry configures retry behavior.
 // Default: 3 retries with exponential backoff (base 1s, max 30s)
 func WithRetry(config RetryConfig) Option {
@@ -82,6 +208,12 @@ func WithRetry(config RetryConfig) Option {
 		if config.MaxAttempts < 0 {
 			return errors.New("max attempts cannot be negative")
 		}
+		if config.RetryBudget < 0 {
+			return errors.New("retry budget cannot be negative")
+		}
+		if config.Strategy < BackoffProportionalJitter || config.Strategy > BackoffDecorrelatedJitter {
+			return errors.New("unknown backoff strategy")
+		}
 		c.retryConfig = &config
 		return nil
 	}
@@ -95,6 +227,23 @@ func WithoutRetry() Option {
 	}
 }
 
+// WithConnectionMaxLifetime closes idle connections once every d, so
+// long-lived clients dial new connections, re-resolving DNS, and stop
+// sending requests to backends that have left the load balancer. Busy
+// connections are closed once they next become idle. It applies to the
+// default HTTP client and to clients set with WithHTTPClient that have a
+// CloseIdleConnections method, such as *http.Client.
+// Default: connections are reused until the server closes them
+func WithConnectionMaxLifetime(d time.Duration) Option {
+	return func(c *clientConfig) error {
+		if d <= 0 {
+			return errors.New("connection max lifetime must be positive")
+		}
+		c.connMaxLifetime = d
+		return nil
+	}
+}
+
 // WithBatching enables event batching.
 // Events are accumulated and sent in bulk for improved throughput.
 func WithBatching(config BatchConfig) Option {
@@ -116,6 +265,514 @@ func WithUserAgent(ua string) Option {
 	}
 }
 
+// WithoutSDKTelemetry stops sending the X-Tryl-SDK header, which reports
+// the SDK version, Go version, platform, and the names of enabled features
+// such as batching. The header carries no event data or identifiers; the
+// service uses it to plan deprecations.
+func WithoutSDKTelemetry() Option {
+	return func(c *clientConfig) error {
+		c.noSDKHeader = true
+		return nil
+	}
+}
+
+// WithDryRun enables dry-run mode.
+// Log, LogBatch, and LogAsync run full client-side validation but never
+// contact the API. Successful calls return synthetic responses whose IDs
+// start with "evt_dryrun_". Read and management operations are unaffected.
+func WithDryRun() Option {
+	return func(c *clientConfig) error {
+		c.dryRun = true
+		return nil
+	}
+}
+
+// WithTaxonomy restricts logged events to actions registered in t.
+// Log, LogBatch, and LogAsync return a ValidationError for any other action.
+func WithTaxonomy(t *Taxonomy) Option {
+	return func(c *clientConfig) error {
+		if t == nil {
+			return errors.New("taxonomy cannot be nil")
+		}
+		c.taxonomy = t
+		return nil
+	}
+}
+
+// WithMigrations upgrades the metadata of events returned by List using the
+// migrations registered in r, so callers only see the latest shapes.
+func WithMigrations(r *MigrationRegistry) Option {
+	return func(c *clientConfig) error {
+		if r == nil {
+			return errors.New("migration registry cannot be nil")
+		}
+		c.migrations = r
+		return nil
+	}
+}
+
+// WithWebSocket sends Log and LogBatch requests over a persistent WebSocket
+// connection to the API instead of one HTTP request each, avoiding
+// per-request TLS and HTTP overhead for very high frequency loggers.
+// The connection is opened on first use and reopened after failures; lost
+// connections surface as a NetworkError and are retried as usual. When the
+// server signals backpressure, new requests wait for the requested pause.
+// Read and management operations still use HTTP.
+func WithWebSocket() Option {
+	return func(c *clientConfig) error {
+		c.webSocket = true
+		return nil
+	}
+}
+
+// WithAgent sends Log and LogBatch requests, including those made for
+// LogAsync and batching, to a local forwarding agent such as
+// cmd/tryl-agent instead of the API. The agent acknowledges events as soon
+// as it has queued them and handles batching, retries, and spilling to
+// disk itself, so logging costs one local round trip. url is the agent's
+// address, such as "http://localhost:4327" or "unix:///var/run/tryl-agent.sock".
+// The agent sends events with its own API key and refuses requests made
+// with a different one, so the client's key must match the agent's.
+// Responses carry IDs assigned by the agent rather than by the API. Group
+// commits and all other requests still go to the API. WithAgent cannot be
+// combined with WithWebSocket.
+// Default: events are sent to the API
+func WithAgent(url string) Option {
+	return func(c *clientConfig) error {
+		if url == "" {
+			return errors.New("agent URL cannot be empty")
+		}
+		c.agentURL = strings.TrimSuffix(url, "/")
+		return nil
+	}
+}
+
+// WithMaxResponseBytes bounds the memory of each response: bodies larger
+// than n bytes fail with ErrResponseTooLarge instead of being read in
+// full. Reduce EventFilter.Limit for queries that exceed it.
+// Default: no limit
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *clientConfig) error {
+		if n <= 0 {
+			return errors.New("max response bytes must be positive")
+		}
+		c.maxResponseBytes = n
+		return nil
+	}
+}
+
+// WithReusableBuffers decodes List responses into pooled buffers and
+// event slices that are reused across calls, reducing garbage collection
+// for clients that read large pages. Call EventList.Release when done with
+// a list to return its events to the pool; lists that are not released
+// are garbage collected as usual. With WithETagCache, response bodies are
+// not pooled, as the cache keeps them.
+// Default: disabled
+func WithReusableBuffers() Option {
+	return func(c *clientConfig) error {
+		c.reusableBuffers = true
+		return nil
+	}
+}
+
+// WithChangeStore sets the store LogChanged remembers values in, for
+// example one shared by every replica of a service.
+// Default: NewMemoryChangeStore(10000)
+func WithChangeStore(store ChangeStore) Option {
+	return func(c *clientConfig) error {
+		if store == nil {
+			return errors.New("change store cannot be nil")
+		}
+		c.changeStore = store
+		return nil
+	}
+}
+
+// WithClientID sets the ID that labels the client's background goroutines
+// in pprof profiles and heads Client.DumpState, to tell several clients in
+// one process apart.
+// Default: "tryl-N", numbering clients in the order they are created
+func WithClientID(id string) Option {
+	return func(c *clientConfig) error {
+		if id == "" {
+			return errors.New("client ID cannot be empty")
+		}
+		c.clientID = id
+		return nil
+	}
+}
+
+// WithProjectLabel sets the project name that labels the client's background
+// goroutines in pprof profiles, alongside its client ID. It does not
+// change which project events are logged to, which the API key decides.
+// Default: no project label
+func WithProjectLabel(project string) Option {
+	return func(c *clientConfig) error {
+		c.project = project
+		return nil
+	}
+}
+
+// WithClockSkewCorrection shifts the StartTime and EndTime of List and
+// ListManagementAudit filters by the measured clock skew (see
+// Client.ClockSkew), so time ranges computed from a skewed local clock
+// select the events the caller expects.
+func WithClockSkewCorrection() Option {
+	return func(c *clientConfig) error {
+		c.skewCorrection = true
+		return nil
+	}
+}
+
+// WithClockSkewWarning calls fn when the measured clock skew first exceeds
+// threshold in either direction. fn is called again only after the skew
+// has returned within the threshold and exceeded it once more.
+func WithClockSkewWarning(threshold time.Duration, fn func(skew time.Duration)) Option {
+	return func(c *clientConfig) error {
+		if threshold <= 0 {
+			return errors.New("clock skew threshold must be positive")
+		}
+		if fn == nil {
+			return errors.New("clock skew warning function cannot be nil")
+		}
+		c.skewWarnAt = threshold
+		c.onSkewWarn = fn
+		return nil
+	}
+}
+
+// WithActionNormalization rewrites event actions with NormalizeAction
+// before validation, so legacy names such as "User Created" or
+// "user-created" are logged as "user_created" instead of failing the
+// action format check.
+func WithActionNormalization() Option {
+	return func(c *clientConfig) error {
+		c.actionNormalization = actionNormalizationRewrite
+		return nil
+	}
+}
+
+// WithStrictActionNormalization rejects actions that NormalizeAction would
+// change, with a *ValidationError naming the normalized form. Use it to
+// find legacy names before switching to WithActionNormalization, or to keep
+// new code from relying on normalization.
+func WithStrictActionNormalization() Option {
+	return func(c *clientConfig) error {
+		c.actionNormalization = actionNormalizationStrict
+		return nil
+	}
+}
+
+// ValidationMode controls client-side event validation; see WithValidation.
+type ValidationMode int
+
+const (
+	// ValidationStrict rejects invalid events with a *ValidationError
+	// before sending them. This is the default.
+	ValidationStrict ValidationMode = iota
+	// ValidationWarn reports invalid events to the validation warning
+	// handler and sends them anyway, leaving the decision to the server.
+	ValidationWarn
+	// ValidationOff skips client-side validation entirely.
+	ValidationOff
+)
+
+// WithValidation sets how the client applies its local event rules (field
+// lengths, action and tag formats, metadata JSON). Use ValidationWarn or
+// ValidationOff with backends that accept events these rules reject.
+// Taxonomy and strict action normalization checks, which are configured
+// explicitly, still apply.
+// Default: ValidationStrict
+func WithValidation(mode ValidationMode) Option {
+	return func(c *clientConfig) error {
+		if mode < ValidationStrict || mode > ValidationOff {
+			return fmt.Errorf("invalid validation mode %d", mode)
+		}
+		c.validation = mode
+		return nil
+	}
+}
+
+// WithValidationWarningHandler sets the function called with events that
+// fail validation in ValidationWarn mode. It is called once per event sent,
+// before any retries, and may be called concurrently.
+// Default: warnings are discarded
+func WithValidationWarningHandler(fn func(event Event, err error)) Option {
+	return func(c *clientConfig) error {
+		if fn == nil {
+			return errors.New("validation warning handler cannot be nil")
+		}
+		c.onValidationWarning = fn
+		return nil
+	}
+}
+
+// WithMaxMetadataBytes sets the largest encoded metadata the client
+// accepts, so oversized events fail (or are reduced; see
+// WithMetadataSizePolicy) before a round trip. Lower it to keep audit
+// payloads small; raising it above the API's limit only moves the failure
+// to the server.
+// Default: DefaultMaxMetadataBytes
+func WithMaxMetadataBytes(n int) Option {
+	return func(c *clientConfig) error {
+		if n <= 0 {
+			return errors.New("max metadata bytes must be positive")
+		}
+		c.maxMetadataBytes = n
+		return nil
+	}
+}
+
+// WithMetadataSizePolicy sets what happens to events whose metadata
+// exceeds the size limit.
+// Default: MetadataReject
+func WithMetadataSizePolicy(p MetadataSizePolicy) Option {
+	return func(c *clientConfig) error {
+		if p < MetadataReject || p > MetadataDropLargestKeys {
+			return fmt.Errorf("invalid metadata size policy %d", p)
+		}
+		c.metadataPolicy = p
+		return nil
+	}
+}
+
+// WithCanonicalMetadata sends event metadata in canonical form (see
+// CanonicalizeJSON), so the same metadata logged from services in
+// different languages has identical bytes for server-side deduplication,
+// signatures, and content hashes.
+func WithCanonicalMetadata() Option {
+	return func(c *clientConfig) error {
+		c.canonicalMetadata = true
+		return nil
+	}
+}
+
+// WithContentHash sends each event with Event.ContentHash set to its
+// EventHash, so consumers can detect tampering or corruption with
+// VerifyEventHash.
+func WithContentHash() Option {
+	return func(c *clientConfig) error {
+		c.contentHash = true
+		return nil
+	}
+}
+
+// WithQueryCache caches List and Count results for ttl, keeping at most
+// maxEntries distinct queries, so dashboards that repeat the same queries
+// every few seconds do not reach the API each time. Queries are keyed by
+// their normalized parameters; relative ranges such as Last24Hours differ
+// on every call, so truncate their times (e.g., to the minute) to share
+// entries. Use ContextWithoutCache to bypass the cache for one call and
+// Client.InvalidateQueryCache to clear it. Only List and Count are cached;
+// methods that page through events, such as Chain, VerifyChain, Poller, and
+// ListAllParallel, always fetch fresh results.
+func WithQueryCache(ttl time.Duration, maxEntries int) Option {
+	return func(c *clientConfig) error {
+		if ttl <= 0 {
+			return errors.New("query cache TTL must be positive")
+		}
+		if maxEntries <= 0 {
+			return errors.New("query cache max entries must be positive")
+		}
+		c.queryCacheTTL = ttl
+		c.queryCacheEntries = maxEntries
+		return nil
+	}
+}
+
+// WithETagCache makes List and GetProject revalidate responses they have
+// fetched before with If-None-Match, keeping the bodies of up to
+// maxEntries requests. When the server answers 304 Not Modified the cached
+// body is reused, saving bandwidth for pollers that repeatedly fetch
+// unchanged pages. Unlike WithQueryCache, every call still reaches the
+// server, so results are never stale.
+func WithETagCache(maxEntries int) Option {
+	return func(c *clientConfig) error {
+		if maxEntries <= 0 {
+			return errors.New("ETag cache max entries must be positive")
+		}
+		c.etagEntries = maxEntries
+		return nil
+	}
+}
+
+// WithEnvironmentGuard declares the environment the client must run in.
+// NewClient fails with ErrEnvironmentMismatch if the API key belongs to
+// another environment, so a misconfigured test deployment cannot log to a
+// live project. For management clients, whose session tokens carry no
+// environment, CreateProject and CreateAPIKey reject other environments.
+func WithEnvironmentGuard(env Environment) Option {
+	return func(c *clientConfig) error {
+		if err := env.validate(true); err != nil {
+			return err
+		}
+		c.environmentGuard = env
+		return nil
+	}
+}
+
+// WithDeprecationHandler sets a function called when an API response
+// announces a deprecation through its Deprecation, Sunset, or X-API-Warn
+// headers. Each distinct warning is reported once per HTTP method and
+// client, with the path of the first request that received it.
+// Default: warnings are discarded
+func WithDeprecationHandler(fn func(Warning)) Option {
+	return func(c *clientConfig) error {
+		if fn == nil {
+			return errors.New("deprecation handler cannot be nil")
+		}
+		c.onDeprecation = fn
+		return nil
+	}
+}
+
+// WithEventMarshaler sets the function that encodes events for the API,
+// for example to add company-wide envelope fields or strip null values. It
+// is applied after validation to every event sent by Log, LogBatch, the
+// Batcher, groups, streams, and LogAt, and must return a JSON object the
+// API accepts as an event.
+// Default: json.Marshal
+func WithEventMarshaler(fn func(Event) ([]byte, error)) Option {
+	return func(c *clientConfig) error {
+		if fn == nil {
+			return errors.New("event marshaler cannot be nil")
+		}
+		c.eventMarshaler = fn
+		return nil
+	}
+}
+
+// WithActionBudget caps the rate of events per action pattern, so noisy
+// actions such as "heartbeat.*" are limited without affecting other
+// events:
+//
+//	tryl.WithActionBudget(map[string]tryl.Rate{
+//	    "heartbeat.*": {Events: 10, Per: time.Minute},
+//	})
+//
+// An event is charged to the most specific matching pattern. Log returns
+// ErrActionBudgetExceeded for events over budget, and LogAsync completes
+// them with it; LogFireAndForget drops them without calling the async
+// error handler. LogBatch is not budgeted. Suppressed events are counted
+// in Client.Stats.
+func WithActionBudget(budgets map[string]Rate) Option {
+	return func(c *clientConfig) error {
+		if len(budgets) == 0 {
+			return errors.New("action budgets cannot be empty")
+		}
+		for pattern, rate := range budgets {
+			if err := ValidateActionPattern(pattern); err != nil {
+				return err
+			}
+			if rate.Events <= 0 || rate.Per <= 0 {
+				return fmt.Errorf("action budget for %q must have positive events and period", pattern)
+			}
+		}
+		c.actionBudgets = budgets
+		return nil
+	}
+}
+
+// WithCompaction counts events of counter-like actions, such as
+// "api.request", locally and sends one event per interval for each user,
+// tenant, actor, and target with the number of events in its metadata:
+//
+//	{"count": 1284, "window_start": "...", "window_end": "..."}
+//
+// This greatly reduces the volume of high-frequency telemetry. The
+// metadata of counted events is dropped. Counted events are validated as
+// the rolled-up event when logged. Log and LogAsync return a synthetic
+// response for counted events, with the local time and an empty ID, since
+// no event is stored for them until the interval ends. Counts are sent with
+// LogBatch, on Flush, and on Close; delivery failures go to the handler
+// set with WithAsyncErrorHandler.
+func WithCompaction(config CompactionConfig) Option {
+	return func(c *clientConfig) error {
+		if len(config.Actions) == 0 {
+			return errors.New("compaction requires at least one action pattern")
+		}
+		for _, pattern := range config.Actions {
+			if err := ValidateActionPattern(pattern); err != nil {
+				return err
+			}
+		}
+		config.Actions = append([]string(nil), config.Actions...)
+		c.compaction = &config
+		return nil
+	}
+}
+
+// WithRetentionWarnings makes List add a WarningRetentionTruncated
+// warning to EventList.Warnings when the filter's StartTime precedes the
+// project's retention window, so callers know results are incomplete
+// because older events were deleted. The policy is fetched with
+// GetRetentionPolicy and reused for an hour; if it cannot be fetched, no
+// warning is added and the fetch is retried after a minute.
+// Default: disabled
+func WithRetentionWarnings() Option {
+	return func(c *clientConfig) error {
+		c.retentionWarnings = true
+		return nil
+	}
+}
+
+// WithWarningHandler sets a function called with each warning in API
+// response bodies, such as deprecated parameters or downgraded filters,
+// so they can be logged or counted in one place instead of checking every
+// result's Warnings. It is called for the warnings of event, batch, list,
+// and summary responses, including internal requests such as batch
+// flushes, and must be safe for concurrent use.
+// Default: none
+func WithWarningHandler(fn func(ResponseWarning)) Option {
+	return func(c *clientConfig) error {
+		if fn == nil {
+			return errors.New("warning handler cannot be nil")
+		}
+		c.onWarning = fn
+		return nil
+	}
+}
+
+// WithClock sets the clock used for retry backoff, flush intervals, and
+// other timers, so tests of retry and batching behavior can advance time
+// instead of sleeping. See Clock.
+// Default: the system clock
+func WithClock(clock Clock) Option {
+	return func(c *clientConfig) error {
+		if clock == nil {
+			return errors.New("clock cannot be nil")
+		}
+		c.clock = clock
+		return nil
+	}
+}
+
+// WithRand sets the random source for retry jitter, so backoff delays are
+// reproducible in tests, for example with rand.New(rand.NewSource(1)).
+// Default: the math/rand global source
+func WithRand(r Rand) Option {
+	return func(c *clientConfig) error {
+		if r == nil {
+			return errors.New("rand cannot be nil")
+		}
+		c.rand = &lockedRand{r: r}
+		return nil
+	}
+}
+
+// WithAsyncErrorHandler sets the function called when an event sent with
+// LogFireAndForget fails to be delivered. It is called from a background
+// goroutine and must be safe for concurrent use.
+func WithAsyncErrorHandler(fn func(event Event, err error)) Option {
+	return func(c *clientConfig) error {
+		if fn == nil {
+			return errors.New("async error handler cannot be nil")
+		}
+		c.asyncErrorHandler = fn
+		return nil
+	}
+}
+
 // RetryConfig configures retry behavior.
 type RetryConfig struct {
 	// MaxAttempts is the maximum number of attempts (including the initial request).
@@ -135,10 +792,52 @@ type RetryConfig struct {
 	Multiplier float64
 
 	// JitterFactor adds randomness to delays (0.0 to 1.0).
+	// Only used by BackoffProportionalJitter.
 	// Default: 0.2 (20% jitter)
 	JitterFactor float64
+
+	// Strategy selects how jitter is applied to the backoff delay.
+	// Default: BackoffProportionalJitter
+	Strategy BackoffStrategy
+
+	// RetryBudget caps the total time spent on an operation, measured from
+	// the first attempt. A retry whose delay would exceed the budget is not
+	// attempted and the last error is returned instead.
+	// Default: 0 (no budget)
+	RetryBudget time.Duration
+
+	// RetryableStatusCodes replaces the default set of HTTP status codes
+	// that are retried (500-599 and 429). Use it to opt into retrying 409s
+	// or to stop retrying 429s. An empty non-nil slice retries no API errors.
+	// Default: nil (use the default set)
+	RetryableStatusCodes []int
+
+	// RetryOn decides whether an API error is retried. When set, it takes
+	// precedence over RetryableStatusCodes. Network errors are unaffected.
+	// Default: nil
+	RetryOn func(*APIError) bool
+
+	// OnRetry is called before each retry with the number of the attempt
+	// that just failed (starting at 1), its error, and the delay before the
+	// next attempt. Useful for logging and metrics (optional).
+	OnRetry func(attempt int, err error, nextDelay time.Duration)
 }
 
+// BackoffStrategy selects the jitter algorithm used between retries.
+type BackoffStrategy int
+
+const (
+	// BackoffProportionalJitter applies exponential backoff and then adds
+	// up to +/- JitterFactor of the delay.
+	BackoffProportionalJitter BackoffStrategy = iota
+	// BackoffFullJitter picks a random delay between zero and the
+	// exponential backoff delay.
+	BackoffFullJitter
+	// BackoffDecorrelatedJitter picks a random delay between BaseDelay and
+	// three times the previous delay, capped at MaxDelay.
+	BackoffDecorrelatedJitter
+)
+
 // defaultRetryConfig returns the default retry configuration.
 func defaultRetryConfig() *RetryConfig {
 	return &RetryConfig{
@@ -167,6 +866,21 @@ type BatchConfig struct {
 
 	// OnError is called when a batch fails (optional).
 	OnError func(events []Event, err error)
+
+	// BeforeSend is called with each batch right before it is sent, and
+	// the batch is replaced by the events it returns (optional). Use it
+	// to mutate or filter events, for example to drop internal test
+	// users. Events are matched to their LogAsync handles by
+	// IdempotencyKey, which is assigned beforehand; events removed from
+	// the batch complete with ErrEventDropped. Returning no events skips
+	// the request.
+	BeforeSend func(events []Event) []Event
+
+	// OnPressure is called with the queue level, from 0 to 1, when it
+	// crosses a tenth of MaxPendingEvents in either direction, as observed
+	// when events are queued (optional). Use it to shed optional events
+	// before LogAsync starts to block; see also Client.QueuePressure.
+	OnPressure func(level float64)
 }
 
 // defaultBatchConfig returns the default batch configuration.
@@ -177,3 +891,26 @@ func defaultBatchConfig() *BatchConfig {
 		MaxPendingEvents: 10000,
 	}
 }
+
+// WithCorrelationIDFunc sets a function that supplies the CorrelationID of
+// events logged without one, for example from the active trace. It is
+// consulted after ContextWithCorrelationID and applies to Log, LogBatch,
+// LogMany, LogAsync, and LogFireAndForget.
+//
+// To correlate events with OpenTelemetry traces:
+//
+//	tryl.WithCorrelationIDFunc(func(ctx context.Context) string {
+//	    if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+//	        return sc.TraceID().String()
+//	    }
+//	    return ""
+//	})
+func WithCorrelationIDFunc(fn func(ctx context.Context) string) Option {
+	return func(c *clientConfig) error {
+		if fn == nil {
+			return errors.New("correlation ID function cannot be nil")
+		}
+		c.correlationFunc = fn
+		return nil
+	}
+}
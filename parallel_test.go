@@ -0,0 +1,95 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_ListAllParallel(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var all []StoredEvent
+	for i := 0; i < 250; i++ {
+		all = append(all, StoredEvent{ID: fmt.Sprintf("evt_%03d", i), UserID: "user_1", Action: "doc.viewed", Timestamp: base.Add(time.Duration(i) * time.Minute)})
+	}
+
+	var running, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(time.Millisecond)
+
+		q := r.URL.Query()
+		var matching []StoredEvent
+		for _, e := range all {
+			if v := q.Get("start_time"); v != "" {
+				if start, _ := time.Parse(time.RFC3339Nano, v); e.Timestamp.Before(start) {
+					continue
+				}
+			}
+			if v := q.Get("end_time"); v != "" {
+				if end, _ := time.Parse(time.RFC3339Nano, v); e.Timestamp.After(end) {
+					continue
+				}
+			}
+			matching = append(matching, e)
+		}
+		if q.Get("order") != "asc" {
+			for i, j := 0, len(matching)-1; i < j; i, j = i+1, j-1 {
+				matching[i], matching[j] = matching[j], matching[i]
+			}
+		}
+		offset, _ := strconv.Atoi(q.Get("cursor"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		page := EventList{Events: matching[offset:min(offset+limit, len(matching))]}
+		if offset+limit < len(matching) {
+			page.HasMore, page.NextCursor = true, strconv.Itoa(offset+limit)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	end := base.Add(300 * time.Minute)
+	for _, order := range []string{"asc", "desc"} {
+		events, err := client.ListAllParallel(ctx, EventFilter{Order: order, EndTime: &end, Limit: 20}, 3)
+		if err != nil {
+			t.Fatalf("ListAllParallel(%s) error = %v", order, err)
+		}
+		if len(events) != len(all) {
+			t.Fatalf("ListAllParallel(%s) returned %d events, want %d", order, len(events), len(all))
+		}
+		for i, e := range events {
+			want := all[i]
+			if order == "desc" {
+				want = all[len(all)-1-i]
+			}
+			if e.ID != want.ID {
+				t.Fatalf("ListAllParallel(%s)[%d] = %s, want %s", order, i, e.ID, want.ID)
+			}
+		}
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("peak concurrent requests = %d, want at most 3", got)
+	}
+
+	empty := base.Add(-time.Hour)
+	if events, err := client.ListAllParallel(ctx, EventFilter{EndTime: &empty}, 2); err != nil || len(events) != 0 {
+		t.Errorf("ListAllParallel() before the first event = %d events, %v", len(events), err)
+	}
+}
@@ -0,0 +1,289 @@
+package tryl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CheckpointStore persists a Poller's position so it resumes where it left
+// off after a restart. Implementations must be safe for use by one Poller
+// per name.
+//
+// FileCheckpointStore and SQLCheckpointStore are provided. A Redis store is
+// a few lines with go-redis:
+//
+//	type redisStore struct{ rdb *redis.Client }
+//
+//	func (s redisStore) Load(ctx context.Context, name string) (string, error) {
+//	    cursor, err := s.rdb.Get(ctx, "tryl:checkpoint:"+name).Result()
+//	    if errors.Is(err, redis.Nil) {
+//	        return "", nil
+//	    }
+//	    return cursor, err
+//	}
+//
+//	func (s redisStore) Save(ctx context.Context, name, cursor string) error {
+//	    return s.rdb.Set(ctx, "tryl:checkpoint:"+name, cursor, 0).Err()
+//	}
+type CheckpointStore interface {
+	// Load returns the cursor saved for name, or "" if there is none.
+	Load(ctx context.Context, name string) (string, error)
+	// Save replaces the cursor saved for name.
+	Save(ctx context.Context, name, cursor string) error
+}
+
+// FileCheckpointStore saves each checkpoint as a file named after the
+// poller in Dir. Writes are atomic.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+// Load implements CheckpointStore.
+func (s FileCheckpointStore) Load(ctx context.Context, name string) (string, error) {
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save implements CheckpointStore.
+func (s FileCheckpointStore) Save(ctx context.Context, name, cursor string) error {
+	path := s.path(name)
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmp, []byte(cursor), 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (s FileCheckpointStore) path(name string) string {
+	return filepath.Join(s.Dir, filepath.Base(name)+".checkpoint")
+}
+
+// SQLCheckpointStore saves checkpoints in a table with a text primary key
+// column "name" and a text column "cursor":
+//
+//	CREATE TABLE tryl_checkpoints (name TEXT PRIMARY KEY, cursor TEXT NOT NULL);
+type SQLCheckpointStore struct {
+	DB *sql.DB
+	// Table is the checkpoint table. Default: "tryl_checkpoints"
+	Table string
+	// DollarPlaceholders uses $1-style bind parameters, for PostgreSQL
+	// drivers. By default "?" is used.
+	DollarPlaceholders bool
+}
+
+// Load implements CheckpointStore.
+func (s SQLCheckpointStore) Load(ctx context.Context, name string) (string, error) {
+	var cursor string
+	err := s.DB.QueryRowContext(ctx, s.query("SELECT cursor FROM %s WHERE name = ?"), name).Scan(&cursor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return cursor, nil
+}
+
+// Save implements CheckpointStore.
+func (s SQLCheckpointStore) Save(ctx context.Context, name, cursor string) error {
+	res, err := s.DB.ExecContext(ctx, s.query("UPDATE %s SET cursor = ? WHERE name = ?"), cursor, name)
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+	if _, err := s.DB.ExecContext(ctx, s.query("INSERT INTO %s (name, cursor) VALUES (?, ?)"), name, cursor); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// query fills in the table name and rewrites placeholders if needed.
+func (s SQLCheckpointStore) query(format string) string {
+	table := s.Table
+	if table == "" {
+		table = "tryl_checkpoints"
+	}
+	q := fmt.Sprintf(format, table)
+	if !s.DollarPlaceholders {
+		return q
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// PollerConfig configures a Poller.
+type PollerConfig struct {
+	// Name identifies the poller's checkpoint. Required.
+	Name string
+	// Filter selects the events to poll. Its StartTime, Order, OrderBy,
+	// Cursor, and Offset are ignored.
+	Filter EventFilter
+	// Handler is called for each new event in time order. If it returns
+	// an error, the poll stops and the event is delivered again on the
+	// next poll. Required.
+	Handler func(ctx context.Context, event StoredEvent) error
+	// Store persists the poller's position. Required.
+	Store CheckpointStore
+	// Interval is the wait between polls when there are no new events.
+	// Default: 5s
+	Interval time.Duration
+	// Since is where polling starts when the store has no checkpoint.
+	// Default: the time of the first poll
+	Since time.Time
+	// OnError, if set, is called with poll errors and Run keeps polling.
+	// If nil, Run returns the first error.
+	OnError func(err error)
+}
+
+// Poller periodically lists new events matching a filter, passes each to
+// a handler, and checkpoints its position after each page. Delivery is at
+// least once: events may be delivered again if the process stops before
+// the checkpoint is saved.
+type Poller struct {
+	client *Client
+	config PollerConfig
+	pos    *ResumeCursor
+}
+
+// NewPoller creates a Poller. Call Run to start polling.
+func (c *Client) NewPoller(config PollerConfig) (*Poller, error) {
+	if config.Name == "" {
+		return nil, &ValidationError{Field: "name", Message: "is required"}
+	}
+	if config.Handler == nil {
+		return nil, &ValidationError{Field: "handler", Message: "is required"}
+	}
+	if config.Store == nil {
+		return nil, &ValidationError{Field: "store", Message: "is required"}
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	config.Filter.StartTime, config.Filter.Order, config.Filter.OrderBy = nil, "", ""
+	config.Filter.Cursor, config.Filter.Offset = "", 0
+	return &Poller{client: c, config: config}, nil
+}
+
+// Run polls until ctx is canceled, then returns ctx.Err(). Pages are
+// fetched back to back while more events are available.
+func (p *Poller) Run(ctx context.Context) error {
+	for {
+		_, more, err := p.poll(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if p.config.OnError == nil {
+				return err
+			}
+			p.config.OnError(err)
+			more = false
+		}
+		if more {
+			continue
+		}
+
+		timer := time.NewTimer(p.config.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll fetches and handles one page of new events and returns how many
+// were handled. Use it instead of Run to drive polling from a scheduler.
+func (p *Poller) Poll(ctx context.Context) (int, error) {
+	n, _, err := p.poll(ctx)
+	return n, err
+}
+
+// poll handles one page and reports whether more events may be waiting.
+func (p *Poller) poll(ctx context.Context) (handled int, more bool, err error) {
+	if p.pos == nil {
+		if err := p.load(ctx); err != nil {
+			return 0, false, err
+		}
+	}
+
+	list, err := p.client.listFrom(ctx, *p.pos)
+	if err != nil {
+		return 0, false, err
+	}
+	for i, event := range list.Events {
+		if err := p.config.Handler(ctx, event); err != nil {
+			if i > 0 {
+				if err := p.save(ctx, p.pos.advance(list.Events[:i], "")); err != nil {
+					return i, false, err
+				}
+			}
+			return i, false, fmt.Errorf("handler failed for event %s: %w", event.ID, err)
+		}
+	}
+	if err := p.save(ctx, *list.resume); err != nil {
+		return len(list.Events), false, err
+	}
+	return len(list.Events), list.HasMore, nil
+}
+
+// load reads the checkpoint, or starts from config.Since if there is none.
+func (p *Poller) load(ctx context.Context) error {
+	saved, err := p.config.Store.Load(ctx, p.config.Name)
+	if err != nil {
+		return err
+	}
+	if saved == "" {
+		since := p.config.Since
+		if since.IsZero() {
+			since = time.Now()
+		}
+		p.pos = &ResumeCursor{Filter: p.config.Filter, After: since}
+		return nil
+	}
+	rc, err := ParseResumeCursor(saved)
+	if err != nil {
+		return err
+	}
+	// The filter may have changed since the checkpoint was saved, so the
+	// server cursor is dropped and the position is found by time.
+	rc.Filter, rc.Cursor = p.config.Filter, ""
+	p.pos = &rc
+	return nil
+}
+
+// save persists pos and makes it the current position.
+func (p *Poller) save(ctx context.Context, pos ResumeCursor) error {
+	if err := p.config.Store.Save(ctx, p.config.Name, pos.String()); err != nil {
+		return err
+	}
+	p.pos = &pos
+	return nil
+}
@@ -0,0 +1,106 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPoller_Poll(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	seek := &seekServer{}
+	seek.add("evt_0", base.Add(-time.Minute))
+	seek.add("evt_1", base.Add(100*time.Millisecond))
+	seek.add("evt_2", base.Add(200*time.Millisecond))
+	seek.add("evt_3", base.Add(300*time.Millisecond))
+	server := httptest.NewServer(seek)
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	store := FileCheckpointStore{Dir: t.TempDir()}
+
+	var handled []string
+	failOn := "evt_2"
+	newPoller := func() *Poller {
+		p, err := client.NewPoller(PollerConfig{
+			Name:   "billing",
+			Filter: EventFilter{UserID: "user_1"},
+			Store:  store,
+			Since:  base,
+			Handler: func(ctx context.Context, e StoredEvent) error {
+				if e.ID == failOn {
+					return errors.New("downstream unavailable")
+				}
+				handled = append(handled, e.ID)
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("NewPoller() error = %v", err)
+		}
+		return p
+	}
+
+	// A handler failure checkpoints the events before it.
+	poller := newPoller()
+	if n, err := poller.Poll(ctx); err == nil || n != 1 {
+		t.Fatalf("Poll() = %d, %v, want 1 and a handler error", n, err)
+	}
+
+	// A new poller resumes from the checkpoint and retries the failed event.
+	failOn = ""
+	poller = newPoller()
+	for _, want := range []int{1, 1, 0} {
+		if n, err := poller.Poll(ctx); err != nil || n != want {
+			t.Fatalf("Poll() = %d, %v, want %d", n, err, want)
+		}
+	}
+	seek.add("evt_4", base.Add(time.Second))
+	if n, err := poller.Poll(ctx); err != nil || n != 1 {
+		t.Fatalf("Poll() = %d, %v, want 1", n, err)
+	}
+
+	if got := fmt.Sprint(handled); got != "[evt_1 evt_2 evt_3 evt_4]" {
+		t.Errorf("handled %s, want each event once", got)
+	}
+}
+
+func TestClient_NewPoller_Validation(t *testing.T) {
+	t.Parallel()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef")
+	handler := func(ctx context.Context, e StoredEvent) error { return nil }
+	store := FileCheckpointStore{Dir: t.TempDir()}
+
+	for _, config := range []PollerConfig{
+		{Handler: handler, Store: store},
+		{Name: "p", Store: store},
+		{Name: "p", Handler: handler},
+	} {
+		if _, err := client.NewPoller(config); !IsClientValidationError(err) {
+			t.Errorf("NewPoller(%+v) error = %v, want validation error", config, err)
+		}
+	}
+}
+
+func TestSQLCheckpointStore_Query(t *testing.T) {
+	t.Parallel()
+
+	s := SQLCheckpointStore{Table: "checkpoints", DollarPlaceholders: true}
+	got := s.query("UPDATE %s SET cursor = ? WHERE name = ?")
+	if want := "UPDATE checkpoints SET cursor = $1 WHERE name = $2"; got != want {
+		t.Errorf("query() = %q, want %q", got, want)
+	}
+	if got := (SQLCheckpointStore{}).query("SELECT cursor FROM %s WHERE name = ?"); got != "SELECT cursor FROM tryl_checkpoints WHERE name = ?" {
+		t.Errorf("query() = %q", got)
+	}
+}
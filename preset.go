@@ -0,0 +1,112 @@
+package tryl
+
+import (
+	"context"
+	"sync"
+)
+
+// filterPresets holds the filters registered with Client.RegisterFilter.
+type filterPresets struct {
+	mu      sync.RWMutex
+	filters map[string]EventFilter
+}
+
+// RegisterFilter registers filter under name, so code across a service
+// can list events with the same definition through ListPreset:
+//
+//	client.RegisterFilter("security", tryl.EventFilter{Tags: []string{"security"}, Action: "auth.*"})
+//	...
+//	list, err := client.ListPreset(ctx, "security", tryl.EventFilter{UserID: id})
+//
+// Registering a name again replaces its filter. Presets are local to the
+// client; use CreateSavedQuery to share a filter through the API.
+func (c *Client) RegisterFilter(name string, filter EventFilter) error {
+	if name == "" {
+		return &ValidationError{Field: "name", Message: "is required"}
+	}
+	if filter.Action != "" {
+		if err := ValidateActionPattern(filter.Action); err != nil {
+			return err
+		}
+	}
+
+	c.presets.mu.Lock()
+	defer c.presets.mu.Unlock()
+	if c.presets.filters == nil {
+		c.presets.filters = make(map[string]EventFilter)
+	}
+	c.presets.filters[name] = filter
+	return nil
+}
+
+// Filter returns the filter registered under name.
+func (c *Client) Filter(name string) (EventFilter, bool) {
+	c.presets.mu.RLock()
+	defer c.presets.mu.RUnlock()
+	filter, ok := c.presets.filters[name]
+	return filter, ok
+}
+
+// ListPreset lists events matching the filter registered under name, with
+// the non-zero fields of overrides replacing the preset's, for example to
+// narrow a preset to one user or page through it. If no filter is
+// registered under name, the error is a *NotFoundError.
+func (c *Client) ListPreset(ctx context.Context, name string, overrides EventFilter) (*EventList, error) {
+	filter, ok := c.Filter(name)
+	if !ok {
+		return nil, &NotFoundError{Resource: "filter_preset", ID: name}
+	}
+	return c.List(ctx, filter.Merge(overrides))
+}
+
+// Merge returns a copy of f with the non-zero fields of overrides
+// replacing its own. Slices and maps are replaced, not combined.
+func (f EventFilter) Merge(overrides EventFilter) EventFilter {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&f.UserID, overrides.UserID)
+	set(&f.TenantID, overrides.TenantID)
+	set(&f.ActorID, overrides.ActorID)
+	set(&f.Action, overrides.Action)
+	set(&f.TargetType, overrides.TargetType)
+	set(&f.TargetID, overrides.TargetID)
+	set(&f.MetadataSearch, overrides.MetadataSearch)
+	set(&f.CorrelationID, overrides.CorrelationID)
+	set(&f.ParentEventID, overrides.ParentEventID)
+	set(&f.GroupID, overrides.GroupID)
+	set(&f.ChainID, overrides.ChainID)
+	set(&f.Cursor, overrides.Cursor)
+	set(&f.Order, overrides.Order)
+	set(&f.OrderBy, overrides.OrderBy)
+	if overrides.StartTime != nil {
+		f.StartTime = overrides.StartTime
+	}
+	if overrides.EndTime != nil {
+		f.EndTime = overrides.EndTime
+	}
+	if overrides.MetadataContains != nil {
+		f.MetadataContains = overrides.MetadataContains
+	}
+	if overrides.Tags != nil {
+		f.Tags = overrides.Tags
+	}
+	if overrides.TagsAny != nil {
+		f.TagsAny = overrides.TagsAny
+	}
+	if overrides.Offset != 0 {
+		f.Offset = overrides.Offset
+	}
+	if overrides.Limit != 0 {
+		f.Limit = overrides.Limit
+	}
+	if overrides.SchemaVersion != 0 {
+		f.SchemaVersion = overrides.SchemaVersion
+	}
+	if overrides.IncludeTotal {
+		f.IncludeTotal = true
+	}
+	return f
+}
@@ -0,0 +1,44 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClient_ListPreset(t *testing.T) {
+	t.Parallel()
+
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	if err := client.RegisterFilter("", EventFilter{}); err == nil {
+		t.Error("RegisterFilter() with an empty name succeeded")
+	}
+	if err := client.RegisterFilter("security", EventFilter{Action: "auth.*", TargetType: "session", Limit: 50}); err != nil {
+		t.Fatalf("RegisterFilter() error = %v", err)
+	}
+
+	if _, err := client.ListPreset(ctx, "security", EventFilter{UserID: "user_1", Limit: 10}); err != nil {
+		t.Fatalf("ListPreset() error = %v", err)
+	}
+	if got.Get("action") != "auth.*" || got.Get("target_type") != "session" || got.Get("user_id") != "user_1" || got.Get("limit") != "10" {
+		t.Errorf("query = %v, want the preset with the overrides applied", got)
+	}
+
+	_, err := client.ListPreset(ctx, "missing", EventFilter{})
+	var nf *NotFoundError
+	if !errors.As(err, &nf) || nf.Resource != "filter_preset" || !IsNotFound(err) {
+		t.Errorf("ListPreset() error = %v, want *NotFoundError", err)
+	}
+}
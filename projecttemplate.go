@@ -0,0 +1,118 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ProjectTemplate is the configuration given to every project created
+// from it, such as the project of each new customer environment.
+type ProjectTemplate struct {
+	// Retention is the retention policy of new projects. Optional; nil
+	// keeps the API's default.
+	Retention *RetentionPolicy
+	// APIKeys are created in each new project, in addition to its initial
+	// key. An empty Environment defaults to the project's.
+	APIKeys []CreateAPIKeyRequest
+	// Webhooks are created in each new project.
+	Webhooks []CreateWebhookRequest
+}
+
+// CreateProjectFromTemplateResponse represents the response after
+// creating a project from a template. It includes every API key and
+// webhook secret, which are only shown once.
+type CreateProjectFromTemplateResponse struct {
+	CreateProjectResponse
+	// Retention is the retention policy set from the template, or nil.
+	Retention *RetentionPolicy
+	// APIKeys are the keys created from the template, in template order.
+	APIKeys []CreateAPIKeyResponse
+	// Webhooks are the webhooks created from the template, in template
+	// order.
+	Webhooks []CreateWebhookResponse
+}
+
+// CreateProjectFromTemplate creates a project and configures it from tmpl,
+// so provisioning an environment is a single call. If configuring the
+// project fails, the project is deleted again and the error returned.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) CreateProjectFromTemplate(ctx context.Context, req CreateProjectRequest, tmpl ProjectTemplate) (*CreateProjectFromTemplateResponse, error) {
+	if tmpl.Retention != nil && tmpl.Retention.Days < 0 {
+		return nil, &ValidationError{
+			Field:   "retention_days",
+			Message: "cannot be negative",
+			Value:   strconv.Itoa(tmpl.Retention.Days),
+		}
+	}
+	keys := make([]CreateAPIKeyRequest, len(tmpl.APIKeys))
+	for i, key := range tmpl.APIKeys {
+		if key.Environment == "" {
+			key.Environment = req.Environment
+		}
+		if key.Name == "" {
+			return nil, &ValidationError{Field: "api_keys.name", Message: "is required"}
+		}
+		if err := key.Environment.validate(true); err != nil {
+			return nil, err
+		}
+		if err := c.config.checkEnvironment(key.Environment); err != nil {
+			return nil, err
+		}
+		if err := validateScopes(key.Scopes); err != nil {
+			return nil, err
+		}
+		if err := validateKeyRestrictions(key.AllowedCIDRs, key.AllowedOrigins); err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	for _, webhook := range tmpl.Webhooks {
+		if webhook.URL == "" {
+			return nil, &ValidationError{Field: "webhooks.url", Message: "is required"}
+		}
+	}
+
+	created, err := c.CreateProject(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &CreateProjectFromTemplateResponse{CreateProjectResponse: *created}
+	if err := c.configureProject(ctx, resp, keys, tmpl); err != nil {
+		// The context may be the reason configuring failed, so the
+		// rollback must not depend on it.
+		if deleteErr := c.DeleteProject(context.WithoutCancel(ctx), created.Project.ID); deleteErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed to delete project %s: %w", created.Project.ID, deleteErr))
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// configureProject applies tmpl to the project created in resp.
+func (c *Client) configureProject(ctx context.Context, resp *CreateProjectFromTemplateResponse, keys []CreateAPIKeyRequest, tmpl ProjectTemplate) error {
+	projectID := resp.Project.ID
+	if tmpl.Retention != nil {
+		retention, err := c.SetProjectRetentionPolicy(ctx, projectID, *tmpl.Retention)
+		if err != nil {
+			return err
+		}
+		resp.Retention = retention
+	}
+	for _, req := range keys {
+		key, err := c.CreateAPIKey(ctx, projectID, req)
+		if err != nil {
+			return err
+		}
+		resp.APIKeys = append(resp.APIKeys, *key)
+	}
+	for _, req := range tmpl.Webhooks {
+		webhook, err := c.CreateWebhook(ctx, projectID, req)
+		if err != nil {
+			return err
+		}
+		resp.Webhooks = append(resp.Webhooks, *webhook)
+	}
+	return nil
+}
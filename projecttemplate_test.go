@@ -0,0 +1,138 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_CreateProjectFromTemplate(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []string
+	var keyEnvironments []Environment
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/projects":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"project":{"id":"proj_1","name":"acme","environment":"live"},"api_key":"actlog_live_initial"}`))
+		case "PUT /v1/projects/proj_1/retention":
+			w.Write([]byte(`{"retention_days":30}`))
+		case "POST /v1/projects/proj_1/keys":
+			var req CreateAPIKeyRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			keyEnvironments = append(keyEnvironments, req.Environment)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"api_key_metadata":{"id":"key_2","name":"` + req.Name + `"},"api_key":"actlog_live_ingest"}`))
+		case "POST /v1/projects/proj_1/webhooks":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"webhook":{"id":"wh_1","url":"https://hooks.example"},"secret":"whsec_1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	tmpl := ProjectTemplate{
+		Retention: &RetentionPolicy{Days: 30},
+		APIKeys:   []CreateAPIKeyRequest{{Name: "ingest", Scopes: []string{"events:write"}}},
+		Webhooks:  []CreateWebhookRequest{{URL: "https://hooks.example"}},
+	}
+	resp, err := client.CreateProjectFromTemplate(context.Background(), CreateProjectRequest{Name: "acme", Environment: EnvLive}, tmpl)
+	if err != nil {
+		t.Fatalf("CreateProjectFromTemplate() error = %v", err)
+	}
+	if resp.Project.ID != "proj_1" || resp.APIKey != "actlog_live_initial" || resp.Retention.Days != 30 {
+		t.Errorf("CreateProjectFromTemplate() = %+v", resp)
+	}
+	if len(resp.APIKeys) != 1 || resp.APIKeys[0].APIKey != "actlog_live_ingest" || len(resp.Webhooks) != 1 || resp.Webhooks[0].Secret != "whsec_1" {
+		t.Errorf("CreateProjectFromTemplate() keys = %+v, webhooks = %+v", resp.APIKeys, resp.Webhooks)
+	}
+	if len(keyEnvironments) != 1 || keyEnvironments[0] != EnvLive {
+		t.Errorf("key environments = %v, want the project's", keyEnvironments)
+	}
+	want := "POST /v1/projects,PUT /v1/projects/proj_1/retention,POST /v1/projects/proj_1/keys,POST /v1/projects/proj_1/webhooks"
+	if got := strings.Join(requests, ","); got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+}
+
+func TestClient_CreateProjectFromTemplate_Rollback(t *testing.T) {
+	t.Parallel()
+
+	var deleted atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/projects":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"project":{"id":"proj_1"},"api_key":"actlog_live_initial"}`))
+		case "DELETE /v1/projects/proj_1":
+			deleted.Store(true)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"invalid_request","message":"bad webhook"}}`))
+		}
+	}))
+	defer server.Close()
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.CreateProjectFromTemplate(context.Background(), CreateProjectRequest{Name: "acme", Environment: EnvLive},
+		ProjectTemplate{Webhooks: []CreateWebhookRequest{{URL: "ftp://hooks.example"}}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("CreateProjectFromTemplate() error = %v, want the *APIError", err)
+	}
+	if !deleted.Load() {
+		t.Error("project was not deleted after the template failed")
+	}
+}
+
+func TestClient_CreateProjectFromTemplate_Validation(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewManagementClient("session_token", WithBaseURL("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	req := CreateProjectRequest{Name: "acme", Environment: EnvLive}
+	tests := []struct {
+		name  string
+		tmpl  ProjectTemplate
+		field string
+	}{
+		{"negative retention", ProjectTemplate{Retention: &RetentionPolicy{Days: -1}}, "retention_days"},
+		{"unnamed key", ProjectTemplate{APIKeys: []CreateAPIKeyRequest{{}}}, "api_keys.name"},
+		{"bad key environment", ProjectTemplate{APIKeys: []CreateAPIKeyRequest{{Name: "k", Environment: "prod"}}}, "environment"},
+		{"webhook without url", ProjectTemplate{Webhooks: []CreateWebhookRequest{{}}}, "webhooks.url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateProjectFromTemplate(context.Background(), req, tt.tmpl)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("CreateProjectFromTemplate() error = %v, want a %s *ValidationError", err, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package tryl
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseQuery parses a human-typed query into an EventFilter, for CLI tools
+// and support dashboards that accept queries as text:
+//
+//	filter, err := tryl.ParseQuery(`action:user.* user_id:u1 since:24h meta.status:active`)
+//
+// A query is a list of space-separated terms. Values containing spaces are
+// double-quoted, as in meta.plan:"team plus". The terms are:
+//
+//	action:<pattern>         Action, e.g. user.* (see ValidateActionPattern)
+//	user_id:, tenant_id:, actor_id:, target_type:, target_id:,
+//	correlation_id:, parent_event_id:, group_id:, chain_id:
+//	                         the EventFilter field of the same name
+//	tag:<tag>                Tags; repeat to require several tags
+//	any_tag:<tag>            TagsAny; repeat to match any of several tags
+//	meta.<key>:<value>       MetadataContains[key]; unquoted true, false
+//	                         and null are matched as JSON values, and
+//	                         other values as strings
+//	since:<time>, until:<time>
+//	                         StartTime and EndTime, as a duration ago
+//	                         (30m, 24h, 7d, 2w), a date (2006-01-02, the
+//	                         whole day for until), or an RFC 3339 time
+//	order:asc|desc, order_by:timestamp|action|user_id,
+//	limit:<n> (at most 100), schema_version:<n>
+//
+// Metadata values such as zip codes and IDs are often digits stored as
+// strings, so a number is matched as a JSON number only for the metadata
+// keys listed in numeric:
+//
+//	filter, err := tryl.ParseQuery(`meta.zip:02134 meta.attempts:3`, "attempts")
+//
+// Terms without a colon are joined into MetadataSearch. Each field other
+// than tag, any_tag and meta may appear once. Errors are
+// *ValidationError values naming the offending term.
+func ParseQuery(query string, numeric ...string) (EventFilter, error) {
+	return parseQuery(query, time.Now(), numeric...)
+}
+
+// queryStringFields maps query keys to the string filter fields they set.
+var queryStringFields = map[string]func(*EventFilter) *string{
+	"user_id":         func(f *EventFilter) *string { return &f.UserID },
+	"tenant_id":       func(f *EventFilter) *string { return &f.TenantID },
+	"actor_id":        func(f *EventFilter) *string { return &f.ActorID },
+	"target_type":     func(f *EventFilter) *string { return &f.TargetType },
+	"target_id":       func(f *EventFilter) *string { return &f.TargetID },
+	"correlation_id":  func(f *EventFilter) *string { return &f.CorrelationID },
+	"parent_event_id": func(f *EventFilter) *string { return &f.ParentEventID },
+	"group_id":        func(f *EventFilter) *string { return &f.GroupID },
+	"chain_id":        func(f *EventFilter) *string { return &f.ChainID },
+}
+
+// parseQuery parses query with relative times measured from now.
+func parseQuery(query string, now time.Time, numeric ...string) (EventFilter, error) {
+	terms, err := splitQuery(query)
+	if err != nil {
+		return EventFilter{}, err
+	}
+
+	var (
+		filter EventFilter
+		search []string
+		seen   = make(map[string]bool)
+	)
+	for _, t := range terms {
+		if !t.hasKey {
+			search = append(search, t.value)
+			continue
+		}
+		invalid := func(format string, args ...any) error {
+			return &ValidationError{Field: "query", Message: fmt.Sprintf(format, args...), Value: t.raw}
+		}
+		if t.value == "" {
+			return EventFilter{}, invalid("%s has no value", t.key)
+		}
+
+		if key, ok := strings.CutPrefix(t.key, "meta."); ok {
+			if key == "" {
+				return EventFilter{}, invalid("metadata key is empty")
+			}
+			if filter.MetadataContains == nil {
+				filter.MetadataContains = make(map[string]any)
+			}
+			filter.MetadataContains[key] = queryValue(t.value, t.quoted, slices.Contains(numeric, key))
+			continue
+		}
+		switch t.key {
+		case "tag":
+			filter.Tags = append(filter.Tags, t.value)
+			continue
+		case "any_tag":
+			filter.TagsAny = append(filter.TagsAny, t.value)
+			continue
+		}
+
+		if seen[t.key] {
+			return EventFilter{}, invalid("%s appears more than once", t.key)
+		}
+		seen[t.key] = true
+
+		if field, ok := queryStringFields[t.key]; ok {
+			*field(&filter) = t.value
+			continue
+		}
+		switch t.key {
+		case "action":
+			if err := ValidateActionPattern(t.value); err != nil {
+				return EventFilter{}, err
+			}
+			filter.Action = t.value
+		case "since", "until":
+			at, err := parseQueryTime(t.value, now, t.key == "until")
+			if err != nil {
+				return EventFilter{}, invalid("%s: %v", t.key, err)
+			}
+			if t.key == "since" {
+				filter.StartTime = &at
+			} else {
+				filter.EndTime = &at
+			}
+		case "order":
+			if t.value != "asc" && t.value != "desc" {
+				return EventFilter{}, invalid("order must be asc or desc")
+			}
+			filter.Order = t.value
+		case "order_by":
+			filter.OrderBy = t.value
+			if err := validateOrder(filter); err != nil {
+				return EventFilter{}, err
+			}
+		case "limit", "schema_version":
+			n, err := strconv.Atoi(t.value)
+			if err != nil || n <= 0 {
+				return EventFilter{}, invalid("%s must be a positive integer", t.key)
+			}
+			if t.key == "limit" && n > maxListLimit {
+				return EventFilter{}, invalid("limit must be at most %d", maxListLimit)
+			}
+			if t.key == "limit" {
+				filter.Limit = n
+			} else {
+				filter.SchemaVersion = n
+			}
+		default:
+			return EventFilter{}, invalid("unknown field %q", t.key)
+		}
+	}
+
+	if filter.StartTime != nil && filter.EndTime != nil && filter.EndTime.Before(*filter.StartTime) {
+		return EventFilter{}, &ValidationError{Field: "query", Message: "until is before since"}
+	}
+	filter.MetadataSearch = strings.Join(search, " ")
+	return filter, nil
+}
+
+// queryTerm is one term of a query: key:value, or a bare value.
+type queryTerm struct {
+	raw    string
+	key    string
+	value  string
+	hasKey bool
+	quoted bool
+}
+
+// splitQuery splits query into terms at unquoted whitespace.
+func splitQuery(query string) ([]queryTerm, error) {
+	var (
+		terms           []queryTerm
+		cur             queryTerm
+		buf             strings.Builder
+		raw             strings.Builder
+		inTerm, inQuote bool
+	)
+	flush := func() {
+		if !inTerm {
+			return
+		}
+		cur.value = buf.String()
+		cur.raw = raw.String()
+		terms = append(terms, cur)
+		cur = queryTerm{}
+		buf.Reset()
+		raw.Reset()
+		inTerm = false
+	}
+	for _, r := range query {
+		switch {
+		case inQuote:
+			raw.WriteRune(r)
+			if r == '"' {
+				inQuote = false
+			} else {
+				buf.WriteRune(r)
+			}
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		case r == '"':
+			inTerm, inQuote, cur.quoted = true, true, true
+			raw.WriteRune(r)
+		case r == ':' && !cur.hasKey && !cur.quoted:
+			inTerm = true
+			raw.WriteRune(r)
+			cur.key, cur.hasKey = buf.String(), true
+			buf.Reset()
+		default:
+			inTerm = true
+			raw.WriteRune(r)
+			buf.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, &ValidationError{Field: "query", Message: "unterminated quote", Value: raw.String()}
+	}
+	flush()
+	return terms, nil
+}
+
+// queryValue converts an unquoted metadata value to the JSON value it
+// spells, leaving other values as strings. Numbers are converted only for
+// numeric keys.
+func queryValue(s string, quoted, numeric bool) any {
+	if quoted {
+		return s
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if numeric {
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n
+		}
+	}
+	return s
+}
+
+// parseQueryTime parses a since or until value. A date used as an end
+// covers the whole day.
+func parseQueryTime(s string, now time.Time, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		if end {
+			return DayOf(t).End, nil
+		}
+		return t, nil
+	}
+	d, err := parseQueryDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want a duration, date, or RFC 3339 time")
+	}
+	return now.Add(-d), nil
+}
+
+// parseQueryDuration parses a duration, accepting d (days) and w (weeks)
+// as a single unit in addition to time.ParseDuration's units.
+func parseQueryDuration(s string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit != 0 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
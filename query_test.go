@@ -0,0 +1,94 @@
+package tryl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time { t := now.Add(-d); return &t }
+	at := func(s string) *time.Time { t, _ := time.Parse(time.RFC3339Nano, s); return &t }
+
+	tests := []struct {
+		name    string
+		query   string
+		numeric []string
+		want    EventFilter
+	}{
+		{
+			name:  "fields",
+			query: "action:user.* user_id:u1 since:24h meta.status:active",
+			want: EventFilter{
+				Action:           "user.*",
+				UserID:           "u1",
+				StartTime:        ago(24 * time.Hour),
+				MetadataContains: map[string]any{"status": "active"},
+			},
+		},
+		{
+			name:  "tags and paging",
+			query: "tag:billing tag:security any_tag:a any_tag:b order:asc limit:25",
+			want:  EventFilter{Tags: []string{"billing", "security"}, TagsAny: []string{"a", "b"}, Order: "asc", Limit: 25},
+		},
+		{
+			name:    "typed and quoted metadata",
+			query:   `meta.mfa:true meta.attempts:3 meta.zip:02134 meta.plan:"team plus" meta.code:"42"`,
+			numeric: []string{"attempts", "code"},
+			want:    EventFilter{MetadataContains: map[string]any{"mfa": true, "attempts": float64(3), "zip": "02134", "plan": "team plus", "code": "42"}},
+		},
+		{
+			name:  "dates and days",
+			query: "since:7d until:2026-03-09",
+			want:  EventFilter{StartTime: ago(7 * 24 * time.Hour), EndTime: at("2026-03-09T23:59:59.999999999Z")},
+		},
+		{
+			name:  "free text",
+			query: `card declined "insufficient funds" target_type:invoice`,
+			want:  EventFilter{TargetType: "invoice", MetadataSearch: "card declined insufficient funds"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseQuery(tt.query, now, tt.numeric...)
+			if err != nil {
+				t.Fatalf("parseQuery() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, query := range []string{
+		"user_id:",
+		"user_id:a user_id:b",
+		"colour:red",
+		"limit:ten",
+		"limit:500",
+		"order:sideways",
+		"since:yesterday",
+		"since:1h until:2h",
+		"meta.:x",
+		`meta.plan:"open`,
+		"action:User.Login",
+	} {
+		_, err := ParseQuery(query)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) {
+			t.Errorf("ParseQuery(%q) error = %v, want *ValidationError", query, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package tryl
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// noCacheKey is the context key set by ContextWithoutCache.
+type noCacheKey struct{}
+
+// ContextWithoutCache returns a context whose List and Count calls skip the
+// query cache, fetching fresh results and refreshing the cached entry.
+func ContextWithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// queryCache is an LRU cache of List responses keyed by the encoded query.
+type queryCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// cacheEntry is a cached List response.
+type cacheEntry struct {
+	key     string
+	list    EventList
+	expires time.Time
+}
+
+func newQueryCache(ttl time.Duration, maxEntries int, now func() time.Time) *queryCache {
+	return &queryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        now,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// get returns a copy of the cached response for key. A nil cache always
+// misses.
+func (qc *queryCache) get(ctx context.Context, key string) (*EventList, bool) {
+	if qc == nil || ctx.Value(noCacheKey{}) != nil {
+		return nil, false
+	}
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	el, ok := qc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if qc.now().After(entry.expires) {
+		qc.lru.Remove(el)
+		delete(qc.entries, key)
+		return nil, false
+	}
+	qc.lru.MoveToFront(el)
+	return copyEventList(&entry.list), true
+}
+
+// put stores a copy of eventList under key, evicting the least recently
+// used entry when full.
+func (qc *queryCache) put(key string, eventList *EventList) {
+	if qc == nil {
+		return
+	}
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	entry := &cacheEntry{key: key, list: *copyEventList(eventList), expires: qc.now().Add(qc.ttl)}
+	if el, ok := qc.entries[key]; ok {
+		el.Value = entry
+		qc.lru.MoveToFront(el)
+		return
+	}
+	qc.entries[key] = qc.lru.PushFront(entry)
+	if qc.lru.Len() > qc.maxEntries {
+		oldest := qc.lru.Back()
+		qc.lru.Remove(oldest)
+		delete(qc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// clear removes all entries.
+func (qc *queryCache) clear() {
+	if qc == nil {
+		return
+	}
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	qc.lru.Init()
+	qc.entries = make(map[string]*list.Element)
+}
+
+// copyEventList copies l, including each event's metadata and tags, so
+// callers cannot modify cached events.
+func copyEventList(l *EventList) *EventList {
+	out := *l
+	out.Events = make([]StoredEvent, len(l.Events))
+	for i, e := range l.Events {
+		if e.Metadata != nil {
+			e.Metadata = append(json.RawMessage(nil), e.Metadata...)
+		}
+		if e.Tags != nil {
+			e.Tags = append([]string(nil), e.Tags...)
+		}
+		out.Events[i] = e
+	}
+	return &out
+}
+
+// InvalidateQueryCache removes all cached List and Count results, for
+// example after logging events a dashboard must show immediately. It is a
+// no-op without WithQueryCache.
+func (c *Client) InvalidateQueryCache() {
+	c.cache.clear()
+}
+
+// Count returns the number of events matching filter. Pagination fields
+// are ignored. The count may be an estimate for large result sets; see
+// EventList.TotalAccuracy.
+func (c *Client) Count(ctx context.Context, filter EventFilter) (int, error) {
+	filter.Cursor, filter.Offset, filter.Limit = "", 0, 1
+	filter.IncludeTotal = true
+	list, err := c.List(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return list.Total, nil
+}
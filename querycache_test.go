@@ -0,0 +1,143 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WithQueryCache(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"events":[{"id":"evt_1","user_id":"user_1","action":"doc.viewed","timestamp":"2026-01-30T10:00:00Z"}],"has_more":false,"total":7,"total_accuracy":"exact"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithQueryCache(time.Minute, 2),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	first, err := client.List(ctx, EventFilter{UserID: "user_1", Limit: 10})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	first.Events[0].ID = "modified"
+	second, err := client.List(ctx, EventFilter{Limit: 10, UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests after identical List calls = %d, want 1", got)
+	}
+	if second.Events[0].ID != "evt_1" {
+		t.Errorf("cached event ID = %q, want evt_1 (cache must not share slices)", second.Events[0].ID)
+	}
+
+	if _, err := client.List(ContextWithoutCache(ctx), EventFilter{UserID: "user_1", Limit: 10}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests after bypassing the cache = %d, want 2", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if n, err := client.Count(ctx, EventFilter{UserID: "user_1"}); err != nil || n != 7 {
+			t.Errorf("Count() = %d, %v, want 7", n, err)
+		}
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests after repeated Count = %d, want 3", got)
+	}
+
+	client.InvalidateQueryCache()
+	client.Count(ctx, EventFilter{UserID: "user_1"})
+	if got := requests.Load(); got != 4 {
+		t.Errorf("requests after InvalidateQueryCache = %d, want 4", got)
+	}
+}
+
+func TestClient_WithQueryCache_InternalReadersBypass(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"events":[{"id":"evt_2","user_id":"user_1","action":"doc.viewed","parent_event_id":"evt_1","metadata":{"page":1},"tags":["docs"],"timestamp":"2026-01-30T10:00:00Z"}],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithQueryCache(time.Minute, 10),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListChildren(ctx, "evt_1"); err != nil {
+			t.Fatalf("ListChildren() error = %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests after repeated ListChildren = %d, want 2", got)
+	}
+
+	first, err := client.List(ctx, EventFilter{ParentEventID: "evt_1"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	first.Events[0].Metadata[1] = 'X'
+	first.Events[0].Tags[0] = "modified"
+	second, err := client.List(ctx, EventFilter{ParentEventID: "evt_1"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := string(second.Events[0].Metadata); got != `{"page":1}` {
+		t.Errorf("cached metadata = %s, want {\"page\":1}", got)
+	}
+	if got := second.Events[0].Tags[0]; got != "docs" {
+		t.Errorf("cached tag = %q, want docs", got)
+	}
+}
+
+func TestQueryCache_Eviction(t *testing.T) {
+	t.Parallel()
+
+	qc := newQueryCache(time.Minute, 2, time.Now)
+	ctx := context.Background()
+	qc.put("a", &EventList{})
+	qc.put("b", &EventList{})
+	qc.get(ctx, "a")
+	qc.put("c", &EventList{})
+	if _, ok := qc.get(ctx, "b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok := qc.get(ctx, "a"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+
+	clock := &stepClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	expiring := newQueryCache(time.Minute, 2, clock.Now)
+	expiring.put("a", &EventList{})
+	clock.After(59 * time.Second)
+	if _, ok := expiring.get(ctx, "a"); !ok {
+		t.Error("entry expired before its TTL")
+	}
+	clock.After(2 * time.Second)
+	if _, ok := expiring.get(ctx, "a"); ok {
+		t.Error("expired entry was returned")
+	}
+}
@@ -0,0 +1,160 @@
+package tryl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ResumeCursor is a serializable position in a time-ordered listing,
+// returned by EventList.ResumeCursor after ListSince or Resume. Unlike the
+// server's pagination cursor it survives restarts and cursor expiry: it
+// records the last event timestamp seen and the IDs seen at that
+// timestamp, so a listing can always be resumed by seeking by time without
+// returning duplicates.
+type ResumeCursor struct {
+	// Filter is the filter of the original listing.
+	Filter EventFilter `json:"filter"`
+	// After is the timestamp of the last event seen.
+	After time.Time `json:"after"`
+	// SeenIDs are the IDs of events seen with timestamp After.
+	SeenIDs []string `json:"seen_ids,omitempty"`
+	// Cursor is the server's pagination cursor for the next page, if any.
+	// It is tried first and ignored if the server rejects it.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// String encodes the cursor as an opaque URL-safe string for storage.
+func (rc ResumeCursor) String() string {
+	data, _ := json.Marshal(rc)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseResumeCursor decodes a cursor produced by ResumeCursor.String.
+func ParseResumeCursor(s string) (ResumeCursor, error) {
+	var rc ResumeCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &rc)
+	}
+	if err != nil {
+		return ResumeCursor{}, &ValidationError{Field: "cursor", Message: "is not a valid resume cursor"}
+	}
+	return rc, nil
+}
+
+// ResumeCursor returns the encoded position after this page, for passing
+// to Resume. It is empty for listings not made with ListSince or Resume.
+func (l *EventList) ResumeCursor() string {
+	if l.resume == nil {
+		return ""
+	}
+	return l.resume.String()
+}
+
+// ListSince lists events at or after since in ascending time order.
+// filter's StartTime, Order, OrderBy, Cursor, and Offset are replaced. Store the
+// returned list's ResumeCursor and pass it to Resume to continue, even
+// from another process.
+func (c *Client) ListSince(ctx context.Context, since time.Time, filter EventFilter) (*EventList, error) {
+	filter.StartTime, filter.Order, filter.OrderBy, filter.Cursor, filter.Offset = nil, "", "", "", 0
+	return c.listFrom(ctx, ResumeCursor{Filter: filter, After: since})
+}
+
+// Resume continues a listing from a cursor returned by
+// EventList.ResumeCursor. Events already returned are not repeated.
+// When there are no new events, the returned list is empty and its
+// ResumeCursor is unchanged, so pollers can call Resume repeatedly.
+func (c *Client) Resume(ctx context.Context, cursor string) (*EventList, error) {
+	rc, err := ParseResumeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return c.listFrom(ctx, rc)
+}
+
+// listFrom fetches the page after rc and advances it. Pages containing
+// only events already seen (the server seeks by whole seconds) are skipped.
+func (c *Client) listFrom(ctx context.Context, rc ResumeCursor) (*EventList, error) {
+	filter := rc.Filter
+	after := rc.After
+	filter.StartTime = &after
+	filter.Order, filter.OrderBy = "asc", ""
+
+	seen := make(map[string]bool, len(rc.SeenIDs))
+	for _, id := range rc.SeenIDs {
+		seen[id] = true
+	}
+
+	cursor, stored := rc.Cursor, true
+	for {
+		list, err := c.listPage(ctx, filter, cursor, stored)
+		if err != nil {
+			return nil, err
+		}
+
+		events := list.Events[:0]
+		for _, e := range list.Events {
+			if e.Timestamp.Before(rc.After) || e.Timestamp.Equal(rc.After) && seen[e.ID] {
+				continue
+			}
+			events = append(events, e)
+		}
+		list.Events = events
+		if len(events) == 0 && list.HasMore && list.NextCursor != "" {
+			cursor, stored = list.NextCursor, false
+			continue
+		}
+
+		next := rc.advance(events, list.NextCursor)
+		list.resume = &next
+		return list, nil
+	}
+}
+
+// advance returns the position after events, which must be in ascending
+// time order, with cursor as the server cursor for the next page.
+func (rc ResumeCursor) advance(events []StoredEvent, cursor string) ResumeCursor {
+	next := rc
+	next.SeenIDs = append([]string(nil), rc.SeenIDs...)
+	next.Cursor = cursor
+	for _, e := range events {
+		if e.Timestamp.After(next.After) {
+			next.After = e.Timestamp
+			next.SeenIDs = nil
+		}
+		if e.Timestamp.Equal(next.After) {
+			next.SeenIDs = append(next.SeenIDs, e.ID)
+		}
+	}
+	return next
+}
+
+// listPage lists one page of filter starting at cursor. A stored cursor
+// the server no longer accepts is dropped and the listing restarts from
+// the filter's start time; a cursor from this listing is not retried.
+func (c *Client) listPage(ctx context.Context, filter EventFilter, cursor string, stored bool) (*EventList, error) {
+	if cursor == "" {
+		return c.listFresh(ctx, filter)
+	}
+	paged := filter
+	paged.Cursor = cursor
+	list, err := c.listFresh(ctx, paged)
+	if err != nil && stored && cursorRejected(err) {
+		return c.listFresh(ctx, filter)
+	}
+	return list, err
+}
+
+// cursorRejected reports whether the server refused a pagination cursor,
+// for example because it expired.
+func cursorRejected(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.HTTPStatus == http.StatusBadRequest || apiErr.HTTPStatus == http.StatusGone
+}
@@ -0,0 +1,167 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// seekServer serves events in ascending time order from start_time
+// (truncated to seconds, as some servers store it), two per page. Cursors carry a
+// generation, and expire invalidates all cursors issued so far.
+type seekServer struct {
+	mu         sync.Mutex
+	events     []StoredEvent
+	generation int
+}
+
+func (s *seekServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	if q.Get("order") != "asc" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	start, _ := time.Parse(time.RFC3339, q.Get("start_time"))
+	start = start.Truncate(time.Second)
+	offset := 0
+	if c := q.Get("cursor"); c != "" {
+		var generation int
+		fmt.Sscanf(c, "%d:%d", &generation, &offset)
+		if generation != s.generation {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"invalid_cursor","message":"cursor expired"}}`))
+			return
+		}
+	}
+
+	var matching []StoredEvent
+	for _, e := range s.events {
+		if !e.Timestamp.Before(start) {
+			matching = append(matching, e)
+		}
+	}
+	page := EventList{Events: []StoredEvent{}}
+	for i := offset; i < len(matching) && i < offset+2; i++ {
+		page.Events = append(page.Events, matching[i])
+	}
+	if offset+2 < len(matching) {
+		page.HasMore = true
+		page.NextCursor = fmt.Sprintf("%d:%d", s.generation, offset+2)
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+func (s *seekServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+}
+
+func (s *seekServer) add(id string, ts time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, StoredEvent{ID: id, UserID: "user_1", Action: "doc.viewed", Timestamp: ts})
+}
+
+func ids(list *EventList) string {
+	var out []string
+	for _, e := range list.Events {
+		out = append(out, e.ID)
+	}
+	return fmt.Sprint(out)
+}
+
+func TestClient_ListSinceAndResume(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	seek := &seekServer{}
+	seek.add("evt_0", base.Add(-time.Minute))
+	seek.add("evt_1", base.Add(100*time.Millisecond))
+	seek.add("evt_2", base.Add(200*time.Millisecond))
+	seek.add("evt_3", base.Add(200*time.Millisecond))
+	server := httptest.NewServer(seek)
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	page, err := client.ListSince(ctx, base.Add(150*time.Millisecond), EventFilter{UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("ListSince() error = %v", err)
+	}
+	// The server seeks by whole seconds; evt_1 is before since and dropped.
+	if got := ids(page); got != "[evt_2]" {
+		t.Errorf("ListSince() = %s, want [evt_2]", got)
+	}
+
+	// Resume follows the server cursor and never repeats events.
+	cursor := page.ResumeCursor()
+	page, err = client.Resume(ctx, cursor)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if got := ids(page); got != "[evt_3]" {
+		t.Errorf("Resume() = %s, want [evt_3]", got)
+	}
+
+	// With nothing new, the cursor does not move.
+	cursor = page.ResumeCursor()
+	page, err = client.Resume(ctx, cursor)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if len(page.Events) != 0 {
+		t.Errorf("Resume() = %s, want no events", ids(page))
+	}
+
+	// After a restart with an expired server cursor, seeking by time picks
+	// up only new events, including one sharing the last timestamp.
+	seek.add("evt_4", base.Add(200*time.Millisecond))
+	seek.add("evt_5", base.Add(time.Second))
+	seek.expire()
+
+	rc, err := ParseResumeCursor(page.ResumeCursor())
+	if err != nil {
+		t.Fatalf("ParseResumeCursor() error = %v", err)
+	}
+	if rc.Filter.UserID != "user_1" || len(rc.SeenIDs) != 2 {
+		t.Errorf("ResumeCursor = %+v, want the filter and both IDs at the last timestamp", rc)
+	}
+	rc.Cursor = "0:2" // issued before expiry
+	page, err = client.Resume(ctx, rc.String())
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if got := ids(page); got != "[evt_4]" {
+		t.Errorf("Resume() after restart = %s, want [evt_4]", got)
+	}
+	page, err = client.Resume(ctx, page.ResumeCursor())
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if got := ids(page); got != "[evt_5]" {
+		t.Errorf("Resume() = %s, want [evt_5]", got)
+	}
+}
+
+func TestParseResumeCursor_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "!!!", "bm90IGpzb24"} {
+		if _, err := ParseResumeCursor(s); !IsClientValidationError(err) {
+			t.Errorf("ParseResumeCursor(%q) error = %v, want validation error", s, err)
+		}
+	}
+}
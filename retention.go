@@ -0,0 +1,272 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+const (
+	// retentionPolicyTTL is how long a fetched RetentionPolicy is reused
+	// for retention warnings.
+	retentionPolicyTTL = time.Hour
+	// retentionFailureTTL is how long a failed fetch is remembered before
+	// the policy is requested again.
+	retentionFailureTTL = time.Minute
+	// retentionFetchTimeout bounds a fetch, which does not end with the
+	// List call that started it.
+	retentionFetchTimeout = 10 * time.Second
+)
+
+// RetentionPolicy describes how long a project keeps its events.
+type RetentionPolicy struct {
+	// Days is the number of days events are kept. Zero means events are
+	// kept indefinitely.
+	Days int `json:"retention_days"`
+}
+
+// Cutoff returns the time before which events have been deleted, as of
+// now. It is zero if events are kept indefinitely.
+func (p *RetentionPolicy) Cutoff(now time.Time) time.Time {
+	if p.Days <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -p.Days)
+}
+
+// GetRetentionPolicy retrieves the retention policy of the API key's
+// project.
+func (c *Client) GetRetentionPolicy(ctx context.Context) (*RetentionPolicy, error) {
+	var resp *RetentionPolicy
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doGetRetentionPolicy(ctx)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doGetRetentionPolicy performs the retention policy request without retries.
+func (c *Client) doGetRetentionPolicy(ctx context.Context) (*RetentionPolicy, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   "/v1/retention",
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var policy RetentionPolicy
+	if err := json.Unmarshal(resp.Body, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// GetProjectRetentionPolicy retrieves the retention policy of a project.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) GetProjectRetentionPolicy(ctx context.Context, projectID string) (*RetentionPolicy, error) {
+	var resp *RetentionPolicy
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doGetProjectRetentionPolicy(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doGetProjectRetentionPolicy performs the project retention policy request
+// without retries.
+func (c *Client) doGetProjectRetentionPolicy(ctx context.Context, projectID string) (*RetentionPolicy, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/projects/%s/retention", projectID),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var policy RetentionPolicy
+	if err := json.Unmarshal(resp.Body, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// SetProjectRetentionPolicy replaces the retention policy of a project.
+// Requires session token authentication (use NewManagementClient).
+// Returns the policy now in effect.
+func (c *Client) SetProjectRetentionPolicy(ctx context.Context, projectID string, policy RetentionPolicy) (*RetentionPolicy, error) {
+	if policy.Days < 0 {
+		return nil, &ValidationError{
+			Field:   "retention_days",
+			Message: "cannot be negative",
+			Value:   strconv.Itoa(policy.Days),
+		}
+	}
+
+	var resp *RetentionPolicy
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doSetProjectRetentionPolicy(ctx, projectID, policy)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doSetProjectRetentionPolicy performs the set project retention policy
+// request without retries.
+func (c *Client) doSetProjectRetentionPolicy(ctx context.Context, projectID string, policy RetentionPolicy) (*RetentionPolicy, error) {
+	req := transport.Request{
+		Method: "PUT",
+		Path:   fmt.Sprintf("/v1/projects/%s/retention", projectID),
+		Body:   policy,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var updated RetentionPolicy
+	if err := json.Unmarshal(resp.Body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// retentionCache holds the retention policy for WithRetentionWarnings.
+type retentionCache struct {
+	mu       sync.Mutex
+	policy   *RetentionPolicy
+	expires  time.Time
+	fetching chan struct{} // closed when the fetch in progress ends
+	now      func() time.Time
+}
+
+func newRetentionCache() *retentionCache {
+	return &retentionCache{now: time.Now}
+}
+
+// get returns the cached policy, fetching it if it has expired. Concurrent
+// callers share one fetch, which runs without the lock and is not canceled
+// with ctx, so one caller's deadline cannot fail the fetch for the others;
+// a caller whose ctx ends first returns the previous policy. A failed
+// fetch is cached as an unknown (nil) policy for retentionFailureTTL, so
+// an unavailable endpoint costs one request per minute rather than one per
+// List.
+func (r *retentionCache) get(ctx context.Context, c *Client) *RetentionPolicy {
+	r.mu.Lock()
+	if r.now().Before(r.expires) {
+		defer r.mu.Unlock()
+		return r.policy
+	}
+	done := r.fetching
+	if done == nil {
+		done = make(chan struct{})
+		r.fetching = done
+		c.goLabeled(context.WithoutCancel(ctx), "retention", func(ctx context.Context) { r.fetch(ctx, c, done) })
+	}
+	stale := r.policy
+	r.mu.Unlock()
+
+	select {
+	case <-done:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.policy
+	case <-ctx.Done():
+		return stale
+	}
+}
+
+// fetch requests the policy and stores the result, closing done.
+func (r *retentionCache) fetch(ctx context.Context, c *Client, done chan struct{}) {
+	ctx, cancel := context.WithTimeout(ctx, retentionFetchTimeout)
+	defer cancel()
+	policy, err := c.GetRetentionPolicy(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy = policy
+	if err != nil {
+		r.expires = r.now().Add(retentionFailureTTL)
+	} else {
+		r.expires = r.now().Add(retentionPolicyTTL)
+	}
+	r.fetching = nil
+	close(done)
+}
+
+// warnRetention returns list with a WarningRetentionTruncated warning
+// added if filter starts before the retention cutoff. list itself is not
+// modified, as it may be shared with the query cache.
+func (c *Client) warnRetention(ctx context.Context, filter EventFilter, list *EventList) *EventList {
+	if c.retention == nil || filter.StartTime == nil {
+		return list
+	}
+	policy := c.retention.get(ctx, c)
+	if policy == nil {
+		return list
+	}
+	cutoff := policy.Cutoff(c.retention.now())
+	if cutoff.IsZero() || !filter.StartTime.Before(cutoff) {
+		return list
+	}
+
+	warned := *list
+	warned.Warnings = append(warned.Warnings[:len(warned.Warnings):len(warned.Warnings)], ResponseWarning{
+		Code:    WarningRetentionTruncated,
+		Message: fmt.Sprintf("events before %s have been deleted by the project's %d-day retention policy", formatTime(cutoff), policy.Days),
+		Param:   "start_time",
+	})
+	return &warned
+}
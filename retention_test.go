@@ -0,0 +1,104 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RetentionWarnings(t *testing.T) {
+	t.Parallel()
+
+	var policyRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/retention" {
+			policyRequests.Add(1)
+			w.Write([]byte(`{"retention_days":30}`))
+			return
+		}
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithRetentionWarnings())
+	ctx := context.Background()
+
+	list, err := client.List(ctx, EventFilter{}.WithWindow(90*24*time.Hour))
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Warnings) != 1 || list.Warnings[0].Code != WarningRetentionTruncated || list.Warnings[0].Param != "start_time" {
+		t.Errorf("Warnings = %+v, want a retention warning", list.Warnings)
+	}
+
+	list, err = client.List(ctx, EventFilter{}.WithWindow(7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Warnings) != 0 {
+		t.Errorf("Warnings = %+v within retention, want none", list.Warnings)
+	}
+	if n := policyRequests.Load(); n != 1 {
+		t.Errorf("fetched the retention policy %d times, want 1", n)
+	}
+}
+
+func TestRetentionPolicy_Cutoff(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	if got := (&RetentionPolicy{Days: 30}).Cutoff(now); !got.Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Cutoff() = %v, want 30 days earlier", got)
+	}
+	if got := (&RetentionPolicy{}).Cutoff(now); !got.IsZero() {
+		t.Errorf("Cutoff() = %v for indefinite retention, want zero", got)
+	}
+}
+
+func TestClient_RetentionWarnings_FetchOutlivesCaller(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var policyRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/retention" {
+			if policyRequests.Add(1) == 1 {
+				<-release
+			}
+			w.Write([]byte(`{"retention_days":30}`))
+			return
+		}
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithRetentionWarnings())
+	filter := EventFilter{}.WithWindow(90 * 24 * time.Hour)
+
+	// The caller gives up while the policy is being fetched; the fetch
+	// continues and its result serves the next call.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	list, err := client.List(ctx, filter)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Warnings) != 0 {
+		t.Errorf("Warnings = %+v before the policy is known, want none", list.Warnings)
+	}
+	close(release)
+
+	list, err = client.List(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Warnings) != 1 {
+		t.Errorf("Warnings = %+v, want a retention warning", list.Warnings)
+	}
+	if n := policyRequests.Load(); n != 1 {
+		t.Errorf("fetched the retention policy %d times, want 1", n)
+	}
+}
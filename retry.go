@@ -5,13 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"math/rand"
+	"sync/atomic"
 	"time"
 )
 
 // retryer handles retry logic with exponential backoff.
 type retryer struct {
 	config *RetryConfig
+	// perTryTimeout bounds each attempt and overallTimeout the whole
+	// operation, including backoff delays. Zero means no limit.
+	perTryTimeout  time.Duration
+	overallTimeout time.Duration
+	// clock and rand time the backoff delays and jitter them.
+	clock Clock
+	rand  Rand
+	// closed, if set, rejects operations once the client is closed, other
+	// than those of its final flush.
+	closed *atomic.Bool
+	// retries, if set, counts retried attempts.
+	retries *atomic.Uint64
 }
 
 // newRetryer creates a retryer with the given configuration.
@@ -31,51 +43,125 @@ func newRetryer(config *RetryConfig) *retryer {
 	if config.MaxAttempts == 0 {
 		config.MaxAttempts = 3
 	}
-	return &retryer{config: config}
+	return &retryer{config: config, clock: systemClock{}, rand: globalRand{}}
 }
 
-// do executes the operation with retries.
-func (r *retryer) do(ctx context.Context, op func() error) error {
+// do executes the operation with retries, passing each attempt a context
+// bounded by the per-try timeout.
+// If the operation is retried at least once, or retries are exhausted, the
+// final error is wrapped in a *RetryError carrying the attempt count and the
+// cumulative backoff delay.
+func (r *retryer) do(ctx context.Context, op func(ctx context.Context) error) error {
+	if r.closed != nil && r.closed.Load() && ctx.Value(finalFlushKey{}) == nil {
+		return ErrClientClosed
+	}
+	if r.overallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.overallTimeout)
+		defer cancel()
+	}
+
 	var lastErr error
+	var prevDelay, totalDelay time.Duration
+	start := r.clock.Now()
+
+	wrap := func(attempts int, reason string, err error) error {
+		return &RetryError{
+			Attempts:   attempts,
+			TotalDelay: totalDelay,
+			Reason:     reason,
+			Err:        err,
+		}
+	}
 
 	for attempt := 0; attempt < r.config.MaxAttempts; attempt++ {
 		if err := ctx.Err(); err != nil {
+			if attempt > 0 {
+				return wrap(attempt, "context cancelled", err)
+			}
 			return fmt.Errorf("context cancelled: %w", err)
 		}
 
-		lastErr = op()
+		lastErr = r.attempt(ctx, op)
 		if lastErr == nil {
 			return nil
 		}
 
 		if !r.isRetryable(lastErr) {
+			if attempt > 0 {
+				return wrap(attempt+1, "non-retryable error", lastErr)
+			}
 			return lastErr
 		}
 
 		if attempt < r.config.MaxAttempts-1 {
-			delay := r.calculateDelay(attempt)
+			delay := r.calculateDelay(attempt, prevDelay)
+			prevDelay = delay
+
+			if r.config.RetryBudget > 0 && r.clock.Now().Sub(start)+delay > r.config.RetryBudget {
+				return wrap(attempt+1, "retry budget exhausted", lastErr)
+			}
+
+			if r.config.OnRetry != nil {
+				r.config.OnRetry(attempt+1, lastErr, delay)
+			}
+			if r.retries != nil {
+				r.retries.Add(1)
+			}
+
 			select {
 			case <-ctx.Done():
-				return fmt.Errorf("context cancelled while waiting for retry: %w", ctx.Err())
-			case <-time.After(delay):
+				return wrap(attempt+1, "context cancelled while waiting for retry", ctx.Err())
+			case <-r.clock.After(delay):
+				totalDelay += delay
 			}
 		}
 	}
 
-	return fmt.Errorf("max retries exceeded: %w", lastErr)
+	return wrap(r.config.MaxAttempts, "max retries exceeded", lastErr)
+}
+
+// attempt runs a single attempt of op under the per-try timeout.
+func (r *retryer) attempt(ctx context.Context, op func(ctx context.Context) error) error {
+	if r.perTryTimeout <= 0 {
+		return op(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.perTryTimeout)
+	defer cancel()
+	return op(ctx)
 }
 
-// calculateDelay computes the delay for a given attempt with jitter.
-func (r *retryer) calculateDelay(attempt int) time.Duration {
+// calculateDelay computes the delay for a given attempt using the configured
+// jitter strategy. prevDelay is the delay chosen for the previous attempt
+// (zero before the first retry) and is used by decorrelated jitter.
+func (r *retryer) calculateDelay(attempt int, prevDelay time.Duration) time.Duration {
+	if r.config.Strategy == BackoffDecorrelatedJitter {
+		base := float64(r.config.BaseDelay)
+		upper := float64(prevDelay) * 3
+		if upper < base {
+			upper = base
+		}
+		delay := base + r.rand.Float64()*(upper-base)
+		if delay > float64(r.config.MaxDelay) {
+			delay = float64(r.config.MaxDelay)
+		}
+		return time.Duration(delay)
+	}
+
 	delay := float64(r.config.BaseDelay) * math.Pow(r.config.Multiplier, float64(attempt))
 
 	if delay > float64(r.config.MaxDelay) {
 		delay = float64(r.config.MaxDelay)
 	}
 
-	if r.config.JitterFactor > 0 {
-		jitter := delay * r.config.JitterFactor * (rand.Float64()*2 - 1)
-		delay += jitter
+	switch r.config.Strategy {
+	case BackoffFullJitter:
+		delay = r.rand.Float64() * delay
+	default:
+		if r.config.JitterFactor > 0 {
+			jitter := delay * r.config.JitterFactor * (r.rand.Float64()*2 - 1)
+			delay += jitter
+		}
 	}
 
 	return time.Duration(delay)
@@ -85,6 +171,17 @@ func (r *retryer) calculateDelay(attempt int) time.Duration {
 func (r *retryer) isRetryable(err error) bool {
 	var apiErr *APIError
 	if errors.As(err, &apiErr) {
+		if r.config.RetryOn != nil {
+			return r.config.RetryOn(apiErr)
+		}
+		if r.config.RetryableStatusCodes != nil {
+			for _, code := range r.config.RetryableStatusCodes {
+				if apiErr.HTTPStatus == code {
+					return true
+				}
+			}
+			return false
+		}
 		return apiErr.IsRetryable()
 	}
 
@@ -0,0 +1,210 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryer_CalculateDelay_Strategies(t *testing.T) {
+	t.Parallel()
+
+	base := 100 * time.Millisecond
+	maxDelay := 2 * time.Second
+
+	tests := []struct {
+		name     string
+		strategy BackoffStrategy
+		attempt  int
+		prev     time.Duration
+		min, max time.Duration
+	}{
+		{name: "full jitter", strategy: BackoffFullJitter, attempt: 3, min: 0, max: 800 * time.Millisecond},
+		{name: "decorrelated first retry", strategy: BackoffDecorrelatedJitter, attempt: 0, min: base, max: base},
+		{name: "decorrelated grows from previous", strategy: BackoffDecorrelatedJitter, attempt: 1, prev: 400 * time.Millisecond, min: base, max: 1200 * time.Millisecond},
+		{name: "decorrelated capped", strategy: BackoffDecorrelatedJitter, attempt: 5, prev: 10 * time.Second, min: base, max: maxDelay},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newRetryer(&RetryConfig{
+				MaxAttempts: 3,
+				BaseDelay:   base,
+				MaxDelay:    maxDelay,
+				Multiplier:  2,
+				Strategy:    tt.strategy,
+			})
+			for i := 0; i < 50; i++ {
+				d := r.calculateDelay(tt.attempt, tt.prev)
+				if d < tt.min || d > tt.max {
+					t.Fatalf("calculateDelay() = %v, want within [%v, %v]", d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryer_RetryBudget(t *testing.T) {
+	t.Parallel()
+
+	r := newRetryer(&RetryConfig{
+		MaxAttempts: 10,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    50 * time.Millisecond,
+		Multiplier:  1,
+		RetryBudget: 120 * time.Millisecond,
+	})
+
+	calls := 0
+	start := time.Now()
+	err := r.do(context.Background(), func(context.Context) error {
+		calls++
+		return &APIError{HTTPStatus: 503, Code: ErrCodeInternalError}
+	})
+
+	if err == nil {
+		t.Fatal("expected error when budget is exhausted")
+	}
+	if calls != 3 {
+		t.Errorf("got %d attempts, want 3 within budget", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("retries took %v, exceeding the budget", elapsed)
+	}
+}
+
+func TestRetryer_IsRetryable_Policy(t *testing.T) {
+	t.Parallel()
+
+	conflict := &APIError{HTTPStatus: 409, Code: "conflict"}
+	rateLimited := &APIError{HTTPStatus: 429, Code: ErrCodeRateLimited}
+	serverErr := &APIError{HTTPStatus: 503, Code: ErrCodeInternalError}
+
+	tests := []struct {
+		name   string
+		config RetryConfig
+		err    error
+		want   bool
+	}{
+		{name: "default retries 429", err: rateLimited, want: true},
+		{name: "default skips 409", err: conflict, want: false},
+		{name: "status codes opt into 409", config: RetryConfig{RetryableStatusCodes: []int{409, 503}}, err: conflict, want: true},
+		{name: "status codes opt out of 429", config: RetryConfig{RetryableStatusCodes: []int{409, 503}}, err: rateLimited, want: false},
+		{name: "empty status codes retry nothing", config: RetryConfig{RetryableStatusCodes: []int{}}, err: serverErr, want: false},
+		{
+			name: "RetryOn takes precedence",
+			config: RetryConfig{
+				RetryableStatusCodes: []int{409},
+				RetryOn:              func(e *APIError) bool { return e.Code == ErrCodeInternalError },
+			},
+			err:  conflict,
+			want: false,
+		},
+		{name: "network errors unaffected", config: RetryConfig{RetryableStatusCodes: []int{}}, err: &NetworkError{Op: "request"}, want: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := tt.config
+			r := newRetryer(&config)
+			if got := r.isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryer_Observability(t *testing.T) {
+	t.Parallel()
+
+	var hookAttempts []int
+	r := newRetryer(&RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		Multiplier:  1,
+		OnRetry: func(attempt int, err error, nextDelay time.Duration) {
+			if err == nil {
+				t.Error("OnRetry called with nil error")
+			}
+			hookAttempts = append(hookAttempts, attempt)
+		},
+	})
+
+	err := r.do(context.Background(), func(context.Context) error {
+		return &APIError{HTTPStatus: 503, Code: ErrCodeInternalError}
+	})
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("error = %v, want *RetryError", err)
+	}
+	if retryErr.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", retryErr.Attempts)
+	}
+	if retryErr.TotalDelay != 2*time.Millisecond {
+		t.Errorf("TotalDelay = %v, want 2ms", retryErr.TotalDelay)
+	}
+	if len(hookAttempts) != 2 || hookAttempts[0] != 1 || hookAttempts[1] != 2 {
+		t.Errorf("OnRetry attempts = %v, want [1 2]", hookAttempts)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != 503 {
+		t.Errorf("RetryError does not unwrap to the last APIError: %v", err)
+	}
+}
+
+func TestRetryer_NonRetryableFirstAttemptNotWrapped(t *testing.T) {
+	t.Parallel()
+
+	r := newRetryer(&RetryConfig{MaxAttempts: 3})
+	want := &ValidationError{Field: "action", Message: "is required"}
+
+	err := r.do(context.Background(), func(context.Context) error { return want })
+	if err != want {
+		t.Errorf("error = %v, want the original error unwrapped", err)
+	}
+}
+
+func TestRetryer_PerTryAndOverallTimeout(t *testing.T) {
+	t.Parallel()
+
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return &NetworkError{Op: "request", Err: ctx.Err()}
+	}
+
+	r := newRetryer(&RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	r.perTryTimeout = 10 * time.Millisecond
+	attempts := 0
+	err := r.do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return hang(ctx)
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("do() = %v after %d attempts, want success on the retry after a per-try timeout", err, attempts)
+	}
+
+	r = newRetryer(&RetryConfig{MaxAttempts: 100, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	r.perTryTimeout = 10 * time.Millisecond
+	r.overallTimeout = 50 * time.Millisecond
+	start := time.Now()
+	err = r.do(context.Background(), hang)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("do() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("do() took %v, want the overall timeout to stop retrying", elapsed)
+	}
+}
@@ -0,0 +1,168 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrorPolicy controls what a Router does when a handler fails.
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyReturn returns the handler's error from Dispatch, so a
+	// Poller delivers the event again. This is the default.
+	ErrorPolicyReturn ErrorPolicy = iota
+	// ErrorPolicySkip reports the error to Router.ErrorHandler and treats
+	// the event as handled.
+	ErrorPolicySkip
+)
+
+// HandleOption configures a handler registered with Router.Handle.
+type HandleOption func(*route)
+
+// WithHandlerConcurrency allows up to n calls of the handler to run at
+// once when Dispatch is called concurrently.
+// Default: 1
+func WithHandlerConcurrency(n int) HandleOption {
+	return func(r *route) {
+		if n > 0 {
+			r.sem = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithErrorPolicy sets what happens when the handler fails after its
+// retries.
+// Default: ErrorPolicyReturn
+func WithErrorPolicy(p ErrorPolicy) HandleOption {
+	return func(r *route) {
+		r.policy = p
+	}
+}
+
+// WithHandlerRetries calls the handler up to attempts times, waiting
+// backoff between attempts, before applying the error policy.
+// Default: 1 attempt
+func WithHandlerRetries(attempts int, backoff time.Duration) HandleOption {
+	return func(r *route) {
+		if attempts > 0 {
+			r.attempts = attempts
+		}
+		r.backoff = backoff
+	}
+}
+
+// Router dispatches events to handlers registered for action patterns.
+// Its Dispatch method can be used directly as a PollerConfig.Handler:
+//
+//	router := &tryl.Router{}
+//	router.Handle("user.*", onUser)
+//	router.Handle("invoice.paid", sendReceipt, tryl.WithHandlerRetries(3, time.Second))
+//	poller, err := client.NewPoller(tryl.PollerConfig{Name: "workflows", Handler: router.Dispatch, Store: store})
+//
+// The zero value is an empty Router ready to use.
+type Router struct {
+	// ErrorHandler, if set, is called with errors from handlers using
+	// ErrorPolicySkip.
+	ErrorHandler func(pattern string, event StoredEvent, err error)
+
+	mu     sync.RWMutex
+	routes []*route
+}
+
+// route is a handler registered for a pattern.
+type route struct {
+	pattern  string
+	fn       func(ctx context.Context, event StoredEvent) error
+	sem      chan struct{}
+	policy   ErrorPolicy
+	attempts int
+	backoff  time.Duration
+}
+
+// Handle registers fn for events whose action matches pattern (e.g.,
+// "user.*", "*.created", or an exact action). An event matching several
+// patterns is passed to each handler. Handle panics if pattern is
+// malformed, since patterns are normally fixed at startup.
+func (r *Router) Handle(pattern string, fn func(ctx context.Context, event StoredEvent) error, opts ...HandleOption) {
+	if err := ValidateActionPattern(pattern); err != nil {
+		panic(fmt.Sprintf("tryl: invalid handler pattern: %v", err))
+	}
+	rt := &route{pattern: pattern, fn: fn, sem: make(chan struct{}, 1), attempts: 1}
+	for _, opt := range opts {
+		opt(rt)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, rt)
+}
+
+// Dispatch passes event to every matching handler and waits for them.
+// Handlers for the same event run concurrently. Events with no matching
+// handler are ignored. The returned error joins the errors of handlers
+// using ErrorPolicyReturn; with that policy, a redelivered event is passed
+// to all matching handlers again, so handlers should be idempotent.
+func (r *Router) Dispatch(ctx context.Context, event StoredEvent) error {
+	r.mu.RLock()
+	var matched []*route
+	for _, rt := range r.routes {
+		if MatchesAction(rt.pattern, event.Action) {
+			matched = append(matched, rt)
+		}
+	}
+	r.mu.RUnlock()
+
+	switch len(matched) {
+	case 0:
+		return nil
+	case 1:
+		return r.call(ctx, matched[0], event)
+	}
+
+	errs := make([]error, len(matched))
+	var wg sync.WaitGroup
+	for i, rt := range matched {
+		wg.Add(1)
+		go func(i int, rt *route) {
+			defer wg.Done()
+			errs[i] = r.call(ctx, rt, event)
+		}(i, rt)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// call runs one handler with its concurrency limit, retries, and error
+// policy.
+func (r *Router) call(ctx context.Context, rt *route, event StoredEvent) error {
+	select {
+	case rt.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-rt.sem }()
+
+	var err error
+	for attempt := 1; attempt <= rt.attempts; attempt++ {
+		if attempt > 1 {
+			if err := sleepUntil(ctx, time.Now().Add(rt.backoff)); err != nil {
+				return err
+			}
+		}
+		if err = rt.fn(ctx, event); err == nil {
+			return nil
+		}
+	}
+
+	if rt.policy == ErrorPolicySkip {
+		if r.ErrorHandler != nil {
+			r.ErrorHandler(rt.pattern, event, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("handler %q: %w", rt.pattern, err)
+}
@@ -0,0 +1,130 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRouter_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) func(context.Context, StoredEvent) error {
+		return func(ctx context.Context, e StoredEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name+":"+e.ID)
+			return nil
+		}
+	}
+
+	router := &Router{}
+	router.Handle("user.*", record("user"))
+	router.Handle("*.created", record("created"))
+
+	ctx := context.Background()
+	for _, e := range []StoredEvent{
+		{ID: "evt_1", Action: "user.created"},
+		{ID: "evt_2", Action: "user.deleted"},
+		{ID: "evt_3", Action: "org.updated"},
+	} {
+		if err := router.Dispatch(ctx, e); err != nil {
+			t.Fatalf("Dispatch(%s) error = %v", e.ID, err)
+		}
+	}
+
+	want := map[string]bool{"user:evt_1": true, "created:evt_1": true, "user:evt_2": true}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for _, c := range calls {
+		if !want[c] {
+			t.Errorf("unexpected call %s", c)
+		}
+	}
+}
+
+func TestRouter_ErrorPolicies(t *testing.T) {
+	t.Parallel()
+
+	failing := errors.New("webhook down")
+	var attempts, skipped atomic.Int32
+	router := &Router{ErrorHandler: func(pattern string, e StoredEvent, err error) {
+		if pattern == "audit.*" && errors.Is(err, failing) {
+			skipped.Add(1)
+		}
+	}}
+	router.Handle("invoice.paid", func(ctx context.Context, e StoredEvent) error {
+		attempts.Add(1)
+		return failing
+	}, WithHandlerRetries(3, time.Millisecond))
+	router.Handle("audit.*", func(ctx context.Context, e StoredEvent) error {
+		return failing
+	}, WithErrorPolicy(ErrorPolicySkip))
+
+	ctx := context.Background()
+	if err := router.Dispatch(ctx, StoredEvent{ID: "evt_1", Action: "invoice.paid"}); !errors.Is(err, failing) {
+		t.Errorf("Dispatch() error = %v, want %v", err, failing)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("handler called %d times, want 3", got)
+	}
+
+	if err := router.Dispatch(ctx, StoredEvent{ID: "evt_2", Action: "audit.exported"}); err != nil {
+		t.Errorf("Dispatch() with ErrorPolicySkip error = %v", err)
+	}
+	if got := skipped.Load(); got != 1 {
+		t.Errorf("ErrorHandler called %d times, want 1", got)
+	}
+}
+
+func TestRouter_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	for _, limit := range []int{1, 3} {
+		var running, peak atomic.Int32
+		router := &Router{}
+		router.Handle("doc.*", func(ctx context.Context, e StoredEvent) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}, WithHandlerConcurrency(limit))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				router.Dispatch(context.Background(), StoredEvent{Action: "doc.viewed"})
+			}()
+		}
+		wg.Wait()
+
+		if got := peak.Load(); got < 1 || got > int32(limit) {
+			t.Errorf("concurrency %d: peak = %d", limit, got)
+		}
+	}
+}
+
+func TestRouter_Handle_InvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Handle() with a malformed pattern did not panic")
+		}
+	}()
+	(&Router{}).Handle("user.**", func(ctx context.Context, e StoredEvent) error { return nil })
+}
@@ -0,0 +1,370 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// SavedQuery is a named EventFilter stored server-side, so dashboards and
+// SDK clients share one definition of common filters.
+type SavedQuery struct {
+	// Name identifies the saved query within its project.
+	Name string
+	// Description is a human-readable summary. Optional.
+	Description string
+	// Filter is the stored filter. Pagination fields are not stored.
+	Filter EventFilter
+	// CreatedAt is when the saved query was created.
+	CreatedAt time.Time
+	// UpdatedAt is when the saved query was last updated.
+	UpdatedAt time.Time
+}
+
+// CreateSavedQueryRequest represents the request to create a saved query.
+type CreateSavedQueryRequest struct {
+	// Name identifies the saved query within its project (required).
+	Name string
+	// Description is a human-readable summary. Optional.
+	Description string
+	// Filter is the filter to store. Its Cursor, Offset, and Limit are
+	// ignored.
+	Filter EventFilter
+}
+
+// SavedQueryList represents a list of saved queries for a project.
+type SavedQueryList struct {
+	// SavedQueries is the array of saved queries.
+	SavedQueries []SavedQuery `json:"saved_queries"`
+}
+
+// PageOptions selects a page of results for queries whose filter is
+// defined elsewhere, such as ListBySavedQuery.
+type PageOptions struct {
+	// Cursor is an opaque pagination cursor returned by the previous query.
+	Cursor string
+	// Limit is the maximum number of events to return (max 100).
+	Limit int
+}
+
+// savedQueryJSON is the wire format of a saved query.
+type savedQueryJSON struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Filter      savedFilterJSON `json:"filter"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// createSavedQueryJSON is the wire format of CreateSavedQueryRequest.
+type createSavedQueryJSON struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Filter      savedFilterJSON `json:"filter"`
+}
+
+// savedFilterJSON is the wire format of an EventFilter, using the same
+// names as the list query parameters.
+type savedFilterJSON struct {
+	UserID           string         `json:"user_id,omitempty"`
+	TenantID         string         `json:"tenant_id,omitempty"`
+	ActorID          string         `json:"actor_id,omitempty"`
+	Action           string         `json:"action,omitempty"`
+	TargetType       string         `json:"target_type,omitempty"`
+	TargetID         string         `json:"target_id,omitempty"`
+	StartTime        *time.Time     `json:"start_time,omitempty"`
+	EndTime          *time.Time     `json:"end_time,omitempty"`
+	MetadataContains map[string]any `json:"metadata_contains,omitempty"`
+	MetadataSearch   string         `json:"metadata_search,omitempty"`
+	CorrelationID    string         `json:"correlation_id,omitempty"`
+	ParentEventID    string         `json:"parent_event_id,omitempty"`
+	GroupID          string         `json:"group_id,omitempty"`
+	ChainID          string         `json:"chain_id,omitempty"`
+	Tags             []string       `json:"tags,omitempty"`
+	TagsAny          []string       `json:"tags_any,omitempty"`
+	Order            string         `json:"order,omitempty"`
+	OrderBy          string         `json:"order_by,omitempty"`
+	SchemaVersion    int            `json:"schema_version,omitempty"`
+}
+
+// MarshalJSON encodes the saved query in the API's format.
+func (q SavedQuery) MarshalJSON() ([]byte, error) {
+	return json.Marshal(savedQueryJSON{
+		Name:        q.Name,
+		Description: q.Description,
+		Filter:      toSavedFilter(q.Filter),
+		CreatedAt:   q.CreatedAt,
+		UpdatedAt:   q.UpdatedAt,
+	})
+}
+
+// UnmarshalJSON decodes a saved query in the API's format.
+func (q *SavedQuery) UnmarshalJSON(data []byte) error {
+	var raw savedQueryJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*q = SavedQuery{
+		Name:        raw.Name,
+		Description: raw.Description,
+		Filter:      raw.Filter.eventFilter(),
+		CreatedAt:   raw.CreatedAt,
+		UpdatedAt:   raw.UpdatedAt,
+	}
+	return nil
+}
+
+// eventFilter converts the wire format to an EventFilter.
+func (f savedFilterJSON) eventFilter() EventFilter {
+	return EventFilter{
+		UserID:           f.UserID,
+		TenantID:         f.TenantID,
+		ActorID:          f.ActorID,
+		Action:           f.Action,
+		TargetType:       f.TargetType,
+		TargetID:         f.TargetID,
+		StartTime:        f.StartTime,
+		EndTime:          f.EndTime,
+		MetadataContains: f.MetadataContains,
+		MetadataSearch:   f.MetadataSearch,
+		CorrelationID:    f.CorrelationID,
+		ParentEventID:    f.ParentEventID,
+		GroupID:          f.GroupID,
+		ChainID:          f.ChainID,
+		Tags:             f.Tags,
+		TagsAny:          f.TagsAny,
+		Order:            f.Order,
+		OrderBy:          f.OrderBy,
+		SchemaVersion:    f.SchemaVersion,
+	}
+}
+
+// toSavedFilter converts f to the wire format, dropping pagination.
+func toSavedFilter(f EventFilter) savedFilterJSON {
+	return savedFilterJSON{
+		UserID:           f.UserID,
+		TenantID:         f.TenantID,
+		ActorID:          f.ActorID,
+		Action:           f.Action,
+		TargetType:       f.TargetType,
+		TargetID:         f.TargetID,
+		StartTime:        f.StartTime,
+		EndTime:          f.EndTime,
+		MetadataContains: f.MetadataContains,
+		MetadataSearch:   f.MetadataSearch,
+		CorrelationID:    f.CorrelationID,
+		ParentEventID:    f.ParentEventID,
+		GroupID:          f.GroupID,
+		ChainID:          f.ChainID,
+		Tags:             f.Tags,
+		TagsAny:          f.TagsAny,
+		Order:            f.Order,
+		OrderBy:          f.OrderBy,
+		SchemaVersion:    f.SchemaVersion,
+	}
+}
+
+// CreateSavedQuery stores a named filter in a project.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) CreateSavedQuery(ctx context.Context, projectID string, req CreateSavedQueryRequest) (*SavedQuery, error) {
+	if req.Name == "" {
+		return nil, &ValidationError{Field: "name", Message: "is required"}
+	}
+	if req.Filter.Action != "" {
+		if err := ValidateActionPattern(req.Filter.Action); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *SavedQuery
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doCreateSavedQuery(ctx, projectID, req)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doCreateSavedQuery performs the create saved query request without retries.
+func (c *Client) doCreateSavedQuery(ctx context.Context, projectID string, req CreateSavedQueryRequest) (*SavedQuery, error) {
+	transportReq := transport.Request{
+		Method: "POST",
+		Path:   fmt.Sprintf("/v1/projects/%s/saved-queries", projectID),
+		Body: createSavedQueryJSON{
+			Name:        req.Name,
+			Description: req.Description,
+			Filter:      toSavedFilter(req.Filter),
+		},
+	}
+
+	resp, err := c.transport.Do(ctx, transportReq)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var query SavedQuery
+	if err := json.Unmarshal(resp.Body, &query); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &query, nil
+}
+
+// ListSavedQueries retrieves the saved queries of a project.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) ListSavedQueries(ctx context.Context, projectID string) (*SavedQueryList, error) {
+	var resp *SavedQueryList
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doListSavedQueries(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doListSavedQueries performs the list saved queries request without retries.
+func (c *Client) doListSavedQueries(ctx context.Context, projectID string) (*SavedQueryList, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/projects/%s/saved-queries", projectID),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var list SavedQueryList
+	if err := json.Unmarshal(resp.Body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &list, nil
+}
+
+// DeleteSavedQuery deletes a saved query by name.
+// Requires session token authentication (use NewManagementClient).
+// If the saved query does not exist, the error is a *NotFoundError.
+func (c *Client) DeleteSavedQuery(ctx context.Context, projectID, name string) error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		return c.doDeleteSavedQuery(ctx, projectID, name)
+	})
+	if err != nil {
+		return notFound(err, "saved_query", name)
+	}
+	return nil
+}
+
+// doDeleteSavedQuery performs the delete saved query request without retries.
+func (c *Client) doDeleteSavedQuery(ctx context.Context, projectID, name string) error {
+	req := transport.Request{
+		Method: "DELETE",
+		Path:   fmt.Sprintf("/v1/projects/%s/saved-queries/%s", projectID, url.PathEscape(name)),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// ListBySavedQuery retrieves events matching the saved query name in the
+// API key's project. The filter is applied server-side, so changes to the
+// saved query take effect without redeploying.
+// If the saved query does not exist, the error is a *NotFoundError.
+func (c *Client) ListBySavedQuery(ctx context.Context, name string, page PageOptions) (*EventList, error) {
+	if name == "" {
+		return nil, &ValidationError{Field: "name", Message: "is required"}
+	}
+
+	var resp *EventList
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doListBySavedQuery(ctx, name, page)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, notFound(err, "saved_query", name)
+	}
+	return resp, nil
+}
+
+// doListBySavedQuery performs the saved query list request without retries.
+func (c *Client) doListBySavedQuery(ctx context.Context, name string, page PageOptions) (*EventList, error) {
+	query := url.Values{}
+	if page.Cursor != "" {
+		query.Set("cursor", page.Cursor)
+	}
+	if page.Limit > 0 {
+		query.Set("limit", strconv.Itoa(page.Limit))
+	}
+
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/saved-queries/%s/events", url.PathEscape(name)),
+		Query:  query,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var eventList EventList
+	if err := json.Unmarshal(resp.Body, &eventList); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	c.reportWarnings(req, eventList.Warnings)
+
+	if c.config.migrations != nil {
+		if err := c.config.migrations.UpgradeList(&eventList); err != nil {
+			return nil, err
+		}
+	}
+
+	return &eventList, nil
+}
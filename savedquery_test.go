@@ -0,0 +1,117 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_SavedQueries(t *testing.T) {
+	t.Parallel()
+
+	saved := map[string]json.RawMessage{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/projects/proj_1/saved-queries":
+			var body map[string]json.RawMessage
+			json.NewDecoder(r.Body).Decode(&body)
+			var name string
+			json.Unmarshal(body["name"], &name)
+			body["created_at"] = json.RawMessage(`"2026-03-01T12:00:00Z"`)
+			data, _ := json.Marshal(body)
+			saved[name] = data
+			w.WriteHeader(http.StatusCreated)
+			w.Write(data)
+		case r.Method == "GET" && r.URL.Path == "/v1/projects/proj_1/saved-queries":
+			var list []json.RawMessage
+			for _, q := range saved {
+				list = append(list, q)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"saved_queries": list})
+		case r.Method == "DELETE" && r.URL.Path == "/v1/projects/proj_1/saved-queries/failed logins":
+			delete(saved, "failed logins")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewManagementClient("session_token", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	created, err := client.CreateSavedQuery(ctx, "proj_1", CreateSavedQueryRequest{
+		Name:   "failed logins",
+		Filter: EventFilter{Action: "user.login_failed", MetadataContains: map[string]any{"mfa": true}, Cursor: "dropped"},
+	})
+	if err != nil {
+		t.Fatalf("CreateSavedQuery() error = %v", err)
+	}
+	if created.Filter.Action != "user.login_failed" || created.Filter.MetadataContains["mfa"] != true || created.CreatedAt.IsZero() {
+		t.Errorf("CreateSavedQuery() = %+v", created)
+	}
+	if stored := string(saved["failed logins"]); !strings.Contains(stored, `"metadata_contains":{"mfa":true}`) || strings.Contains(stored, "dropped") {
+		t.Errorf("stored query = %s, want the filter without its cursor", stored)
+	}
+
+	list, err := client.ListSavedQueries(ctx, "proj_1")
+	if err != nil {
+		t.Fatalf("ListSavedQueries() error = %v", err)
+	}
+	if len(list.SavedQueries) != 1 || list.SavedQueries[0].Name != "failed logins" {
+		t.Errorf("ListSavedQueries() = %+v", list)
+	}
+
+	if err := client.DeleteSavedQuery(ctx, "proj_1", "failed logins"); err != nil {
+		t.Fatalf("DeleteSavedQuery() error = %v", err)
+	}
+	err = client.DeleteSavedQuery(ctx, "proj_1", "missing")
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) || notFoundErr.Resource != "saved_query" {
+		t.Errorf("DeleteSavedQuery() error = %v, want *NotFoundError", err)
+	}
+
+	if _, err := client.CreateSavedQuery(ctx, "proj_1", CreateSavedQueryRequest{}); !IsClientValidationError(err) {
+		t.Errorf("CreateSavedQuery() without a name error = %v, want validation error", err)
+	}
+}
+
+func TestClient_ListBySavedQuery(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/saved-queries/failed logins/events" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"not_found","message":"saved query not found"}}`))
+			return
+		}
+		if r.URL.Query().Get("cursor") != "c1" || r.URL.Query().Get("limit") != "10" {
+			t.Errorf("query = %v, want cursor c1 and limit 10", r.URL.Query())
+		}
+		json.NewEncoder(w).Encode(EventList{Events: []StoredEvent{{ID: "evt_1", Action: "user.login_failed"}}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	list, err := client.ListBySavedQuery(ctx, "failed logins", PageOptions{Cursor: "c1", Limit: 10})
+	if err != nil {
+		t.Fatalf("ListBySavedQuery() error = %v", err)
+	}
+	if len(list.Events) != 1 || list.Events[0].ID != "evt_1" {
+		t.Errorf("ListBySavedQuery() = %+v", list)
+	}
+
+	if _, err := client.ListBySavedQuery(ctx, "missing", PageOptions{}); !IsNotFound(err) {
+		t.Errorf("ListBySavedQuery() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,201 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// ScheduledEvent is an event held by the server until its scheduled time,
+// when it is ingested like an event sent with Log.
+type ScheduledEvent struct {
+	// ID identifies the scheduled event. It differs from the ID the event
+	// gets when it is ingested.
+	ID string `json:"id"`
+	// Event is the event to ingest.
+	Event Event `json:"event"`
+	// ScheduledAt is when the event will be ingested.
+	ScheduledAt time.Time `json:"scheduled_at"`
+	// CreatedAt is when the event was scheduled.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScheduledEventList represents a page of pending scheduled events.
+type ScheduledEventList struct {
+	// ScheduledEvents is the list of pending scheduled events, soonest first.
+	ScheduledEvents []ScheduledEvent `json:"scheduled_events"`
+	// HasMore indicates if there are more scheduled events to fetch.
+	HasMore bool `json:"has_more"`
+	// NextCursor is the cursor to use for fetching the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// scheduleRequest is the internal request format for LogAt.
+type scheduleRequest struct {
+	Event       Event     `json:"event"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// LogAt schedules an event to be ingested at time at, for markers such as
+// "subscription.expired" that should appear when something happens rather
+// than when it is known. The event is validated now. Pending events can be
+// listed with ListScheduled and cancelled with CancelScheduled.
+//
+// The event is assigned an IdempotencyKey if it has none, so a retried
+// request schedules it once.
+func (c *Client) LogAt(ctx context.Context, event Event, at time.Time) (*ScheduledEvent, error) {
+	c.correlate(ctx, &event)
+	if err := c.validateEvent(&event); err != nil {
+		return nil, err
+	}
+	if at.IsZero() {
+		return nil, &ValidationError{Field: "scheduled_at", Message: "is required"}
+	}
+	if event.IdempotencyKey == "" {
+		event.IdempotencyKey = newIdempotencyKey()
+	}
+
+	var resp *ScheduledEvent
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doLogAt(ctx, event, at)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doLogAt performs the schedule request without retries.
+func (c *Client) doLogAt(ctx context.Context, event Event, at time.Time) (*ScheduledEvent, error) {
+	if c.config.dryRun {
+		return &ScheduledEvent{
+			ID:          fmt.Sprintf("sched_dryrun_%d", c.dryRunSeq.Add(1)),
+			Event:       event,
+			ScheduledAt: at.UTC(),
+			CreatedAt:   time.Now().UTC(),
+		}, nil
+	}
+
+	body, err := c.scheduleBody(scheduleRequest{Event: event, ScheduledAt: at.UTC()})
+	if err != nil {
+		return nil, err
+	}
+	req := transport.Request{
+		Method: "POST",
+		Path:   "/v1/events/scheduled",
+		Body:   body,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var scheduled ScheduledEvent
+	if err := json.Unmarshal(resp.Body, &scheduled); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &scheduled, nil
+}
+
+// ListScheduled retrieves the scheduled events that have not been ingested
+// or cancelled yet.
+func (c *Client) ListScheduled(ctx context.Context, page PageOptions) (*ScheduledEventList, error) {
+	var resp *ScheduledEventList
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doListScheduled(ctx, page)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doListScheduled performs the list scheduled events request without retries.
+func (c *Client) doListScheduled(ctx context.Context, page PageOptions) (*ScheduledEventList, error) {
+	query := url.Values{}
+	if page.Cursor != "" {
+		query.Set("cursor", page.Cursor)
+	}
+	if page.Limit > 0 {
+		query.Set("limit", strconv.Itoa(page.Limit))
+	}
+
+	req := transport.Request{
+		Method: "GET",
+		Path:   "/v1/events/scheduled",
+		Query:  query,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var list ScheduledEventList
+	if err := json.Unmarshal(resp.Body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &list, nil
+}
+
+// CancelScheduled cancels a pending scheduled event by ID.
+// If the event does not exist or was already ingested, the error is a
+// *NotFoundError.
+func (c *Client) CancelScheduled(ctx context.Context, id string) error {
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		return c.doCancelScheduled(ctx, id)
+	})
+	if err != nil {
+		return notFound(err, "scheduled_event", id)
+	}
+	return nil
+}
+
+// doCancelScheduled performs the cancel scheduled event request without retries.
+func (c *Client) doCancelScheduled(ctx context.Context, id string) error {
+	req := transport.Request{
+		Method: "DELETE",
+		Path:   fmt.Sprintf("/v1/events/scheduled/%s", url.PathEscape(id)),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
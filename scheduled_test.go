@@ -0,0 +1,79 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ScheduledEvents(t *testing.T) {
+	t.Parallel()
+
+	pending := map[string]ScheduledEvent{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/events/scheduled":
+			var req scheduleRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			scheduled := ScheduledEvent{ID: "sched_1", Event: req.Event, ScheduledAt: req.ScheduledAt, CreatedAt: time.Now().UTC()}
+			pending[scheduled.ID] = scheduled
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(scheduled)
+		case r.Method == "GET" && r.URL.Path == "/v1/events/scheduled":
+			list := ScheduledEventList{ScheduledEvents: []ScheduledEvent{}}
+			for _, s := range pending {
+				list.ScheduledEvents = append(list.ScheduledEvents, s)
+			}
+			json.NewEncoder(w).Encode(list)
+		case r.Method == "DELETE" && r.URL.Path == "/v1/events/scheduled/sched_1" && pending["sched_1"].ID != "":
+			delete(pending, "sched_1")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	at := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduled, err := client.LogAt(ctx, Event{UserID: "user_1", Action: "subscription.expired"}, at)
+	if err != nil {
+		t.Fatalf("LogAt() error = %v", err)
+	}
+	if scheduled.ID != "sched_1" || !scheduled.ScheduledAt.Equal(at) || scheduled.Event.IdempotencyKey == "" {
+		t.Errorf("LogAt() = %+v", scheduled)
+	}
+
+	list, err := client.ListScheduled(ctx, PageOptions{})
+	if err != nil {
+		t.Fatalf("ListScheduled() error = %v", err)
+	}
+	if len(list.ScheduledEvents) != 1 || list.ScheduledEvents[0].Event.Action != "subscription.expired" {
+		t.Errorf("ListScheduled() = %+v", list)
+	}
+
+	if err := client.CancelScheduled(ctx, "sched_1"); err != nil {
+		t.Fatalf("CancelScheduled() error = %v", err)
+	}
+	var notFoundErr *NotFoundError
+	if err := client.CancelScheduled(ctx, "sched_1"); !errors.As(err, &notFoundErr) || notFoundErr.Resource != "scheduled_event" {
+		t.Errorf("CancelScheduled() twice error = %v, want *NotFoundError", err)
+	}
+
+	if _, err := client.LogAt(ctx, Event{Action: "subscription.expired"}, at); !IsClientValidationError(err) {
+		t.Errorf("LogAt(invalid event) error = %v, want validation error", err)
+	}
+	if _, err := client.LogAt(ctx, Event{UserID: "user_1", Action: "subscription.expired"}, time.Time{}); !IsClientValidationError(err) {
+		t.Errorf("LogAt(zero time) error = %v, want validation error", err)
+	}
+}
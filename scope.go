@@ -0,0 +1,175 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// API key scopes.
+const (
+	// ScopeEventsRead allows a key to query events.
+	ScopeEventsRead = "events:read"
+	// ScopeEventsWrite allows a key to log events with any action. See
+	// WriteScope to restrict it to an action namespace.
+	ScopeEventsWrite = "events:write"
+)
+
+// keyScopesTTL is how long CanLog reuses the scopes of the client's key.
+const keyScopesTTL = 5 * time.Minute
+
+// WriteScope returns the scope that allows a key to log only actions
+// matching pattern, such as "events:write:billing.*" for
+// WriteScope("billing.*"). Patterns follow ValidateActionPattern.
+func WriteScope(pattern string) string {
+	return ScopeEventsWrite + ":" + pattern
+}
+
+// ScopesAllowAction reports whether a key with scopes may log action:
+// whether scopes include ScopeEventsWrite or a WriteScope whose pattern
+// matches action. A key created without scopes has every scope.
+func ScopesAllowAction(scopes []string, action string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if scope == ScopeEventsWrite {
+			return true
+		}
+		if pattern, ok := strings.CutPrefix(scope, ScopeEventsWrite+":"); ok && validation.MatchAction(pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateScopes checks the scopes of a key request. Scopes have the form
+// resource:permission; only events:write takes an action pattern.
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		resource, rest, ok := strings.Cut(scope, ":")
+		if !ok || resource == "" || rest == "" {
+			return &ValidationError{
+				Field:   "scopes",
+				Message: "must have the form resource:permission (e.g., 'events:write')",
+				Value:   scope,
+			}
+		}
+		permission, pattern, restricted := strings.Cut(rest, ":")
+		if !restricted {
+			continue
+		}
+		if resource+":"+permission != ScopeEventsWrite {
+			return &ValidationError{
+				Field:   "scopes",
+				Message: "only events:write can be restricted to an action pattern",
+				Value:   scope,
+			}
+		}
+		if err := validation.ValidateActionPattern(pattern); err != nil {
+			var fieldErr *validation.FieldError
+			if errors.As(err, &fieldErr) {
+				err = errors.New(fieldErr.Message)
+			}
+			return &ValidationError{
+				Field:   "scopes",
+				Message: fmt.Sprintf("action pattern %v", err),
+				Value:   scope,
+			}
+		}
+	}
+	return nil
+}
+
+// GetCurrentAPIKey retrieves the metadata of the client's own API key.
+// Requires API key authentication (use NewClient).
+func (c *Client) GetCurrentAPIKey(ctx context.Context) (*APIKey, error) {
+	var resp *APIKey
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doGetCurrentAPIKey(ctx)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doGetCurrentAPIKey performs the current API key request without retries.
+func (c *Client) doGetCurrentAPIKey(ctx context.Context) (*APIKey, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   "/v1/keys/current",
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var key APIKey
+	if err := json.Unmarshal(resp.Body, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &key, nil
+}
+
+// CanLog reports whether the client's API key may log action, so callers
+// can skip or reroute events the API would reject as forbidden. The key's
+// scopes are fetched with GetCurrentAPIKey and reused for five minutes.
+// Requires API key authentication (use NewClient).
+func (c *Client) CanLog(ctx context.Context, action string) (bool, error) {
+	scopes, err := c.keyScopes.get(ctx, c)
+	if err != nil {
+		return false, err
+	}
+	return ScopesAllowAction(scopes, action), nil
+}
+
+// scopeCache holds the scopes of the client's API key for CanLog.
+type scopeCache struct {
+	mu      sync.Mutex
+	scopes  []string
+	expires time.Time
+}
+
+// get returns the cached scopes, fetching them if they have expired. The
+// fetch runs without the lock and with the caller's ctx, so a slow or
+// canceled request does not hold up other callers, who may fetch too
+// until one succeeds. Unlike the retention cache, a failed fetch is not
+// cached, since CanLog reports the error.
+func (s *scopeCache) get(ctx context.Context, c *Client) ([]string, error) {
+	s.mu.Lock()
+	scopes, expires := s.scopes, s.expires
+	s.mu.Unlock()
+	if c.config.clock.Now().Before(expires) {
+		return scopes, nil
+	}
+
+	key, err := c.GetCurrentAPIKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scopes, s.expires = key.Scopes, c.config.clock.Now().Add(keyScopesTTL)
+	return key.Scopes, nil
+}
@@ -0,0 +1,142 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScopesAllowAction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		scopes []string
+		action string
+		want   bool
+	}{
+		{"no scopes", nil, "billing.paid", true},
+		{"write scope", []string{ScopeEventsWrite}, "billing.paid", true},
+		{"read only", []string{ScopeEventsRead}, "billing.paid", false},
+		{"matching namespace", []string{ScopeEventsRead, WriteScope("billing.*")}, "billing.invoice.paid", true},
+		{"other namespace", []string{WriteScope("billing.*")}, "user.login", false},
+		{"exact action", []string{WriteScope("user.login")}, "user.login", true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ScopesAllowAction(tt.scopes, tt.action); got != tt.want {
+				t.Errorf("ScopesAllowAction(%v, %q) = %v, want %v", tt.scopes, tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{ScopeEventsRead, ScopeEventsWrite, "events:write:billing.*", "events:write:*.created", "audit:read"}
+	if err := validateScopes(valid); err != nil {
+		t.Errorf("validateScopes(%v) error = %v", valid, err)
+	}
+	for _, scope := range []string{"", "events", "events:", ":write", "events:read:billing.*", "events:write:", "events:write:Billing.*", "events:write:billing.**"} {
+		err := validateScopes([]string{scope})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "scopes" {
+			t.Errorf("validateScopes(%q) error = %v, want a scopes *ValidationError", scope, err)
+		}
+	}
+}
+
+func TestCreateAPIKey_InvalidScope(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewManagementClient("session_token", WithBaseURL("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	_, err = client.CreateAPIKey(context.Background(), "proj_1", CreateAPIKeyRequest{
+		Name:        "billing",
+		Environment: EnvLive,
+		Scopes:      []string{WriteScope("Billing")},
+	})
+	if !IsClientValidationError(err) {
+		t.Errorf("CreateAPIKey() error = %v, want a client validation error", err)
+	}
+}
+
+func TestClient_CanLog(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/v1/keys/current" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"key_1","scopes":["events:read","events:write:billing.*"]}`))
+	}))
+	defer server.Close()
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for action, want := range map[string]bool{"billing.paid": true, "user.login": false} {
+		got, err := client.CanLog(ctx, action)
+		if err != nil {
+			t.Fatalf("CanLog(%q) error = %v", action, err)
+		}
+		if got != want {
+			t.Errorf("CanLog(%q) = %v, want %v", action, got, want)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("CanLog fetched the key %d times, want 1", n)
+	}
+}
+
+func TestClient_CanLog_SlowFetchDoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			<-release
+		}
+		w.Write([]byte(`{"id":"key_1","scopes":["events:write"]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	go client.CanLog(context.Background(), "user.login")
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.CanLog(context.Background(), "user.login")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("CanLog() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("CanLog() blocked behind another caller's fetch")
+	}
+}
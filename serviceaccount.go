@@ -0,0 +1,191 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// ServiceAccount is a non-human identity for management automation. Its
+// token authenticates NewServiceAccountClient and, unlike a session token,
+// does not expire when a user signs out.
+type ServiceAccount struct {
+	// ID is the unique identifier for the service account (format: sa_<ulid>).
+	ID string `json:"id"`
+	// Name is the human-readable service account name.
+	Name string `json:"name"`
+	// Description says what the service account is used for.
+	Description string `json:"description,omitempty"`
+	// ProjectIDs are the projects the service account may manage. Empty
+	// means every project of the account.
+	ProjectIDs []string `json:"project_ids,omitempty"`
+	// TokenPrefix is the first characters of the token, for identification.
+	TokenPrefix string `json:"token_prefix"`
+	// CreatedAt is when the service account was created.
+	CreatedAt time.Time `json:"created_at"`
+	// LastUsedAt is when the token was last used (nil if never used).
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// ExpiresAt is when the token expires (nil if it never expires).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// RevokedAt is when the service account was revoked (nil if active).
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateServiceAccountRequest represents the request to create a service
+// account.
+type CreateServiceAccountRequest struct {
+	// Name is a human-readable name for the service account (required).
+	Name string `json:"name"`
+	// Description says what the service account is used for (optional).
+	Description string `json:"description,omitempty"`
+	// ProjectIDs restricts the service account to these projects
+	// (optional, defaults to every project).
+	ProjectIDs []string `json:"project_ids,omitempty"`
+	// ExpiresAt sets an expiration time for the token (optional, nil = no expiration).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateServiceAccountResponse represents the response after creating a
+// service account.
+type CreateServiceAccountResponse struct {
+	// ServiceAccount is the created service account.
+	ServiceAccount ServiceAccount `json:"service_account"`
+	// Token is the service account token, for NewServiceAccountClient.
+	// IMPORTANT: This is only returned once at creation time. Store it securely.
+	Token string `json:"token"`
+}
+
+// ServiceAccountList represents a list of service accounts.
+type ServiceAccountList struct {
+	// ServiceAccounts is the array of service accounts.
+	ServiceAccounts []ServiceAccount `json:"service_accounts"`
+}
+
+// CreateServiceAccount creates a service account.
+// Requires session token authentication (use NewManagementClient).
+// Returns the service account and its token (shown only once).
+func (c *Client) CreateServiceAccount(ctx context.Context, req CreateServiceAccountRequest) (*CreateServiceAccountResponse, error) {
+	if req.Name == "" {
+		return nil, &ValidationError{Field: "name", Message: "is required"}
+	}
+
+	var resp *CreateServiceAccountResponse
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doCreateServiceAccount(ctx, req)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doCreateServiceAccount performs the create service account request
+// without retries.
+func (c *Client) doCreateServiceAccount(ctx context.Context, req CreateServiceAccountRequest) (*CreateServiceAccountResponse, error) {
+	transportReq := transport.Request{
+		Method: "POST",
+		Path:   "/v1/service-accounts",
+		Body:   req,
+	}
+
+	resp, err := c.transport.Do(ctx, transportReq)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var created CreateServiceAccountResponse
+	if err := json.Unmarshal(resp.Body, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ListServiceAccounts retrieves the service accounts of the account,
+// including revoked ones.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) ListServiceAccounts(ctx context.Context) (*ServiceAccountList, error) {
+	var resp *ServiceAccountList
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doListServiceAccounts(ctx)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doListServiceAccounts performs the list service accounts request without
+// retries.
+func (c *Client) doListServiceAccounts(ctx context.Context) (*ServiceAccountList, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   "/v1/service-accounts",
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var list ServiceAccountList
+	if err := json.Unmarshal(resp.Body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &list, nil
+}
+
+// RevokeServiceAccount revokes a service account by ID. Its token stops
+// working immediately.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) RevokeServiceAccount(ctx context.Context, serviceAccountID string) error {
+	return c.retryer.do(ctx, func(ctx context.Context) error {
+		return c.doRevokeServiceAccount(ctx, serviceAccountID)
+	})
+}
+
+// doRevokeServiceAccount performs the revoke service account request
+// without retries.
+func (c *Client) doRevokeServiceAccount(ctx context.Context, serviceAccountID string) error {
+	req := transport.Request{
+		Method: "POST",
+		Path:   fmt.Sprintf("/v1/service-accounts/%s/revoke", serviceAccountID),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
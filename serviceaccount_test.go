@@ -0,0 +1,100 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testServiceAccountToken = "actlog_sa_1234567890abcdef1234567890abcdef"
+
+func TestServiceAccounts(t *testing.T) {
+	t.Parallel()
+
+	var revoked string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/service-accounts":
+			var req CreateServiceAccountRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(CreateServiceAccountResponse{
+				ServiceAccount: ServiceAccount{ID: "sa_1", Name: req.Name, ProjectIDs: req.ProjectIDs, TokenPrefix: "actlog_sa_12"},
+				Token:          testServiceAccountToken,
+			})
+		case "GET /v1/service-accounts":
+			w.Write([]byte(`{"service_accounts":[{"id":"sa_1","name":"ci","token_prefix":"actlog_sa_12"}]}`))
+		case "POST /v1/service-accounts/sa_1/revoke":
+			revoked = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	mgmt, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	created, err := mgmt.CreateServiceAccount(ctx, CreateServiceAccountRequest{Name: "ci", ProjectIDs: []string{"proj_1"}})
+	if err != nil {
+		t.Fatalf("CreateServiceAccount() error = %v", err)
+	}
+	if created.Token != testServiceAccountToken || created.ServiceAccount.ID != "sa_1" || len(created.ServiceAccount.ProjectIDs) != 1 {
+		t.Errorf("CreateServiceAccount() = %+v", created)
+	}
+
+	list, err := mgmt.ListServiceAccounts(ctx)
+	if err != nil {
+		t.Fatalf("ListServiceAccounts() error = %v", err)
+	}
+	if len(list.ServiceAccounts) != 1 || list.ServiceAccounts[0].Name != "ci" {
+		t.Errorf("ListServiceAccounts() = %+v", list)
+	}
+
+	if err := mgmt.RevokeServiceAccount(ctx, "sa_1"); err != nil {
+		t.Fatalf("RevokeServiceAccount() error = %v", err)
+	}
+	if revoked == "" {
+		t.Error("RevokeServiceAccount() did not call the revoke endpoint")
+	}
+
+	_, err = mgmt.CreateServiceAccount(ctx, CreateServiceAccountRequest{})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "name" {
+		t.Errorf("CreateServiceAccount() without name error = %v", err)
+	}
+}
+
+func TestNewServiceAccountClient(t *testing.T) {
+	t.Parallel()
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"projects":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewServiceAccountClient(testServiceAccountToken, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewServiceAccountClient() error = %v", err)
+	}
+	if _, err := client.ListProjects(context.Background()); err != nil {
+		t.Fatalf("ListProjects() error = %v", err)
+	}
+	if auth != "Bearer "+testServiceAccountToken {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	for _, token := range []string{"", "session_token", "actlog_test_1234567890abcdef1234567890abcdef", "actlog_sa_short"} {
+		if _, err := NewServiceAccountClient(token); err == nil {
+			t.Errorf("NewServiceAccountClient(%q) error = nil", token)
+		}
+	}
+}
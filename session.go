@@ -0,0 +1,68 @@
+package tryl
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultSessionGap is the idle gap used by Sessions when none is given.
+const DefaultSessionGap = 30 * time.Minute
+
+// Session is a run of one user's events with no idle gap longer than the
+// gap passed to Sessions.
+type Session struct {
+	// UserID is the user the events belong to.
+	UserID string
+	// Start and End are the timestamps of the first and last events.
+	Start, End time.Time
+	// FirstAction and LastAction are the actions of the first and last
+	// events.
+	FirstAction, LastAction string
+	// Events is the number of events in the session.
+	Events int
+	// ActionCounts is the number of events per action.
+	ActionCounts map[string]int
+}
+
+// Duration returns the time between the session's first and last events.
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Sessions groups events into sessions per user: a new session starts
+// when more than gap has passed since the user's previous event. A gap
+// of zero or less uses DefaultSessionGap. events need not be sorted.
+// Sessions are returned in order of start time.
+func Sessions(events []StoredEvent, gap time.Duration) []Session {
+	if gap <= 0 {
+		gap = DefaultSessionGap
+	}
+
+	sorted := make([]StoredEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var sessions []Session
+	open := map[string]int{} // user ID to index in sessions
+	for _, e := range sorted {
+		i, ok := open[e.UserID]
+		if !ok || e.Timestamp.Sub(sessions[i].End) > gap {
+			sessions = append(sessions, Session{
+				UserID:       e.UserID,
+				Start:        e.Timestamp,
+				FirstAction:  e.Action,
+				ActionCounts: map[string]int{},
+			})
+			i = len(sessions) - 1
+			open[e.UserID] = i
+		}
+		s := &sessions[i]
+		s.End = e.Timestamp
+		s.LastAction = e.Action
+		s.Events++
+		s.ActionCounts[e.Action]++
+	}
+	return sessions
+}
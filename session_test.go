@@ -0,0 +1,44 @@
+package tryl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(min int, user, action string) StoredEvent {
+		return StoredEvent{UserID: user, Action: action, Timestamp: base.Add(time.Duration(min) * time.Minute)}
+	}
+	sessions := Sessions([]StoredEvent{
+		at(50, "user_1", "doc.exported"),
+		at(0, "user_1", "user.login"),
+		at(10, "user_1", "doc.viewed"),
+		at(15, "user_2", "user.login"),
+		at(20, "user_1", "doc.viewed"),
+		at(120, "user_1", "user.login"),
+	}, 0)
+
+	if len(sessions) != 3 {
+		t.Fatalf("Sessions() = %+v, want 3 sessions", sessions)
+	}
+	first := sessions[0]
+	if first.UserID != "user_1" || first.Events != 4 || first.Duration() != 50*time.Minute {
+		t.Errorf("first session = %+v", first)
+	}
+	if first.FirstAction != "user.login" || first.LastAction != "doc.exported" || first.ActionCounts["doc.viewed"] != 2 {
+		t.Errorf("first session actions = %+v", first)
+	}
+	if sessions[1].UserID != "user_2" || sessions[1].Duration() != 0 {
+		t.Errorf("second session = %+v", sessions[1])
+	}
+	if sessions[2].UserID != "user_1" || !sessions[2].Start.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("third session = %+v", sessions[2])
+	}
+
+	if got := Sessions(nil, time.Minute); got != nil {
+		t.Errorf("Sessions(nil) = %+v, want nil", got)
+	}
+}
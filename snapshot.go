@@ -0,0 +1,300 @@
+package tryl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotFormat is the encoding WriteSnapshot writes.
+type SnapshotFormat string
+
+const (
+	// SnapshotJSON writes the snapshot as indented JSON.
+	SnapshotJSON SnapshotFormat = "json"
+	// SnapshotYAML writes the snapshot as YAML.
+	SnapshotYAML SnapshotFormat = "yaml"
+)
+
+// AccountSnapshot is the configuration of every project an account can
+// see, captured by Snapshot for backups and disaster-recovery runbooks.
+// It holds API key metadata only; key secrets cannot be read back from
+// the API and are never part of a snapshot.
+type AccountSnapshot struct {
+	// TakenAt is when the snapshot was started.
+	TakenAt time.Time `json:"taken_at"`
+	// SDKVersion is the version of the SDK that took the snapshot.
+	SDKVersion string `json:"sdk_version"`
+	// Projects are the account's projects, in the order the API lists them.
+	Projects []ProjectSnapshot `json:"projects"`
+}
+
+// ProjectSnapshot is the configuration of one project.
+type ProjectSnapshot struct {
+	Project
+	// Retention is the project's retention policy. Apply leaves the
+	// policy unchanged if it is nil.
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+	// APIKeys is the metadata of the project's API keys, including
+	// revoked ones. Apply does not change API keys.
+	APIKeys []APIKey `json:"api_keys"`
+	// Webhooks are the project's webhooks. Apply leaves the webhooks
+	// unchanged if it is nil.
+	Webhooks []Webhook `json:"webhooks"`
+	// SavedQueries are the project's saved queries.
+	SavedQueries []SavedQuery `json:"saved_queries"`
+}
+
+// Snapshot captures the projects of the account with their API key
+// metadata, webhooks, saved queries, and retention policies. It makes
+// several requests per project and fails on the first error, so a
+// snapshot is never partial.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) Snapshot(ctx context.Context) (*AccountSnapshot, error) {
+	snapshot := &AccountSnapshot{
+		TakenAt:    c.config.clock.Now().UTC(),
+		SDKVersion: Version,
+	}
+
+	projects, err := c.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Projects = make([]ProjectSnapshot, 0, len(projects.Projects))
+	for _, project := range projects.Projects {
+		p, err := c.snapshotProject(ctx, project)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot of project %s: %w", project.ID, err)
+		}
+		snapshot.Projects = append(snapshot.Projects, *p)
+	}
+	return snapshot, nil
+}
+
+// snapshotProject fetches the configuration of project.
+func (c *Client) snapshotProject(ctx context.Context, project Project) (*ProjectSnapshot, error) {
+	p := &ProjectSnapshot{Project: project}
+
+	retention, err := c.GetProjectRetentionPolicy(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.Retention = retention
+
+	keys, err := c.ListAPIKeys(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.APIKeys = nonNil(keys.APIKeys)
+
+	webhooks, err := c.ListWebhooks(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.Webhooks = nonNil(webhooks.Webhooks)
+
+	queries, err := c.ListSavedQueries(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.SavedQueries = nonNil(queries.SavedQueries)
+
+	return p, nil
+}
+
+// nonNil returns s, or an empty slice if s is nil, so empty lists encode
+// as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// WriteSnapshot writes snapshot to w in format.
+func WriteSnapshot(w io.Writer, snapshot *AccountSnapshot, format SnapshotFormat) error {
+	switch format {
+	case SnapshotJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snapshot)
+	case SnapshotYAML:
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		return writeYAML(w, data)
+	default:
+		return &ValidationError{
+			Field:   "format",
+			Message: "must be json or yaml",
+			Value:   string(format),
+		}
+	}
+}
+
+// yamlField is a key of a JSON object, kept in document order.
+type yamlField struct {
+	key   string
+	value any
+}
+
+// writeYAML re-encodes the JSON document data as block-style YAML,
+// keeping the order of object keys. Strings are always double-quoted,
+// which makes every JSON string a valid YAML scalar.
+func writeYAML(w io.Writer, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	doc, err := decodeOrdered(dec)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	switch doc.(type) {
+	case []yamlField, []any:
+		writeYAMLBlock(bw, doc, 0)
+	default:
+		bw.WriteString(yamlScalar(doc) + "\n")
+	}
+	return bw.Flush()
+}
+
+// decodeOrdered decodes the next JSON value, representing objects as
+// []yamlField and arrays as []any.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		fields := []yamlField{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, yamlField{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return fields, err
+	default:
+		items := []any{}
+		for dec.More() {
+			item, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err = dec.Token()
+		return items, err
+	}
+}
+
+// writeYAMLBlock writes a non-empty object or array indented by indent
+// spaces.
+func writeYAMLBlock(w *bufio.Writer, v any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch v := v.(type) {
+	case []yamlField:
+		for _, f := range v {
+			w.WriteString(pad + yamlKey(f.key) + ":")
+			writeYAMLValue(w, f.value, indent, false)
+		}
+	case []any:
+		for _, item := range v {
+			w.WriteString(pad + "-")
+			writeYAMLValue(w, item, indent, true)
+		}
+	}
+}
+
+// writeYAMLValue writes the value of a key or sequence item at indent.
+// The first line of a nested sequence item is written inline after its
+// dash.
+func writeYAMLValue(w *bufio.Writer, v any, indent int, item bool) {
+	if yamlEmpty(v) {
+		w.WriteString(" " + yamlScalar(v) + "\n")
+		return
+	}
+	if !item {
+		w.WriteString("\n")
+		writeYAMLBlock(w, v, indent+2)
+		return
+	}
+	var nested bytes.Buffer
+	nw := bufio.NewWriter(&nested)
+	writeYAMLBlock(nw, v, indent+2)
+	nw.Flush()
+	w.WriteString(" ")
+	w.Write(nested.Bytes()[indent+2:])
+}
+
+// yamlEmpty reports whether v is written on the line of its key: a
+// scalar or an empty object or array.
+func yamlEmpty(v any) bool {
+	switch v := v.(type) {
+	case []yamlField:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return true
+	}
+}
+
+// yamlScalar formats a scalar or empty container in flow style.
+func yamlScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		quoted, _ := json.Marshal(v)
+		return string(quoted)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []yamlField:
+		return "{}"
+	default:
+		return "[]"
+	}
+}
+
+// yamlKey formats an object key, quoting it unless it is a plain
+// identifier that YAML would not read as a number, boolean, or null.
+func yamlKey(key string) string {
+	if key == "" || yamlReserved[strings.ToLower(key)] || key[0] == '-' || key[0] >= '0' && key[0] <= '9' {
+		return yamlScalar(key)
+	}
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return yamlScalar(key)
+		}
+	}
+	return key
+}
+
+// yamlReserved are the plain scalars YAML 1.1 reads as booleans or null.
+var yamlReserved = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true,
+	"true": true, "false": true, "null": true,
+}
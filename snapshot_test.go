@@ -0,0 +1,134 @@
+package tryl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newSnapshotServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"projects":[{"id":"proj_1","name":"Billing","environment":"live","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-02T00:00:00Z"}]}`))
+	})
+	mux.HandleFunc("/v1/projects/proj_1/retention", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"retention_days":90}`))
+	})
+	mux.HandleFunc("/v1/projects/proj_1/keys", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"api_keys":[{"id":"key_1","project_id":"proj_1","name":"backend","environment":"live","prefix":"actlog_live_ab","scopes":["events:write"],"created_at":"2026-01-01T00:00:00Z"}]}`))
+	})
+	mux.HandleFunc("/v1/projects/proj_1/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"webhooks":[]}`))
+	})
+	mux.HandleFunc("/v1/projects/proj_1/saved-queries", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"saved_queries":[{"name":"logins","filter":{"action":"user.login"},"created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z"}]}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	server := newSnapshotServer(t)
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	snapshot, err := client.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if len(snapshot.Projects) != 1 {
+		t.Fatalf("Snapshot() projects = %d, want 1", len(snapshot.Projects))
+	}
+	p := snapshot.Projects[0]
+	if p.ID != "proj_1" || p.Retention == nil || p.Retention.Days != 90 || len(p.APIKeys) != 1 || len(p.SavedQueries) != 1 {
+		t.Errorf("Snapshot() project = %+v", p)
+	}
+	if p.Webhooks == nil {
+		t.Error("Snapshot() Webhooks is nil, want empty")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, snapshot, SnapshotJSON); err != nil {
+		t.Fatalf("WriteSnapshot(json) error = %v", err)
+	}
+	var decoded AccountSnapshot
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON snapshot does not decode: %v", err)
+	}
+	if decoded.Projects[0].APIKeys[0].Prefix != "actlog_live_ab" {
+		t.Errorf("decoded key = %+v", decoded.Projects[0].APIKeys[0])
+	}
+
+	buf.Reset()
+	if err := WriteSnapshot(&buf, snapshot, SnapshotYAML); err != nil {
+		t.Fatalf("WriteSnapshot(yaml) error = %v", err)
+	}
+	for _, want := range []string{
+		"projects:\n  - id: \"proj_1\"\n    name: \"Billing\"\n",
+		"    retention:\n      retention_days: 90\n",
+		"    api_keys:\n      - id: \"key_1\"\n",
+		"        scopes:\n          - \"events:write\"\n",
+		"    webhooks: []\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("YAML snapshot missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestClient_Snapshot_Error(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/projects" {
+			w.Write([]byte(`{"projects":[{"id":"proj_1"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+	}))
+	defer server.Close()
+	client, err := NewManagementClient("session_token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Snapshot(context.Background())
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "proj_1") {
+		t.Errorf("Snapshot() error = %v, want ErrNotFound naming proj_1", err)
+	}
+}
+
+func TestWriteSnapshot_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	err := WriteSnapshot(&bytes.Buffer{}, &AccountSnapshot{}, "xml")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "format" {
+		t.Errorf("WriteSnapshot() error = %v, want a format *ValidationError", err)
+	}
+}
+
+func TestWriteYAML_Keys(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, []byte(`{"on":true,"9lives":null,"a b":{},"list":[[1,2],{"k":"v"}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	want := "\"on\": true\n\"9lives\": null\n\"a b\": {}\nlist:\n  - - 1\n    - 2\n  - k: \"v\"\n"
+	if buf.String() != want {
+		t.Errorf("writeYAML() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
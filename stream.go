@@ -0,0 +1,273 @@
+package tryl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// ErrStreamClosed is returned by EventWriter.Write after the stream is closed.
+var ErrStreamClosed = errors.New("tryl: event stream is closed")
+
+// StreamAck is a periodic acknowledgement sent by the server on an event stream.
+type StreamAck struct {
+	// Acked is the total number of events the server has committed so far.
+	Acked int64 `json:"acked"`
+	// Errors lists events rejected since the previous acknowledgement.
+	Errors []StreamError `json:"errors,omitempty"`
+}
+
+// StreamError describes an event the server rejected on a stream.
+type StreamError struct {
+	// Index is the zero-based position of the event in the stream.
+	Index int64 `json:"index"`
+	// Code is the error code from the API.
+	Code string `json:"code"`
+	// Message is the human-readable error message.
+	Message string `json:"message"`
+}
+
+// EventWriter writes events to a single long-lived NDJSON request.
+// It is safe for concurrent use. Create one with Client.LogStream.
+type EventWriter struct {
+	client *Client
+	pw     *io.PipeWriter
+	enc    *json.Encoder
+	acks   chan StreamAck
+	doneCh chan struct{}
+	dryRun bool
+
+	// writeMu serializes writes to the pipe; mu guards the fields below.
+	// They are separate so a write blocked on the network never holds mu.
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	closed  bool
+	sent    int64
+	acked   int64
+	rejects []StreamError
+	err     error
+}
+
+// LogStream opens a streaming ingestion request to /v1/events/stream.
+// Events written to the returned EventWriter are validated and sent
+// immediately as newline-delimited JSON over one chunked POST, avoiding
+// per-request overhead for sustained high-rate producers. The server
+// acknowledges progress periodically; see EventWriter.Acks.
+//
+// Streams are not retried. Call Close to finish the request and wait for
+// the final acknowledgement. Cancelling ctx aborts the stream. LogStream
+// is not supported with a Transport set by WithTransport.
+func (c *Client) LogStream(ctx context.Context) (*EventWriter, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+	if c.config.transport != nil && !c.config.dryRun {
+		return nil, transport.ErrOpenUnsupported
+	}
+
+	pr, pw := io.Pipe()
+	w := &EventWriter{
+		client: c,
+		pw:     pw,
+		enc:    json.NewEncoder(pw),
+		acks:   make(chan StreamAck, 16),
+		doneCh: make(chan struct{}),
+	}
+
+	if c.config.dryRun {
+		w.dryRun = true
+		c.goLabeled(ctx, "stream", func(context.Context) {
+			defer close(w.doneCh)
+			defer close(w.acks)
+			io.Copy(io.Discard, pr)
+		})
+		return w, nil
+	}
+
+	c.goLabeled(ctx, "stream", func(ctx context.Context) { w.run(ctx, pr) })
+	return w, nil
+}
+
+// run performs the streaming request and reads acknowledgements until the
+// server finishes the response.
+func (w *EventWriter) run(ctx context.Context, pr *io.PipeReader) {
+	defer close(w.doneCh)
+	defer close(w.acks)
+
+	req := transport.Request{
+		Method: "POST",
+		Path:   "/v1/events/stream",
+		Headers: map[string]string{
+			"Content-Type": "application/x-ndjson",
+			"Accept":       "application/x-ndjson",
+		},
+	}
+
+	resp, err := w.client.transport.Open(ctx, req, pr)
+	if err != nil {
+		w.fail(&NetworkError{Op: "stream", Err: err}, pr)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		w.fail(w.client.parseError(&transport.Response{
+			StatusCode: resp.StatusCode,
+			Body:       body,
+			Headers:    resp.Header,
+			RequestID:  resp.Header.Get("X-Request-ID"),
+		}), pr)
+		return
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ack StreamAck
+		if err := json.Unmarshal(scanner.Bytes(), &ack); err != nil {
+			w.fail(fmt.Errorf("failed to parse stream acknowledgement: %w", err), pr)
+			return
+		}
+
+		w.mu.Lock()
+		w.acked = ack.Acked
+		w.rejects = append(w.rejects, ack.Errors...)
+		w.mu.Unlock()
+
+		// Deliver without blocking; slow consumers can read Acked and Rejected instead.
+		select {
+		case w.acks <- ack:
+		default:
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		w.fail(&NetworkError{Op: "stream", Err: err}, pr)
+		return
+	}
+
+	// The server ended the response; unblock any pending writes.
+	pr.CloseWithError(ErrStreamClosed)
+}
+
+// fail aborts pending writes and records the first stream error.
+func (w *EventWriter) fail(err error, pr *io.PipeReader) {
+	pr.CloseWithError(err)
+
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+}
+
+// Write validates the event and sends it on the stream.
+// It returns a *ValidationError for invalid events, ErrStreamClosed after
+// Close, or the stream's error if the request has failed.
+func (w *EventWriter) Write(event Event) error {
+	if err := w.client.validateEvent(&event); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	err, closed := w.err, w.closed
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrStreamClosed
+	}
+
+	body, err := w.client.eventBody(event)
+	if err != nil {
+		return err
+	}
+	w.writeMu.Lock()
+	err = w.enc.Encode(body)
+	w.writeMu.Unlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		if w.err != nil {
+			return w.err
+		}
+		if errors.Is(err, io.ErrClosedPipe) {
+			return ErrStreamClosed
+		}
+		return &NetworkError{Op: "stream", Err: err}
+	}
+	w.sent++
+	if w.dryRun {
+		w.acked = w.sent
+	}
+	return nil
+}
+
+// Acks returns a channel of acknowledgements from the server.
+// Acknowledgements are dropped if the channel is not drained; Acked and
+// Rejected always reflect the latest state. The channel is closed when the
+// stream ends.
+func (w *EventWriter) Acks() <-chan StreamAck {
+	return w.acks
+}
+
+// Sent returns the number of events written to the stream.
+func (w *EventWriter) Sent() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.sent
+}
+
+// Acked returns the number of events the server has acknowledged.
+func (w *EventWriter) Acked() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.acked
+}
+
+// Rejected returns all events the server has rejected so far.
+func (w *EventWriter) Rejected() []StreamError {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]StreamError(nil), w.rejects...)
+}
+
+// Close ends the request body and waits for the server to finish
+// acknowledging, or for ctx to be done. It returns the stream's error, if any.
+func (w *EventWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	alreadyClosed := w.closed
+	w.closed = true
+	w.mu.Unlock()
+
+	if !alreadyClosed {
+		// Wait for any in-flight write before ending the body.
+		w.writeMu.Lock()
+		w.pw.Close()
+		w.writeMu.Unlock()
+	}
+
+	select {
+	case <-w.doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
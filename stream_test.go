@@ -0,0 +1,97 @@
+package tryl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_LogStream(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/events/stream" {
+			t.Errorf("got path %s, want /v1/events/stream", r.URL.Path)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+		}
+
+		rc := http.NewResponseController(w)
+		rc.EnableFullDuplex()
+		w.WriteHeader(http.StatusOK)
+		rc.Flush()
+
+		var acked int64
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var e Event
+			json.Unmarshal(scanner.Bytes(), &e)
+			acked++
+			fmt.Fprintf(w, `{"acked":%d}`+"\n", acked)
+			rc.Flush()
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	w, err := client.LogStream(context.Background())
+	if err != nil {
+		t.Fatalf("LogStream() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := w.Write(Event{UserID: fmt.Sprintf("user_%d", i), Action: "user.created"}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		// Each event is acknowledged individually before the stream ends.
+		if ack := <-w.Acks(); ack.Acked != int64(i+1) {
+			t.Errorf("ack %d: Acked = %d, want %d", i, ack.Acked, i+1)
+		}
+	}
+
+	if err := w.Write(Event{UserID: "user_x", Action: "Bad Action"}); !IsClientValidationError(err) {
+		t.Errorf("Write() invalid event error = %v, want ValidationError", err)
+	}
+
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if w.Sent() != 3 || w.Acked() != 3 {
+		t.Errorf("Sent() = %d, Acked() = %d, want 3 and 3", w.Sent(), w.Acked())
+	}
+	if err := w.Write(Event{UserID: "user_1", Action: "user.created"}); err != ErrStreamClosed {
+		t.Errorf("Write() after Close error = %v, want ErrStreamClosed", err)
+	}
+}
+
+func TestClient_LogStream_ServerError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":"unauthorized","message":"invalid key"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	w, err := client.LogStream(context.Background())
+	if err != nil {
+		t.Fatalf("LogStream() error = %v", err)
+	}
+	if err := w.Close(context.Background()); !IsUnauthorized(err) {
+		t.Errorf("Close() error = %v, want unauthorized", err)
+	}
+}
@@ -0,0 +1,101 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// UserSummary is an overview of a user's activity, as returned by
+// UserSummary.
+type UserSummary struct {
+	// UserID is the summarized user.
+	UserID string `json:"user_id"`
+	// Total is the number of events logged for the user in the window.
+	Total int `json:"total"`
+	// Actions is the number of events for each action.
+	Actions map[string]int `json:"actions"`
+	// FirstSeen and LastSeen are the timestamps of the user's first and
+	// last events in the window. Both are zero if Total is zero.
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// ActiveDays is the number of UTC calendar days with at least one
+	// event.
+	ActiveDays int `json:"active_days"`
+	// Warnings are non-fatal conditions affecting the summary.
+	Warnings []ResponseWarning `json:"warnings,omitempty"`
+}
+
+// UserSummary retrieves counts by action, first and last seen times, and
+// active days for userID within window, for profile pages that would
+// otherwise page through the user's events. A zero side of window is
+// open. A user with no events in the window has a zero Total rather than
+// an error.
+//
+//	summary, err := client.UserSummary(ctx, userID, tryl.Last(30*24*time.Hour))
+func (c *Client) UserSummary(ctx context.Context, userID string, window TimeRange) (*UserSummary, error) {
+	if userID == "" {
+		return nil, &ValidationError{Field: "user_id", Message: "is required"}
+	}
+	if !window.Start.IsZero() && !window.End.IsZero() && window.End.Before(window.Start) {
+		return nil, &ValidationError{Field: "window", Message: "end is before start"}
+	}
+
+	var resp *UserSummary
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doUserSummary(ctx, userID, window)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doUserSummary performs the user summary request without retries.
+func (c *Client) doUserSummary(ctx context.Context, userID string, window TimeRange) (*UserSummary, error) {
+	query := url.Values{}
+	filter := EventFilter{}.WithRange(window)
+	if start := c.toServerTime(filter.StartTime); start != nil {
+		query.Set("start_time", formatTime(*start))
+	}
+	if end := c.toServerTime(filter.EndTime); end != nil {
+		query.Set("end_time", formatTime(*end))
+	}
+
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/users/%s/summary", url.PathEscape(userID)),
+		Query:  query,
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var summary UserSummary
+	if err := json.Unmarshal(resp.Body, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	c.reportWarnings(req, summary.Warnings)
+	if summary.Actions == nil {
+		summary.Actions = map[string]int{}
+	}
+
+	return &summary, nil
+}
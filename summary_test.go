@@ -0,0 +1,39 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_UserSummary(t *testing.T) {
+	t.Parallel()
+
+	var path, start, end string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, start, end = r.URL.EscapedPath(), r.URL.Query().Get("start_time"), r.URL.Query().Get("end_time")
+		w.Write([]byte(`{"user_id":"system:billing","total":3,"actions":{"invoice.sent":2,"invoice.paid":1},` +
+			`"first_seen":"2026-03-01T10:00:00Z","last_seen":"2026-03-04T09:00:00Z","active_days":2}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	summary, err := client.UserSummary(ctx, "system:billing", TimeRange{Start: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("UserSummary() error = %v", err)
+	}
+	if path != "/v1/users/system:billing/summary" || start != "2026-03-01T00:00:00Z" || end != "" {
+		t.Errorf("request = %s start_time=%q end_time=%q", path, start, end)
+	}
+	if summary.Total != 3 || summary.Actions["invoice.sent"] != 2 || summary.ActiveDays != 2 || summary.LastSeen.Day() != 4 {
+		t.Errorf("UserSummary() = %+v", summary)
+	}
+
+	if _, err := client.UserSummary(ctx, "", TimeRange{}); err == nil {
+		t.Error("UserSummary() without a user ID succeeded")
+	}
+}
@@ -0,0 +1,35 @@
+package tryl
+
+import (
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// SystemUserPrefix marks the UserID of events logged by a system process
+// rather than a user. The API accepts such IDs without a matching user.
+const SystemUserPrefix = validation.SystemUserPrefix
+
+// SystemUser returns the UserID for events logged by the named system
+// process, such as a cron job or worker, which have no user:
+//
+//	client.Log(ctx, tryl.Event{UserID: tryl.SystemUser("billing-cron"), Action: "invoice.generated"})
+//
+// Use it instead of inventing placeholder user IDs, so system activity can
+// be told apart and filtered consistently.
+func SystemUser(name string) string {
+	return SystemUserPrefix + name
+}
+
+// IsSystemUser reports whether userID was created with SystemUser, and
+// returns the process name.
+func IsSystemUser(userID string) (name string, ok bool) {
+	return strings.CutPrefix(userID, SystemUserPrefix)
+}
+
+// IsSystem reports whether the event was logged by a system process; see
+// SystemUser.
+func (e StoredEvent) IsSystem() bool {
+	_, ok := IsSystemUser(e.UserID)
+	return ok
+}
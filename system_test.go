@@ -0,0 +1,37 @@
+package tryl
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSystemUser(t *testing.T) {
+	t.Parallel()
+
+	userID := SystemUser("billing-cron")
+	if userID != "system:billing-cron" {
+		t.Errorf("SystemUser() = %q, want system:billing-cron", userID)
+	}
+	if name, ok := IsSystemUser(userID); !ok || name != "billing-cron" {
+		t.Errorf("IsSystemUser(%q) = %q, %v, want billing-cron, true", userID, name, ok)
+	}
+	if _, ok := IsSystemUser("user_1"); ok {
+		t.Error("IsSystemUser(user_1) = true, want false")
+	}
+	if !(StoredEvent{UserID: userID}).IsSystem() {
+		t.Error("IsSystem() = false for a system event")
+	}
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithDryRun())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	event := Event{UserID: userID, Action: "invoice.generated"}
+	if _, err := client.Log(context.Background(), event); err != nil {
+		t.Errorf("Log() system event error = %v", err)
+	}
+	event.UserID = SystemUser("")
+	if _, err := client.Log(context.Background(), event); err == nil {
+		t.Error("expected error for system user without a name")
+	}
+}
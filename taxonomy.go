@@ -0,0 +1,72 @@
+package tryl
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// ActionDef describes a single action in an action taxonomy.
+type ActionDef struct {
+	// Name is the action name (e.g., "user.created").
+	Name string
+	// Description explains when the action is emitted (optional).
+	Description string
+}
+
+// Taxonomy is a registry of allowed actions.
+// Pass it to WithTaxonomy to have the client reject events whose action is
+// not registered. Taxonomies are usually generated from a registry file with
+// cmd/tryl-actiongen, which also emits typed constants and metadata structs.
+type Taxonomy struct {
+	actions map[string]ActionDef
+}
+
+// NewTaxonomy creates a taxonomy from the given action definitions.
+// Every name must be a valid action and names must be unique.
+func NewTaxonomy(defs ...ActionDef) (*Taxonomy, error) {
+	t := &Taxonomy{actions: make(map[string]ActionDef, len(defs))}
+	for _, def := range defs {
+		if err := validation.ValidateAction(def.Name); err != nil {
+			return nil, fmt.Errorf("invalid taxonomy action %q: %w", def.Name, newValidationError(err))
+		}
+		if _, exists := t.actions[def.Name]; exists {
+			return nil, fmt.Errorf("duplicate taxonomy action %q", def.Name)
+		}
+		t.actions[def.Name] = def
+	}
+	return t, nil
+}
+
+// MustTaxonomy is like NewTaxonomy but panics on error.
+// It is intended for package-level variables in generated code.
+func MustTaxonomy(defs ...ActionDef) *Taxonomy {
+	t, err := NewTaxonomy(defs...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Has reports whether action is registered.
+func (t *Taxonomy) Has(action string) bool {
+	_, ok := t.actions[action]
+	return ok
+}
+
+// Lookup returns the definition for action, if registered.
+func (t *Taxonomy) Lookup(action string) (ActionDef, bool) {
+	def, ok := t.actions[action]
+	return def, ok
+}
+
+// Actions returns all registered actions sorted by name.
+func (t *Taxonomy) Actions() []ActionDef {
+	defs := make([]ActionDef, 0, len(t.actions))
+	for _, def := range t.actions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
@@ -0,0 +1,54 @@
+package tryl
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewTaxonomy(t *testing.T) {
+	t.Parallel()
+
+	tax, err := NewTaxonomy(
+		ActionDef{Name: "user.created"},
+		ActionDef{Name: "org.created", Description: "An organization was created."},
+	)
+	if err != nil {
+		t.Fatalf("NewTaxonomy() error = %v", err)
+	}
+	if !tax.Has("user.created") || tax.Has("user.deleted") {
+		t.Error("Has() returned unexpected results")
+	}
+	if got := tax.Actions(); len(got) != 2 || got[0].Name != "org.created" {
+		t.Errorf("Actions() = %+v, want sorted by name", got)
+	}
+
+	if _, err := NewTaxonomy(ActionDef{Name: "a.b"}, ActionDef{Name: "a.b"}); err == nil {
+		t.Error("NewTaxonomy() with duplicates error = nil, want error")
+	}
+	if _, err := NewTaxonomy(ActionDef{Name: "Not Valid"}); err == nil {
+		t.Error("NewTaxonomy() with invalid name error = nil, want error")
+	}
+}
+
+func TestClient_WithTaxonomy(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithDryRun(),
+		WithTaxonomy(MustTaxonomy(ActionDef{Name: "user.created"})))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.Log(context.Background(), Event{UserID: "user_123", Action: "user.created"}); err != nil {
+		t.Errorf("Log() registered action error = %v", err)
+	}
+	_, err = client.Log(context.Background(), Event{UserID: "user_123", Action: "user.renamed"})
+	if !IsClientValidationError(err) {
+		t.Errorf("Log() unregistered action error = %v, want ValidationError", err)
+	}
+	_, err = client.LogBatch(context.Background(), []Event{{UserID: "user_123", Action: "user.renamed"}})
+	if !IsClientValidationError(err) {
+		t.Errorf("LogBatch() unregistered action error = %v, want ValidationError", err)
+	}
+}
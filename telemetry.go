@@ -0,0 +1,49 @@
+package tryl
+
+import (
+	"runtime"
+	"strings"
+)
+
+// sdkHeader returns the X-Tryl-SDK header value describing the SDK build
+// and the features enabled by options, so the service can tell which
+// clients a deprecation affects. It is a semicolon-separated list of
+// key=value pairs:
+//
+//	version=1.4.0; go=go1.22.1; os=linux; arch=amd64; features=batching,retry
+func (c *clientConfig) sdkHeader() string {
+	fields := []string{
+		"version=" + Version,
+		"go=" + runtime.Version(),
+		"os=" + runtime.GOOS,
+		"arch=" + runtime.GOARCH,
+	}
+	if features := c.features(); len(features) > 0 {
+		fields = append(fields, "features="+strings.Join(features, ","))
+	}
+	return strings.Join(fields, "; ")
+}
+
+// features lists the optional features enabled in the configuration.
+func (c *clientConfig) features() []string {
+	var features []string
+	add := func(enabled bool, name string) {
+		if enabled {
+			features = append(features, name)
+		}
+	}
+	add(c.batchConfig != nil, "batching")
+	add(c.retryConfig != nil && c.retryConfig.MaxAttempts > 1, "retry")
+	add(c.webSocket, "websocket")
+	add(c.dryRun, "dry_run")
+	add(c.taxonomy != nil, "taxonomy")
+	add(c.migrations != nil, "migrations")
+	add(c.skewCorrection, "clock_skew")
+	add(c.actionNormalization != actionNormalizationOff, "action_normalization")
+	add(c.canonicalMetadata, "canonical_metadata")
+	add(c.contentHash, "content_hash")
+	add(c.queryCacheTTL > 0, "query_cache")
+	add(c.etagEntries > 0, "etag_cache")
+	add(c.environmentGuard != "", "environment_guard")
+	return features
+}
@@ -0,0 +1,43 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestClient_SDKHeader(t *testing.T) {
+	t.Parallel()
+
+	headers := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Get("X-Tryl-SDK")
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	list := func(opts ...Option) string {
+		client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", append(opts, WithBaseURL(server.URL))...)
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		if _, err := client.List(context.Background(), EventFilter{}); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		return <-headers
+	}
+
+	got := list(WithContentHash(), WithETagCache(4))
+	for _, want := range []string{"version=" + Version, "go=" + runtime.Version(), "features=retry,content_hash,etag_cache"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("X-Tryl-SDK = %q, want it to contain %q", got, want)
+		}
+	}
+
+	if got := list(WithoutSDKTelemetry()); got != "" {
+		t.Errorf("X-Tryl-SDK with WithoutSDKTelemetry = %q, want empty", got)
+	}
+}
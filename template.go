@@ -0,0 +1,177 @@
+package tryl
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// placeholderRegexp matches a {name} placeholder in a template action.
+var placeholderRegexp = regexp.MustCompile(`\{([a-z][a-z0-9_]*)\}`)
+
+// Vars are the values substituted for the placeholders of an EventTemplate.
+type Vars map[string]string
+
+// EventTemplate produces events for a family of similar actions, such as
+// "document.created" and "document.deleted", that share a target type,
+// metadata, or tags. Create one with Template; it is safe for concurrent
+// use.
+type EventTemplate struct {
+	action   string
+	defaults Event
+	names    []string
+}
+
+// Template returns an EventTemplate whose action contains {name}
+// placeholders filled from Vars, and whose events start from defaults:
+//
+//	var documents = tryl.Template("document.{verb}", tryl.Event{TargetType: "document"})
+//
+//	event, err := documents.Event(tryl.Vars{"verb": "shared"}, tryl.Event{
+//	    UserID:   userID,
+//	    TargetID: doc.ID,
+//	})
+//
+// Template panics if action is malformed, since templates are normally
+// package-level variables. Placeholder names are lowercase identifiers.
+func Template(action string, defaults Event) *EventTemplate {
+	t := &EventTemplate{action: action, defaults: defaults}
+	for _, m := range placeholderRegexp.FindAllStringSubmatch(action, -1) {
+		t.names = append(t.names, m[1])
+	}
+	sample := placeholderRegexp.ReplaceAllString(action, "x")
+	if strings.ContainsAny(sample, "{}") {
+		panic(fmt.Sprintf("tryl: invalid template action %q: malformed placeholder", action))
+	}
+	if err := validation.ValidateAction(sample); err != nil {
+		panic(fmt.Sprintf("tryl: invalid template action %q: %v", action, err))
+	}
+	if len(defaults.Metadata) > 0 {
+		if _, err := metadataObject(defaults.Metadata); err != nil {
+			panic(fmt.Sprintf("tryl: invalid template metadata: %v", err))
+		}
+	}
+	return t
+}
+
+// Action returns the action for vars. A missing variable is reported as a
+// *ValidationError; the result is otherwise validated when the event is
+// logged.
+func (t *EventTemplate) Action(vars Vars) (string, error) {
+	for _, name := range t.names {
+		if _, ok := vars[name]; !ok {
+			return "", &ValidationError{
+				Field:   "action",
+				Message: fmt.Sprintf("missing template variable %q", name),
+				Value:   t.action,
+			}
+		}
+	}
+	return placeholderRegexp.ReplaceAllStringFunc(t.action, func(p string) string {
+		return vars[p[1:len(p)-1]]
+	}), nil
+}
+
+// Event returns the template's defaults with the action filled from vars
+// and the non-empty fields of event applied on top. event's Action is
+// ignored. Metadata is merged key by key, with event's keys winning, so
+// both must be JSON objects; the template's tags are kept and event's
+// tags added.
+func (t *EventTemplate) Event(vars Vars, event Event) (Event, error) {
+	action, err := t.Action(vars)
+	if err != nil {
+		return Event{}, err
+	}
+
+	out := t.defaults
+	out.Action = action
+	if event.UserID != "" {
+		out.UserID = event.UserID
+	}
+	if event.ActorID != "" {
+		out.ActorID = event.ActorID
+	}
+	if event.TargetType != "" {
+		out.TargetType = event.TargetType
+	}
+	if event.TargetID != "" {
+		out.TargetID = event.TargetID
+	}
+	if event.TenantID != "" {
+		out.TenantID = event.TenantID
+	}
+	if event.CorrelationID != "" {
+		out.CorrelationID = event.CorrelationID
+	}
+	if event.ParentEventID != "" {
+		out.ParentEventID = event.ParentEventID
+	}
+	if event.IdempotencyKey != "" {
+		out.IdempotencyKey = event.IdempotencyKey
+	}
+	if event.SchemaVersion != 0 {
+		out.SchemaVersion = event.SchemaVersion
+	}
+	out.Tags = mergeTags(t.defaults.Tags, event.Tags)
+
+	if len(event.Metadata) > 0 {
+		out.Metadata, err = mergeMetadata(t.defaults.Metadata, event.Metadata)
+		if err != nil {
+			return Event{}, err
+		}
+	}
+	return out, nil
+}
+
+// metadataObject decodes metadata that must be a JSON object.
+func metadataObject(metadata json.RawMessage) (map[string]json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(metadata, &obj); err != nil || obj == nil {
+		return nil, &ValidationError{Field: "metadata", Message: "must be a JSON object"}
+	}
+	return obj, nil
+}
+
+// mergeMetadata returns base with the keys of override added or replaced.
+func mergeMetadata(base, override json.RawMessage) (json.RawMessage, error) {
+	merged, err := metadataObject(override)
+	if err != nil {
+		return nil, err
+	}
+	if len(base) > 0 {
+		defaults, err := metadataObject(base)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range defaults {
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// mergeTags returns base followed by the tags of extra not already in it.
+func mergeTags(base, extra []string) []string {
+	if len(extra) == 0 {
+		return base
+	}
+	out := append([]string(nil), base...)
+	for _, tag := range extra {
+		seen := false
+		for _, have := range out {
+			if have == tag {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package tryl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTemplate_Event(t *testing.T) {
+	t.Parallel()
+
+	documents := Template("document.{verb}", Event{
+		TargetType: "document",
+		Metadata:   json.RawMessage(`{"source":"editor","version":1}`),
+		Tags:       []string{"docs"},
+	})
+
+	event, err := documents.Event(Vars{"verb": "shared"}, Event{
+		UserID:   "user_1",
+		TargetID: "doc_1",
+		Metadata: json.RawMessage(`{"version":2,"with":"user_2"}`),
+		Tags:     []string{"sharing", "docs"},
+	})
+	if err != nil {
+		t.Fatalf("Event() error = %v", err)
+	}
+	if event.Action != "document.shared" || event.TargetType != "document" || event.UserID != "user_1" || event.TargetID != "doc_1" {
+		t.Errorf("Event() = %+v", event)
+	}
+	var metadata map[string]any
+	json.Unmarshal(event.Metadata, &metadata)
+	if metadata["source"] != "editor" || metadata["version"] != float64(2) || metadata["with"] != "user_2" {
+		t.Errorf("Event() metadata = %s, want merged metadata", event.Metadata)
+	}
+	if len(event.Tags) != 2 || event.Tags[1] != "sharing" {
+		t.Errorf("Event() tags = %v, want [docs sharing]", event.Tags)
+	}
+
+	plain, err := documents.Event(Vars{"verb": "viewed"}, Event{UserID: "user_1"})
+	if err != nil || string(plain.Metadata) != `{"source":"editor","version":1}` {
+		t.Errorf("Event() without metadata = %s, %v, want template metadata", plain.Metadata, err)
+	}
+
+	if _, err := documents.Event(Vars{}, Event{UserID: "user_1"}); !IsClientValidationError(err) {
+		t.Errorf("Event() with a missing variable error = %v, want validation error", err)
+	}
+	if _, err := documents.Event(Vars{"verb": "viewed"}, Event{Metadata: json.RawMessage(`[1]`)}); !IsClientValidationError(err) {
+		t.Errorf("Event() with array metadata error = %v, want validation error", err)
+	}
+}
+
+func TestTemplate_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, action := range []string{"document.{Verb}", "document.{verb", "Document.{verb}", "document-{verb}"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Template(%q) did not panic", action)
+				}
+			}()
+			Template(action, Event{})
+		}()
+	}
+}
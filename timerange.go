@@ -0,0 +1,75 @@
+package tryl
+
+import "time"
+
+// TimeRange is a span of time. A zero Start or End leaves that side open.
+// Both ends are inclusive, as with EventFilter.StartTime and EndTime.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Last returns the range from d ago onwards, with an open end so events
+// logged while the query runs are included.
+func Last(d time.Duration) TimeRange {
+	return TimeRange{Start: time.Now().Add(-d)}
+}
+
+// Last24Hours returns the range from 24 hours ago onwards.
+func Last24Hours() TimeRange {
+	return Last(24 * time.Hour)
+}
+
+// Today returns the current calendar day in the local time zone.
+func Today() TimeRange {
+	return DayOf(time.Now())
+}
+
+// ThisMonth returns the current calendar month in the local time zone.
+func ThisMonth() TimeRange {
+	return MonthOf(time.Now())
+}
+
+// DayOf returns the calendar day containing t, in t's location. Use
+// t.In(loc) to select another time zone. The range ends one nanosecond
+// before the next day starts, so consecutive days do not overlap.
+func DayOf(t time.Time) TimeRange {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return TimeRange{Start: start, End: start.AddDate(0, 0, 1).Add(-time.Nanosecond)}
+}
+
+// MonthOf returns the calendar month containing t, in t's location. The
+// range ends one nanosecond before the next month starts.
+func MonthOf(t time.Time) TimeRange {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return TimeRange{Start: start, End: start.AddDate(0, 1, 0).Add(-time.Nanosecond)}
+}
+
+// WithRange returns a copy of f limited to r. A zero side of r clears the
+// corresponding filter time.
+//
+//	events, err := client.List(ctx, tryl.EventFilter{UserID: id}.WithRange(tryl.ThisMonth()))
+func (f EventFilter) WithRange(r TimeRange) EventFilter {
+	f.StartTime, f.EndTime = nil, nil
+	if !r.Start.IsZero() {
+		start := r.Start
+		f.StartTime = &start
+	}
+	if !r.End.IsZero() {
+		end := r.End
+		f.EndTime = &end
+	}
+	return f
+}
+
+// WithWindow returns a copy of f limited to events from d ago onwards.
+func (f EventFilter) WithWindow(d time.Duration) EventFilter {
+	return f.WithRange(Last(d))
+}
+
+// formatTime formats a filter time for a query parameter: in UTC, so the
+// value has no offset to misread, and with sub-second precision, so range
+// ends are not rounded to the second.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,57 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMonthOf(t *testing.T) {
+	t.Parallel()
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	r := MonthOf(time.Date(2026, 2, 10, 1, 0, 0, 0, tokyo))
+	if want := time.Date(2026, 2, 1, 0, 0, 0, 0, tokyo); !r.Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", r.Start, want)
+	}
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, tokyo).Add(-time.Nanosecond); !r.End.Equal(want) {
+		t.Errorf("End = %v, want %v", r.End, want)
+	}
+
+	day := DayOf(time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC))
+	if !day.End.Add(time.Nanosecond).Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DayOf() End = %v", day.End)
+	}
+}
+
+func TestEventFilter_WithRange(t *testing.T) {
+	t.Parallel()
+
+	var start, end string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, end = r.URL.Query().Get("start_time"), r.URL.Query().Get("end_time")
+		w.Write([]byte(`{"events":[],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	berlin := time.FixedZone("CET", 60*60)
+	filter := EventFilter{UserID: "user_1"}.WithRange(MonthOf(time.Date(2026, 1, 15, 0, 0, 0, 0, berlin)))
+	if _, err := client.List(context.Background(), filter); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if start != "2025-12-31T23:00:00Z" || end != "2026-01-31T22:59:59.999999999Z" {
+		t.Errorf("start_time, end_time = %q, %q", start, end)
+	}
+
+	filter = filter.WithWindow(time.Hour)
+	if filter.EndTime != nil || filter.StartTime == nil || time.Since(*filter.StartTime) < time.Hour {
+		t.Errorf("WithWindow() = %v - %v, want the last hour", filter.StartTime, filter.EndTime)
+	}
+}
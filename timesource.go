@@ -0,0 +1,71 @@
+package tryl
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock is a source of time and timers. The client uses it for retry
+// backoff and retry budgets, the Batcher's and compaction's flush
+// intervals, action budgets, and the retention policy cache. Tests can
+// pass a manually advanced Clock to WithClock to exercise these without
+// sleeping. Per-try and overall timeouts use context deadlines and always
+// follow the system clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the current time once d has
+	// elapsed.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a Ticker that ticks every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	// C returns the channel on which ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// Rand is a source of random numbers for retry jitter. *rand.Rand
+// implements it; WithRand serializes access, so it need not be safe for
+// concurrent use.
+type Rand interface {
+	// Float64 returns a number in [0.0, 1.0).
+	Float64() float64
+}
+
+// systemClock is the Clock backed by package time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTicker(d time.Duration) Ticker       { return systemTicker{time.NewTicker(d)} }
+
+// systemTicker adapts a *time.Ticker to Ticker.
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// globalRand is the Rand backed by package math/rand's shared source.
+type globalRand struct{}
+
+func (globalRand) Float64() float64 { return rand.Float64() }
+
+// lockedRand makes a Rand safe for concurrent use.
+type lockedRand struct {
+	mu sync.Mutex
+	r  Rand
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
@@ -0,0 +1,82 @@
+package tryl
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stepClock is a Clock whose timers fire immediately, advancing its time
+// by the duration waited.
+type stepClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	waited []time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.waited = append(c.waited, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *stepClock) NewTicker(time.Duration) Ticker { return idleTicker{} }
+
+// idleTicker never ticks.
+type idleTicker struct{}
+
+func (idleTicker) C() <-chan time.Time { return nil }
+func (idleTicker) Stop()               {}
+
+func TestClient_WithClockAndRand(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"code":"internal_error","message":"unavailable"}}`))
+	}))
+	defer server.Close()
+
+	run := func() []time.Duration {
+		clock := &stepClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+		client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+			WithBaseURL(server.URL),
+			WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: 10 * time.Second, MaxDelay: time.Minute, Multiplier: 2, JitterFactor: 0.5}),
+			WithClock(clock),
+			WithRand(rand.New(rand.NewSource(1))),
+		)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		start := time.Now()
+		if _, err := client.Log(context.Background(), Event{UserID: "user_1", Action: "doc.viewed"}); err == nil {
+			t.Fatal("Log() succeeded, want the server error")
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Log() took %v, want the backoff to use the injected clock", elapsed)
+		}
+		return clock.waited
+	}
+
+	first, second := run(), run()
+	if len(first) != 2 || first[0] < 5*time.Second || first[0] > 15*time.Second || first[1] < 10*time.Second {
+		t.Errorf("backoff delays = %v, want two jittered delays from 10s", first)
+	}
+	if len(second) != 2 || first[0] != second[0] || first[1] != second[1] {
+		t.Errorf("backoff delays = %v then %v, want the same with the same seed", first, second)
+	}
+}
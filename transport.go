@@ -0,0 +1,42 @@
+package tryl
+
+import (
+	"errors"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// Transport sends API requests. The client uses an HTTP transport built
+// from WithHTTPClient and related options unless one is set with
+// WithTransport, which lets advanced users carry requests over another
+// wire protocol or serve them from a test fake.
+//
+// A Transport receives requests without authentication: it is responsible
+// for encoding Request.Body (as JSON for the Tryl API) and for any
+// credentials its protocol needs. Responses with a StatusCode of 400 or
+// more are parsed as API errors; an error returned by Do is reported as
+// a NetworkError and retried like one.
+type Transport = transport.Doer
+
+// Request is an API request passed to a Transport. Path is relative to
+// the API base URL, and Body, if not nil, is the value to encode.
+type Request = transport.Request
+
+// Response is a response returned by a Transport. Body is the raw
+// response body. RequestID defaults to the X-Request-ID header.
+type Response = transport.Response
+
+// WithTransport sends every request with t in place of the HTTP
+// transport, so WithHTTPClient and the options that configure it have no
+// effect. LogStream is not supported with a custom transport, and
+// WithWebSocket cannot be combined with it.
+// Default: the HTTP transport
+func WithTransport(t Transport) Option {
+	return func(c *clientConfig) error {
+		if t == nil {
+			return errors.New("transport cannot be nil")
+		}
+		c.transport = t
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package tryl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// fakeTransport answers requests from a function and records them.
+type fakeTransport struct {
+	mu       sync.Mutex
+	requests []Request
+	respond  func(req Request) (*Response, error)
+}
+
+func (f *fakeTransport) Do(ctx context.Context, req Request) (*Response, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	return f.respond(req)
+}
+
+func TestClient_WithTransport(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeTransport{respond: func(req Request) (*Response, error) {
+		if req.Method == "POST" {
+			return &Response{StatusCode: http.StatusCreated, Body: []byte(`{"id":"evt_1","timestamp":"2026-03-01T12:00:00Z"}`)}, nil
+		}
+		return &Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       []byte(`{"error":{"code":"invalid_filter","message":"bad filter"}}`),
+			Headers:    http.Header{"X-Request-Id": {"req_1"}},
+		}, nil
+	}}
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithTransport(fake))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := client.Log(ctx, Event{UserID: "user_1", Action: "doc.viewed"})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if resp.ID != "evt_1" {
+		t.Errorf("Log() ID = %q, want evt_1", resp.ID)
+	}
+
+	_, err = client.List(ctx, EventFilter{UserID: "user_1"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusBadRequest || apiErr.RequestID != "req_1" {
+		t.Errorf("List() error = %v, want a 400 APIError with request ID req_1", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.requests) != 2 || fake.requests[0].Path != "/v1/events" || fake.requests[1].Query.Get("user_id") != "user_1" {
+		t.Errorf("transport saw %+v", fake.requests)
+	}
+	if fake.requests[0].Body == nil {
+		t.Error("Log() request has no body")
+	}
+}
+
+func TestClient_WithTransport_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeTransport{respond: func(Request) (*Response, error) {
+		return nil, errors.New("unused")
+	}}
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithTransport(nil)); err == nil {
+		t.Error("NewClient(WithTransport(nil)) error = nil")
+	}
+	if _, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithTransport(fake), WithWebSocket()); err == nil {
+		t.Error("NewClient(WithTransport, WithWebSocket) error = nil")
+	}
+
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithTransport(fake))
+	if _, err := client.LogStream(context.Background()); err == nil {
+		t.Error("LogStream() error = nil with a custom transport")
+	}
+}
@@ -29,6 +29,9 @@
 //
 //	projects, err := mgmt.ListProjects(ctx)
 //
+// Automation uses a service account token (see CreateServiceAccount)
+// with NewServiceAccountClient instead.
+//
 // # Querying Events
 //
 // Advanced filtering with time ranges, metadata, and pagination:
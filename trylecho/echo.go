@@ -0,0 +1,89 @@
+// Package trylecho logs Echo requests as Activity Logger events.
+//
+// Middleware records each request through a trylhttp.Logger with Echo's
+// matched route template (e.g., "/users/:id") rather than the raw path, so
+// requests to one route share a target and route filters see the template.
+// Route inclusion and exclusion, the anonymous user, and the action are
+// configured on the Logger.
+//
+// It lives in its own module so the SDK itself does not depend on Echo.
+//
+// Usage:
+//
+//	logger := trylhttp.New(client, trylhttp.WithExclude("/healthz"))
+//	e := echo.New()
+//	e.Use(trylecho.Middleware(logger))
+package trylecho
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/trylhttp"
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultUserKey is the Echo context key the user ID is read from unless
+// WithUserKey is set.
+const DefaultUserKey = "user_id"
+
+// Option configures Middleware.
+type Option func(*config)
+
+type config struct {
+	userKey string
+}
+
+// WithUserKey sets the Echo context key that authentication middleware
+// stores the user ID under. If the key holds no string, the Logger's user
+// function or anonymous user is used.
+// Default: DefaultUserKey
+func WithUserKey(key string) Option {
+	return func(c *config) {
+		c.userKey = key
+	}
+}
+
+// Middleware returns Echo middleware that logs each request through logger
+// after the handler has run.
+func Middleware(logger *trylhttp.Logger, opts ...Option) echo.MiddlewareFunc {
+	cfg := config{userKey: DefaultUserKey}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			userID, _ := c.Get(cfg.userKey).(string)
+			logger.Log(c.Request().Context(), trylhttp.Request{
+				Method:      c.Request().Method,
+				Route:       c.Path(),
+				Path:        c.Request().URL.Path,
+				Status:      status(c, err),
+				Latency:     time.Since(start),
+				UserID:      userID,
+				ClientIP:    c.RealIP(),
+				HTTPRequest: c.Request(),
+			})
+			return err
+		}
+	}
+}
+
+// status returns the response status. Echo writes the response for a
+// returned error after the middleware chain, so it is derived from err
+// unless the handler already responded.
+func status(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
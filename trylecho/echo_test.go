@@ -0,0 +1,114 @@
+package trylecho
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+	"github.com/joshuawatkins04/tryl_sdk/trylhttp"
+	"github.com/labstack/echo/v4"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+// newLogger returns a Logger whose events are delivered to the channel.
+func newLogger(t *testing.T, opts ...trylhttp.Option) (*trylhttp.Logger, <-chan tryl.Event) {
+	t.Helper()
+
+	events := make(chan tryl.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event tryl.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return trylhttp.New(client, opts...), events
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	logger, events := newLogger(t, trylhttp.WithExclude("/healthz"))
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("account", "user_1")
+			return next(c)
+		}
+	})
+	e.Use(Middleware(logger, WithUserKey("account")))
+	e.GET("/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusAccepted) })
+	e.GET("/healthz", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	select {
+	case event := <-events:
+		var metadata map[string]any
+		json.Unmarshal(event.Metadata, &metadata)
+		if event.UserID != "user_1" || event.TargetID != "GET /users/:id" {
+			t.Errorf("event = %+v, want user_1 on the route template", event)
+		}
+		if metadata["path"] != "/users/42" || metadata["status"] != 202.0 {
+			t.Errorf("metadata = %v", metadata)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event logged")
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("excluded route logged %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMiddleware_ErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want float64
+	}{
+		{"http error", echo.NewHTTPError(http.StatusConflict, "taken"), 409},
+		{"other error", errors.New("boom"), 500},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, events := newLogger(t, trylhttp.WithAnonymousUser("anonymous"))
+			e := echo.New()
+			e.Use(Middleware(logger))
+			e.POST("/orders", func(c echo.Context) error { return tt.err })
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+			select {
+			case event := <-events:
+				var metadata map[string]any
+				json.Unmarshal(event.Metadata, &metadata)
+				if metadata["status"] != tt.want {
+					t.Errorf("status = %v, want %v", metadata["status"], tt.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("no event logged")
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/joshuawatkins04/tryl_sdk/trylecho
+
+go 1.21
+
+require (
+	github.com/joshuawatkins04/tryl_sdk v0.1.0
+	github.com/labstack/echo/v4 v4.12.0
+)
+
+replace github.com/joshuawatkins04/tryl_sdk => ../
@@ -0,0 +1,70 @@
+// Package trylgin logs Gin requests as Activity Logger events.
+//
+// Middleware records each request through a trylhttp.Logger with Gin's
+// matched route template (e.g., "/users/:id") rather than the raw path, so
+// requests to one route share a target and route filters see the template.
+// Route inclusion and exclusion, the anonymous user, and the action are
+// configured on the Logger.
+//
+// It lives in its own module so the SDK itself does not depend on Gin.
+//
+// Usage:
+//
+//	logger := trylhttp.New(client, trylhttp.WithExclude("/healthz"))
+//	r := gin.New()
+//	r.Use(trylgin.Middleware(logger))
+package trylgin
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshuawatkins04/tryl_sdk/trylhttp"
+)
+
+// DefaultUserKey is the Gin context key the user ID is read from unless
+// WithUserKey is set.
+const DefaultUserKey = "user_id"
+
+// Option configures Middleware.
+type Option func(*config)
+
+type config struct {
+	userKey string
+}
+
+// WithUserKey sets the Gin context key that authentication middleware
+// stores the user ID under. If the key holds no string, the Logger's user
+// function or anonymous user is used.
+// Default: DefaultUserKey
+func WithUserKey(key string) Option {
+	return func(c *config) {
+		c.userKey = key
+	}
+}
+
+// Middleware returns Gin middleware that logs each request through logger
+// after the rest of the chain has run.
+func Middleware(logger *trylhttp.Logger, opts ...Option) gin.HandlerFunc {
+	cfg := config{userKey: DefaultUserKey}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// FullPath is empty for unmatched routes; the Logger then uses the path.
+		logger.Log(c.Request.Context(), trylhttp.Request{
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Path:        c.Request.URL.Path,
+			Status:      c.Writer.Status(),
+			Latency:     time.Since(start),
+			UserID:      c.GetString(cfg.userKey),
+			ClientIP:    c.ClientIP(),
+			HTTPRequest: c.Request,
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package trylgin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshuawatkins04/tryl_sdk"
+	"github.com/joshuawatkins04/tryl_sdk/trylhttp"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newLogger returns a Logger whose events are delivered to the channel.
+func newLogger(t *testing.T, opts ...trylhttp.Option) (*trylhttp.Logger, <-chan tryl.Event) {
+	t.Helper()
+
+	events := make(chan tryl.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event tryl.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return trylhttp.New(client, opts...), events
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	logger, events := newLogger(t, trylhttp.WithExclude("/healthz"))
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("account", "user_1") })
+	r.Use(Middleware(logger, WithUserKey("account")))
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusAccepted) })
+	r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	select {
+	case event := <-events:
+		var metadata map[string]any
+		json.Unmarshal(event.Metadata, &metadata)
+		if event.UserID != "user_1" || event.TargetID != "GET /users/:id" {
+			t.Errorf("event = %+v, want user_1 on the route template", event)
+		}
+		if metadata["path"] != "/users/42" || metadata["status"] != 202.0 {
+			t.Errorf("metadata = %v", metadata)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event logged")
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("excluded route logged %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMiddleware_UnmatchedRoute(t *testing.T) {
+	t.Parallel()
+
+	logger, events := newLogger(t, trylhttp.WithAnonymousUser("anonymous"))
+	r := gin.New()
+	r.Use(Middleware(logger))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	select {
+	case event := <-events:
+		if event.UserID != "anonymous" || event.TargetID != "GET /missing" {
+			t.Errorf("event = %+v, want the anonymous user on the raw path", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event logged")
+	}
+}
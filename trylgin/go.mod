@@ -0,0 +1,10 @@
+module github.com/joshuawatkins04/tryl_sdk/trylgin
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.10.0
+	github.com/joshuawatkins04/tryl_sdk v0.1.0
+)
+
+replace github.com/joshuawatkins04/tryl_sdk => ../
@@ -0,0 +1,312 @@
+// Package trylgorm logs create, update, and delete events for database
+// models automatically, so CRUD audit logging needs no call sites.
+//
+// An Auditor turns a model value into an event: the action is the model's
+// snake_case type name plus the operation ("invoice_item.updated"), the
+// target is the model type and primary key, and the metadata holds the
+// changed fields. Events are sent with Client.LogFireAndForget, so hooks
+// never slow down or fail the database operation; delivery errors go to the
+// client's WithAsyncErrorHandler.
+//
+// Register the models to audit, then call Created, Updated (or
+// UpdatedFrom with a Snapshot of the stored row), and Deleted from the
+// ORM's after-write callbacks:
+//
+//	auditor := trylgorm.New(client, trylgorm.WithUserFunc(userFromContext))
+//	auditor.Register(&Invoice{}, &Customer{})
+//
+//	auditor.Created(ctx, &invoice)
+//
+// Models that were not registered are ignored.
+package trylgorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+	"github.com/joshuawatkins04/tryl_sdk/internal/naming"
+)
+
+// Operation is a database write operation.
+type Operation string
+
+// Operations logged by an Auditor. The value is the action suffix.
+const (
+	Create Operation = "created"
+	Update Operation = "updated"
+	Delete Operation = "deleted"
+)
+
+// Change is the old and new value of an updated field.
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Option configures an Auditor.
+type Option func(*Auditor)
+
+// WithUserFunc sets how the acting user is read from the operation's
+// context. Events without a user are logged as the default user.
+func WithUserFunc(fn func(ctx context.Context) string) Option {
+	return func(a *Auditor) {
+		a.userFunc = fn
+	}
+}
+
+// WithDefaultUser sets the user ID for operations with no user in context,
+// such as migrations and background jobs.
+// Default: "system"
+func WithDefaultUser(userID string) Option {
+	return func(a *Auditor) {
+		a.defaultUser = userID
+	}
+}
+
+// WithIgnoredFields excludes fields, by Go name, from change metadata for
+// every model. "CreatedAt", "UpdatedAt", and "DeletedAt" are always ignored.
+func WithIgnoredFields(fields ...string) Option {
+	return func(a *Auditor) {
+		for _, f := range fields {
+			a.ignored[f] = true
+		}
+	}
+}
+
+// Auditor logs events for registered models.
+type Auditor struct {
+	client      *tryl.Client
+	userFunc    func(ctx context.Context) string
+	defaultUser string
+	ignored     map[string]bool
+	models      map[reflect.Type]string
+}
+
+// New creates an Auditor that logs via client.
+func New(client *tryl.Client, opts ...Option) *Auditor {
+	a := &Auditor{
+		client:      client,
+		defaultUser: "system",
+		ignored:     map[string]bool{"CreatedAt": true, "UpdatedAt": true, "DeletedAt": true},
+		models:      map[reflect.Type]string{},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Register enables auditing for the given model types. Pass a pointer or
+// value of each model struct. Register is not safe to call concurrently
+// with the hooks; call it during setup.
+func (a *Auditor) Register(models ...any) {
+	for _, m := range models {
+		t := structType(reflect.TypeOf(m))
+		if t != nil {
+			a.models[t] = naming.SnakeCase(t.Name())
+		}
+	}
+}
+
+// Created logs a create event for model, or for each element if model is a
+// slice. The metadata holds the model's fields under "changes".
+func (a *Auditor) Created(ctx context.Context, model any) {
+	a.each(model, func(v reflect.Value, name string) {
+		a.log(ctx, Create, v, name, map[string]any{"changes": a.fields(v)})
+	})
+}
+
+// Updated logs an update event for model. changes is what was written: a
+// map of column or field names to new values (as passed to GORM's Updates),
+// or a model struct, in which case every field is reported. The metadata
+// holds the new values under "changes"; for old and new values, use
+// Snapshot and UpdatedFrom.
+func (a *Auditor) Updated(ctx context.Context, model, changes any) {
+	a.each(model, func(v reflect.Value, name string) {
+		var diff any
+		switch c := changes.(type) {
+		case map[string]any:
+			diff = c
+		default:
+			if cv := reflect.Indirect(reflect.ValueOf(changes)); cv.Kind() == reflect.Struct {
+				diff = a.fields(cv)
+			} else {
+				diff = a.fields(v)
+			}
+		}
+		a.log(ctx, Update, v, name, map[string]any{"changes": diff})
+	})
+}
+
+// UpdatedFrom logs an update event with a field-level diff between before
+// and after, for callers that keep the previous state. before must have the
+// same type as after. Unchanged fields are omitted; nothing is logged if no
+// field changed.
+func (a *Auditor) UpdatedFrom(ctx context.Context, before, after any) {
+	bv := reflect.Indirect(reflect.ValueOf(before))
+	a.each(after, func(v reflect.Value, name string) {
+		if !bv.IsValid() || bv.Type() != v.Type() {
+			return
+		}
+		diff := a.diff(bv, v)
+		if len(diff) == 0 {
+			return
+		}
+		a.log(ctx, Update, v, name, map[string]any{"changes": diff})
+	})
+}
+
+// Snapshot returns a pointer to a copy of model, for passing to
+// UpdatedFrom after the update, or nil if model is not a single registered
+// model. Call it before the update: GORM writes updated values back into
+// the model.
+func (a *Auditor) Snapshot(model any) any {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	if _, ok := a.models[v.Type()]; !ok {
+		return nil
+	}
+	before := reflect.New(v.Type())
+	before.Elem().Set(v)
+	return before.Interface()
+}
+
+// Deleted logs a delete event for model, or for each element if model is a
+// slice.
+func (a *Auditor) Deleted(ctx context.Context, model any) {
+	a.each(model, func(v reflect.Value, name string) {
+		a.log(ctx, Delete, v, name, nil)
+	})
+}
+
+// diff returns the fields whose values differ between two values of the
+// same struct type, keyed by JSON field name.
+func (a *Auditor) diff(before, after reflect.Value) map[string]Change {
+	from, to := a.fields(before), a.fields(after)
+	diff := map[string]Change{}
+	for k, v := range to {
+		if !reflect.DeepEqual(from[k], v) {
+			diff[k] = Change{From: from[k], To: v}
+		}
+	}
+	return diff
+}
+
+// each calls fn for model, or for each element of a slice of models, if
+// its type is registered.
+func (a *Auditor) each(model any, fn func(v reflect.Value, name string)) {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			a.each(v.Index(i).Interface(), fn)
+		}
+		return
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	if name, ok := a.models[v.Type()]; ok {
+		fn(v, name)
+	}
+}
+
+// log builds and sends the event for one model value.
+func (a *Auditor) log(ctx context.Context, op Operation, v reflect.Value, name string, metadata map[string]any) {
+	event := tryl.Event{
+		Action:     name + "." + string(op),
+		TargetType: name,
+		TargetID:   primaryKey(v),
+	}
+	if a.userFunc != nil {
+		event.UserID = a.userFunc(ctx)
+	}
+	if event.UserID == "" {
+		event.UserID = a.defaultUser
+	}
+	if metadata != nil {
+		data, err := json.Marshal(metadata)
+		if err == nil {
+			event.Metadata = data
+		}
+	}
+	a.client.LogFireAndForget(context.WithoutCancel(ctx), event)
+}
+
+// fields returns the exported, non-ignored fields of a struct keyed by JSON
+// name. Embedded structs are flattened.
+func (a *Auditor) fields(v reflect.Value) map[string]any {
+	out := map[string]any{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || a.ignored[f.Name] {
+			continue
+		}
+		if f.Anonymous && reflect.Indirect(v.Field(i)).Kind() == reflect.Struct {
+			for k, fv := range a.fields(reflect.Indirect(v.Field(i))) {
+				out[k] = fv
+			}
+			continue
+		}
+		name := jsonName(f)
+		if name == "-" || f.Tag.Get("gorm") == "-" {
+			continue
+		}
+		out[name] = v.Field(i).Interface()
+	}
+	return out
+}
+
+// primaryKey returns the value of the field tagged `gorm:"primaryKey"`, or
+// of the field named ID, formatted as a string.
+func primaryKey(v reflect.Value) string {
+	t := v.Type()
+	idField := -1
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && reflect.Indirect(v.Field(i)).Kind() == reflect.Struct {
+			if id := primaryKey(reflect.Indirect(v.Field(i))); id != "" {
+				return id
+			}
+			continue
+		}
+		tag := strings.ToLower(f.Tag.Get("gorm"))
+		if strings.Contains(tag, "primarykey") || strings.Contains(tag, "primary_key") {
+			return fmt.Sprint(v.Field(i).Interface())
+		}
+		if f.Name == "ID" {
+			idField = i
+		}
+	}
+	if idField < 0 {
+		return ""
+	}
+	return fmt.Sprint(v.Field(idField).Interface())
+}
+
+// jsonName returns the field's JSON key, defaulting to its Go name.
+func jsonName(f reflect.StructField) string {
+	if tag := f.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// structType dereferences t to a struct type, or returns nil.
+func structType(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
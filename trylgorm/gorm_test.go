@@ -0,0 +1,180 @@
+package trylgorm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+type Model struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type InvoiceItem struct {
+	Model
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	Secret   string  `gorm:"-"`
+}
+
+type Customer struct {
+	Email string `gorm:"primaryKey"`
+}
+
+type userKey struct{}
+
+// newAuditor returns an Auditor whose events are delivered to the channel.
+func newAuditor(t *testing.T, opts ...Option) (*Auditor, <-chan tryl.Event) {
+	t.Helper()
+
+	events := make(chan tryl.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event tryl.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	auditor := New(client, opts...)
+	auditor.Register(&InvoiceItem{}, Customer{})
+	return auditor, events
+}
+
+func receive(t *testing.T, events <-chan tryl.Event) (tryl.Event, map[string]any) {
+	t.Helper()
+	select {
+	case e := <-events:
+		var metadata map[string]any
+		json.Unmarshal(e.Metadata, &metadata)
+		return e, metadata
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return tryl.Event{}, nil
+	}
+}
+
+func TestAuditor_Created(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t, WithUserFunc(func(ctx context.Context) string {
+		id, _ := ctx.Value(userKey{}).(string)
+		return id
+	}))
+	ctx := context.WithValue(context.Background(), userKey{}, "user_42")
+
+	auditor.Created(ctx, &InvoiceItem{Model: Model{ID: 7}, Amount: 9.5, Currency: "EUR", Secret: "x"})
+
+	event, metadata := receive(t, events)
+	if event.Action != "invoice_item.created" || event.TargetType != "invoice_item" || event.TargetID != "7" || event.UserID != "user_42" {
+		t.Errorf("event = %+v", event)
+	}
+	changes := metadata["changes"].(map[string]any)
+	if changes["amount"] != 9.5 || changes["currency"] != "EUR" {
+		t.Errorf("changes = %v", changes)
+	}
+	for _, field := range []string{"Secret", "CreatedAt", "UpdatedAt"} {
+		if _, ok := changes[field]; ok {
+			t.Errorf("changes include ignored field %s", field)
+		}
+	}
+}
+
+func TestAuditor_UpdatedAndDeleted(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t)
+	ctx := context.Background()
+
+	auditor.Updated(ctx, &Customer{Email: "a@example.com"}, map[string]any{"email": "b@example.com"})
+	event, metadata := receive(t, events)
+	if event.Action != "customer.updated" || event.TargetID != "a@example.com" || event.UserID != "system" {
+		t.Errorf("update event = %+v", event)
+	}
+	if metadata["changes"].(map[string]any)["email"] != "b@example.com" {
+		t.Errorf("update metadata = %v", metadata)
+	}
+
+	auditor.Deleted(ctx, []InvoiceItem{{Model: Model{ID: 1}}, {Model: Model{ID: 2}}})
+	for _, want := range []string{"1", "2"} {
+		event, _ := receive(t, events)
+		if event.Action != "invoice_item.deleted" {
+			t.Errorf("delete action = %q", event.Action)
+		}
+		if event.TargetID != "1" && event.TargetID != "2" {
+			t.Errorf("delete target = %q, want %s", event.TargetID, want)
+		}
+	}
+}
+
+func TestAuditor_UpdatedFrom(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t)
+	before := InvoiceItem{Model: Model{ID: 3}, Amount: 1, Currency: "USD"}
+	after := before
+	after.Amount = 2
+	after.UpdatedAt = time.Now()
+
+	auditor.UpdatedFrom(context.Background(), before, &after)
+	_, metadata := receive(t, events)
+	changes := metadata["changes"].(map[string]any)
+	if len(changes) != 1 {
+		t.Fatalf("changes = %v, want only amount", changes)
+	}
+	if amount := changes["amount"].(map[string]any); amount["from"] != 1.0 || amount["to"] != 2.0 {
+		t.Errorf("amount change = %v", amount)
+	}
+}
+
+func TestAuditor_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t)
+	item := &InvoiceItem{Model: Model{ID: 4}, Amount: 1, Currency: "USD"}
+	before := auditor.Snapshot(item)
+	item.Currency = "EUR"
+
+	auditor.UpdatedFrom(context.Background(), before, item)
+	_, metadata := receive(t, events)
+	change := metadata["changes"].(map[string]any)["currency"].(map[string]any)
+	if change["from"] != "USD" || change["to"] != "EUR" {
+		t.Errorf("currency change = %v, want USD to EUR", change)
+	}
+
+	type Session struct{ ID int }
+	if got := auditor.Snapshot(&Session{ID: 1}); got != nil {
+		t.Errorf("Snapshot(unregistered) = %v, want nil", got)
+	}
+	if got := auditor.Snapshot([]InvoiceItem{*item}); got != nil {
+		t.Errorf("Snapshot(slice) = %v, want nil", got)
+	}
+}
+
+func TestAuditor_IgnoresUnregisteredModels(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t)
+	type Session struct{ ID int }
+	auditor.Created(context.Background(), &Session{ID: 1})
+
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
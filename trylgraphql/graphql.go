@@ -0,0 +1,195 @@
+// Package trylgraphql logs GraphQL mutations as Activity Logger events.
+//
+// HTTP middleware only sees POST /graphql; a Logger records the operation
+// itself: its name, root fields, redacted variables, requesting user, and
+// whether it returned errors. Events are sent with Client.LogFireAndForget.
+//
+// Call Logger.Log from the GraphQL server's response hook once the
+// operation has run:
+//
+//	logger := trylgraphql.New(client, trylgraphql.WithUserFunc(userFromContext))
+//	logger.Log(ctx, trylgraphql.Operation{
+//	    Name: "UpdateInvoice", Type: "mutation",
+//	    Fields: []string{"updateInvoice"}, Variables: vars,
+//	})
+package trylgraphql
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+	"github.com/joshuawatkins04/tryl_sdk/internal/naming"
+)
+
+// Redacted replaces the values of sensitive variables.
+const Redacted = "[REDACTED]"
+
+// defaultRedactKeys are variable names redacted by default. A variable is
+// redacted if its lowercased name contains any of them.
+var defaultRedactKeys = []string{"password", "secret", "token", "apikey", "api_key", "authorization", "credential"}
+
+// Operation describes an executed GraphQL operation.
+type Operation struct {
+	// Name is the operation name (e.g., "CreateInvoice"). May be empty.
+	Name string
+	// Type is "query", "mutation", or "subscription".
+	Type string
+	// Fields are the root fields selected by the operation.
+	Fields    []string
+	Variables map[string]any
+	// Failed reports whether the response contained errors.
+	Failed bool
+}
+
+// Option configures a Logger.
+type Option func(*Logger)
+
+// WithUserFunc sets how the requesting user is read from the context.
+func WithUserFunc(fn func(ctx context.Context) string) Option {
+	return func(l *Logger) {
+		l.userFunc = fn
+	}
+}
+
+// WithAnonymousUser sets the user ID for operations without a user.
+// By default such operations are not logged.
+func WithAnonymousUser(userID string) Option {
+	return func(l *Logger) {
+		l.anonymousUser = userID
+	}
+}
+
+// WithOperationTypes sets which operation types are logged.
+// Default: "mutation"
+func WithOperationTypes(types ...string) Option {
+	return func(l *Logger) {
+		l.types = map[string]bool{}
+		for _, t := range types {
+			l.types[t] = true
+		}
+	}
+}
+
+// WithRedactKeys adds variable names whose values are redacted, matched
+// case-insensitively as substrings. Names containing "password", "secret",
+// "token", "apikey", "api_key", "authorization", or "credential" are
+// always redacted.
+func WithRedactKeys(keys ...string) Option {
+	return func(l *Logger) {
+		for _, k := range keys {
+			l.redactKeys = append(l.redactKeys, strings.ToLower(k))
+		}
+	}
+}
+
+// WithActionFunc sets how an operation is mapped to an action.
+// Default: "graphql." followed by the snake_case operation name
+// ("CreateInvoice" becomes "graphql.create_invoice"), or "graphql." and
+// the operation type for anonymous operations.
+func WithActionFunc(fn func(Operation) string) Option {
+	return func(l *Logger) {
+		l.actionFunc = fn
+	}
+}
+
+// Logger logs GraphQL operations.
+type Logger struct {
+	client        *tryl.Client
+	userFunc      func(ctx context.Context) string
+	anonymousUser string
+	types         map[string]bool
+	redactKeys    []string
+	actionFunc    func(Operation) string
+}
+
+// New creates a Logger that logs via client.
+func New(client *tryl.Client, opts ...Option) *Logger {
+	l := &Logger{
+		client:     client,
+		types:      map[string]bool{"mutation": true},
+		redactKeys: append([]string(nil), defaultRedactKeys...),
+		actionFunc: defaultAction,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Log records op if its type is logged and a user is known.
+func (l *Logger) Log(ctx context.Context, op Operation) {
+	if !l.types[op.Type] {
+		return
+	}
+	var userID string
+	if l.userFunc != nil {
+		userID = l.userFunc(ctx)
+	}
+	if userID == "" {
+		userID = l.anonymousUser
+	}
+	if userID == "" {
+		return
+	}
+
+	metadata, err := json.Marshal(map[string]any{
+		"operation_name": op.Name,
+		"operation_type": op.Type,
+		"fields":         op.Fields,
+		"variables":      l.redact(op.Variables),
+		"failed":         op.Failed,
+	})
+	if err != nil {
+		return
+	}
+	l.client.LogFireAndForget(context.WithoutCancel(ctx), tryl.Event{
+		UserID:     userID,
+		Action:     l.actionFunc(op),
+		TargetType: "graphql_operation",
+		TargetID:   op.Name,
+		Metadata:   metadata,
+	})
+}
+
+// redact returns a copy of v with sensitive keys replaced, recursively.
+func (l *Logger) redact(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, child := range val {
+			if l.sensitive(k) {
+				out[k] = Redacted
+				continue
+			}
+			out[k] = l.redact(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, child := range val {
+			out[i] = l.redact(child)
+		}
+		return out
+	}
+	return v
+}
+
+func (l *Logger) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range l.redactKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultAction(op Operation) string {
+	name := naming.SnakeCase(op.Name)
+	if name == "" {
+		name = op.Type
+	}
+	return "graphql." + name
+}
@@ -0,0 +1,112 @@
+package trylgraphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+type userKey struct{}
+
+func newLogger(t *testing.T, opts ...Option) (*Logger, <-chan tryl.Event) {
+	t.Helper()
+
+	events := make(chan tryl.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event tryl.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	opts = append([]Option{WithUserFunc(func(ctx context.Context) string {
+		id, _ := ctx.Value(userKey{}).(string)
+		return id
+	})}, opts...)
+	return New(client, opts...), events
+}
+
+func TestLogger_Log(t *testing.T) {
+	t.Parallel()
+
+	logger, events := newLogger(t, WithRedactKeys("cardNumber"))
+	ctx := context.WithValue(context.Background(), userKey{}, "user_1")
+
+	logger.Log(ctx, Operation{
+		Name:   "UpdateUserPassword",
+		Type:   "mutation",
+		Fields: []string{"updateUser"},
+		Variables: map[string]any{
+			"id":    "u_1",
+			"input": map[string]any{"newPassword": "hunter2", "cardNumber": "4242", "name": "Ada"},
+		},
+	})
+
+	select {
+	case event := <-events:
+		if event.Action != "graphql.update_user_password" || event.UserID != "user_1" || event.TargetID != "UpdateUserPassword" {
+			t.Errorf("event = %+v", event)
+		}
+		var metadata struct {
+			Variables map[string]any `json:"variables"`
+			Fields    []string       `json:"fields"`
+		}
+		json.Unmarshal(event.Metadata, &metadata)
+		input := metadata.Variables["input"].(map[string]any)
+		if input["newPassword"] != Redacted || input["cardNumber"] != Redacted || input["name"] != "Ada" {
+			t.Errorf("input = %v, want password and card redacted", input)
+		}
+		if metadata.Variables["id"] != "u_1" || len(metadata.Fields) != 1 {
+			t.Errorf("metadata = %+v", metadata)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
+
+func TestLogger_Log_Skips(t *testing.T) {
+	t.Parallel()
+
+	logger, events := newLogger(t)
+	ctx := context.WithValue(context.Background(), userKey{}, "user_1")
+
+	logger.Log(ctx, Operation{Name: "GetUser", Type: "query"})
+	logger.Log(context.Background(), Operation{Name: "CreateUser", Type: "mutation"})
+
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDefaultAction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		op   Operation
+		want string
+	}{
+		{Operation{Name: "CreateInvoice", Type: "mutation"}, "graphql.create_invoice"},
+		{Operation{Name: "deleteAPIKey", Type: "mutation"}, "graphql.delete_api_key"},
+		{Operation{Name: "", Type: "mutation"}, "graphql.mutation"},
+	}
+	for _, tt := range tests {
+		if got := defaultAction(tt.op); got != tt.want {
+			t.Errorf("defaultAction(%q) = %q, want %q", tt.op.Name, got, tt.want)
+		}
+	}
+}
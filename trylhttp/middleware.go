@@ -0,0 +1,253 @@
+// Package trylhttp logs HTTP requests as Activity Logger events.
+//
+// A Logger turns a completed request (route template, status, latency, and
+// user) into an event and sends it with Client.LogFireAndForget, so logging
+// never delays the response. Middleware wraps a net/http handler; for other
+// frameworks, call Logger.Log from their middleware so the framework's route
+// template is recorded instead of the raw path. The trylgin and trylecho
+// modules do this for Gin and Echo.
+package trylhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// DefaultAction is the action of request events unless WithActionFunc is set.
+const DefaultAction = "http.request"
+
+// Request describes a completed HTTP request.
+type Request struct {
+	Method string
+	// Route is the route template (e.g., "/users/:id"). If empty, Path is
+	// used for filtering and as the event target.
+	Route   string
+	Path    string
+	Status  int
+	Latency time.Duration
+	// UserID is the authenticated user. If empty, the Logger's user
+	// function or anonymous user is used.
+	UserID   string
+	ClientIP string
+	// HTTPRequest is the underlying request, if available. It is passed to
+	// the function set with WithUserFunc.
+	HTTPRequest *http.Request
+}
+
+// Option configures a Logger.
+type Option func(*Logger)
+
+// WithUserFunc sets how the user is read from a request when
+// Request.UserID is empty, for example from a context key set by
+// authentication middleware.
+func WithUserFunc(fn func(*http.Request) string) Option {
+	return func(l *Logger) {
+		l.userFunc = fn
+	}
+}
+
+// WithAnonymousUser sets the user ID for requests without a user.
+// By default such requests are not logged.
+func WithAnonymousUser(userID string) Option {
+	return func(l *Logger) {
+		l.anonymousUser = userID
+	}
+}
+
+// WithInclude limits logging to routes matching at least one pattern.
+// Patterns use path.Match syntax, where "*" matches within one path segment
+// (e.g., "/users/*"); a pattern ending in "/**" also matches every route
+// below its prefix (e.g., "/api/**" matches "/api/users/:id").
+func WithInclude(patterns ...string) Option {
+	return func(l *Logger) {
+		l.include = append(l.include, patterns...)
+	}
+}
+
+// WithExclude skips routes matching any pattern (e.g., "/healthz",
+// "/internal/**"), as for WithInclude. Exclusion takes precedence over
+// inclusion.
+func WithExclude(patterns ...string) Option {
+	return func(l *Logger) {
+		l.exclude = append(l.exclude, patterns...)
+	}
+}
+
+// WithActionFunc sets how a request is mapped to an action.
+// Default: DefaultAction for every request
+func WithActionFunc(fn func(Request) string) Option {
+	return func(l *Logger) {
+		l.actionFunc = fn
+	}
+}
+
+// WithRouteFunc sets how Middleware determines the route template of a
+// request, for routers that expose it.
+// Default: the request path
+func WithRouteFunc(fn func(*http.Request) string) Option {
+	return func(l *Logger) {
+		l.routeFunc = fn
+	}
+}
+
+// Logger logs HTTP requests.
+type Logger struct {
+	client        *tryl.Client
+	userFunc      func(*http.Request) string
+	anonymousUser string
+	include       []string
+	exclude       []string
+	actionFunc    func(Request) string
+	routeFunc     func(*http.Request) string
+}
+
+// New creates a Logger that logs via client.
+func New(client *tryl.Client, opts ...Option) *Logger {
+	l := &Logger{client: client}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Log records a completed request, unless its route is filtered out or it
+// has no user.
+func (l *Logger) Log(ctx context.Context, req Request) {
+	route := req.Route
+	if route == "" {
+		route = req.Path
+	}
+	if !l.matches(route) {
+		return
+	}
+
+	userID := req.UserID
+	if userID == "" && l.userFunc != nil && req.HTTPRequest != nil {
+		userID = l.userFunc(req.HTTPRequest)
+	}
+	if userID == "" {
+		userID = l.anonymousUser
+	}
+	if userID == "" {
+		return
+	}
+
+	action := DefaultAction
+	if l.actionFunc != nil {
+		action = l.actionFunc(req)
+	}
+
+	metadata, _ := json.Marshal(map[string]any{
+		"method":     req.Method,
+		"route":      route,
+		"path":       req.Path,
+		"status":     req.Status,
+		"latency_ms": req.Latency.Milliseconds(),
+		"client_ip":  req.ClientIP,
+	})
+	l.client.LogFireAndForget(context.WithoutCancel(ctx), tryl.Event{
+		UserID:     userID,
+		Action:     action,
+		TargetType: "route",
+		TargetID:   req.Method + " " + route,
+		Metadata:   metadata,
+	})
+}
+
+// Middleware wraps next and logs each request after it is served.
+func (l *Logger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		route := r.URL.Path
+		if l.routeFunc != nil {
+			route = l.routeFunc(r)
+		}
+		l.Log(r.Context(), Request{
+			Method:      r.Method,
+			Route:       route,
+			Path:        r.URL.Path,
+			Status:      rec.status,
+			Latency:     time.Since(start),
+			ClientIP:    clientIP(r),
+			HTTPRequest: r,
+		})
+	})
+}
+
+// matches applies the include and exclude patterns to route.
+func (l *Logger) matches(route string) bool {
+	for _, p := range l.exclude {
+		if matchRoute(p, route) {
+			return false
+		}
+	}
+	if len(l.include) == 0 {
+		return true
+	}
+	for _, p := range l.include {
+		if matchRoute(p, route) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRoute reports whether route matches pattern. A pattern ending in
+// "/**" matches when its prefix matches the route's leading segments.
+func matchRoute(pattern, route string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		head := route
+		segments := strings.Count(prefix, "/")
+		for i, c := range route {
+			if c == '/' {
+				if segments == 0 {
+					head = route[:i]
+					break
+				}
+				segments--
+			}
+		}
+		pattern, route = prefix, head
+	}
+	ok, _ := path.Match(pattern, route)
+	return ok
+}
+
+// statusRecorder captures the response status code.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// clientIP returns the request's remote address without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
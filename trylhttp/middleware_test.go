@@ -0,0 +1,138 @@
+package trylhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+type userKey struct{}
+
+// newLogger returns a Logger whose events are delivered to the channel.
+func newLogger(t *testing.T, opts ...Option) (*Logger, <-chan tryl.Event) {
+	t.Helper()
+
+	events := make(chan tryl.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event tryl.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return New(client, opts...), events
+}
+
+func expectNone(t *testing.T, events <-chan tryl.Event) {
+	t.Helper()
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	logger, events := newLogger(t,
+		WithUserFunc(func(r *http.Request) string {
+			id, _ := r.Context().Value(userKey{}).(string)
+			return id
+		}),
+		WithExclude("/healthz"),
+		WithActionFunc(func(r Request) string {
+			if r.Status >= 400 {
+				return "http.request_failed"
+			}
+			return DefaultAction
+		}))
+
+	handler := logger.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/42", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userKey{}, "user_1"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	select {
+	case event := <-events:
+		var metadata map[string]any
+		json.Unmarshal(event.Metadata, &metadata)
+		if event.UserID != "user_1" || event.Action != "http.request_failed" || event.TargetID != "POST /orders/42" {
+			t.Errorf("event = %+v", event)
+		}
+		if metadata["status"] != 418.0 || metadata["method"] != "POST" || metadata["client_ip"] != "192.0.2.1" {
+			t.Errorf("metadata = %v", metadata)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	// Excluded routes and requests without a user are skipped.
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userKey{}, "user_1"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	expectNone(t, events)
+}
+
+func TestLogger_Log(t *testing.T) {
+	t.Parallel()
+
+	logger, events := newLogger(t, WithInclude("/api/*"), WithAnonymousUser("anonymous"))
+
+	logger.Log(context.Background(), Request{Method: "GET", Route: "/admin", Status: 200})
+	expectNone(t, events)
+
+	logger.Log(context.Background(), Request{
+		Method: "GET", Route: "/api/:id", Path: "/api/7", Status: 200, Latency: 15 * time.Millisecond,
+	})
+	select {
+	case event := <-events:
+		var metadata map[string]any
+		json.Unmarshal(event.Metadata, &metadata)
+		if event.UserID != "anonymous" || event.TargetID != "GET /api/:id" || metadata["latency_ms"] != 15.0 {
+			t.Errorf("event = %+v, metadata = %v", event, metadata)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
+
+func TestMatchRoute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern, route string
+		want           bool
+	}{
+		{"/api/*", "/api/users", true},
+		{"/api/*", "/api/users/:id", false},
+		{"/api/**", "/api/users/:id", true},
+		{"/api/**", "/api", true},
+		{"/api/**", "/apiv2/users", false},
+		{"/*/admin/**", "/v1/admin/users", true},
+		{"/**", "/anything/at/all", true},
+		{"/healthz", "/healthz", true},
+	}
+	for _, tt := range tests {
+		if got := matchRoute(tt.pattern, tt.route); got != tt.want {
+			t.Errorf("matchRoute(%q, %q) = %v, want %v", tt.pattern, tt.route, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package tryljob standardizes audit trails for background jobs.
+//
+// An Auditor wraps job execution and logs job.started, then job.completed
+// or job.failed, with the job type, ID, attempt, and duration. Events are
+// sent with Client.LogFireAndForget, so auditing never delays or fails a
+// job.
+//
+// Wrap the job handler in the worker's middleware:
+//
+//	job := tryljob.Job{Type: "invoice.send", ID: taskID, Attempt: attempt, Queue: "default"}
+//	err := auditor.Run(ctx, job, func(ctx context.Context) error {
+//	    return sendInvoice(ctx, payload)
+//	})
+package tryljob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// Actions logged by an Auditor.
+const (
+	ActionStarted   = "job.started"
+	ActionCompleted = "job.completed"
+	ActionFailed    = "job.failed"
+)
+
+// Job identifies a unit of background work.
+type Job struct {
+	// Type is the job or task type (e.g., "email:welcome").
+	Type string
+	// ID is the job's unique ID, used as the event target.
+	ID string
+	// Attempt is the 1-based attempt number.
+	Attempt int
+	// Queue is the queue or task queue name. Optional.
+	Queue string
+	// UserID is the user the job acts for. If empty, the Auditor's user
+	// function or default user is used.
+	UserID string
+}
+
+// Option configures an Auditor.
+type Option func(*Auditor)
+
+// WithUserFunc sets how the acting user is read from the job's context
+// when Job.UserID is empty.
+func WithUserFunc(fn func(ctx context.Context) string) Option {
+	return func(a *Auditor) {
+		a.userFunc = fn
+	}
+}
+
+// WithDefaultUser sets the user ID for jobs with no user.
+// Default: "system"
+func WithDefaultUser(userID string) Option {
+	return func(a *Auditor) {
+		a.defaultUser = userID
+	}
+}
+
+// WithoutStartEvents disables job.started events; only outcomes are logged.
+func WithoutStartEvents() Option {
+	return func(a *Auditor) {
+		a.skipStart = true
+	}
+}
+
+// Auditor logs job lifecycle events.
+type Auditor struct {
+	client      *tryl.Client
+	userFunc    func(ctx context.Context) string
+	defaultUser string
+	skipStart   bool
+}
+
+// New creates an Auditor that logs via client.
+func New(client *tryl.Client, opts ...Option) *Auditor {
+	a := &Auditor{client: client, defaultUser: "system"}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Run logs the start of job, calls fn, and logs its outcome. It returns
+// fn's error. A panic in fn is logged as a failure and re-raised.
+func (a *Auditor) Run(ctx context.Context, job Job, fn func(ctx context.Context) error) (err error) {
+	run := a.Start(ctx, job)
+	defer func() {
+		if r := recover(); r != nil {
+			run.End(fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+		run.End(err)
+	}()
+	return fn(ctx)
+}
+
+// Start logs the start of job and returns a Run to record its outcome,
+// for frameworks where the job body cannot be wrapped in a function.
+func (a *Auditor) Start(ctx context.Context, job Job) *Run {
+	run := &Run{auditor: a, ctx: ctx, job: job, start: time.Now()}
+	if !a.skipStart {
+		a.log(ctx, job, ActionStarted, nil)
+	}
+	return run
+}
+
+// Run is a job in progress.
+type Run struct {
+	auditor *Auditor
+	ctx     context.Context
+	job     Job
+	start   time.Time
+	ended   bool
+}
+
+// End logs job.completed if err is nil and job.failed otherwise. Calls
+// after the first are ignored.
+func (r *Run) End(err error) {
+	if r.ended {
+		return
+	}
+	r.ended = true
+
+	extra := map[string]any{"duration_ms": time.Since(r.start).Milliseconds()}
+	action := ActionCompleted
+	if err != nil {
+		action = ActionFailed
+		extra["error"] = err.Error()
+	}
+	r.auditor.log(r.ctx, r.job, action, extra)
+}
+
+// log sends one lifecycle event.
+func (a *Auditor) log(ctx context.Context, job Job, action string, extra map[string]any) {
+	userID := job.UserID
+	if userID == "" && a.userFunc != nil {
+		userID = a.userFunc(ctx)
+	}
+	if userID == "" {
+		userID = a.defaultUser
+	}
+
+	fields := map[string]any{"job_type": job.Type, "attempt": job.Attempt}
+	if job.Queue != "" {
+		fields["queue"] = job.Queue
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	metadata, err := json.Marshal(fields)
+	if err != nil {
+		return
+	}
+
+	a.client.LogFireAndForget(context.WithoutCancel(ctx), tryl.Event{
+		UserID:     userID,
+		Action:     action,
+		TargetType: "job",
+		TargetID:   job.ID,
+		Metadata:   metadata,
+	})
+}
@@ -0,0 +1,125 @@
+package tryljob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+func newAuditor(t *testing.T, opts ...Option) (*Auditor, <-chan tryl.Event) {
+	t.Helper()
+
+	events := make(chan tryl.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event tryl.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return New(client, opts...), events
+}
+
+// collect receives n events, keyed by action.
+func collect(t *testing.T, events <-chan tryl.Event, n int) map[string]tryl.Event {
+	t.Helper()
+	out := map[string]tryl.Event{}
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-events:
+			out[e.Action] = e
+		case <-time.After(time.Second):
+			t.Fatalf("timed out after %d of %d events", i, n)
+		}
+	}
+	return out
+}
+
+func TestAuditor_Run(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t)
+	job := Job{Type: "email:welcome", ID: "job_1", Attempt: 2, Queue: "critical"}
+
+	wantErr := errors.New("smtp unavailable")
+	err := auditor.Run(context.Background(), job, func(ctx context.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("Run() error = %v, want %v", err, wantErr)
+	}
+
+	got := collect(t, events, 2)
+	if _, ok := got[ActionStarted]; !ok {
+		t.Error("missing job.started event")
+	}
+	failed, ok := got[ActionFailed]
+	if !ok {
+		t.Fatalf("missing job.failed event; got %v", got)
+	}
+	var metadata map[string]any
+	json.Unmarshal(failed.Metadata, &metadata)
+	if failed.TargetID != "job_1" || failed.UserID != "system" {
+		t.Errorf("failed event = %+v", failed)
+	}
+	if metadata["job_type"] != "email:welcome" || metadata["attempt"] != 2.0 || metadata["queue"] != "critical" ||
+		metadata["error"] != "smtp unavailable" || metadata["duration_ms"].(float64) < 5 {
+		t.Errorf("metadata = %v", metadata)
+	}
+}
+
+func TestAuditor_Run_Panic(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t, WithoutStartEvents())
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Run() did not re-raise the panic")
+			}
+		}()
+		auditor.Run(context.Background(), Job{Type: "report", ID: "job_2", UserID: "user_1"}, func(ctx context.Context) error {
+			panic("boom")
+		})
+	}()
+
+	got := collect(t, events, 1)
+	failed, ok := got[ActionFailed]
+	if !ok || failed.UserID != "user_1" {
+		t.Errorf("events = %v, want a single job.failed for user_1", got)
+	}
+}
+
+func TestRun_End(t *testing.T) {
+	t.Parallel()
+
+	auditor, events := newAuditor(t, WithoutStartEvents())
+	run := auditor.Start(context.Background(), Job{Type: "sync", ID: "job_3"})
+	run.End(nil)
+	run.End(errors.New("ignored"))
+
+	got := collect(t, events, 1)
+	if _, ok := got[ActionCompleted]; !ok {
+		t.Errorf("events = %v, want job.completed", got)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
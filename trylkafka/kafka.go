@@ -0,0 +1,239 @@
+// Package trylkafka forwards events from a Kafka topic to the Activity
+// Logger, so an existing event bus can feed the audit log without custom
+// glue.
+//
+// A Bridge reads messages from a Consumer, decodes each JSON payload into a
+// tryl.Event, and sends them in batches with Client.LogMany. Offsets are committed only
+// after the batch has been acknowledged, so a crash or a failed request
+// redelivers the messages (at-least-once delivery). Each event without an
+// IdempotencyKey is given one derived from its topic, partition and offset,
+// so the API discards a redelivered message it already stored. Messages that can never
+// be ingested, such as malformed payloads or events rejected by the API, are
+// reported to the error handler and committed so they do not block the
+// partition.
+//
+// Usage, with reader adapting the Kafka client to Consumer:
+//
+//	bridge := trylkafka.NewBridge(client, reader,
+//	    trylkafka.WithBatchSize(100),
+//	    trylkafka.WithErrorHandler(func(m trylkafka.Message, err error) {
+//	        log.Printf("dropped %s/%d@%d: %v", m.Topic, m.Partition, m.Offset, err)
+//	    }))
+//	err := bridge.Run(ctx) // blocks until ctx is canceled
+package trylkafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// Message is a Kafka message.
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	// Raw holds the client library's own message value, for adapters that
+	// need it to commit.
+	Raw any
+}
+
+// Consumer reads and commits Kafka messages. Its methods mirror the
+// kafka-go Reader: FetchMessage blocks until a message is available or ctx
+// is done, and CommitMessages marks messages as processed.
+type Consumer interface {
+	FetchMessage(ctx context.Context) (Message, error)
+	CommitMessages(ctx context.Context, msgs ...Message) error
+}
+
+// Decoder converts a message into an event.
+type Decoder func(Message) (tryl.Event, error)
+
+// DecodeJSON decodes the message value as a JSON tryl.Event.
+// It is the default Decoder.
+func DecodeJSON(m Message) (tryl.Event, error) {
+	var event tryl.Event
+	if err := json.Unmarshal(m.Value, &event); err != nil {
+		return tryl.Event{}, fmt.Errorf("trylkafka: failed to decode message: %w", err)
+	}
+	return event, nil
+}
+
+// Option configures a Bridge.
+type Option func(*Bridge)
+
+// WithBatchSize sets the maximum number of messages buffered before they
+// are sent and committed.
+// Default: 100
+func WithBatchSize(n int) Option {
+	return func(b *Bridge) {
+		if n > 0 {
+			b.batchSize = n
+		}
+	}
+}
+
+// WithFlushInterval sets how long a partial batch waits for more messages
+// before it is sent.
+// Default: 1s
+func WithFlushInterval(d time.Duration) Option {
+	return func(b *Bridge) {
+		if d > 0 {
+			b.flushInterval = d
+		}
+	}
+}
+
+// WithDecoder sets how messages are converted into events.
+// Default: DecodeJSON
+func WithDecoder(d Decoder) Option {
+	return func(b *Bridge) {
+		b.decode = d
+	}
+}
+
+// WithErrorHandler sets a function called for each message that is
+// skipped because it could not be decoded or was rejected by the API.
+func WithErrorHandler(fn func(Message, error)) Option {
+	return func(b *Bridge) {
+		b.onError = fn
+	}
+}
+
+// Bridge forwards messages from a Consumer to the Activity Logger.
+type Bridge struct {
+	client        *tryl.Client
+	consumer      Consumer
+	batchSize     int
+	flushInterval time.Duration
+	decode        Decoder
+	onError       func(Message, error)
+
+	// pending holds every fetched, uncommitted message; decoded and events
+	// hold the messages that decoded and their events, index-aligned.
+	pending []Message
+	decoded []Message
+	events  []tryl.Event
+}
+
+// NewBridge creates a Bridge that reads from consumer and logs via client.
+func NewBridge(client *tryl.Client, consumer Consumer, opts ...Option) *Bridge {
+	b := &Bridge{
+		client:        client,
+		consumer:      consumer,
+		batchSize:     100,
+		flushInterval: time.Second,
+		decode:        DecodeJSON,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Run forwards messages until ctx is canceled or an error occurs. Messages
+// of a batch that could not be sent are left uncommitted and the error is
+// returned; the Bridge keeps the batch and sends it first when Run is
+// called again. When ctx is canceled, Run returns ctx.Err() without sending
+// the partial batch.
+func (b *Bridge) Run(ctx context.Context) error {
+	if err := b.flush(ctx); err != nil {
+		return err
+	}
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, b.flushInterval)
+		msg, err := b.consumer.FetchMessage(fetchCtx)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				return fmt.Errorf("trylkafka: failed to fetch message: %w", err)
+			}
+			// The flush interval elapsed without a new message.
+			if err := b.flush(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b.pending = append(b.pending, msg)
+		if event, err := b.decode(msg); err != nil {
+			b.reject(msg, err)
+		} else {
+			if event.IdempotencyKey == "" {
+				event.IdempotencyKey = messageKey(msg)
+			}
+			b.decoded = append(b.decoded, msg)
+			b.events = append(b.events, event)
+		}
+		if len(b.pending) >= b.batchSize {
+			if err := b.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// flush sends buffered events and commits every buffered message once the
+// batch is acknowledged. If some events failed with a retryable error, only
+// those are kept for the next flush.
+func (b *Bridge) flush(ctx context.Context) error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+
+	if len(b.events) > 0 {
+		report, err := b.client.LogMany(ctx, b.events)
+		if err != nil {
+			return fmt.Errorf("trylkafka: failed to log batch: %w", err)
+		}
+		var retryErr error
+		var decoded []Message
+		var events []tryl.Event
+		for _, item := range report.Failed() {
+			if tryl.IsPermanent(item.Error) {
+				b.reject(b.decoded[item.Index], item.Error)
+				continue
+			}
+			if retryErr == nil {
+				retryErr = item.Error
+			}
+			decoded = append(decoded, b.decoded[item.Index])
+			events = append(events, b.events[item.Index])
+		}
+		if retryErr != nil {
+			b.decoded, b.events = decoded, events
+			return fmt.Errorf("trylkafka: failed to log batch: %w", retryErr)
+		}
+	}
+
+	if err := b.consumer.CommitMessages(ctx, b.pending...); err != nil {
+		return fmt.Errorf("trylkafka: failed to commit offsets: %w", err)
+	}
+	b.pending = b.pending[:0]
+	b.decoded = b.decoded[:0]
+	b.events = b.events[:0]
+	return nil
+}
+
+// messageKey derives an idempotency key that is the same each time m is
+// delivered.
+func messageKey(m Message) string {
+	return fmt.Sprintf("kafka-%s-%d-%d", m.Topic, m.Partition, m.Offset)
+}
+
+// reject reports a message that will be committed without being logged.
+func (b *Bridge) reject(m Message, err error) {
+	if b.onError != nil {
+		b.onError(m, err)
+	}
+}
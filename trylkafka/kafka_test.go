@@ -0,0 +1,232 @@
+package trylkafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+// fakeConsumer serves queued messages and records commits.
+type fakeConsumer struct {
+	mu        sync.Mutex
+	queue     []Message
+	committed []int64
+}
+
+func (c *fakeConsumer) FetchMessage(ctx context.Context) (Message, error) {
+	c.mu.Lock()
+	if len(c.queue) > 0 {
+		m := c.queue[0]
+		c.queue = c.queue[1:]
+		c.mu.Unlock()
+		return m, nil
+	}
+	c.mu.Unlock()
+	<-ctx.Done()
+	return Message{}, ctx.Err()
+}
+
+func (c *fakeConsumer) CommitMessages(ctx context.Context, msgs ...Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range msgs {
+		c.committed = append(c.committed, m.Offset)
+	}
+	return nil
+}
+
+func (c *fakeConsumer) commits() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64(nil), c.committed...)
+}
+
+func messages(values ...string) []Message {
+	msgs := make([]Message, len(values))
+	for i, v := range values {
+		msgs[i] = Message{Topic: "audit", Offset: int64(i), Value: []byte(v)}
+	}
+	return msgs
+}
+
+// batchServer accepts every event, or fails with 503 while failing is set.
+func batchServer(t *testing.T, failing *atomic.Bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing != nil && failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":"unavailable","message":"try later"}}`))
+			return
+		}
+		var req struct {
+			Events []tryl.Event `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		received.Add(int32(len(req.Events)))
+
+		results := make([]tryl.EventResponse, len(req.Events))
+		for i := range results {
+			results[i] = tryl.EventResponse{ID: "evt_1", Timestamp: time.Now()}
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestBridge_CommitsAfterAck(t *testing.T) {
+	t.Parallel()
+
+	server, received := batchServer(t, nil)
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	consumer := &fakeConsumer{queue: messages(
+		`{"user_id":"user_1","action":"doc.created"}`,
+		`not json`,
+		`{"user_id":"user_3","action":"Not Valid"}`,
+		`{"user_id":"user_4","action":"doc.deleted"}`,
+	)}
+
+	var mu sync.Mutex
+	var rejected []int64
+	bridge := NewBridge(client, consumer,
+		WithBatchSize(4),
+		WithErrorHandler(func(m Message, err error) {
+			mu.Lock()
+			rejected = append(rejected, m.Offset)
+			mu.Unlock()
+		}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := bridge.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run() error = %v, want context deadline", err)
+	}
+
+	if got := received.Load(); got != 2 {
+		t.Errorf("server received %d events, want 2", got)
+	}
+	if got := consumer.commits(); len(got) != 4 {
+		t.Errorf("committed offsets = %v, want all 4", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(rejected) != 2 || rejected[0] != 1 || rejected[1] != 2 {
+		t.Errorf("rejected offsets = %v, want [1 2]", rejected)
+	}
+}
+
+func TestBridge_NoCommitOnFailure(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	server, received := batchServer(t, &failing)
+	client, err := tryl.NewClient(testAPIKey,
+		tryl.WithBaseURL(server.URL),
+		tryl.WithRetry(tryl.RetryConfig{MaxAttempts: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	consumer := &fakeConsumer{queue: messages(`{"user_id":"user_1","action":"doc.created"}`)}
+	bridge := NewBridge(client, consumer, WithFlushInterval(10*time.Millisecond))
+
+	if err := bridge.Run(context.Background()); err == nil {
+		t.Fatal("Run() succeeded, want error while the API is unavailable")
+	}
+	if got := consumer.commits(); len(got) != 0 {
+		t.Fatalf("committed offsets = %v before the batch was acknowledged", got)
+	}
+
+	// The kept batch is sent first on the next run.
+	failing.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	bridge.Run(ctx)
+	if got := consumer.commits(); len(got) != 1 || received.Load() != 1 {
+		t.Errorf("after recovery committed %v, received %d; want offset 0 and 1 event", got, received.Load())
+	}
+}
+
+func TestBridge_PartialFailureResendsOnlyFailed(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := make(map[string]int)
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Events []tryl.Event `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		// The first request stores every event but the second.
+		var errs []map[string]any
+		results := make([]tryl.EventResponse, len(req.Events))
+		for i, event := range req.Events {
+			if requests == 1 && i == 1 {
+				errs = append(errs, map[string]any{"index": i, "code": tryl.ErrCodeInternalError, "message": "try later"})
+				continue
+			}
+			stored[event.IdempotencyKey]++
+			results[i] = tryl.EventResponse{ID: "evt_1", Timestamp: time.Now()}
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(map[string]any{"results": results, "errors": errs})
+	}))
+	defer server.Close()
+
+	client, err := tryl.NewClient(testAPIKey,
+		tryl.WithBaseURL(server.URL),
+		tryl.WithRetry(tryl.RetryConfig{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	consumer := &fakeConsumer{queue: messages(
+		`{"user_id":"user_1","action":"doc.created"}`,
+		`{"user_id":"user_2","action":"doc.created"}`,
+	)}
+	bridge := NewBridge(client, consumer, WithBatchSize(2))
+
+	if err := bridge.Run(context.Background()); err == nil {
+		t.Fatal("Run() succeeded, want error for the failed item")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	bridge.Run(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(stored) != 2 {
+		t.Errorf("server stored %d distinct events, want 2", len(stored))
+	}
+	for key, n := range stored {
+		if key == "" || n != 1 {
+			t.Errorf("event with key %q stored %d times, want once with a key", key, n)
+		}
+	}
+	if got := consumer.commits(); len(got) != 2 {
+		t.Errorf("committed offsets = %v, want both", got)
+	}
+}
@@ -0,0 +1,317 @@
+// Package tryloutbox implements the transactional outbox pattern for the
+// Activity Logger.
+//
+// Events are written to a local tryl_outbox table inside the caller's
+// database transaction, so they exist only if the business transaction
+// commits. A Relay drains the table in the background and sends the events
+// with Client.LogMany, deleting rows once the API has acknowledged them.
+// Delivery is at-least-once: a crash between sending and deleting resends
+// the rows. Write assigns each event an IdempotencyKey so the API can
+// discard the duplicate.
+//
+// The package uses only database/sql; bring your own driver.
+//
+// Usage:
+//
+//	outbox := tryloutbox.New(db, tryloutbox.WithDialect(tryloutbox.Postgres))
+//	if err := outbox.CreateTable(ctx); err != nil {
+//	    return err
+//	}
+//
+//	tx, _ := db.BeginTx(ctx, nil)
+//	// ... business writes ...
+//	outbox.Write(ctx, tx, tryl.Event{UserID: "user_123", Action: "order.placed"})
+//	tx.Commit()
+//
+//	relay := tryloutbox.NewRelay(client, outbox)
+//	go relay.Run(ctx)
+package tryloutbox
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// DefaultTable is the default outbox table name.
+const DefaultTable = "tryl_outbox"
+
+// Dialect selects SQL syntax for a database.
+type Dialect int
+
+const (
+	// Postgres uses $1 placeholders and locks rows with FOR UPDATE SKIP LOCKED,
+	// so several relays can drain one table.
+	Postgres Dialect = iota
+	// MySQL uses ? placeholders and locks rows with FOR UPDATE SKIP LOCKED
+	// (MySQL 8.0+).
+	MySQL
+	// SQLite uses ? placeholders. SQLite has no row locks; run a single relay.
+	SQLite
+)
+
+// Execer executes statements. *sql.Tx, *sql.DB, and *sql.Conn implement it.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Option configures an Outbox.
+type Option func(*Outbox)
+
+// WithTable sets the outbox table name.
+// Default: "tryl_outbox"
+func WithTable(name string) Option {
+	return func(o *Outbox) {
+		o.table = name
+	}
+}
+
+// WithDialect sets the SQL dialect.
+// Default: Postgres
+func WithDialect(d Dialect) Option {
+	return func(o *Outbox) {
+		o.dialect = d
+	}
+}
+
+// Outbox writes events to an outbox table.
+type Outbox struct {
+	db      *sql.DB
+	table   string
+	dialect Dialect
+}
+
+// New creates an Outbox backed by db.
+func New(db *sql.DB, opts ...Option) *Outbox {
+	o := &Outbox{db: db, table: DefaultTable, dialect: Postgres}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// CreateTable creates the outbox table if it does not exist.
+func (o *Outbox) CreateTable(ctx context.Context) error {
+	id := "BIGSERIAL PRIMARY KEY"
+	switch o.dialect {
+	case MySQL:
+		id = "BIGINT AUTO_INCREMENT PRIMARY KEY"
+	case SQLite:
+		id = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id %s, payload TEXT NOT NULL, created_at TIMESTAMP NOT NULL)", o.table, id)
+	if _, err := o.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("tryloutbox: failed to create table: %w", err)
+	}
+	return nil
+}
+
+// Write stores events in the outbox using tx, which should be the caller's
+// business transaction. The events are sent only after tx commits.
+func (o *Outbox) Write(ctx context.Context, tx Execer, events ...tryl.Event) error {
+	query := fmt.Sprintf("INSERT INTO %s (payload, created_at) VALUES (%s, %s)", o.table, o.placeholder(1), o.placeholder(2))
+	now := time.Now().UTC()
+	for _, event := range events {
+		if event.IdempotencyKey == "" {
+			event.IdempotencyKey = newKey()
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("tryloutbox: failed to encode event: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, string(payload), now); err != nil {
+			return fmt.Errorf("tryloutbox: failed to write event: %w", err)
+		}
+	}
+	return nil
+}
+
+// newKey returns a random 128-bit hex idempotency key.
+func newKey() string {
+	var buf [16]byte
+	rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// placeholder returns the n-th (1-based) bind parameter.
+func (o *Outbox) placeholder(n int) string {
+	if o.dialect == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// RelayOption configures a Relay.
+type RelayOption func(*Relay)
+
+// WithBatchSize sets the maximum number of rows read per drain.
+// Default: 100
+func WithBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithPollInterval sets how long Run waits after finding an empty outbox.
+// Default: 1s
+func WithPollInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		if d > 0 {
+			r.pollInterval = d
+		}
+	}
+}
+
+// WithErrorHandler sets a function called for each event the API rejects
+// permanently. Such rows are deleted so they do not block the outbox.
+func WithErrorHandler(fn func(tryl.Event, error)) RelayOption {
+	return func(r *Relay) {
+		r.onError = fn
+	}
+}
+
+// Relay drains an outbox table into the Activity Logger.
+type Relay struct {
+	client       *tryl.Client
+	outbox       *Outbox
+	batchSize    int
+	pollInterval time.Duration
+	onError      func(tryl.Event, error)
+}
+
+// NewRelay creates a Relay that sends events from outbox via client.
+func NewRelay(client *tryl.Client, outbox *Outbox, opts ...RelayOption) *Relay {
+	r := &Relay{
+		client:       client,
+		outbox:       outbox,
+		batchSize:    100,
+		pollInterval: time.Second,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run drains the outbox until ctx is canceled, waiting the poll interval
+// whenever the outbox is empty or a drain fails. Drain errors are retried;
+// Run returns only ctx.Err().
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.Drain(ctx)
+		if err == nil && n == r.batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+// Drain sends up to one batch of outbox rows and deletes the rows that were
+// acknowledged or permanently rejected. It returns the number of rows
+// removed. If any event fails with a retryable error, nothing is deleted
+// and the error is returned; the rows are sent again on the next drain.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	o := r.outbox
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("tryloutbox: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("SELECT id, payload FROM %s ORDER BY id LIMIT %d", o.table, r.batchSize)
+	if o.dialect != SQLite {
+		query += " FOR UPDATE SKIP LOCKED"
+	}
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("tryloutbox: failed to read outbox: %w", err)
+	}
+	var ids []int64
+	var events []tryl.Event
+	var done []int64
+	for rows.Next() {
+		var id int64
+		var payload string
+		if err := rows.Scan(&id, &payload); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("tryloutbox: failed to read outbox: %w", err)
+		}
+		var event tryl.Event
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			r.reject(event, fmt.Errorf("tryloutbox: failed to decode row %d: %w", id, err))
+			done = append(done, id)
+			continue
+		}
+		ids = append(ids, id)
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("tryloutbox: failed to read outbox: %w", err)
+	}
+
+	if len(events) > 0 {
+		report, err := r.client.LogMany(ctx, events)
+		if err != nil {
+			return 0, err
+		}
+		failed := report.Failed()
+		for _, item := range failed {
+			if !tryl.IsPermanent(item.Error) {
+				return 0, fmt.Errorf("tryloutbox: failed to send events: %w", item.Error)
+			}
+		}
+		for _, item := range failed {
+			r.reject(events[item.Index], item.Error)
+		}
+		done = append(done, ids...)
+	}
+
+	if len(done) == 0 {
+		return 0, nil
+	}
+	if err := r.delete(ctx, tx, done); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("tryloutbox: failed to commit: %w", err)
+	}
+	return len(done), nil
+}
+
+// delete removes rows by ID.
+func (r *Relay) delete(ctx context.Context, tx *sql.Tx, ids []int64) error {
+	o := r.outbox
+	marks := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		marks[i] = o.placeholder(i + 1)
+		args[i] = id
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", o.table, strings.Join(marks, ", "))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("tryloutbox: failed to delete sent rows: %w", err)
+	}
+	return nil
+}
+
+// reject reports an event that is removed without being logged.
+func (r *Relay) reject(event tryl.Event, err error) {
+	if r.onError != nil {
+		r.onError(event, err)
+	}
+}
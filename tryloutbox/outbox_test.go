@@ -0,0 +1,304 @@
+package tryloutbox
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+// memDB is a tiny in-memory database/sql driver that understands exactly the
+// statements tryloutbox issues. Transactions snapshot the table and restore
+// it on rollback.
+type memDB struct {
+	mu     sync.Mutex
+	rows   map[int64]string
+	nextID int64
+}
+
+var dbCount atomic.Int32
+
+func openMemDB(t *testing.T) (*sql.DB, *memDB) {
+	t.Helper()
+	mem := &memDB{rows: map[int64]string{}}
+	name := fmt.Sprintf("tryloutbox_mem_%d", dbCount.Add(1))
+	sql.Register(name, mem)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, mem
+}
+
+func (m *memDB) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.rows)
+}
+
+func (m *memDB) Open(string) (driver.Conn, error) { return &memConn{db: m}, nil }
+
+type memConn struct {
+	db       *memDB
+	snapshot map[int64]string
+}
+
+func (c *memConn) Prepare(query string) (driver.Stmt, error) { return &memStmt{c, query}, nil }
+func (c *memConn) Close() error                              { return nil }
+
+func (c *memConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = make(map[int64]string, len(c.db.rows))
+	for k, v := range c.db.rows {
+		c.snapshot[k] = v
+	}
+	return c, nil
+}
+
+func (c *memConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *memConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.rows = c.snapshot
+	c.snapshot = nil
+	return nil
+}
+
+type memStmt struct {
+	conn  *memConn
+	query string
+}
+
+func (s *memStmt) Close() error  { return nil }
+func (s *memStmt) NumInput() int { return -1 }
+
+func (s *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT"):
+		db.nextID++
+		db.rows[db.nextID] = args[0].(string)
+	case strings.HasPrefix(s.query, "DELETE"):
+		for _, id := range args {
+			delete(db.rows, id.(int64))
+		}
+	default:
+		return nil, fmt.Errorf("unexpected exec %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.HasPrefix(s.query, "SELECT id, payload FROM") {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	ids := make([]int64, 0, len(db.rows))
+	for id := range db.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	rows := &memRows{}
+	for _, id := range ids {
+		rows.data = append(rows.data, []driver.Value{id, db.rows[id]})
+	}
+	return rows, nil
+}
+
+type memRows struct {
+	data [][]driver.Value
+}
+
+func (r *memRows) Columns() []string { return []string{"id", "payload"} }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.data) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.data[0])
+	r.data = r.data[1:]
+	return nil
+}
+
+// ingestServer accepts every event, or fails with 503 while failing is set.
+func ingestServer(t *testing.T, failing *atomic.Bool) (*httptest.Server, func() []tryl.Event) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var received []tryl.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":"unavailable","message":"try later"}}`))
+			return
+		}
+		var req struct {
+			Events []tryl.Event `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		received = append(received, req.Events...)
+		mu.Unlock()
+
+		results := make([]tryl.EventResponse, len(req.Events))
+		for i := range results {
+			results[i] = tryl.EventResponse{ID: "evt_1", Timestamp: time.Now()}
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []tryl.Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]tryl.Event(nil), received...)
+	}
+}
+
+func TestOutbox_OnlyCommittedEventsAreSent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, mem := openMemDB(t)
+	outbox := New(db, WithDialect(SQLite))
+	if err := outbox.CreateTable(ctx); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	tx, _ := db.BeginTx(ctx, nil)
+	if err := outbox.Write(ctx, tx, tryl.Event{UserID: "user_1", Action: "order.placed"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	tx.Commit()
+
+	tx, _ = db.BeginTx(ctx, nil)
+	outbox.Write(ctx, tx, tryl.Event{UserID: "user_2", Action: "order.placed"})
+	tx.Rollback()
+
+	var failing atomic.Bool
+	server, received := ingestServer(t, &failing)
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	n, err := NewRelay(client, outbox).Drain(ctx)
+	if err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if n != 1 || mem.count() != 0 {
+		t.Errorf("Drain() = %d with %d rows left, want 1 and 0", n, mem.count())
+	}
+	events := received()
+	if len(events) != 1 || events[0].UserID != "user_1" || events[0].IdempotencyKey == "" {
+		t.Errorf("received %+v, want only user_1 with an idempotency key", events)
+	}
+}
+
+func TestRelay_Drain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, mem := openMemDB(t)
+	outbox := New(db, WithDialect(SQLite))
+	outbox.Write(ctx, db,
+		tryl.Event{UserID: "user_1", Action: "order.placed"},
+		tryl.Event{UserID: "user_2", Action: "Not Valid"})
+
+	var failing atomic.Bool
+	failing.Store(true)
+	server, received := ingestServer(t, &failing)
+	client, err := tryl.NewClient(testAPIKey,
+		tryl.WithBaseURL(server.URL),
+		tryl.WithRetry(tryl.RetryConfig{MaxAttempts: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var rejected []tryl.Event
+	relay := NewRelay(client, outbox, WithErrorHandler(func(e tryl.Event, err error) {
+		rejected = append(rejected, e)
+	}))
+
+	if _, err := relay.Drain(ctx); err == nil {
+		t.Fatal("Drain() succeeded, want error while the API is unavailable")
+	}
+	if mem.count() != 2 {
+		t.Fatalf("%d rows left after failed drain, want 2", mem.count())
+	}
+
+	failing.Store(false)
+	n, err := relay.Drain(ctx)
+	if err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if n != 2 || mem.count() != 0 {
+		t.Errorf("Drain() = %d with %d rows left, want 2 and 0", n, mem.count())
+	}
+	if len(received()) != 1 {
+		t.Errorf("received %d events, want 1", len(received()))
+	}
+	if len(rejected) != 1 || rejected[0].UserID != "user_2" {
+		t.Errorf("rejected = %+v, want the invalid event", rejected)
+	}
+}
+
+func TestRelay_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	db, mem := openMemDB(t)
+	outbox := New(db, WithDialect(SQLite))
+	outbox.Write(ctx, db, tryl.Event{UserID: "user_1", Action: "order.placed"})
+
+	var failing atomic.Bool
+	server, _ := ingestServer(t, &failing)
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- NewRelay(client, outbox, WithPollInterval(5*time.Millisecond)).Run(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for mem.count() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	if mem.count() != 0 {
+		t.Error("Run() did not drain the outbox")
+	}
+}
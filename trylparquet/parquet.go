@@ -0,0 +1,467 @@
+// Package trylparquet exports Activity Logger events as Parquet files for
+// analytics pipelines, data lakes, and tools such as DuckDB.
+//
+// Each StoredEvent becomes a row with the columns id, user_id, action,
+// actor_id, target_type, target_id, schema_version, timestamp (microsecond
+// UTC timestamp), and metadata (the raw JSON). Metadata is also flattened
+// into one column per leaf key, named "metadata_" followed by the key path
+// joined with underscores ({"geo":{"city":"Paris"}} becomes
+// metadata_geo_city). Flattened columns are DOUBLE when every value is a
+// number, BOOLEAN when every value is a boolean, and UTF-8 strings
+// otherwise; arrays are stored as JSON text.
+//
+// Files are written uncompressed with a single row group, so a Writer
+// holds all rows in memory until Close. Split very large exports across
+// several files.
+//
+// Usage:
+//
+//	f, err := os.Create("events.parquet")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	n, err := trylparquet.Export(ctx, client, tryl.EventFilter{Action: "order.*"}, f)
+package trylparquet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// Parquet physical types.
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+)
+
+// Parquet converted types.
+const (
+	convertedNone            = -1
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+)
+
+// Parquet encodings.
+const (
+	encodingPlain = 0
+	encodingRLE   = 3
+)
+
+const (
+	magic          = "PAR1"
+	repOptional    = 1
+	pageTypeData   = 0
+	codecNone      = 0
+	metadataPrefix = "metadata_"
+)
+
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("trylparquet: writer is closed")
+
+// Option configures a Writer.
+type Option func(*Writer)
+
+// WithMetadataColumns limits flattened metadata columns to the given key
+// paths (e.g., "amount", "geo_city"), in the given order. By default every
+// key found in the written events gets a column.
+func WithMetadataColumns(keys ...string) Option {
+	return func(w *Writer) {
+		w.metadataKeys = keys
+	}
+}
+
+// Writer buffers events and writes them as a Parquet file on Close.
+type Writer struct {
+	out          io.Writer
+	metadataKeys []string
+	events       []tryl.StoredEvent
+	metadata     []map[string]any
+	closed       bool
+}
+
+// NewWriter returns a Writer that writes a Parquet file to out.
+func NewWriter(out io.Writer, opts ...Option) *Writer {
+	w := &Writer{out: out}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write adds events to the file.
+func (w *Writer) Write(events ...tryl.StoredEvent) error {
+	if w.closed {
+		return ErrClosed
+	}
+	for _, e := range events {
+		flat := map[string]any{}
+		if len(e.Metadata) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(e.Metadata))
+			dec.UseNumber()
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				return fmt.Errorf("trylparquet: event %s has invalid metadata: %w", e.ID, err)
+			}
+			flatten("", v, flat)
+		}
+		w.events = append(w.events, e)
+		w.metadata = append(w.metadata, flat)
+	}
+	return nil
+}
+
+// Close writes the Parquet file. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+
+	columns := w.columns()
+	numRows := int64(len(w.events))
+
+	var file bytes.Buffer
+	file.WriteString(magic)
+
+	chunks := make([]chunkInfo, len(columns))
+	for i, col := range columns {
+		header, data := col.encodePage()
+		chunks[i] = chunkInfo{
+			offset: int64(file.Len()),
+			size:   int64(len(header) + len(data)),
+		}
+		file.Write(header)
+		file.Write(data)
+	}
+
+	footer := fileMetadata(columns, chunks, numRows)
+	file.Write(footer)
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer))))
+	file.WriteString(magic)
+
+	if _, err := w.out.Write(file.Bytes()); err != nil {
+		return fmt.Errorf("trylparquet: failed to write file: %w", err)
+	}
+	return nil
+}
+
+// Export writes every event matching filter to out as a Parquet file,
+// paging through results with cursors. It returns the number of events
+// written.
+func Export(ctx context.Context, client *tryl.Client, filter tryl.EventFilter, out io.Writer, opts ...Option) (int, error) {
+	if filter.Limit == 0 {
+		filter.Limit = 100
+	}
+
+	w := NewWriter(out, opts...)
+	for {
+		page, err := client.List(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		if err := w.Write(page.Events...); err != nil {
+			return 0, err
+		}
+		if !page.HasMore || page.NextCursor == "" {
+			break
+		}
+		filter.Cursor, filter.Offset = page.NextCursor, 0
+	}
+
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return len(w.events), nil
+}
+
+// Format returns a tryl.ExportFormat that writes each exported chunk as a
+// Parquet file, for use with Client.ExportToObjectStorage.
+func Format(opts ...Option) tryl.ExportFormat {
+	return tryl.ExportFormat{
+		Name:        "parquet",
+		Extension:   ".parquet",
+		ContentType: "application/vnd.apache.parquet",
+		Encode: func(out io.Writer, events []tryl.StoredEvent) error {
+			w := NewWriter(out, opts...)
+			if err := w.Write(events...); err != nil {
+				return err
+			}
+			return w.Close()
+		},
+	}
+}
+
+// flatten stores the leaf values of v in out, keyed by underscore-joined path.
+func flatten(prefix string, v any, out map[string]any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			key := k
+			if prefix != "" {
+				key = prefix + "_" + k
+			}
+			flatten(key, child, out)
+		}
+	case []any:
+		data, _ := json.Marshal(val)
+		out[prefix] = string(data)
+	case json.Number:
+		if f, err := val.Float64(); err == nil {
+			out[prefix] = f
+		} else {
+			out[prefix] = val.String()
+		}
+	case nil:
+		// Nulls are left out and become null cells.
+	default:
+		out[prefix] = val
+	}
+}
+
+// column is one column's schema and values. Nil values are null.
+type column struct {
+	name      string
+	typ       int32
+	converted int32
+	values    []any
+}
+
+// chunkInfo locates a column chunk in the file.
+type chunkInfo struct {
+	offset int64
+	size   int64
+}
+
+// columns builds the fixed event columns followed by metadata columns.
+func (w *Writer) columns() []column {
+	n := len(w.events)
+	str := func(name string, get func(e tryl.StoredEvent) string) column {
+		col := column{name: name, typ: typeByteArray, converted: convertedUTF8, values: make([]any, n)}
+		for i, e := range w.events {
+			if s := get(e); s != "" {
+				col.values[i] = s
+			}
+		}
+		return col
+	}
+
+	columns := []column{
+		str("id", func(e tryl.StoredEvent) string { return e.ID }),
+		str("user_id", func(e tryl.StoredEvent) string { return e.UserID }),
+		str("action", func(e tryl.StoredEvent) string { return e.Action }),
+		str("actor_id", func(e tryl.StoredEvent) string { return e.ActorID }),
+		str("target_type", func(e tryl.StoredEvent) string { return e.TargetType }),
+		str("target_id", func(e tryl.StoredEvent) string { return e.TargetID }),
+	}
+
+	version := column{name: "schema_version", typ: typeInt32, converted: convertedNone, values: make([]any, n)}
+	timestamp := column{name: "timestamp", typ: typeInt64, converted: convertedTimestampMicros, values: make([]any, n)}
+	for i, e := range w.events {
+		if e.SchemaVersion != 0 {
+			version.values[i] = int32(e.SchemaVersion)
+		}
+		if !e.Timestamp.IsZero() {
+			timestamp.values[i] = e.Timestamp.UnixMicro()
+		}
+	}
+	columns = append(columns, version, timestamp,
+		str("metadata", func(e tryl.StoredEvent) string { return string(e.Metadata) }))
+
+	keys := w.metadataKeys
+	if keys == nil {
+		seen := map[string]bool{}
+		for _, m := range w.metadata {
+			for k := range m {
+				if !seen[k] {
+					seen[k] = true
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Strings(keys)
+	}
+	for _, k := range keys {
+		columns = append(columns, w.metadataColumn(k))
+	}
+	return columns
+}
+
+// metadataColumn builds the flattened column for key, inferring its type.
+func (w *Writer) metadataColumn(key string) column {
+	allNumbers, allBools := true, true
+	for _, m := range w.metadata {
+		switch m[key].(type) {
+		case nil:
+		case float64:
+			allBools = false
+		case bool:
+			allNumbers = false
+		default:
+			allNumbers, allBools = false, false
+		}
+	}
+
+	col := column{name: metadataPrefix + key, values: make([]any, len(w.metadata))}
+	switch {
+	case allNumbers:
+		col.typ, col.converted = typeDouble, convertedNone
+	case allBools:
+		col.typ, col.converted = typeBoolean, convertedNone
+	default:
+		col.typ, col.converted = typeByteArray, convertedUTF8
+	}
+
+	for i, m := range w.metadata {
+		v, ok := m[key]
+		if !ok {
+			continue
+		}
+		if col.typ == typeByteArray {
+			if s, isString := v.(string); isString {
+				v = s
+			} else {
+				data, _ := json.Marshal(v)
+				v = string(data)
+			}
+		}
+		col.values[i] = v
+	}
+	return col
+}
+
+// encodePage returns the page header and data for a single data page
+// holding every value of the column.
+func (c column) encodePage() (header, data []byte) {
+	var page bytes.Buffer
+
+	// Definition levels: 1 for present, 0 for null, as one bit-packed run
+	// prefixed with its byte length.
+	levels := make([]bool, len(c.values))
+	for i, v := range c.values {
+		levels[i] = v != nil
+	}
+	run := binary.AppendUvarint(nil, uint64((len(levels)+7)/8)<<1|1)
+	run = append(run, packBits(levels)...)
+	page.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(run))))
+	page.Write(run)
+
+	// Values, PLAIN-encoded, nulls omitted.
+	var bools []bool
+	for _, v := range c.values {
+		switch val := v.(type) {
+		case nil:
+		case string:
+			page.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(val))))
+			page.WriteString(val)
+		case int32:
+			page.Write(binary.LittleEndian.AppendUint32(nil, uint32(val)))
+		case int64:
+			page.Write(binary.LittleEndian.AppendUint64(nil, uint64(val)))
+		case float64:
+			page.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(val)))
+		case bool:
+			bools = append(bools, val)
+		}
+	}
+	if c.typ == typeBoolean {
+		page.Write(packBits(bools))
+	}
+
+	data = page.Bytes()
+	var h compactWriter
+	h.beginStruct()
+	h.i32(1, pageTypeData)
+	h.i32(2, int32(len(data)))
+	h.i32(3, int32(len(data)))
+	h.field(5) // DataPageHeader
+	h.i32(1, int32(len(c.values)))
+	h.i32(2, encodingPlain)
+	h.i32(3, encodingRLE)
+	h.i32(4, encodingRLE)
+	h.endStruct()
+	h.endStruct()
+	return h.buf, data
+}
+
+// packBits packs bools LSB-first, padding the last byte with zeros.
+func packBits(bits []bool) []byte {
+	out := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
+
+// fileMetadata encodes the Parquet FileMetaData footer.
+func fileMetadata(columns []column, chunks []chunkInfo, numRows int64) []byte {
+	var w compactWriter
+	w.beginStruct()
+	w.i32(1, 1) // version
+
+	// Schema: a root group followed by one optional leaf per column.
+	w.listHeader(2, tStruct, len(columns)+1)
+	w.beginStruct()
+	w.binary(4, "schema")
+	w.i32(5, int32(len(columns)))
+	w.endStruct()
+	for _, c := range columns {
+		w.beginStruct()
+		w.i32(1, c.typ)
+		w.i32(3, repOptional)
+		w.binary(4, c.name)
+		if c.converted != convertedNone {
+			w.i32(6, c.converted)
+		}
+		w.endStruct()
+	}
+
+	w.i64(3, numRows)
+
+	// A single row group.
+	var totalSize int64
+	for _, ch := range chunks {
+		totalSize += ch.size
+	}
+	w.listHeader(4, tStruct, 1)
+	w.beginStruct()
+	w.listHeader(1, tStruct, len(columns))
+	for i, c := range columns {
+		w.beginStruct() // ColumnChunk
+		w.i64(2, chunks[i].offset)
+		w.field(3) // ColumnMetaData
+		w.i32(1, c.typ)
+		w.i32List(2, encodingPlain, encodingRLE)
+		w.binaryList(3, c.name)
+		w.i32(4, codecNone)
+		w.i64(5, int64(len(c.values)))
+		w.i64(6, chunks[i].size)
+		w.i64(7, chunks[i].size)
+		w.i64(9, chunks[i].offset)
+		w.endStruct()
+		w.endStruct()
+	}
+	w.i64(2, totalSize)
+	w.i64(3, numRows)
+	w.endStruct()
+
+	w.binary(6, "tryl-go "+strings.TrimPrefix(tryl.Version, "v"))
+	w.endStruct()
+	return w.buf
+}
@@ -0,0 +1,271 @@
+package trylparquet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// compactReader decodes Thrift compact structs generically, as maps from
+// field ID to value, so tests can inspect what the writer produced.
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) any {
+	switch typ {
+	case tI32, tI64:
+		return r.zigzag()
+	case tBinary:
+		n := int(r.uvarint())
+		s := string(r.buf[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case tList:
+		head := r.buf[r.pos]
+		r.pos++
+		size := int(head >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(head & 0x0F)
+		}
+		return list
+	case tStruct:
+		return r.structure()
+	}
+	panic(fmt.Sprintf("unsupported thrift type %d", typ))
+}
+
+func (r *compactReader) structure() map[int16]any {
+	fields := map[int16]any{}
+	var last int16
+	for {
+		head := r.buf[r.pos]
+		r.pos++
+		if head == 0 {
+			return fields
+		}
+		id := last + int16(head>>4)
+		if head>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(head & 0x0F)
+		last = id
+	}
+}
+
+// parsedFile is a decoded Parquet file.
+type parsedFile struct {
+	numRows int64
+	columns map[string][]any
+}
+
+// parseFile decodes files produced by Writer: one row group, one PLAIN
+// data page per column, and bit-packed definition levels.
+func parseFile(t *testing.T, data []byte) parsedFile {
+	t.Helper()
+
+	if string(data[:4]) != magic || string(data[len(data)-4:]) != magic {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &compactReader{buf: data[len(data)-8-footerLen : len(data)-8]}
+	meta := footer.structure()
+
+	schema := meta[2].([]any)
+	types := map[string]int64{}
+	for _, el := range schema[1:] {
+		fields := el.(map[int16]any)
+		types[fields[4].(string)] = fields[1].(int64)
+	}
+
+	result := parsedFile{numRows: meta[3].(int64), columns: map[string][]any{}}
+	rowGroup := meta[4].([]any)[0].(map[int16]any)
+	for _, cc := range rowGroup[1].([]any) {
+		colMeta := cc.(map[int16]any)[3].(map[int16]any)
+		name := colMeta[3].([]any)[0].(string)
+		offset := int(colMeta[9].(int64))
+
+		page := &compactReader{buf: data, pos: offset}
+		header := page.structure()
+		numValues := int(header[5].(map[int16]any)[1].(int64))
+		body := data[page.pos : page.pos+int(header[2].(int64))]
+
+		runLen := int(binary.LittleEndian.Uint32(body))
+		run := &compactReader{buf: body[4 : 4+runLen]}
+		run.uvarint()
+		levels := run.buf[run.pos:]
+		values := body[4+runLen:]
+
+		col := make([]any, numValues)
+		bit := 0
+		for i := range col {
+			if levels[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+			switch types[name] {
+			case typeByteArray:
+				n := int(binary.LittleEndian.Uint32(values))
+				col[i] = string(values[4 : 4+n])
+				values = values[4+n:]
+			case typeInt32:
+				col[i] = int32(binary.LittleEndian.Uint32(values))
+				values = values[4:]
+			case typeInt64:
+				col[i] = int64(binary.LittleEndian.Uint64(values))
+				values = values[8:]
+			case typeDouble:
+				col[i] = math.Float64frombits(binary.LittleEndian.Uint64(values))
+				values = values[8:]
+			case typeBoolean:
+				col[i] = values[bit/8]&(1<<(bit%8)) != 0
+				bit++
+			}
+		}
+		result.columns[name] = col
+	}
+	return result
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	err := w.Write(
+		tryl.StoredEvent{
+			ID: "evt_1", UserID: "u1", Action: "order.placed", SchemaVersion: 2, Timestamp: ts,
+			Metadata: json.RawMessage(`{"amount":12.5,"paid":true,"geo":{"city":"Paris"},"tags":["a","b"]}`),
+		},
+		tryl.StoredEvent{
+			ID: "evt_2", UserID: "u2", Action: "order.placed", ActorID: "admin", Timestamp: ts,
+			Metadata: json.RawMessage(`{"amount":3,"paid":false,"geo":{"city":7}}`),
+		},
+		tryl.StoredEvent{ID: "evt_3", UserID: "u3", Action: "user.login", Timestamp: ts},
+	)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write(tryl.StoredEvent{}); err != ErrClosed {
+		t.Errorf("Write() after Close error = %v, want ErrClosed", err)
+	}
+
+	file := parseFile(t, buf.Bytes())
+	if file.numRows != 3 {
+		t.Errorf("numRows = %d, want 3", file.numRows)
+	}
+
+	tests := []struct {
+		column string
+		want   []any
+	}{
+		{"id", []any{"evt_1", "evt_2", "evt_3"}},
+		{"actor_id", []any{nil, "admin", nil}},
+		{"schema_version", []any{int32(2), nil, nil}},
+		{"timestamp", []any{ts.UnixMicro(), ts.UnixMicro(), ts.UnixMicro()}},
+		{"metadata_amount", []any{12.5, 3.0, nil}},
+		{"metadata_paid", []any{true, false, nil}},
+		{"metadata_geo_city", []any{"Paris", "7", nil}},
+		{"metadata_tags", []any{`["a","b"]`, nil, nil}},
+	}
+	for _, tt := range tests {
+		got, ok := file.columns[tt.column]
+		if !ok {
+			t.Errorf("missing column %q", tt.column)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("column %q = %v, want %v", tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestWriter_MetadataColumns(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WithMetadataColumns("amount"))
+	w.Write(tryl.StoredEvent{ID: "evt_1", Metadata: json.RawMessage(`{"amount":1,"other":"x"}`)})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file := parseFile(t, buf.Bytes())
+	if _, ok := file.columns["metadata_amount"]; !ok {
+		t.Error("missing metadata_amount column")
+	}
+	if _, ok := file.columns["metadata_other"]; ok {
+		t.Error("unexpected metadata_other column")
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := tryl.EventList{Events: []tryl.StoredEvent{{ID: "evt_1", UserID: "u1", Action: "a.b"}}, HasMore: true, NextCursor: "c2"}
+		if r.URL.Query().Get("cursor") == "c2" {
+			page = tryl.EventList{Events: []tryl.StoredEvent{{ID: "evt_2", UserID: "u2", Action: "a.b"}}}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client, err := tryl.NewClient("actlog_test_1234567890abcdef1234567890abcdef", tryl.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := Export(context.Background(), client, tryl.EventFilter{}, &buf)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Export() = %d, want 2", n)
+	}
+	if got := parseFile(t, buf.Bytes()).columns["id"]; fmt.Sprint(got) != "[evt_1 evt_2]" {
+		t.Errorf("id column = %v", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := Format().Encode(&buf, []tryl.StoredEvent{{ID: "evt_1"}, {ID: "evt_2"}})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if got := parseFile(t, buf.Bytes()).numRows; got != 2 {
+		t.Errorf("numRows = %d, want 2", got)
+	}
+}
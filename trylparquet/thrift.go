@@ -0,0 +1,93 @@
+package trylparquet
+
+import "encoding/binary"
+
+// Thrift compact protocol type IDs used by the Parquet footer.
+const (
+	tI32    = 5
+	tI64    = 6
+	tBinary = 8
+	tList   = 9
+	tStruct = 12
+)
+
+// compactWriter encodes the subset of the Thrift compact protocol needed
+// for Parquet page headers and file metadata.
+type compactWriter struct {
+	buf []byte
+	// last holds the previous field ID of each open struct.
+	last []int16
+}
+
+func (w *compactWriter) beginStruct() {
+	w.last = append(w.last, 0)
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf = append(w.buf, 0) // field stop
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	top := len(w.last) - 1
+	delta := id - w.last[top]
+	if delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(int64(id))
+	}
+	w.last[top] = id
+}
+
+// varint writes a zigzag-encoded variable-length integer.
+func (w *compactWriter) varint(v int64) {
+	w.buf = binary.AppendUvarint(w.buf, uint64((v<<1)^(v>>63)))
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.fieldHeader(id, tI32)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.fieldHeader(id, tI64)
+	w.varint(v)
+}
+
+func (w *compactWriter) binary(id int16, v string) {
+	w.fieldHeader(id, tBinary)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// field starts a nested struct field; close it with endStruct.
+func (w *compactWriter) field(id int16) {
+	w.fieldHeader(id, tStruct)
+	w.beginStruct()
+}
+
+func (w *compactWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(id, tList)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elemType)
+		return
+	}
+	w.buf = append(w.buf, 0xF0|elemType)
+	w.buf = binary.AppendUvarint(w.buf, uint64(size))
+}
+
+func (w *compactWriter) i32List(id int16, values ...int32) {
+	w.listHeader(id, tI32, len(values))
+	for _, v := range values {
+		w.varint(int64(v))
+	}
+}
+
+func (w *compactWriter) binaryList(id int16, values ...string) {
+	w.listHeader(id, tBinary, len(values))
+	for _, v := range values {
+		w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+		w.buf = append(w.buf, v...)
+	}
+}
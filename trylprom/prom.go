@@ -0,0 +1,174 @@
+// Package trylprom exposes a client's delivery counters (see
+// tryl.Client.Stats) in the Prometheus text exposition format, so services
+// standardized on Prometheus can scrape SDK metrics without writing glue.
+//
+// Serve them next to, or instead of, promhttp.Handler:
+//
+//	http.Handle("/metrics/tryl", trylprom.Handler(client, trylprom.WithLabels(map[string]string{"service": "billing"})))
+//
+// The metrics, with the default "tryl" namespace, are:
+//
+//	tryl_events_sent_total     events the API committed
+//	tryl_batches_total         successful batch requests
+//	tryl_retries_total         retried request attempts
+//	tryl_dropped_total         events queued for batching that were not sent
+//	tryl_suppressed_total      events suppressed by an action budget, by budget
+//	tryl_queue_depth           events waiting in the batching queue
+package trylprom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// contentType is the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Option configures the exported metrics.
+type Option func(*exporter)
+
+// WithNamespace sets the prefix of metric names.
+// Default: "tryl"
+func WithNamespace(namespace string) Option {
+	return func(e *exporter) {
+		e.namespace = namespace
+	}
+}
+
+// WithLabels adds constant labels to every metric, for example to tell
+// the clients of several services apart.
+// Default: no labels
+func WithLabels(labels map[string]string) Option {
+	return func(e *exporter) {
+		for k, v := range labels {
+			e.labels[k] = v
+		}
+	}
+}
+
+// WithGauge exports an extra gauge named namespace_name, read from value
+// on every scrape.
+func WithGauge(name, help string, value func() float64) Option {
+	return func(e *exporter) {
+		e.gauges = append(e.gauges, gauge{name: name, help: help, value: value})
+	}
+}
+
+// gauge is an extra gauge set with WithGauge.
+type gauge struct {
+	name, help string
+	value      func() float64
+}
+
+// exporter writes a client's metrics.
+type exporter struct {
+	client    *tryl.Client
+	namespace string
+	labels    map[string]string
+	gauges    []gauge
+}
+
+func newExporter(client *tryl.Client, opts []Option) *exporter {
+	e := &exporter{client: client, namespace: "tryl", labels: make(map[string]string)}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Handler returns an http.Handler serving client's metrics.
+func Handler(client *tryl.Client, opts ...Option) http.Handler {
+	e := newExporter(client, opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		e.write(w)
+	})
+}
+
+// Write writes client's metrics to w in the text exposition format.
+func Write(w io.Writer, client *tryl.Client, opts ...Option) error {
+	return newExporter(client, opts).write(w)
+}
+
+func (e *exporter) write(w io.Writer) error {
+	s := e.client.Stats()
+	bw := bufio.NewWriter(w)
+
+	e.metric(bw, "events_sent_total", "counter", "Events the API committed.", nil, float64(s.EventsSent))
+	e.metric(bw, "batches_total", "counter", "Successful batch requests.", nil, float64(s.Batches))
+	e.metric(bw, "retries_total", "counter", "Retried request attempts.", nil, float64(s.Retries))
+	e.metric(bw, "dropped_total", "counter", "Events queued for batching that were not sent.", nil, float64(s.Dropped))
+
+	budgets := make([]string, 0, len(s.SuppressedEvents))
+	for pattern := range s.SuppressedEvents {
+		budgets = append(budgets, pattern)
+	}
+	sort.Strings(budgets)
+	e.header(bw, "suppressed_total", "counter", "Events suppressed by an action budget.")
+	for _, pattern := range budgets {
+		e.sample(bw, "suppressed_total", map[string]string{"budget": pattern}, float64(s.SuppressedEvents[pattern]))
+	}
+
+	e.metric(bw, "queue_depth", "gauge", "Events waiting in the batching queue.", nil, float64(s.QueueDepth))
+	for _, g := range e.gauges {
+		e.metric(bw, g.name, "gauge", g.help, nil, g.value())
+	}
+	return bw.Flush()
+}
+
+// metric writes a metric with a single sample.
+func (e *exporter) metric(w *bufio.Writer, name, typ, help string, labels map[string]string, value float64) {
+	e.header(w, name, typ, help)
+	e.sample(w, name, labels, value)
+}
+
+func (e *exporter) header(w *bufio.Writer, name, typ, help string) {
+	name = e.name(name)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (e *exporter) sample(w *bufio.Writer, name string, labels map[string]string, value float64) {
+	w.WriteString(e.name(name))
+	all := make(map[string]string, len(e.labels)+len(labels))
+	for k, v := range e.labels {
+		all[k] = v
+	}
+	for k, v := range labels {
+		all[k] = v
+	}
+	if len(all) > 0 {
+		keys := make([]string, 0, len(all))
+		for k := range all {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", k, labelEscaper.Replace(all[k]))
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	w.WriteByte('\n')
+}
+
+func (e *exporter) name(name string) string {
+	if e.namespace == "" {
+		return name
+	}
+	return e.namespace + "_" + name
+}
+
+// labelEscaper escapes label values as the exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
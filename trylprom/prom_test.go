@@ -0,0 +1,76 @@
+package trylprom
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/v1/events/batch" {
+			w.Write([]byte(`{"results":[{"id":"evt_2"},{"id":"evt_3"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_1","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL),
+		tryl.WithRetry(tryl.RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.Log(ctx, tryl.Event{UserID: "user_1", Action: "doc.viewed"}); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if _, err := client.LogBatch(ctx, []tryl.Event{{UserID: "user_1", Action: "doc.edited"}, {UserID: "user_1", Action: "doc.shared"}}); err != nil {
+		t.Fatalf("LogBatch() error = %v", err)
+	}
+
+	metrics := httptest.NewServer(Handler(client,
+		WithLabels(map[string]string{"service": `bill"ing`}),
+		WithGauge("extra", "An extra gauge.", func() float64 { return 1.5 })))
+	defer metrics.Close()
+	resp, err := http.Get(metrics.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	for _, want := range []string{
+		"# TYPE tryl_events_sent_total counter\n",
+		`tryl_events_sent_total{service="bill\"ing"} 3` + "\n",
+		`tryl_batches_total{service="bill\"ing"} 1` + "\n",
+		`tryl_retries_total{service="bill\"ing"} 1` + "\n",
+		`tryl_dropped_total{service="bill\"ing"} 0` + "\n",
+		"# TYPE tryl_queue_depth gauge\n",
+		`tryl_extra{service="bill\"ing"} 1.5` + "\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Package trylreplay provides a record/replay HTTP client for integration tests.
+//
+// A Recorder wraps a real HTTP client while recording, capturing every API
+// interaction to a JSON fixture file ("cassette"). In replay mode it serves
+// responses from the cassette without touching the network, so tests can run
+// in CI without live credentials. Bearer tokens and API key values are
+// scrubbed before anything is written to disk.
+//
+// Usage:
+//
+//	rec, err := trylreplay.New("testdata/log_event.json", trylreplay.ModeAuto)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client, err := tryl.NewClient(apiKey, tryl.WithHTTPClient(rec))
+package trylreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// Mode selects whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay serves responses from an existing cassette and never
+	// contacts the network. Unmatched requests return an error.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the real API and saves every
+	// interaction to the cassette when Stop is called.
+	ModeRecord
+	// ModeAuto replays if the cassette file exists and records otherwise.
+	ModeAuto
+)
+
+// Redacted replaces scrubbed secrets in recorded interactions.
+const Redacted = "REDACTED"
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction
+// matches a request.
+var ErrNoInteraction = errors.New("trylreplay: no recorded interaction matches request")
+
+// defaultScrubFields are JSON body fields whose values are always redacted.
+var defaultScrubFields = []string{"api_key", "new_api_key"}
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded form of an HTTP request.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the recorded form of an HTTP response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is the on-disk fixture format.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithHTTPClient sets the client used to reach the real API while recording.
+// Default: http.DefaultClient
+func WithHTTPClient(client tryl.HTTPDoer) Option {
+	return func(r *Recorder) {
+		r.inner = client
+	}
+}
+
+// WithScrubFields adds JSON body fields whose values are redacted before
+// interactions are saved. "api_key" and "new_api_key" are always scrubbed.
+func WithScrubFields(fields ...string) Option {
+	return func(r *Recorder) {
+		r.scrubFields = append(r.scrubFields, fields...)
+	}
+}
+
+// Recorder is a tryl.HTTPDoer that records or replays API interactions.
+type Recorder struct {
+	path        string
+	mode        Mode
+	inner       tryl.HTTPDoer
+	scrubFields []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates a Recorder backed by the cassette at path.
+// In ModeReplay the cassette must exist; in ModeAuto its existence selects
+// between replaying and recording.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:        path,
+		mode:        mode,
+		inner:       http.DefaultClient,
+		scrubFields: append([]string(nil), defaultScrubFields...),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeAuto {
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("trylreplay: failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("trylreplay: failed to parse cassette: %w", err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the effective mode of the recorder.
+// ModeAuto is resolved to ModeReplay or ModeRecord at construction.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Do records or replays a single HTTP request.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("trylreplay: failed to read request body: %w", err)
+		}
+		req.Body.Close()
+		reqBody = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req)
+	}
+	return r.record(req, reqBody)
+}
+
+// replay returns the first unused interaction matching the request's method and URL.
+// The host is ignored so cassettes work against any base URL.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target := requestURL(req)
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.URL != target {
+			continue
+		}
+		r.used[i] = true
+
+		header := in.Response.Headers.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			StatusCode: in.Response.StatusCode,
+			Status:     fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, target)
+}
+
+// record forwards the request to the inner client and captures the exchange.
+func (r *Recorder) record(req *http.Request, reqBody []byte) (*http.Response, error) {
+	resp, err := r.inner.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("trylreplay: failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	reqHeaders := req.Header.Clone()
+	if reqHeaders.Get("Authorization") != "" {
+		reqHeaders.Set("Authorization", "Bearer "+Redacted)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     requestURL(req),
+			Headers: reqHeaders,
+			Body:    string(r.scrub(reqBody)),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Header.Clone(),
+			Body:       string(r.scrub(respBody)),
+		},
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Stop finishes the session. In record mode the cassette is written to disk.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("trylreplay: failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("trylreplay: failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return fmt.Errorf("trylreplay: failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// scrub redacts configured fields in a JSON body. Non-JSON bodies are returned unchanged.
+func (r *Recorder) scrub(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+
+	fields := make(map[string]bool, len(r.scrubFields))
+	for _, f := range r.scrubFields {
+		fields[f] = true
+	}
+	scrubValue(v, fields)
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// scrubValue walks decoded JSON and redacts string values under matching keys.
+func scrubValue(v any, fields map[string]bool) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if _, isString := child.(string); isString && fields[k] {
+				val[k] = Redacted
+				continue
+			}
+			scrubValue(child, fields)
+		}
+	case []any:
+		for _, child := range val {
+			scrubValue(child, fields)
+		}
+	}
+}
+
+// requestURL returns the request path and query, without scheme or host.
+func requestURL(req *http.Request) string {
+	return req.URL.RequestURI()
+}
@@ -0,0 +1,98 @@
+package trylreplay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+const testAPIKey = "actlog_test_1234567890abcdef1234567890abcdef"
+
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"evt_abc123","timestamp":"2026-01-30T10:00:00Z"}`))
+	}))
+
+	rec, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("Mode() = %v, want ModeRecord for missing cassette", rec.Mode())
+	}
+
+	client, err := tryl.NewClient(testAPIKey, tryl.WithBaseURL(server.URL), tryl.WithHTTPClient(rec))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	event := tryl.Event{UserID: "user_123", Action: "user.created"}
+	if _, err := client.Log(context.Background(), event); err != nil {
+		t.Fatalf("Log() while recording error = %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), testAPIKey) {
+		t.Error("cassette contains unscrubbed API key")
+	}
+
+	replay, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if replay.Mode() != ModeReplay {
+		t.Fatalf("Mode() = %v, want ModeReplay for existing cassette", replay.Mode())
+	}
+
+	client, err = tryl.NewClient(testAPIKey,
+		tryl.WithBaseURL("http://replay.invalid"),
+		tryl.WithHTTPClient(replay),
+		tryl.WithoutRetry())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	resp, err := client.Log(context.Background(), event)
+	if err != nil {
+		t.Fatalf("Log() while replaying error = %v", err)
+	}
+	if resp.ID != "evt_abc123" {
+		t.Errorf("got ID %q, want evt_abc123", resp.ID)
+	}
+
+	_, err = client.Log(context.Background(), event)
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("second Log() error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorder_ScrubsSecretFields(t *testing.T) {
+	t.Parallel()
+
+	rec := &Recorder{scrubFields: append([]string(nil), defaultScrubFields...)}
+	got := string(rec.scrub([]byte(`{"api_key":"actlog_live_secret","api_key_metadata":{"name":"k"}}`)))
+
+	if strings.Contains(got, "actlog_live_secret") {
+		t.Errorf("scrub() = %s, secret not redacted", got)
+	}
+	if !strings.Contains(got, `"name":"k"`) {
+		t.Errorf("scrub() = %s, unrelated fields altered", got)
+	}
+}
@@ -0,0 +1,141 @@
+package tryltest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+// ManualBatcher controls when a client's Batcher flushes, so tests of code
+// that logs with LogAsync can check flush boundaries and error callbacks
+// without waiting for FlushInterval or racing it. The batcher flushes only
+// on TriggerFlush, AdvanceTime past a FlushInterval, a full batch, or an
+// explicit Flush or Close.
+//
+// Usage:
+//
+//	mb := tryltest.NewManualBatcher()
+//	client, _ := srv.Client(mb.Option(), tryl.WithBatching(tryl.BatchConfig{FlushInterval: time.Second}))
+//	client.LogAsync(ctx, event)
+//	mb.TriggerFlush() // the event has been sent when TriggerFlush returns
+//
+// ManualBatcher replaces the client's clock (see tryl.WithClock): retry
+// backoff delays elapse immediately, and Now advances only with
+// AdvanceTime. Compaction (see tryl.WithCompaction) flushes on the same
+// triggers. Use one ManualBatcher per client.
+type ManualBatcher struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*manualTicker
+}
+
+// NewManualBatcher returns a ManualBatcher whose clock starts at the
+// current time.
+func NewManualBatcher() *ManualBatcher {
+	return &ManualBatcher{now: time.Now()}
+}
+
+// Option returns the client option that puts the client's Batcher under
+// m's control.
+func (m *ManualBatcher) Option() tryl.Option {
+	return tryl.WithClock(manualClock{m})
+}
+
+// TriggerFlush flushes the batcher as if FlushInterval had elapsed, and
+// returns once every event queued before the call has been sent and its
+// result delivered. It does not advance time.
+func (m *ManualBatcher) TriggerFlush() {
+	m.mu.Lock()
+	tickers := append([]*manualTicker(nil), m.tickers...)
+	now := m.now
+	m.mu.Unlock()
+
+	for _, t := range tickers {
+		t.tick(now)
+	}
+}
+
+// AdvanceTime moves the clock forward by d and flushes the batcher if a
+// FlushInterval boundary was crossed, returning once the flush is
+// complete. Several crossed boundaries cause one flush, as with a
+// time.Ticker whose receiver is busy.
+func (m *ManualBatcher) AdvanceTime(d time.Duration) {
+	m.mu.Lock()
+	m.now = m.now.Add(d)
+	now := m.now
+	var due []*manualTicker
+	for _, t := range m.tickers {
+		if !t.next.After(now) {
+			for !t.next.After(now) {
+				t.next = t.next.Add(t.period)
+			}
+			due = append(due, t)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, t := range due {
+		t.tick(now)
+	}
+}
+
+// manualClock is the tryl.Clock of a ManualBatcher.
+type manualClock struct {
+	m *ManualBatcher
+}
+
+func (c manualClock) Now() time.Time {
+	c.m.mu.Lock()
+	defer c.m.mu.Unlock()
+	return c.m.now
+}
+
+func (c manualClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+func (c manualClock) NewTicker(d time.Duration) tryl.Ticker {
+	c.m.mu.Lock()
+	defer c.m.mu.Unlock()
+	t := &manualTicker{
+		period: d,
+		next:   c.m.now.Add(d),
+		ch:     make(chan time.Time),
+		done:   make(chan struct{}),
+	}
+	c.m.tickers = append(c.m.tickers, t)
+	return t
+}
+
+// manualTicker ticks when its ManualBatcher says so. Its channel is
+// unbuffered, so a delivered tick has been received by the batcher.
+type manualTicker struct {
+	period time.Duration
+	next   time.Time // guarded by the ManualBatcher's mu
+
+	ch       chan time.Time
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.ch }
+
+func (t *manualTicker) Stop() {
+	t.stopOnce.Do(func() { close(t.done) })
+}
+
+// tick delivers a tick and waits until it has been handled. The second
+// tick finds nothing to send and is received only once the receiver has
+// finished handling the first. Stopped tickers are skipped.
+func (t *manualTicker) tick(now time.Time) {
+	for i := 0; i < 2; i++ {
+		select {
+		case t.ch <- now:
+		case <-t.done:
+			return
+		}
+	}
+}
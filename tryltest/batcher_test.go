@@ -0,0 +1,45 @@
+package tryltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+func TestManualBatcher(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	mb := NewManualBatcher()
+	client, err := srv.Client(mb.Option(), tryl.WithBatching(tryl.BatchConfig{MaxBatchSize: 10, FlushInterval: time.Second}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		client.LogAsync(ctx, tryl.Event{UserID: "user_123", Action: "doc.viewed"})
+	}
+	mb.AdvanceTime(500 * time.Millisecond)
+	if got := len(srv.Events()); got != 0 {
+		t.Fatalf("server stored %d events before the flush interval, want 0", got)
+	}
+	mb.AdvanceTime(600 * time.Millisecond)
+	if got := len(srv.Events()); got != 3 {
+		t.Fatalf("server stored %d events after the flush interval, want 3", got)
+	}
+
+	pending := client.LogAsync(ctx, tryl.Event{UserID: "user_123", Action: "doc.edited"})
+	mb.TriggerFlush()
+	if result, ok := pending.Result(); !ok || result.Error != nil {
+		t.Errorf("Result() = %+v, %v after TriggerFlush, want a delivered event", result, ok)
+	}
+	if got := len(srv.Events()); got != 4 {
+		t.Errorf("server stored %d events after TriggerFlush, want 4", got)
+	}
+}
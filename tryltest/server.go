@@ -0,0 +1,832 @@
+// Package tryltest provides an in-memory Activity Logger server for local
+// development and tests.
+//
+// LocalServer implements the events and management APIs on top of
+// httptest.Server, including action wildcards, time ranges, metadata
+// filters, cursor and offset pagination, and partial-success batches.
+// Data lives only for the lifetime of the server.
+//
+// Usage:
+//
+//	srv := tryltest.NewLocalServer()
+//	defer srv.Close()
+//
+//	client, err := srv.Client()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package tryltest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+	"github.com/joshuawatkins04/tryl_sdk/internal/validation"
+)
+
+// APIKey is a well-formed test API key accepted by NewClient.
+// LocalServer accepts any non-empty bearer token.
+const APIKey = "actlog_test_00000000000000000000000000000000"
+
+// SessionToken is a session token for management operations against LocalServer.
+const SessionToken = "tryltest_session"
+
+const (
+	defaultLimit = 50
+	maxLimit     = 100
+	maxBatchSize = 100
+)
+
+// LocalServer is an in-memory implementation of the Activity Logger API.
+type LocalServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	seq      int
+	events   []tryl.StoredEvent
+	byKey    map[string]tryl.EventResponse
+	projects []tryl.Project
+	keys     []tryl.APIKey
+	audit    []tryl.AuditRecord
+}
+
+// NewLocalServer starts an empty LocalServer. Call Close when done.
+func NewLocalServer() *LocalServer {
+	s := &LocalServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an event-logging client configured to talk to the server.
+func (s *LocalServer) Client(opts ...tryl.Option) (*tryl.Client, error) {
+	return tryl.NewClient(APIKey, append([]tryl.Option{tryl.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// ManagementClient returns a session-authenticated client configured to talk to the server.
+func (s *LocalServer) ManagementClient(opts ...tryl.Option) (*tryl.Client, error) {
+	return tryl.NewManagementClient(SessionToken, append([]tryl.Option{tryl.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// Events returns a copy of all stored events in insertion order.
+func (s *LocalServer) Events() []tryl.StoredEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]tryl.StoredEvent(nil), s.events...)
+}
+
+// Reset discards all stored events, projects, keys, and audit records.
+func (s *LocalServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = nil
+	s.byKey = nil
+	s.projects = nil
+	s.keys = nil
+	s.audit = nil
+}
+
+// serveHTTP authenticates and routes a request.
+func (s *LocalServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") ||
+		strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") == "" {
+		writeError(w, http.StatusUnauthorized, tryl.ErrCodeUnauthorized, "missing bearer token")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/v1/events" && r.Method == http.MethodPost:
+		s.createEvent(w, r)
+	case r.URL.Path == "/v1/events" && r.Method == http.MethodGet:
+		s.listEvents(w, r)
+	case r.URL.Path == "/v1/events/latest" && r.Method == http.MethodGet:
+		s.listEvents(w, r)
+	case r.URL.Path == "/v1/events/batch" && r.Method == http.MethodPost:
+		s.createBatch(w, r)
+	case r.URL.Path == "/v1/projects" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, tryl.ProjectList{Projects: append([]tryl.Project{}, s.projects...)})
+	case r.URL.Path == "/v1/projects" && r.Method == http.MethodPost:
+		s.createProject(w, r)
+	case len(parts) == 3 && parts[1] == "projects" && r.Method == http.MethodDelete:
+		s.deleteProject(w, parts[2])
+	case len(parts) == 4 && parts[1] == "projects" && parts[3] == "keys" && r.Method == http.MethodGet:
+		s.listKeys(w, r, parts[2])
+	case len(parts) == 4 && parts[1] == "projects" && parts[3] == "keys" && r.Method == http.MethodPost:
+		s.createKey(w, r, parts[2])
+	case len(parts) == 3 && parts[1] == "keys" && r.Method == http.MethodPatch:
+		s.updateKey(w, r, parts[2])
+	case len(parts) == 4 && parts[1] == "keys" && parts[3] == "revoke" && r.Method == http.MethodPost:
+		s.revokeKey(w, parts[2])
+	case len(parts) == 4 && parts[1] == "keys" && parts[3] == "rotate" && r.Method == http.MethodPost:
+		s.rotateKey(w, r, parts[2])
+	case r.URL.Path == "/v1/audit" && r.Method == http.MethodGet:
+		s.listAudit(w, r)
+	case len(parts) == 4 && parts[1] == "users" && parts[3] == "summary" && r.Method == http.MethodGet:
+		s.userSummary(w, r, parts[2])
+	default:
+		writeError(w, http.StatusNotFound, tryl.ErrCodeNotFound, fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// ========== Events ==========
+
+func (s *LocalServer) createEvent(w http.ResponseWriter, r *http.Request) {
+	var event tryl.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeInvalidRequest, "invalid JSON body")
+		return
+	}
+	if err := validation.ValidateEvent(&event); err != nil {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.store(event, ""))
+}
+
+func (s *LocalServer) createBatch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Events  []tryl.Event `json:"events"`
+		GroupID string       `json:"group_id"`
+		Atomic  bool         `json:"atomic"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeInvalidRequest, "invalid JSON body")
+		return
+	}
+	if len(req.Events) == 0 || len(req.Events) > maxBatchSize {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError,
+			fmt.Sprintf("events must contain between 1 and %d items", maxBatchSize))
+		return
+	}
+
+	type itemError struct {
+		Index   int    `json:"index"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	results := make([]tryl.EventResponse, len(req.Events))
+	var errs []itemError
+	valid := make([]bool, len(req.Events))
+	for i := range req.Events {
+		if err := validation.ValidateEvent(&req.Events[i]); err != nil {
+			errs = append(errs, itemError{Index: i, Code: tryl.ErrCodeValidationError, Message: err.Error()})
+			continue
+		}
+		valid[i] = true
+	}
+	// Atomic batches (Group commits) are stored entirely or not at all.
+	if req.Atomic && len(errs) > 0 {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError,
+			fmt.Sprintf("events[%d]: %s", errs[0].Index, errs[0].Message))
+		return
+	}
+	for i := range req.Events {
+		if valid[i] {
+			results[i] = s.store(req.Events[i], req.GroupID)
+		}
+	}
+
+	status := http.StatusCreated
+	if len(errs) > 0 {
+		status = http.StatusMultiStatus
+	}
+	writeJSON(w, status, map[string]any{"results": results, "errors": errs})
+}
+
+// store appends an event and returns its response. An event whose
+// idempotency key was already seen is not stored again; the original
+// response is returned instead. Callers must hold s.mu.
+func (s *LocalServer) store(event tryl.Event, groupID string) tryl.EventResponse {
+	if event.IdempotencyKey != "" {
+		if resp, ok := s.byKey[event.IdempotencyKey]; ok {
+			return resp
+		}
+	}
+
+	stored := tryl.StoredEvent{
+		ID:            s.nextID("evt"),
+		UserID:        event.UserID,
+		TenantID:      event.TenantID,
+		Action:        event.Action,
+		ActorID:       event.ActorID,
+		TargetType:    event.TargetType,
+		TargetID:      event.TargetID,
+		Metadata:      event.Metadata,
+		Tags:          event.Tags,
+		CorrelationID: event.CorrelationID,
+		ParentEventID: event.ParentEventID,
+		GroupID:       groupID,
+		SchemaVersion: event.SchemaVersion,
+		ContentHash:   event.ContentHash,
+		ChainID:       event.ChainID,
+		PrevHash:      event.PrevHash,
+		Timestamp:     time.Now().UTC(),
+	}
+	if event.Timestamp != nil {
+		stored.Timestamp = event.Timestamp.UTC()
+	}
+	s.events = append(s.events, stored)
+
+	resp := tryl.EventResponse{ID: stored.ID, Timestamp: stored.Timestamp}
+	if event.IdempotencyKey != "" {
+		if s.byKey == nil {
+			s.byKey = make(map[string]tryl.EventResponse)
+		}
+		s.byKey[event.IdempotencyKey] = resp
+	}
+	return resp
+}
+
+func (s *LocalServer) userSummary(w http.ResponseWriter, r *http.Request, userID string) {
+	q := r.URL.Query()
+	var start, end time.Time
+	for name, dst := range map[string]*time.Time{"start_time": &start, "end_time": &end} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, name+" must be RFC3339")
+				return
+			}
+			*dst = t
+		}
+	}
+
+	summary := tryl.UserSummary{UserID: userID, Actions: map[string]int{}}
+	days := make(map[string]bool)
+	for _, e := range s.events {
+		if e.UserID != userID ||
+			(!start.IsZero() && e.Timestamp.Before(start)) ||
+			(!end.IsZero() && e.Timestamp.After(end)) {
+			continue
+		}
+		if summary.Total == 0 {
+			summary.FirstSeen = e.Timestamp
+		}
+		summary.Total++
+		summary.Actions[e.Action]++
+		summary.LastSeen = e.Timestamp
+		days[e.Timestamp.UTC().Format(time.DateOnly)] = true
+	}
+	summary.ActiveDays = len(days)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// latestPerGroup keeps the newest of events, which are oldest first, in
+// each group, preserving their order. ok is false for an unknown groupBy.
+func latestPerGroup(events []tryl.StoredEvent, groupBy string) (latest []tryl.StoredEvent, ok bool) {
+	var key func(e tryl.StoredEvent) string
+	switch groupBy {
+	case "user_id":
+		key = func(e tryl.StoredEvent) string { return e.UserID }
+	case "target":
+		key = func(e tryl.StoredEvent) string {
+			if e.TargetType == "" && e.TargetID == "" {
+				return ""
+			}
+			return e.TargetType + "\x00" + e.TargetID
+		}
+	default:
+		return nil, false
+	}
+
+	seen := make(map[string]bool)
+	for i := len(events) - 1; i >= 0; i-- {
+		k := key(events[i])
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		latest = append(latest, events[i])
+	}
+	for i, j := 0, len(latest)-1; i < j; i, j = i+1, j-1 {
+		latest[i], latest[j] = latest[j], latest[i]
+	}
+	return latest, true
+}
+
+func (s *LocalServer) listEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var start, end *time.Time
+	for name, dst := range map[string]**time.Time{"start_time": &start, "end_time": &end} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, name+" must be RFC3339")
+				return
+			}
+			*dst = &t
+		}
+	}
+
+	var contains any
+	if v := q.Get("metadata_contains"); v != "" {
+		if err := json.Unmarshal([]byte(v), &contains); err != nil {
+			writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "metadata_contains must be valid JSON")
+			return
+		}
+	}
+
+	var matched []tryl.StoredEvent
+	for _, e := range s.events {
+		if v := q.Get("user_id"); v != "" && e.UserID != v {
+			continue
+		}
+		if v := q.Get("actor_id"); v != "" && e.ActorID != v {
+			continue
+		}
+		if v := q.Get("action"); v != "" && !validation.MatchAction(v, e.Action) {
+			continue
+		}
+		if v := q.Get("target_type"); v != "" && e.TargetType != v {
+			continue
+		}
+		if v := q.Get("target_id"); v != "" && e.TargetID != v {
+			continue
+		}
+		if v := q.Get("schema_version"); v != "" && strconv.Itoa(e.SchemaVersion) != v {
+			continue
+		}
+		if start != nil && e.Timestamp.Before(*start) {
+			continue
+		}
+		if end != nil && e.Timestamp.After(*end) {
+			continue
+		}
+		if contains != nil && !metadataContains(e.Metadata, contains) {
+			continue
+		}
+		if v := q.Get("metadata_search"); v != "" &&
+			!strings.Contains(strings.ToLower(string(e.Metadata)), strings.ToLower(v)) {
+			continue
+		}
+		if v := q.Get("tenant_id"); v != "" && e.TenantID != v {
+			continue
+		}
+		if v := q.Get("correlation_id"); v != "" && e.CorrelationID != v {
+			continue
+		}
+		if v := q.Get("parent_event_id"); v != "" && e.ParentEventID != v {
+			continue
+		}
+		if v := q.Get("group_id"); v != "" && e.GroupID != v {
+			continue
+		}
+		if v := q.Get("chain_id"); v != "" && e.ChainID != v {
+			continue
+		}
+		if v := q.Get("tags"); v != "" && !hasTags(e.Tags, strings.Split(v, ","), true) {
+			continue
+		}
+		if v := q.Get("tags_any"); v != "" && !hasTags(e.Tags, strings.Split(v, ","), false) {
+			continue
+		}
+		matched = append(matched, e)
+	}
+
+	if r.URL.Path == "/v1/events/latest" {
+		var ok bool
+		if matched, ok = latestPerGroup(matched, q.Get("group_by")); !ok {
+			writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "group_by must be user_id or target")
+			return
+		}
+	}
+
+	// Events are stored oldest first; the default order is newest first.
+	if q.Get("order") != "asc" {
+		for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+			matched[i], matched[j] = matched[j], matched[i]
+		}
+	}
+
+	// Sorting by another field keeps the time order among ties.
+	var field func(e tryl.StoredEvent) string
+	switch q.Get("order_by") {
+	case "", tryl.OrderByTimestamp:
+	case tryl.OrderByAction:
+		field = func(e tryl.StoredEvent) string { return e.Action }
+	case tryl.OrderByUserID:
+		field = func(e tryl.StoredEvent) string { return e.UserID }
+	default:
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "order_by must be timestamp, action, or user_id")
+		return
+	}
+	if field != nil {
+		asc := q.Get("order") == "asc"
+		sort.SliceStable(matched, func(i, j int) bool {
+			if asc {
+				return field(matched[i]) < field(matched[j])
+			}
+			return field(matched[i]) > field(matched[j])
+		})
+	}
+
+	limit := defaultLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError,
+				fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+			return
+		}
+		limit = n
+	}
+
+	offset := 0
+	cursorMode := q.Get("cursor") != ""
+	if cursorMode {
+		n, err := strconv.Atoi(strings.TrimPrefix(q.Get("cursor"), "cur_"))
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, tryl.ErrCodeInvalidRequest, "invalid cursor")
+			return
+		}
+		offset = n
+	} else if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "offset must be non-negative")
+			return
+		}
+		offset = n
+	}
+
+	list := tryl.EventList{Events: []tryl.StoredEvent{}}
+	if offset < len(matched) {
+		endIdx := offset + limit
+		if endIdx > len(matched) {
+			endIdx = len(matched)
+		}
+		list.Events = matched[offset:endIdx]
+		list.HasMore = endIdx < len(matched)
+		if list.HasMore {
+			list.NextCursor = "cur_" + strconv.Itoa(endIdx)
+		}
+	}
+	if !cursorMode {
+		list.Total = len(matched)
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// hasTags reports whether tags include all of want, or any of want if all
+// is false.
+func hasTags(tags, want []string, all bool) bool {
+	for _, w := range want {
+		found := false
+		for _, t := range tags {
+			if t == w {
+				found = true
+				break
+			}
+		}
+		if found != all {
+			return found
+		}
+	}
+	return all
+}
+
+// metadataContains implements JSON containment: every key/value in want must be present in raw.
+func metadataContains(raw json.RawMessage, want any) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	var have any
+	if err := json.Unmarshal(raw, &have); err != nil {
+		return false
+	}
+	return jsonContains(have, want)
+}
+
+func jsonContains(have, want any) bool {
+	switch w := want.(type) {
+	case map[string]any:
+		h, ok := have.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, wv := range w {
+			hv, ok := h[k]
+			if !ok || !jsonContains(hv, wv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		h, ok := have.([]any)
+		if !ok {
+			return false
+		}
+		for _, wv := range w {
+			found := false
+			for _, hv := range h {
+				if jsonContains(hv, wv) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		return have == want
+	}
+}
+
+// ========== Projects ==========
+
+func (s *LocalServer) createProject(w http.ResponseWriter, r *http.Request) {
+	var req tryl.CreateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeInvalidRequest, "invalid JSON body")
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "name is required")
+		return
+	}
+	if !req.Environment.Valid() {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "environment must be live or test")
+		return
+	}
+
+	now := time.Now().UTC()
+	project := tryl.Project{
+		ID:          s.nextID("proj"),
+		Name:        req.Name,
+		Environment: req.Environment,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.projects = append(s.projects, project)
+	s.recordAudit(tryl.AuditActionProjectCreated, "project", project.ID, project.ID)
+
+	_, secret := s.newKey(project.ID, "Default", req.Environment, nil, nil)
+	writeJSON(w, http.StatusCreated, tryl.CreateProjectResponse{Project: project, APIKey: secret})
+}
+
+func (s *LocalServer) deleteProject(w http.ResponseWriter, projectID string) {
+	for i, p := range s.projects {
+		if p.ID == projectID {
+			s.projects = append(s.projects[:i], s.projects[i+1:]...)
+			s.recordAudit(tryl.AuditActionProjectDeleted, "project", projectID, projectID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, tryl.ErrCodeProjectNotFound, "project not found")
+}
+
+func (s *LocalServer) findProject(projectID string) bool {
+	for _, p := range s.projects {
+		if p.ID == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// ========== API Keys ==========
+
+func (s *LocalServer) listKeys(w http.ResponseWriter, r *http.Request, projectID string) {
+	if !s.findProject(projectID) {
+		writeError(w, http.StatusNotFound, tryl.ErrCodeProjectNotFound, "project not found")
+		return
+	}
+
+	q := r.URL.Query()
+	now := time.Now()
+	keys := []tryl.APIKey{}
+	for _, k := range s.keys {
+		if k.ProjectID != projectID {
+			continue
+		}
+		if v := q.Get("environment"); v != "" && k.Environment != tryl.Environment(v) {
+			continue
+		}
+		if v := q.Get("status"); v != "" && string(k.Status(now)) != v {
+			continue
+		}
+		if v := q.Get("scope"); v != "" && !hasScope(k.Scopes, v) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	writeJSON(w, http.StatusOK, tryl.APIKeyList{APIKeys: keys})
+}
+
+func (s *LocalServer) createKey(w http.ResponseWriter, r *http.Request, projectID string) {
+	if !s.findProject(projectID) {
+		writeError(w, http.StatusNotFound, tryl.ErrCodeProjectNotFound, "project not found")
+		return
+	}
+
+	var req tryl.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeInvalidRequest, "invalid JSON body")
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "name is required")
+		return
+	}
+	if !req.Environment.Valid() {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeValidationError, "environment must be live or test")
+		return
+	}
+
+	key, secret := s.newKey(projectID, req.Name, req.Environment, req.Scopes, req.ExpiresAt)
+	key.AllowedCIDRs, key.AllowedOrigins = req.AllowedCIDRs, req.AllowedOrigins
+	s.keys[len(s.keys)-1] = key
+	s.recordAudit(tryl.AuditActionKeyCreated, "api_key", key.ID, projectID)
+	writeJSON(w, http.StatusCreated, tryl.CreateAPIKeyResponse{APIKeyMetadata: key, APIKey: secret})
+}
+
+func (s *LocalServer) updateKey(w http.ResponseWriter, r *http.Request, keyID string) {
+	i := s.findKey(keyID)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, tryl.ErrCodeKeyNotFound, "API key not found")
+		return
+	}
+
+	var req tryl.UpdateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeInvalidRequest, "invalid JSON body")
+		return
+	}
+	if req.Name != nil {
+		s.keys[i].Name = *req.Name
+	}
+	if req.Scopes != nil {
+		s.keys[i].Scopes = req.Scopes
+	}
+	if req.ExpiresAt != nil {
+		s.keys[i].ExpiresAt = req.ExpiresAt
+	}
+	if req.AllowedCIDRs != nil {
+		s.keys[i].AllowedCIDRs = req.AllowedCIDRs
+	}
+	if req.AllowedOrigins != nil {
+		s.keys[i].AllowedOrigins = req.AllowedOrigins
+	}
+	s.recordAudit(tryl.AuditActionKeyUpdated, "api_key", keyID, s.keys[i].ProjectID)
+	writeJSON(w, http.StatusOK, s.keys[i])
+}
+
+func (s *LocalServer) revokeKey(w http.ResponseWriter, keyID string) {
+	i := s.findKey(keyID)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, tryl.ErrCodeKeyNotFound, "API key not found")
+		return
+	}
+	now := time.Now().UTC()
+	s.keys[i].RevokedAt = &now
+	s.recordAudit(tryl.AuditActionKeyRevoked, "api_key", keyID, s.keys[i].ProjectID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *LocalServer) rotateKey(w http.ResponseWriter, r *http.Request, keyID string) {
+	i := s.findKey(keyID)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, tryl.ErrCodeKeyNotFound, "API key not found")
+		return
+	}
+
+	var req tryl.RotateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, tryl.ErrCodeInvalidRequest, "invalid JSON body")
+		return
+	}
+
+	old := s.keys[i]
+	now := time.Now().UTC()
+	s.keys[i].RevokedAt = &now
+
+	name := old.Name
+	if req.NewName != "" {
+		name = req.NewName
+	}
+	key, secret := s.newKey(old.ProjectID, name, old.Environment, old.Scopes, req.ExpiresAt)
+	key.AllowedCIDRs, key.AllowedOrigins = old.AllowedCIDRs, old.AllowedOrigins
+	s.keys[len(s.keys)-1] = key
+	s.recordAudit(tryl.AuditActionKeyRotated, "api_key", keyID, old.ProjectID)
+	writeJSON(w, http.StatusOK, tryl.RotateAPIKeyResponse{
+		NewAPIKeyMetadata: key,
+		NewAPIKey:         secret,
+		OldKeyRevokedAt:   now,
+	})
+}
+
+func (s *LocalServer) findKey(keyID string) int {
+	for i, k := range s.keys {
+		if k.ID == keyID {
+			return i
+		}
+	}
+	return -1
+}
+
+// newKey creates and stores a key, returning its metadata and secret value.
+func (s *LocalServer) newKey(projectID, name string, env tryl.Environment, scopes []string, expiresAt *time.Time) (tryl.APIKey, string) {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	prefix := "actlog_" + string(env) + "_"
+	secret := prefix + hex.EncodeToString(buf)
+
+	if scopes == nil {
+		scopes = []string{"events:write", "events:read"}
+	}
+	key := tryl.APIKey{
+		ID:          s.nextID("key"),
+		ProjectID:   projectID,
+		Name:        name,
+		Environment: env,
+		Prefix:      secret[:len(prefix)+3],
+		Scopes:      scopes,
+		CreatedAt:   time.Now().UTC(),
+		ExpiresAt:   expiresAt,
+	}
+	s.keys = append(s.keys, key)
+	return key, secret
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ========== Audit ==========
+
+func (s *LocalServer) recordAudit(action, resourceType, resourceID, projectID string) {
+	s.audit = append(s.audit, tryl.AuditRecord{
+		ID:           s.nextID("aud"),
+		ActorID:      "tryltest",
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ProjectID:    projectID,
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+func (s *LocalServer) listAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	records := []tryl.AuditRecord{}
+	for _, a := range s.audit {
+		if v := q.Get("project_id"); v != "" && a.ProjectID != v {
+			continue
+		}
+		if v := q.Get("action"); v != "" && a.Action != v {
+			continue
+		}
+		if v := q.Get("resource_type"); v != "" && a.ResourceType != v {
+			continue
+		}
+		if v := q.Get("resource_id"); v != "" && a.ResourceID != v {
+			continue
+		}
+		records = append(records, a)
+	}
+	writeJSON(w, http.StatusOK, tryl.AuditRecordList{Records: records})
+}
+
+// ========== Helpers ==========
+
+// nextID returns a sequential identifier with the given prefix. Callers must hold s.mu.
+func (s *LocalServer) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s_%06d", prefix, s.seq)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]string{"code": code, "message": message},
+	})
+}
@@ -0,0 +1,474 @@
+package tryltest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/joshuawatkins04/tryl_sdk"
+)
+
+func TestLocalServer_Events(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, action := range []string{"user.created", "user.deleted", "org.created"} {
+		if _, err := client.Log(ctx, tryl.Event{UserID: "user_123", Action: action}); err != nil {
+			t.Fatalf("Log(%s) error = %v", action, err)
+		}
+	}
+
+	list, err := client.List(ctx, tryl.EventFilter{Action: "user.*"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Events) != 2 || list.Total != 2 {
+		t.Errorf("List(user.*) returned %d events (total %d), want 2", len(list.Events), list.Total)
+	}
+	if list.Events[0].Action != "user.deleted" {
+		t.Errorf("first event = %s, want newest (user.deleted)", list.Events[0].Action)
+	}
+
+	page, err := client.List(ctx, tryl.EventFilter{Limit: 2, Cursor: "cur_0", Order: "asc"})
+	if err != nil {
+		t.Fatalf("List() page error = %v", err)
+	}
+	if !page.HasMore || page.NextCursor == "" {
+		t.Errorf("expected another page, got HasMore=%v NextCursor=%q", page.HasMore, page.NextCursor)
+	}
+
+	batch, err := client.LogBatch(ctx, []tryl.Event{
+		{UserID: "user_1", Action: "doc.created"},
+		{UserID: "user_2", Action: "doc.created"},
+	})
+	if err != nil {
+		t.Fatalf("LogBatch() error = %v", err)
+	}
+	if len(batch.Succeeded()) != 2 {
+		t.Errorf("LogBatch() committed %d events, want 2", len(batch.Succeeded()))
+	}
+	if got := len(srv.Events()); got != 5 {
+		t.Errorf("server stored %d events, want 5", got)
+	}
+}
+
+func TestLocalServer_Tags(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, tags := range [][]string{{"billing"}, {"billing", "security"}, {"security"}, nil} {
+		if _, err := client.Log(ctx, tryl.Event{UserID: "user_123", Action: "invoice.updated", Tags: tags}); err != nil {
+			t.Fatalf("Log(%v) error = %v", tags, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		filter tryl.EventFilter
+		want   int
+	}{
+		{tryl.EventFilter{Tags: []string{"billing"}}, 2},
+		{tryl.EventFilter{Tags: []string{"billing", "security"}}, 1},
+		{tryl.EventFilter{TagsAny: []string{"billing", "security"}}, 3},
+	} {
+		list, err := client.List(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("List(%+v) error = %v", tt.filter, err)
+		}
+		if len(list.Events) != tt.want {
+			t.Errorf("List(%+v) returned %d events, want %d", tt.filter, len(list.Events), tt.want)
+		}
+	}
+
+	if _, err := client.Log(ctx, tryl.Event{UserID: "user_123", Action: "invoice.updated", Tags: []string{"Billing"}}); !tryl.IsClientValidationError(err) {
+		t.Errorf("Log() with an uppercase tag error = %v, want validation error", err)
+	}
+	if _, err := client.List(ctx, tryl.EventFilter{TagsAny: []string{"has space"}}); !tryl.IsClientValidationError(err) {
+		t.Errorf("List() with an invalid tag error = %v, want validation error", err)
+	}
+}
+
+func TestLocalServer_ListChildren(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	parent, err := client.Log(ctx, tryl.Event{UserID: "user_123", Action: "import.started"})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	var children []tryl.Event
+	for i := 0; i < 150; i++ {
+		children = append(children, tryl.Event{UserID: "user_123", Action: "contact.imported", ParentEventID: parent.ID})
+	}
+	children = append(children, tryl.Event{UserID: "user_123", Action: "contact.imported"})
+	if _, err := client.LogMany(ctx, children); err != nil {
+		t.Fatalf("LogMany() error = %v", err)
+	}
+
+	got, err := client.ListChildren(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("ListChildren() error = %v", err)
+	}
+	if len(got) != 150 || got[0].ParentEventID != parent.ID {
+		t.Errorf("ListChildren() returned %d events, want 150 children of %s", len(got), parent.ID)
+	}
+	if leaves, err := client.ListChildren(ctx, got[0].ID); err != nil || len(leaves) != 0 {
+		t.Errorf("ListChildren(leaf) = %v, %v, want no events", leaves, err)
+	}
+}
+
+func TestLocalServer_Management(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.ManagementClient()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	created, err := client.CreateProject(ctx, tryl.CreateProjectRequest{Name: "Dev", Environment: "test"})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if _, err := tryl.NewClient(created.APIKey); err != nil {
+		t.Errorf("initial API key %q is not well-formed: %v", created.APIKey, err)
+	}
+
+	keys, err := client.ListAPIKeys(ctx, created.Project.ID)
+	if err != nil {
+		t.Fatalf("ListAPIKeys() error = %v", err)
+	}
+	if len(keys.APIKeys) != 1 {
+		t.Fatalf("ListAPIKeys() returned %d keys, want 1", len(keys.APIKeys))
+	}
+
+	if err := client.RevokeAPIKey(ctx, keys.APIKeys[0].ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	active, err := client.ListAPIKeys(ctx, created.Project.ID, tryl.APIKeyFilter{Status: tryl.APIKeyStatusActive})
+	if err != nil {
+		t.Fatalf("ListAPIKeys(active) error = %v", err)
+	}
+	if len(active.APIKeys) != 0 {
+		t.Errorf("ListAPIKeys(active) returned %d keys, want 0", len(active.APIKeys))
+	}
+
+	err = client.DeleteProject(ctx, "proj_missing")
+	if !tryl.IsProjectNotFound(err) {
+		t.Errorf("DeleteProject(missing) error = %v, want project not found", err)
+	}
+}
+
+func TestLocalServer_Group(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	group := client.BeginGroup(ctx)
+	group.Add(tryl.Event{UserID: "user_123", Action: "order.created"})
+	group.Add(tryl.Event{UserID: "user_123", Action: "payment.captured"})
+	if _, err := client.Log(ctx, tryl.Event{UserID: "user_123", Action: "page.viewed"}); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if _, err := group.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	list, err := client.List(ctx, tryl.EventFilter{GroupID: group.ID()})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Events) != 2 || list.Events[0].GroupID != group.ID() {
+		t.Errorf("List(GroupID) returned %d events, want 2 in group %s", len(list.Events), group.ID())
+	}
+}
+
+func TestLocalServer_ContentHash(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client(tryl.WithContentHash())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	event, _ := tryl.Event{UserID: "user_123", Action: "invoice.paid"}.WithMetadataValidated(map[string]any{"amount": 1200})
+	if _, err := client.Log(ctx, event); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	list, err := client.List(ctx, tryl.EventFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Events) != 1 {
+		t.Fatalf("List() returned %d events, want 1", len(list.Events))
+	}
+	if err := tryl.VerifyEventHash(list.Events[0]); err != nil {
+		t.Errorf("VerifyEventHash() error = %v", err)
+	}
+}
+
+func TestLocalServer_Tenant(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, tenant := range []string{"org_a", "org_b", "org_a"} {
+		if _, err := client.Log(ctx, tryl.Event{UserID: "user_123", TenantID: tenant, Action: "doc.viewed"}); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	list, err := client.List(ctx, tryl.EventFilter{TenantID: "org_a"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Events) != 2 {
+		t.Fatalf("List() returned %d events for org_a, want 2", len(list.Events))
+	}
+	for _, e := range list.Events {
+		if e.TenantID != "org_a" {
+			t.Errorf("event %s TenantID = %q, want org_a", e.ID, e.TenantID)
+		}
+	}
+}
+
+func TestLocalServer_OrderBy(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, action := range []string{"doc.viewed", "auth.login", "doc.created", "auth.logout"} {
+		if _, err := client.Log(ctx, tryl.Event{UserID: "user_123", Action: action}); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	list, err := client.List(ctx, tryl.EventFilter{OrderBy: tryl.OrderByAction, Order: "asc"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var got []string
+	for _, e := range list.Events {
+		got = append(got, e.Action)
+	}
+	if strings.Join(got, ",") != "auth.login,auth.logout,doc.created,doc.viewed" {
+		t.Errorf("List() actions = %v, want ascending by action", got)
+	}
+}
+
+func TestLocalServer_Latest(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, e := range []tryl.Event{
+		{UserID: "user_1", Action: "doc.viewed", TargetType: "doc", TargetID: "d1"},
+		{UserID: "user_2", Action: "doc.viewed", TargetType: "doc", TargetID: "d1"},
+		{UserID: "user_1", Action: "doc.edited", TargetType: "doc", TargetID: "d2"},
+		{UserID: "user_3", Action: "auth.login"},
+	} {
+		if _, err := client.Log(ctx, e); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	users, err := client.LatestPerUser(ctx, tryl.EventFilter{Action: "doc.*"})
+	if err != nil {
+		t.Fatalf("LatestPerUser() error = %v", err)
+	}
+	if len(users.Events) != 2 || users.Events[0].Action != "doc.edited" || users.Events[1].UserID != "user_2" {
+		t.Errorf("LatestPerUser() = %+v, want user_1's edit then user_2's view", users.Events)
+	}
+
+	targets, err := client.LatestPerTarget(ctx, tryl.EventFilter{})
+	if err != nil {
+		t.Fatalf("LatestPerTarget() error = %v", err)
+	}
+	if len(targets.Events) != 2 || targets.Events[1].UserID != "user_2" {
+		t.Errorf("LatestPerTarget() = %+v, want one event per document", targets.Events)
+	}
+}
+
+func TestLocalServer_UserSummary(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, e := range []tryl.Event{
+		{UserID: "user_1", Action: "doc.viewed"},
+		{UserID: "user_2", Action: "doc.viewed"},
+		{UserID: "user_1", Action: "doc.viewed"},
+		{UserID: "user_1", Action: "doc.edited"},
+	} {
+		if _, err := client.Log(ctx, e); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	summary, err := client.UserSummary(ctx, "user_1", tryl.Last24Hours())
+	if err != nil {
+		t.Fatalf("UserSummary() error = %v", err)
+	}
+	if summary.Total != 3 || summary.Actions["doc.viewed"] != 2 || summary.ActiveDays != 1 || summary.FirstSeen.After(summary.LastSeen) {
+		t.Errorf("UserSummary() = %+v", summary)
+	}
+
+	empty, err := client.UserSummary(ctx, "user_9", tryl.TimeRange{})
+	if err != nil || empty.Total != 0 {
+		t.Errorf("UserSummary(unknown) = %+v, %v, want an empty summary", empty, err)
+	}
+}
+
+func TestLocalServer_Chain(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	chain, err := client.Chain(ctx, "admin")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	for _, action := range []string{"user.invited", "user.role_changed"} {
+		if _, err := chain.Log(ctx, tryl.Event{UserID: "admin_1", Action: action}); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+	if _, err := client.Log(ctx, tryl.Event{UserID: "user_123", Action: "page.viewed"}); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	// A new Chain continues from the stored events, as after a restart.
+	resumed, err := client.Chain(ctx, "admin")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if resumed.LastHash() != chain.LastHash() {
+		t.Errorf("resumed LastHash() = %q, want %q", resumed.LastHash(), chain.LastHash())
+	}
+	if _, err := resumed.Log(ctx, tryl.Event{UserID: "admin_1", Action: "user.removed"}); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	if err := client.VerifyChain(ctx, "admin"); err != nil {
+		t.Errorf("VerifyChain() error = %v", err)
+	}
+}
+
+func TestLocalServer_KeyRestrictions(t *testing.T) {
+	t.Parallel()
+
+	srv := NewLocalServer()
+	defer srv.Close()
+
+	client, err := srv.ManagementClient()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	project, err := client.CreateProject(ctx, tryl.CreateProjectRequest{Name: "Dev", Environment: "test"})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	created, err := client.CreateAPIKey(ctx, project.Project.ID, tryl.CreateAPIKeyRequest{
+		Name:           "browser",
+		Environment:    "test",
+		AllowedCIDRs:   []string{"203.0.113.0/24"},
+		AllowedOrigins: []string{"https://app.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if len(created.APIKeyMetadata.AllowedCIDRs) != 1 || len(created.APIKeyMetadata.AllowedOrigins) != 1 {
+		t.Fatalf("CreateAPIKey() = %+v", created.APIKeyMetadata)
+	}
+
+	updated, err := client.UpdateAPIKey(ctx, created.APIKeyMetadata.ID, tryl.UpdateAPIKeyRequest{AllowedOrigins: []string{}})
+	if err != nil {
+		t.Fatalf("UpdateAPIKey() error = %v", err)
+	}
+	if len(updated.AllowedCIDRs) != 1 || len(updated.AllowedOrigins) != 0 {
+		t.Errorf("UpdateAPIKey() = %+v, want the origins cleared and the CIDRs kept", updated)
+	}
+
+	rotated, err := client.RotateAPIKey(ctx, created.APIKeyMetadata.ID, tryl.RotateAPIKeyRequest{})
+	if err != nil {
+		t.Fatalf("RotateAPIKey() error = %v", err)
+	}
+	if got := rotated.NewAPIKeyMetadata.AllowedCIDRs; len(got) != 1 || got[0] != "203.0.113.0/24" {
+		t.Errorf("rotated key AllowedCIDRs = %v", got)
+	}
+}
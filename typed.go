@@ -0,0 +1,66 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ListAs retrieves all events matching filter and decodes each into a T,
+// replacing per-consumer json.Unmarshal loops. T is usually a struct whose
+// fields are tagged with metadata keys and, optionally, the StoredEvent
+// JSON names of base fields:
+//
+//	type Payment struct {
+//	    ID        string    `json:"id"`
+//	    UserID    string    `json:"user_id"`
+//	    Timestamp time.Time `json:"timestamp"`
+//	    Amount    int       `json:"amount"`   // from metadata
+//	    Currency  string    `json:"currency"` // from metadata
+//	}
+//
+//	payments, err := tryl.ListAs[Payment](ctx, client, tryl.EventFilter{Action: "payment.captured"})
+//
+// Embedding StoredEvent in T also works. Base fields take precedence over
+// metadata keys of the same name. All pages are fetched, using
+// filter.Limit as the page size, so narrow the filter for large result
+// sets. A decoding failure reports the event's ID.
+func ListAs[T any](ctx context.Context, client *Client, filter EventFilter) ([]T, error) {
+	var out []T
+	for {
+		page, err := client.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page.Events {
+			v, err := DecodeEvent[T](e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		if !page.HasMore || page.NextCursor == "" {
+			return out, nil
+		}
+		filter.Cursor, filter.Offset = page.NextCursor, 0
+	}
+}
+
+// DecodeEvent decodes a stored event's metadata and base fields into a T,
+// as ListAs does for each event.
+func DecodeEvent[T any](event StoredEvent) (T, error) {
+	var v T
+	if len(event.Metadata) > 0 && string(event.Metadata) != "null" {
+		if err := json.Unmarshal(event.Metadata, &v); err != nil {
+			return v, fmt.Errorf("failed to decode metadata of event %s: %w", event.ID, err)
+		}
+	}
+	base, err := json.Marshal(event)
+	if err != nil {
+		return v, fmt.Errorf("failed to decode event %s: %w", event.ID, err)
+	}
+	if err := json.Unmarshal(base, &v); err != nil {
+		return v, fmt.Errorf("failed to decode event %s: %w", event.ID, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,58 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListAs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"events":[{"id":"evt_1","user_id":"user_1","action":"payment.captured","metadata":{"amount":1200,"currency":"EUR","id":"spoofed"},"timestamp":"2026-01-30T10:00:00Z"}],"has_more":true,"next_cursor":"c1"}`))
+			return
+		}
+		w.Write([]byte(`{"events":[{"id":"evt_2","user_id":"user_2","action":"payment.captured","metadata":{"amount":300,"currency":"USD"},"timestamp":"2026-01-30T11:00:00Z"}],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	type Payment struct {
+		ID        string    `json:"id"`
+		UserID    string    `json:"user_id"`
+		Timestamp time.Time `json:"timestamp"`
+		Amount    int       `json:"amount"`
+		Currency  string    `json:"currency"`
+	}
+	payments, err := ListAs[Payment](context.Background(), client, EventFilter{Action: "payment.captured"})
+	if err != nil {
+		t.Fatalf("ListAs() error = %v", err)
+	}
+	if len(payments) != 2 {
+		t.Fatalf("ListAs() returned %d values, want 2", len(payments))
+	}
+	if p := payments[0]; p.ID != "evt_1" || p.UserID != "user_1" || p.Amount != 1200 || p.Currency != "EUR" || p.Timestamp.IsZero() {
+		t.Errorf("payments[0] = %+v", p)
+	}
+
+	type Embedded struct {
+		StoredEvent
+		Amount int `json:"amount"`
+	}
+	e, err := DecodeEvent[Embedded](StoredEvent{ID: "evt_3", Action: "payment.captured", Metadata: []byte(`{"amount":5}`)})
+	if err != nil || e.ID != "evt_3" || e.Amount != 5 {
+		t.Errorf("DecodeEvent() = %+v, %v", e, err)
+	}
+
+	if _, err := DecodeEvent[Payment](StoredEvent{ID: "evt_4", Metadata: []byte(`{"amount":"lots"}`)}); err == nil {
+		t.Error("DecodeEvent() with mistyped metadata succeeded")
+	}
+}
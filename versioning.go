@@ -0,0 +1,103 @@
+package tryl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// MetadataMigration upgrades event metadata from one schema version to the next.
+type MetadataMigration func(metadata json.RawMessage) (json.RawMessage, error)
+
+// migration is a registered upgrade step.
+type migration struct {
+	pattern     string
+	fromVersion int
+	fn          MetadataMigration
+}
+
+// MigrationRegistry holds metadata migrations keyed by action and schema version.
+// It is safe for concurrent use.
+//
+// Example:
+//
+//	migrations := tryl.NewMigrationRegistry()
+//	migrations.Register("user.created", 1, func(m json.RawMessage) (json.RawMessage, error) {
+//	    // v1 stored "name"; v2 splits it into "first_name" and "last_name".
+//	    ...
+//	})
+//
+//	client, err := tryl.NewClient(apiKey, tryl.WithMigrations(migrations))
+type MigrationRegistry struct {
+	mu         sync.RWMutex
+	migrations []migration
+}
+
+// NewMigrationRegistry creates an empty migration registry.
+func NewMigrationRegistry() *MigrationRegistry {
+	return &MigrationRegistry{}
+}
+
+// Register adds a migration that upgrades events whose action matches
+// pattern (which may contain "*" wildcards) from fromVersion to
+// fromVersion+1. Use fromVersion 0 to upgrade unversioned events.
+// When several migrations match, the first registered wins.
+func (r *MigrationRegistry) Register(pattern string, fromVersion int, fn MetadataMigration) error {
+	if err := ValidateActionPattern(pattern); err != nil {
+		return err
+	}
+	if fromVersion < 0 {
+		return &ValidationError{Field: "from_version", Message: "cannot be negative"}
+	}
+	if fn == nil {
+		return &ValidationError{Field: "migration", Message: "cannot be nil"}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.migrations = append(r.migrations, migration{pattern: pattern, fromVersion: fromVersion, fn: fn})
+	return nil
+}
+
+// Upgrade applies registered migrations to the event until no migration
+// matches its action and current schema version.
+func (r *MigrationRegistry) Upgrade(event StoredEvent) (StoredEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for {
+		m, ok := r.find(event.Action, event.SchemaVersion)
+		if !ok {
+			return event, nil
+		}
+		metadata, err := m.fn(event.Metadata)
+		if err != nil {
+			return event, fmt.Errorf("failed to migrate %s (%s) from schema version %d: %w",
+				event.ID, event.Action, event.SchemaVersion, err)
+		}
+		event.Metadata = metadata
+		event.SchemaVersion++
+	}
+}
+
+// UpgradeList upgrades every event in the list in place.
+func (r *MigrationRegistry) UpgradeList(list *EventList) error {
+	for i, e := range list.Events {
+		upgraded, err := r.Upgrade(e)
+		if err != nil {
+			return err
+		}
+		list.Events[i] = upgraded
+	}
+	return nil
+}
+
+// find returns the first migration for action at version. Callers must hold r.mu.
+func (r *MigrationRegistry) find(action string, version int) (migration, bool) {
+	for _, m := range r.migrations {
+		if m.fromVersion == version && MatchesAction(m.pattern, action) {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
@@ -0,0 +1,71 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMigrationRegistry_Upgrade(t *testing.T) {
+	t.Parallel()
+
+	r := NewMigrationRegistry()
+	if err := r.Register("user.*", 0, func(m json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`{"v":1}`), nil
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := r.Register("user.created", 1, func(m json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`{"v":2}`), nil
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	got, err := r.Upgrade(StoredEvent{Action: "user.created", Metadata: json.RawMessage(`{"v":0}`)})
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if got.SchemaVersion != 2 || string(got.Metadata) != `{"v":2}` {
+		t.Errorf("Upgrade() = version %d metadata %s, want version 2 {\"v\":2}", got.SchemaVersion, got.Metadata)
+	}
+
+	got, err = r.Upgrade(StoredEvent{Action: "org.created", Metadata: json.RawMessage(`{"v":0}`)})
+	if err != nil || got.SchemaVersion != 0 {
+		t.Errorf("Upgrade() of unmatched action = version %d, err %v; want unchanged", got.SchemaVersion, err)
+	}
+}
+
+func TestClient_List_WithMigrations(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("schema_version"); got != "1" {
+			t.Errorf("schema_version = %q, want 1", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"events":[{"id":"evt_1","user_id":"u","action":"user.created","schema_version":1,"metadata":{"name":"Ada"}}]}`))
+	}))
+	defer server.Close()
+
+	migrations := NewMigrationRegistry()
+	migrations.Register("user.created", 1, func(m json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`{"first_name":"Ada"}`), nil
+	})
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithMigrations(migrations))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	list, err := client.List(context.Background(), EventFilter{SchemaVersion: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if list.Events[0].SchemaVersion != 2 || string(list.Events[0].Metadata) != `{"first_name":"Ada"}` {
+		t.Errorf("List() returned un-migrated event: %+v", list.Events[0])
+	}
+}
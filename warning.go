@@ -0,0 +1,58 @@
+package tryl
+
+import "github.com/joshuawatkins04/tryl_sdk/internal/transport"
+
+// Codes of ResponseWarning.
+const (
+	// WarningDeprecatedParameter means a request parameter is deprecated;
+	// Param names it.
+	WarningDeprecatedParameter = "deprecated_parameter"
+	// WarningFilterDowngraded means the server applied a filter less
+	// precisely than requested, for example a metadata search over a
+	// sample of events; Param names the filter.
+	WarningFilterDowngraded = "filter_downgraded"
+	// WarningRetentionTruncated means the query's time range starts before
+	// the project's retention window, so older matching events are missing
+	// from the results. Added by WithRetentionWarnings.
+	WarningRetentionTruncated = "retention_truncated"
+)
+
+// ResponseWarning is a non-fatal condition reported alongside a result,
+// such as a deprecated parameter or results truncated by retention. The
+// API reports them in a "warnings" array in response bodies; they are
+// returned in the Warnings field of EventList, EventResponse, and
+// UserSummary, and passed to the handler set with WithWarningHandler.
+type ResponseWarning struct {
+	// Code identifies the kind of warning (e.g., WarningRetentionTruncated).
+	Code string `json:"code"`
+	// Message is a human-readable description.
+	Message string `json:"message"`
+	// Param is the request parameter the warning concerns, if any.
+	Param string `json:"param,omitempty"`
+
+	// Method and Path identify the request that returned the warning. They
+	// are set only for warnings passed to a WithWarningHandler handler.
+	Method string `json:"-"`
+	Path   string `json:"-"`
+}
+
+// reportWarnings passes the warnings decoded from the response to req to
+// the WithWarningHandler handler.
+func (c *Client) reportWarnings(req transport.Request, warnings []ResponseWarning) {
+	if c.config.onWarning == nil {
+		return
+	}
+	for _, w := range warnings {
+		w.Method, w.Path = req.Method, req.Path
+		c.config.onWarning(w)
+	}
+}
+
+// reportWarnings passes the warnings of the batch and of each event to
+// the WithWarningHandler handler.
+func (r *batchResponse) reportWarnings(c *Client, req transport.Request) {
+	c.reportWarnings(req, r.Warnings)
+	for i := range r.Results {
+		c.reportWarnings(req, r.Results[i].Warnings)
+	}
+}
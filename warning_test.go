@@ -0,0 +1,68 @@
+package tryl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestClient_ResponseWarnings(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/events/batch" {
+			w.Write([]byte(`{"results":[{"id":"evt_2","warnings":[{"code":"deprecated_parameter","message":"target is deprecated","param":"target"}]}],"warnings":[{"code":"filter_downgraded","message":"batch downgraded"}]}`))
+			return
+		}
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"evt_1","timestamp":"2026-03-01T12:00:00Z","warnings":[{"code":"deprecated_parameter","message":"target is deprecated","param":"target"}]}`))
+			return
+		}
+		w.Write([]byte(`{"events":[],"has_more":false,"warnings":[{"code":"filter_downgraded","message":"metadata search sampled","param":"metadata_search"}]}`))
+	}))
+	defer server.Close()
+
+	var (
+		mu   sync.Mutex
+		seen []ResponseWarning
+	)
+	client, _ := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL),
+		WithWarningHandler(func(w ResponseWarning) {
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, w)
+		}))
+	ctx := context.Background()
+
+	resp, err := client.Log(ctx, Event{UserID: "user_1", Action: "doc.viewed"})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Code != WarningDeprecatedParameter {
+		t.Errorf("EventResponse.Warnings = %+v", resp.Warnings)
+	}
+
+	list, err := client.List(ctx, EventFilter{MetadataSearch: "declined"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Warnings) != 1 || list.Warnings[0].Code != WarningFilterDowngraded || list.Warnings[0].Param != "metadata_search" {
+		t.Errorf("EventList.Warnings = %+v", list.Warnings)
+	}
+
+	if _, err := client.LogBatch(ctx, []Event{{UserID: "user_1", Action: "doc.viewed"}}); err != nil {
+		t.Fatalf("LogBatch() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 4 || seen[0].Method != "POST" || seen[1].Path != "/v1/events" || seen[1].Code != WarningFilterDowngraded {
+		t.Errorf("handler saw %+v, want every warning with its request", seen)
+	}
+	if len(seen) == 4 && (seen[2].Path != "/v1/events/batch" || seen[2].Message != "batch downgraded" || seen[3].Code != WarningDeprecatedParameter) {
+		t.Errorf("handler saw batch warnings %+v, want the batch's and the event's", seen[2:])
+	}
+}
@@ -0,0 +1,257 @@
+package tryl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/joshuawatkins04/tryl_sdk/internal/transport"
+)
+
+// Webhook is an endpoint the API notifies of a project's events. Its
+// signing secret is never returned after creation.
+type Webhook struct {
+	// ID is the unique identifier for the webhook.
+	ID string `json:"id"`
+	// ProjectID is the project the webhook belongs to.
+	ProjectID string `json:"project_id"`
+	// URL is the endpoint notifications are posted to.
+	URL string `json:"url"`
+	// Actions are the action patterns that trigger a notification. Empty
+	// means every action.
+	Actions []string `json:"actions,omitempty"`
+	// Enabled reports whether notifications are being sent.
+	Enabled bool `json:"enabled"`
+	// CreatedAt is when the webhook was created.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookList represents a list of webhooks for a project.
+type WebhookList struct {
+	// Webhooks is the array of webhooks.
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// CreateWebhookRequest represents the request to create a webhook.
+type CreateWebhookRequest struct {
+	// URL is the endpoint notifications are posted to (required).
+	URL string `json:"url"`
+	// Actions are the action patterns that trigger a notification
+	// (optional, defaults to every action).
+	Actions []string `json:"actions,omitempty"`
+	// Disabled creates the webhook without sending notifications.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// CreateWebhookResponse represents the response after creating a webhook.
+type CreateWebhookResponse struct {
+	// Webhook is the created webhook.
+	Webhook Webhook `json:"webhook"`
+	// Secret signs the webhook's notifications.
+	// IMPORTANT: This is only returned once at creation time. Store it securely.
+	Secret string `json:"secret"`
+}
+
+// UpdateWebhookRequest represents the request to modify a webhook.
+// Only fields that are set are changed; nil fields are left untouched.
+type UpdateWebhookRequest struct {
+	// URL is a new endpoint for notifications (optional).
+	URL *string `json:"url,omitempty"`
+	// Actions replaces the action patterns that trigger a notification
+	// (optional). An empty, non-nil slice notifies of every action.
+	Actions []string `json:"actions"`
+	// Enabled starts or stops notifications (optional).
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// MarshalJSON encodes the request, leaving out nil actions and keeping
+// empty ones, which notify of every action.
+func (r UpdateWebhookRequest) MarshalJSON() ([]byte, error) {
+	type request UpdateWebhookRequest
+	out := struct {
+		request
+		Actions *[]string `json:"actions,omitempty"`
+	}{request: request(r)}
+	if r.Actions != nil {
+		out.Actions = &r.Actions
+	}
+	return json.Marshal(out)
+}
+
+// CreateWebhook creates a webhook in a project.
+// Requires session token authentication (use NewManagementClient).
+// Returns the webhook and its signing secret (shown only once).
+func (c *Client) CreateWebhook(ctx context.Context, projectID string, req CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	if req.URL == "" {
+		return nil, &ValidationError{Field: "url", Message: "is required"}
+	}
+
+	var resp *CreateWebhookResponse
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doCreateWebhook(ctx, projectID, req)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doCreateWebhook performs the create webhook request without retries.
+func (c *Client) doCreateWebhook(ctx context.Context, projectID string, req CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	transportReq := transport.Request{
+		Method: "POST",
+		Path:   fmt.Sprintf("/v1/projects/%s/webhooks", projectID),
+		Body:   req,
+	}
+
+	resp, err := c.transport.Do(ctx, transportReq)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var created CreateWebhookResponse
+	if err := json.Unmarshal(resp.Body, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateWebhook changes the URL, actions, or state of a webhook.
+// Requires session token authentication (use NewManagementClient).
+// Returns the updated webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, webhookID string, req UpdateWebhookRequest) (*Webhook, error) {
+	if req.URL == nil && req.Actions == nil && req.Enabled == nil {
+		return nil, &ValidationError{
+			Field:   "request",
+			Message: "at least one of url, actions, or enabled must be set",
+		}
+	}
+	if req.URL != nil && *req.URL == "" {
+		return nil, &ValidationError{Field: "url", Message: "cannot be empty"}
+	}
+
+	var resp *Webhook
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doUpdateWebhook(ctx, webhookID, req)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doUpdateWebhook performs the update webhook request without retries.
+func (c *Client) doUpdateWebhook(ctx context.Context, webhookID string, req UpdateWebhookRequest) (*Webhook, error) {
+	transportReq := transport.Request{
+		Method: "PATCH",
+		Path:   fmt.Sprintf("/v1/webhooks/%s", webhookID),
+		Body:   req,
+	}
+
+	resp, err := c.transport.Do(ctx, transportReq)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var webhook Webhook
+	if err := json.Unmarshal(resp.Body, &webhook); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// DeleteWebhook deletes a webhook by ID.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
+	return c.retryer.do(ctx, func(ctx context.Context) error {
+		return c.doDeleteWebhook(ctx, webhookID)
+	})
+}
+
+// doDeleteWebhook performs the delete webhook request without retries.
+func (c *Client) doDeleteWebhook(ctx context.Context, webhookID string) error {
+	req := transport.Request{
+		Method: "DELETE",
+		Path:   fmt.Sprintf("/v1/webhooks/%s", webhookID),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// ListWebhooks retrieves the webhooks of a project.
+// Requires session token authentication (use NewManagementClient).
+func (c *Client) ListWebhooks(ctx context.Context, projectID string) (*WebhookList, error) {
+	var resp *WebhookList
+
+	err := c.retryer.do(ctx, func(ctx context.Context) error {
+		r, err := c.doListWebhooks(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doListWebhooks performs the list webhooks request without retries.
+func (c *Client) doListWebhooks(ctx context.Context, projectID string) (*WebhookList, error) {
+	req := transport.Request{
+		Method: "GET",
+		Path:   fmt.Sprintf("/v1/projects/%s/webhooks", projectID),
+	}
+
+	resp, err := c.transport.Do(ctx, req)
+	if err != nil {
+		return nil, &NetworkError{Op: "request", Err: err}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	var list WebhookList
+	if err := json.Unmarshal(resp.Body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &list, nil
+}
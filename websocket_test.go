@@ -0,0 +1,265 @@
+package tryl
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// wsTestServer upgrades /v1/ws and passes each decoded request envelope to
+// handle, writing back whatever frames it returns. A nil result closes the
+// connection.
+func wsTestServer(t *testing.T, handle func(req map[string]any) [][]byte) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/ws" || r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		h := sha1.New()
+		h.Write([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(h.Sum(nil)) + "\r\n\r\n")
+		rw.Flush()
+
+		for {
+			payload, err := readTestFrame(rw.Reader)
+			if err != nil {
+				return
+			}
+			var req map[string]any
+			if err := json.Unmarshal(payload, &req); err != nil {
+				return
+			}
+			frames := handle(req)
+			if frames == nil {
+				return
+			}
+			for _, out := range frames {
+				writeTestFrame(conn, out)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readTestFrame reads one masked client text frame.
+func readTestFrame(r *bufio.Reader) ([]byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	if head[0]&0x0F == 0x8 {
+		return nil, io.EOF
+	}
+	n := uint64(head[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(r, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return payload, nil
+}
+
+// writeTestFrame writes one unmasked server text frame.
+func writeTestFrame(w io.Writer, payload []byte) {
+	header := []byte{0x81, 0}
+	if len(payload) < 126 {
+		header[1] = byte(len(payload))
+	} else {
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(len(payload)))
+	}
+	w.Write(append(header, payload...))
+}
+
+func TestClient_WebSocket_Log(t *testing.T) {
+	t.Parallel()
+
+	server := wsTestServer(t, func(req map[string]any) [][]byte {
+		if req["method"] != "POST" || req["path"] != "/v1/events" {
+			t.Errorf("request = %v %v, want POST /v1/events", req["method"], req["path"])
+		}
+		reply, _ := json.Marshal(map[string]any{
+			"id":     req["id"],
+			"status": 201,
+			"body":   map[string]any{"id": "evt_ws", "created_at": time.Now()},
+		})
+		return [][]byte{reply}
+	})
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithWebSocket())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Log(context.Background(), Event{UserID: "user_1", Action: "user.login"})
+		if err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+		if resp.ID != "evt_ws" {
+			t.Errorf("ID = %q, want %q", resp.ID, "evt_ws")
+		}
+	}
+}
+
+func TestClient_WebSocket_DuplicateReply(t *testing.T) {
+	t.Parallel()
+
+	server := wsTestServer(t, func(req map[string]any) [][]byte {
+		reply, _ := json.Marshal(map[string]any{
+			"id":     req["id"],
+			"status": 201,
+			"body":   map[string]any{"id": "evt_ws", "created_at": time.Now()},
+		})
+		return [][]byte{reply, reply, reply}
+	})
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithWebSocket())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Log(ctx, Event{UserID: "user_1", Action: "user.login"}); err != nil {
+			t.Fatalf("Log() #%d error = %v", i, err)
+		}
+	}
+}
+
+func TestClient_WebSocket_APIError(t *testing.T) {
+	t.Parallel()
+
+	server := wsTestServer(t, func(req map[string]any) [][]byte {
+		reply, _ := json.Marshal(map[string]any{
+			"id":     req["id"],
+			"status": 401,
+			"body":   map[string]any{"error": map[string]any{"code": "unauthorized", "message": "bad key"}},
+		})
+		return [][]byte{reply}
+	})
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithWebSocket())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Log(context.Background(), Event{UserID: "user_1", Action: "user.login"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != 401 {
+		t.Fatalf("Log() error = %v, want 401 APIError", err)
+	}
+}
+
+func TestClient_WebSocket_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	server := wsTestServer(t, func(req map[string]any) [][]byte {
+		pause, _ := json.Marshal(map[string]any{"type": "backpressure", "pause_ms": 200})
+		reply, _ := json.Marshal(map[string]any{
+			"id":     req["id"],
+			"status": 201,
+			"body":   map[string]any{"id": "evt_ws"},
+		})
+		return [][]byte{pause, reply}
+	})
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef", WithBaseURL(server.URL), WithWebSocket())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	event := Event{UserID: "user_1", Action: "user.login"}
+	if _, err := client.Log(context.Background(), event); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.Log(context.Background(), event); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("second Log() took %v, want it to wait out the backpressure pause", elapsed)
+	}
+}
+
+func TestClient_WebSocket_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := wsTestServer(t, func(req map[string]any) [][]byte {
+		// Drop the connection on the first request.
+		if calls.Add(1) == 1 {
+			return nil
+		}
+		reply, _ := json.Marshal(map[string]any{"id": req["id"], "status": 201, "body": map[string]any{"id": "evt_ws"}})
+		return [][]byte{reply}
+	})
+
+	client, err := NewClient("actlog_test_1234567890abcdef1234567890abcdef",
+		WithBaseURL(server.URL),
+		WithWebSocket(),
+		WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	resp, err := client.Log(context.Background(), Event{UserID: "user_1", Action: "user.login"})
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if resp.ID != "evt_ws" {
+		t.Errorf("ID = %q, want %q", resp.ID, "evt_ws")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+}